@@ -0,0 +1,17 @@
+// Package analysis computes measures describing the quality of an image.
+package analysis
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// SharpnessGray estimates the sharpness of a grayscale image as the variance of its Laplacian, see
+// utils.LaplacianVarianceGray. Higher values mean a sharper image, so it can be used to cull out-of-focus photos. The
+// values are only comparable between images of similar content, constant images give 0.
+// Example of usage:
+//
+//	sharpness := analysis.SharpnessGray(img)
+func SharpnessGray(img *image.Gray) float64 {
+	return utils.LaplacianVarianceGray(img)
+}
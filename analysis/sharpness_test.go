@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_SharpnessGray(t *testing.T) {
+	// a sharp vertical edge with a few horizontal bars
+	img := image.NewGray(image.Rect(0, 0, 48, 40))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if x >= 24 || (y%10 < 3 && x >= 8) {
+			img.SetGray(x, y, color.Gray{Y: 220})
+		} else {
+			img.SetGray(x, y, color.Gray{Y: 30})
+		}
+	})
+	blurred, _, err := blur.GaussianBlurGray(img, 6, 3, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	sharp, defocused := SharpnessGray(img), SharpnessGray(blurred)
+	if sharp < 20*defocused {
+		t.Errorf("Expected the sharp image to score much higher - sharp: %f, blurred: %f", sharp, defocused)
+	}
+	flat := image.NewGray(img.Bounds())
+	for i := range flat.Pix {
+		flat.Pix[i] = 90
+	}
+	if v := SharpnessGray(flat); v > 1e-9 {
+		t.Errorf("Expected a score near 0 for a constant image - actual: %f", v)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
+	"math"
 )
 
 // AddScalarToGray takes a grayscale image and adds an integer value to all pixels of the image. If the  result
@@ -22,12 +23,64 @@ func AddScalarToGray(img *image.Gray, value int) *image.Gray {
 	return res
 }
 
+// OverflowMode selects how the arithmetic operations handle results which do not fit into uint8.
+type OverflowMode int
+
+const (
+	// OverflowSaturate clamps the result to the [0, 255] interval.
+	OverflowSaturate OverflowMode = iota
+	// OverflowWrap uses modular arithmetic, the result is taken modulo 256 (e.g. 200 + 100 = 44, 50 - 100 = 206).
+	OverflowWrap
+)
+
 // AddGray accepts two grayscale images and adds their pixel values. If the result for a given position overflows uint8,
 // the result will be clamped to max uint8 (255).
 // Example of usage:
 //
 //	res, err := blend.AddGray(gray1, gray2)
 func AddGray(img1 *image.Gray, img2 *image.Gray) (*image.Gray, error) {
+	return AddGrayWithMode(img1, img2, OverflowSaturate)
+}
+
+// AddGrayWithMode accepts two grayscale images and adds their pixel values, the overflowing results are handled
+// according to the given mode.
+// Example of usage:
+//
+//	res, err := blend.AddGrayWithMode(gray1, gray2, blend.OverflowWrap)
+func AddGrayWithMode(img1 *image.Gray, img2 *image.Gray, mode OverflowMode) (*image.Gray, error) {
+	return arithmeticGray(img1, img2, mode, func(p1, p2 int) int {
+		return p1 + p2
+	})
+}
+
+// SubtractGray accepts two grayscale images and subtracts the pixel values of the second image from the pixel values
+// of the first image. If the result for a given position is negative, the result will be clamped to 0.
+// Example of usage:
+//
+//	res, err := blend.SubtractGray(gray1, gray2)
+func SubtractGray(img1 *image.Gray, img2 *image.Gray) (*image.Gray, error) {
+	return SubtractGrayWithMode(img1, img2, OverflowSaturate)
+}
+
+// SubtractGrayWithMode accepts two grayscale images and subtracts the pixel values of the second image from the pixel
+// values of the first image, the results which do not fit into uint8 are handled according to the given mode.
+// Example of usage:
+//
+//	res, err := blend.SubtractGrayWithMode(gray1, gray2, blend.OverflowWrap)
+func SubtractGrayWithMode(img1 *image.Gray, img2 *image.Gray, mode OverflowMode) (*image.Gray, error) {
+	return arithmeticGray(img1, img2, mode, func(p1, p2 int) int {
+		return p1 - p2
+	})
+}
+
+// AddGrayWeighted accepts two grayscale images and adds their pixel values using the following equation:
+// res(x, y) = img1(x, y) * w1 + img2(x, y) * w2
+// If the result for a given position overflows uint8, the result will be clamped to max uint8 (255).
+// If the result for a given position is negative, then it will be clamped to 0.
+// Example of usage:
+//
+//	res, err := blend.AddGrayWeighted(gray1, 0.25, gray2, 0.75)
+func AddGrayWeighted(img1 *image.Gray, w1 float64, img2 *image.Gray, w2 float64) (*image.Gray, error) {
 	size1 := img1.Bounds().Size()
 	size2 := img2.Bounds().Size()
 	if size1.X != size2.X || size1.Y != size2.Y {
@@ -37,31 +90,54 @@ func AddGray(img1 *image.Gray, img2 *image.Gray) (*image.Gray, error) {
 	utils.ParallelForEachPixel(size1, func(x int, y int) {
 		p1 := img1.GrayAt(x, y)
 		p2 := img2.GrayAt(x, y)
-		sum := utils.ClampInt(int(p1.Y)+int(p2.Y), utils.MinUint8, int(utils.MaxUint8))
+		sum := utils.ClampF64(float64(p1.Y)*w1+float64(p2.Y)*w2, utils.MinUint8, float64(utils.MaxUint8))
 		res.SetGray(x, y, color.Gray{uint8(sum)})
 	})
 	return res, nil
 }
 
-// AddGrayWeighted accepts two grayscale images and adds their pixel values using the following equation:
-// res(x, y) = img1(x, y) * w1 + img2(x, y) * w2
-// If the result for a given position overflows uint8, the result will be clamped to max uint8 (255).
-// If the result for a given position is negative, then it will be clamped to 0.
+// BlendGrayMap blends two grayscale images with a per-pixel weight given by an alpha map:
+// res(x, y) = a(x, y) * alpha(x, y) / 255 + b(x, y) * (1 - alpha(x, y) / 255)
+// so the pixels where the alpha map is 255 are taken from a, the pixels where it is 0 from b, and a smooth alpha map
+// gives a seamless transition between the images. The result is rounded to the nearest integer. Returns an error if
+// the sizes of the images and of the alpha map do not match.
 // Example of usage:
 //
-//	res, err := blend.AddGrayWeighted(gray1, 0.25, gray2, 0.75)
-func AddGrayWeighted(img1 *image.Gray, w1 float64, img2 *image.Gray, w2 float64) (*image.Gray, error) {
+//	res, err := blend.BlendGrayMap(gray1, gray2, alpha)
+func BlendGrayMap(a, b *image.Gray, alphaMap *image.Gray) (*image.Gray, error) {
+	size := a.Bounds().Size()
+	if !size.Eq(b.Bounds().Size()) || !size.Eq(alphaMap.Bounds().Size()) {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x int, y int) {
+		pa := float64(a.Pix[a.PixOffset(a.Rect.Min.X+x, a.Rect.Min.Y+y)])
+		pb := float64(b.Pix[b.PixOffset(b.Rect.Min.X+x, b.Rect.Min.Y+y)])
+		alpha := float64(alphaMap.Pix[alphaMap.PixOffset(alphaMap.Rect.Min.X+x, alphaMap.Rect.Min.Y+y)]) / float64(utils.MaxUint8)
+		res.Pix[y*res.Stride+x] = uint8(math.Round(pa*alpha + pb*(1-alpha)))
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func arithmeticGray(img1 *image.Gray, img2 *image.Gray, mode OverflowMode, op func(p1, p2 int) int) (*image.Gray, error) {
 	size1 := img1.Bounds().Size()
 	size2 := img2.Bounds().Size()
 	if size1.X != size2.X || size1.Y != size2.Y {
 		return nil, errors.New("the size of the two image does not match")
 	}
+	if mode != OverflowSaturate && mode != OverflowWrap {
+		return nil, errors.New("unknown overflow mode")
+	}
 	res := image.NewGray(img1.Bounds())
 	utils.ParallelForEachPixel(size1, func(x int, y int) {
-		p1 := img1.GrayAt(x, y)
-		p2 := img2.GrayAt(x, y)
-		sum := utils.ClampF64(float64(p1.Y)*w1+float64(p2.Y)*w2, utils.MinUint8, float64(utils.MaxUint8))
-		res.SetGray(x, y, color.Gray{uint8(sum)})
+		value := op(int(img1.GrayAt(x, y).Y), int(img2.GrayAt(x, y).Y))
+		if mode == OverflowWrap {
+			value &= int(utils.MaxUint8)
+		} else {
+			value = utils.ClampInt(value, utils.MinUint8, int(utils.MaxUint8))
+		}
+		res.SetGray(x, y, color.Gray{uint8(value)})
 	})
 	return res, nil
 }
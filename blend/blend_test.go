@@ -3,6 +3,8 @@ package blend
 import (
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
+	"math"
 	"testing"
 )
 
@@ -126,3 +128,79 @@ func Test_AddGrayWeightedError(t *testing.T) {
 		t.Fatalf("Should not reach this point")
 	}
 }
+
+func Test_BlendGrayMap(t *testing.T) {
+	a := image.NewGray(image.Rect(0, 0, 21, 5))
+	b := image.NewGray(image.Rect(0, 0, 21, 5))
+	alpha := image.NewGray(image.Rect(0, 0, 21, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 21; x++ {
+			a.SetGray(x, y, color.Gray{Y: uint8(200 + y)})
+			b.SetGray(x, y, color.Gray{Y: uint8(100 - 3*y)})
+			// a left to right ramp from 0 to 255
+			alpha.SetGray(x, y, color.Gray{Y: uint8(math.Round(255 * float64(x) / 20))})
+		}
+	}
+	res, err := BlendGrayMap(a, b, alpha)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 5; y++ {
+		if actual := res.GrayAt(0, y); actual != b.GrayAt(0, y) {
+			t.Errorf("Expected value: %d - actual value: %d at: 0 %d", b.GrayAt(0, y).Y, actual.Y, y)
+		}
+		if actual := res.GrayAt(20, y); actual != a.GrayAt(20, y) {
+			t.Errorf("Expected value: %d - actual value: %d at: 20 %d", a.GrayAt(20, y).Y, actual.Y, y)
+		}
+		half := (float64(a.GrayAt(10, y).Y) + float64(b.GrayAt(10, y).Y)) / 2
+		if actual := float64(res.GrayAt(10, y).Y); math.Abs(actual-half) > 1 {
+			t.Errorf("Expected value: %f - actual value: %f at: 10 %d", half, actual, y)
+		}
+		for x := 1; x < 21; x++ {
+			if res.GrayAt(x, y).Y < res.GrayAt(x-1, y).Y {
+				t.Errorf("Expected the blend to move monotonically from b to a at: %d %d", x, y)
+			}
+		}
+	}
+	if _, err := BlendGrayMap(a, b, image.NewGray(image.Rect(0, 0, 20, 5))); err == nil {
+		t.Error("Expected error for an alpha map of a different size")
+	}
+	if _, err := BlendGrayMap(a, image.NewGray(image.Rect(0, 0, 21, 4)), alpha); err == nil {
+		t.Error("Expected error for images of different sizes")
+	}
+}
+
+func Test_OverflowMode(t *testing.T) {
+	a := &image.Gray{Rect: image.Rect(0, 0, 2, 1), Stride: 2, Pix: []uint8{200, 50}}
+	b := &image.Gray{Rect: image.Rect(0, 0, 2, 1), Stride: 2, Pix: []uint8{100, 100}}
+	cases := []struct {
+		mode     OverflowMode
+		add, sub []uint8
+	}{
+		{OverflowSaturate, []uint8{255, 150}, []uint8{100, 0}},
+		{OverflowWrap, []uint8{44, 150}, []uint8{100, 206}},
+	}
+	for _, c := range cases {
+		sum, err := AddGrayWithMode(a, b, c.mode)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, &image.Gray{Rect: a.Rect, Stride: 2, Pix: c.add}, sum)
+		diff, err := SubtractGrayWithMode(a, b, c.mode)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, &image.Gray{Rect: a.Rect, Stride: 2, Pix: c.sub}, diff)
+	}
+	diff, err := SubtractGray(a, b)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, &image.Gray{Rect: a.Rect, Stride: 2, Pix: []uint8{100, 0}}, diff)
+	if _, err := AddGrayWithMode(a, b, OverflowMode(7)); err == nil {
+		t.Error("Expected error for unknown overflow mode")
+	}
+	if _, err := SubtractGray(a, image.NewGray(image.Rect(0, 0, 3, 3))); err == nil {
+		t.Error("Expected error for different sizes")
+	}
+}
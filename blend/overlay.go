@@ -0,0 +1,173 @@
+package blend
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// OverlayAnchor selects the position of an overlay on the base image.
+type OverlayAnchor int
+
+const (
+	// OverlayTopLeft places the overlay margin.X pixels from the left and margin.Y pixels from the top border.
+	OverlayTopLeft OverlayAnchor = iota
+	// OverlayTopRight places the overlay margin.X pixels from the right and margin.Y pixels from the top border.
+	OverlayTopRight
+	// OverlayBottomLeft places the overlay margin.X pixels from the left and margin.Y pixels from the bottom border.
+	OverlayBottomLeft
+	// OverlayBottomRight places the overlay margin.X pixels from the right and margin.Y pixels from the bottom border.
+	OverlayBottomRight
+	// OverlayCenter places the overlay in the center of the base image, the margin is ignored.
+	OverlayCenter
+)
+
+// OverlayRGBA alpha-composites an overlay, e.g. a logo, onto a copy of the base image at the position given by the
+// anchor and the margin. The overlay can be of any image type, its own alpha is respected (an NRGBA overlay is
+// premultiplied first) and it is multiplied by the opacity, which has to be in the [0, 1] interval, so an opacity of 0
+// returns an unchanged copy of the base. Returns an error if the opacity or the anchor is invalid or the overlay does
+// not fit into the base image at the given position, see OverlayRGBAFit to downscale such overlays.
+// Example of usage:
+//
+//	res, err := blend.OverlayRGBA(photo, logo, blend.OverlayBottomRight, image.Point{X: 16, Y: 16}, 0.8)
+func OverlayRGBA(base *image.RGBA, overlay image.Image, anchor OverlayAnchor, margin image.Point, opacity float64) (*image.RGBA, error) {
+	if opacity < 0 || opacity > 1 {
+		return nil, errors.New("opacity must be in the [0, 1] interval")
+	}
+	src := toRGBA(overlay)
+	position, err := overlayPosition(base.Bounds().Size(), src.Bounds().Size(), anchor, margin)
+	if err != nil {
+		return nil, err
+	}
+	baseSize := base.Bounds().Size()
+	srcSize := src.Bounds().Size()
+	if position.X < 0 || position.Y < 0 || position.X+srcSize.X > baseSize.X || position.Y+srcSize.Y > baseSize.Y {
+		return nil, errors.New("the overlay does not fit into the image")
+	}
+	res := copyRGBA(base)
+	compositeRGBA(res, src, position, opacity)
+	return res, nil
+}
+
+// OverlayRGBAFit works like OverlayRGBA, but an overlay which does not fit into the base image at the given position
+// is downscaled, keeping its aspect ratio, to the largest size which fits. Returns an error if the opacity or the
+// anchor is invalid or the margin leaves no room for the overlay.
+// Example of usage:
+//
+//	res, err := blend.OverlayRGBAFit(thumbnail, logo, blend.OverlayTopLeft, image.Point{X: 4, Y: 4}, 1)
+func OverlayRGBAFit(base *image.RGBA, overlay image.Image, anchor OverlayAnchor, margin image.Point, opacity float64) (*image.RGBA, error) {
+	src := toRGBA(overlay)
+	baseSize := base.Bounds().Size()
+	srcSize := src.Bounds().Size()
+	available := baseSize.Sub(margin)
+	if anchor == OverlayCenter {
+		available = baseSize
+	}
+	if available.X <= 0 || available.Y <= 0 {
+		return nil, errors.New("the margin leaves no room for the overlay")
+	}
+	if srcSize.X > available.X || srcSize.Y > available.Y {
+		f := math.Min(float64(available.X)/float64(srcSize.X), float64(available.Y)/float64(srcSize.Y))
+		scaled, err := resize.ResizeRGBAAntiAlias(src, f, f, resize.InterLinear)
+		if err != nil {
+			return nil, err
+		}
+		src = scaled
+	}
+	return OverlayRGBA(base, src, anchor, margin, opacity)
+}
+
+// TileWatermarkRGBA alpha-composites an overlay repeatedly across a copy of the base image, starting from the top left
+// corner, with the given spacing between the copies, e.g. to stamp a watermark over a whole preview image. The copies
+// on the right and the bottom border are clipped. The overlay is composited the same way as by OverlayRGBA. Returns an
+// error if the opacity is not in the [0, 1] interval, the spacing is negative or the overlay is empty.
+// Example of usage:
+//
+//	res, err := blend.TileWatermarkRGBA(preview, watermark, image.Point{X: 40, Y: 40}, 0.3)
+func TileWatermarkRGBA(base *image.RGBA, overlay image.Image, spacing image.Point, opacity float64) (*image.RGBA, error) {
+	if opacity < 0 || opacity > 1 {
+		return nil, errors.New("opacity must be in the [0, 1] interval")
+	}
+	if spacing.X < 0 || spacing.Y < 0 {
+		return nil, errors.New("spacing must not be negative")
+	}
+	src := toRGBA(overlay)
+	srcSize := src.Bounds().Size()
+	if srcSize.X == 0 || srcSize.Y == 0 {
+		return nil, errors.New("the overlay is empty")
+	}
+	res := copyRGBA(base)
+	baseSize := base.Bounds().Size()
+	for y := 0; y < baseSize.Y; y += srcSize.Y + spacing.Y {
+		for x := 0; x < baseSize.X; x += srcSize.X + spacing.X {
+			compositeRGBA(res, src, image.Point{X: x, Y: y}, opacity)
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// overlayPosition computes the top left corner of the overlay on the base image.
+func overlayPosition(baseSize, overlaySize image.Point, anchor OverlayAnchor, margin image.Point) (image.Point, error) {
+	right := baseSize.X - overlaySize.X - margin.X
+	bottom := baseSize.Y - overlaySize.Y - margin.Y
+	switch anchor {
+	case OverlayTopLeft:
+		return margin, nil
+	case OverlayTopRight:
+		return image.Point{X: right, Y: margin.Y}, nil
+	case OverlayBottomLeft:
+		return image.Point{X: margin.X, Y: bottom}, nil
+	case OverlayBottomRight:
+		return image.Point{X: right, Y: bottom}, nil
+	case OverlayCenter:
+		return baseSize.Sub(overlaySize).Div(2), nil
+	}
+	return image.Point{}, errors.New("unknown overlay anchor")
+}
+
+// toRGBA converts an image of any type to a premultiplied RGBA image with its top left corner at the origin.
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	res := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(res, res.Bounds(), img, bounds.Min, draw.Src)
+	return res
+}
+
+func copyRGBA(img *image.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		copy(res.Pix[y*res.Stride:y*res.Stride+4*size.X], img.Pix[y*img.Stride:y*img.Stride+4*size.X])
+	}
+	return res
+}
+
+// compositeRGBA composites the premultiplied src over dst at the given position with the "over" operator, the alpha
+// of src is multiplied by the opacity. The pixels of src which fall outside of dst are ignored.
+func compositeRGBA(dst, src *image.RGBA, position image.Point, opacity float64) {
+	dstSize := dst.Bounds().Size()
+	srcSize := src.Bounds().Size()
+	for y := 0; y < srcSize.Y; y++ {
+		dy := y + position.Y
+		if dy < 0 || dy >= dstSize.Y {
+			continue
+		}
+		for x := 0; x < srcSize.X; x++ {
+			dx := x + position.X
+			if dx < 0 || dx >= dstSize.X {
+				continue
+			}
+			s := src.Pix[y*src.Stride+4*x : y*src.Stride+4*x+4]
+			d := dst.Pix[dy*dst.Stride+4*dx : dy*dst.Stride+4*dx+4]
+			keep := 1 - float64(s[3])*opacity/float64(utils.MaxUint8)
+			for c := 0; c < 4; c++ {
+				v := float64(s[c])*opacity + float64(d[c])*keep
+				d[c] = uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+			}
+		}
+	}
+}
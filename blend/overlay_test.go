@@ -0,0 +1,119 @@
+package blend
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_OverlayRGBA_Anchors(t *testing.T) {
+	base := setupTestCaseOverlayBase(20, 10)
+	logo := setupTestCaseOverlayLogo(4, 3, 0xFF)
+	margin := image.Point{X: 2, Y: 1}
+	expected := map[OverlayAnchor]image.Rectangle{
+		OverlayTopLeft:     image.Rect(2, 1, 6, 4),
+		OverlayTopRight:    image.Rect(14, 1, 18, 4),
+		OverlayBottomLeft:  image.Rect(2, 6, 6, 9),
+		OverlayBottomRight: image.Rect(14, 6, 18, 9),
+		OverlayCenter:      image.Rect(8, 3, 12, 6),
+	}
+	for anchor, rect := range expected {
+		res, err := OverlayRGBA(base, logo, anchor, margin, 1)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if actual := redBounds(res); actual != rect {
+			t.Errorf("Expected the overlay at %v for anchor %d - actual: %v", rect, anchor, actual)
+		}
+	}
+	if _, err := OverlayRGBA(base, logo, OverlayAnchor(9), margin, 1); err == nil {
+		t.Errorf("Expected error for an unknown anchor")
+	}
+}
+
+func Test_OverlayRGBA_Opacity(t *testing.T) {
+	base := setupTestCaseOverlayBase(6, 6)
+	res, err := OverlayRGBA(base, setupTestCaseOverlayLogo(3, 3, 0xFF), OverlayCenter, image.Point{}, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImages(t, base, res)
+
+	// a half transparent straight red is premultiplied before the compositing: 128 + 50 * (1 - 128 / 255) = 152.9
+	res, err = OverlayRGBA(base, setupTestCaseOverlayLogo(3, 3, 0x80), OverlayTopLeft, image.Point{}, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if actual := res.RGBAAt(1, 1); actual != (color.RGBA{R: 153, G: 25, B: 25, A: 255}) {
+		t.Errorf("Expected color: %v - actual: %v", color.RGBA{R: 153, G: 25, B: 25, A: 255}, actual)
+	}
+	for _, opacity := range []float64{-0.1, 1.5} {
+		if _, err := OverlayRGBA(base, setupTestCaseOverlayLogo(3, 3, 0xFF), OverlayTopLeft, image.Point{}, opacity); err == nil {
+			t.Errorf("Expected error for opacity %f", opacity)
+		}
+	}
+}
+
+func Test_OverlayRGBAFit(t *testing.T) {
+	base := setupTestCaseOverlayBase(20, 10)
+	logo := setupTestCaseOverlayLogo(40, 10, 0xFF)
+	if _, err := OverlayRGBA(base, logo, OverlayTopLeft, image.Point{}, 1); err == nil {
+		t.Errorf("Expected error for an overlay bigger then the image")
+	}
+	res, err := OverlayRGBAFit(base, logo, OverlayBottomRight, image.Point{}, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if actual := redBounds(res); actual != image.Rect(0, 5, 20, 10) {
+		t.Errorf("Expected the downscaled overlay at %v - actual: %v", image.Rect(0, 5, 20, 10), actual)
+	}
+}
+
+func Test_TileWatermarkRGBA(t *testing.T) {
+	base := setupTestCaseOverlayBase(10, 10)
+	res, err := TileWatermarkRGBA(base, setupTestCaseOverlayLogo(2, 2, 0xFF), image.Point{X: 3, Y: 3}, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	tiled := map[int]bool{0: true, 1: true, 5: true, 6: true}
+	utils.ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if red := res.RGBAAt(x, y).R == 0xFF; red != (tiled[x] && tiled[y]) {
+			t.Errorf("Unexpected watermark pixel at: %d %d", x, y)
+		}
+	})
+	if _, err := TileWatermarkRGBA(base, setupTestCaseOverlayLogo(2, 2, 0xFF), image.Point{X: -1, Y: 0}, 1); err == nil {
+		t.Errorf("Expected error for a negative spacing")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseOverlayBase(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, color.RGBA{R: 50, G: 50, B: 50, A: 255})
+	})
+	return img
+}
+
+// setupTestCaseOverlayLogo creates a red NRGBA overlay with the given alpha.
+func setupTestCaseOverlayLogo(width, height int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetNRGBA(x, y, color.NRGBA{R: 255, A: alpha})
+	})
+	return img
+}
+
+// redBounds returns the bounding box of the pure red pixels.
+func redBounds(img *image.RGBA) image.Rectangle {
+	var bounds image.Rectangle
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if img.RGBAAt(x, y) == (color.RGBA{R: 255, A: 255}) {
+			bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+		}
+	})
+	return bounds
+}
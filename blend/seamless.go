@@ -0,0 +1,142 @@
+package blend
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// PoissonOptions configures the iterative solver of SeamlessCloneRGBAWithOptions.
+type PoissonOptions struct {
+	// MaxIterations is the maximum number of successive over-relaxation sweeps.
+	MaxIterations int
+	// Tolerance stops the solver when no pixel changed more then Tolerance (in intensity levels) during a sweep.
+	Tolerance float64
+}
+
+// DefaultPoissonOptions returns the solver options used by SeamlessCloneRGBA.
+func DefaultPoissonOptions() PoissonOptions {
+	return PoissonOptions{MaxIterations: 5000, Tolerance: 0.01}
+}
+
+// SeamlessCloneRGBA pastes the region of src where the mask is non-zero into dst at the given offset using Poisson
+// (gradient domain) blending: inside the region the result keeps the gradients of src, while on the boundary of the
+// region it matches dst, so no seam is visible. The mask has the size of src, the pixel {x, y} of src is pasted to
+// {x + offset.X, y + offset.Y} of dst and the masked pixels which fall outside of dst are ignored. Where the region
+// touches the border of dst the missing neighbours are left out of the equations, i.e. the border behaves as if the
+// gradient across it were 0. The alpha channel of dst is kept. Returns an error if the sizes of src and mask do not
+// match.
+// Example of usage:
+//
+//	res, err := blend.SeamlessCloneRGBA(photo, patch, mask, image.Point{X: 120, Y: 80})
+func SeamlessCloneRGBA(dst, src *image.RGBA, mask *image.Gray, offset image.Point) (*image.RGBA, error) {
+	return SeamlessCloneRGBAWithOptions(dst, src, mask, offset, DefaultPoissonOptions())
+}
+
+// SeamlessCloneRGBAWithOptions works like SeamlessCloneRGBA, but the number of iterations and the tolerance of the
+// solver can be chosen. Returns an error if MaxIterations is smaller then 1 or Tolerance is negative.
+// Example of usage:
+//
+//	res, err := blend.SeamlessCloneRGBAWithOptions(photo, patch, mask, offset, blend.PoissonOptions{MaxIterations: 500, Tolerance: 0.1})
+func SeamlessCloneRGBAWithOptions(dst, src *image.RGBA, mask *image.Gray, offset image.Point, options PoissonOptions) (*image.RGBA, error) {
+	srcSize := src.Bounds().Size()
+	if mask.Bounds().Size() != srcSize {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	if options.MaxIterations < 1 || options.Tolerance < 0 {
+		return nil, errors.New("invalid solver options")
+	}
+	dstSize := dst.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, dstSize.X, dstSize.Y))
+	for y := 0; y < dstSize.Y; y++ {
+		copy(res.Pix[y*res.Stride:y*res.Stride+4*dstSize.X], dst.Pix[y*dst.Stride:y*dst.Stride+4*dstSize.X])
+	}
+
+	// index of every unknown pixel of the region, in dst coordinates
+	unknown := make(map[image.Point]int)
+	var region []image.Point
+	for y := 0; y < srcSize.Y; y++ {
+		for x := 0; x < srcSize.X; x++ {
+			p := image.Point{X: x + offset.X, Y: y + offset.Y}
+			if mask.Pix[y*mask.Stride+x] == 0 || p.X < 0 || p.Y < 0 || p.X >= dstSize.X || p.Y >= dstSize.Y {
+				continue
+			}
+			unknown[p] = len(region)
+			region = append(region, p)
+		}
+	}
+	if len(region) == 0 {
+		return res, nil
+	}
+
+	srcAt := func(p image.Point, c int) float64 {
+		return float64(src.Pix[(p.Y-offset.Y)*src.Stride+4*(p.X-offset.X)+c])
+	}
+	inSrc := func(p image.Point) bool {
+		return p.X-offset.X >= 0 && p.Y-offset.Y >= 0 && p.X-offset.X < srcSize.X && p.Y-offset.Y < srcSize.Y
+	}
+	// every equation is: count * f(p) - sum of f(unknown neighbours) = guidance + sum of dst(known neighbours)
+	equations := make([]poissonEquation, len(region))
+	values := make([][3]float64, len(region))
+	for i, p := range region {
+		eq := &equations[i]
+		for _, d := range []image.Point{{X: -1}, {X: 1}, {Y: -1}, {Y: 1}} {
+			q := p.Add(d)
+			if q.X < 0 || q.Y < 0 || q.X >= dstSize.X || q.Y >= dstSize.Y || !inSrc(q) {
+				continue
+			}
+			eq.count++
+			for c := 0; c < 3; c++ {
+				eq.rhs[c] += srcAt(p, c) - srcAt(q, c)
+			}
+			if j, ok := unknown[q]; ok {
+				eq.neighbours = append(eq.neighbours, j)
+			} else {
+				for c := 0; c < 3; c++ {
+					eq.rhs[c] += float64(res.Pix[q.Y*res.Stride+4*q.X+c])
+				}
+			}
+		}
+		for c := 0; c < 3; c++ {
+			values[i][c] = srcAt(p, c)
+		}
+	}
+
+	// successive over-relaxation
+	const omega = 1.9
+	for it := 0; it < options.MaxIterations; it++ {
+		maxChange := 0.0
+		for i, eq := range equations {
+			if eq.count == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				sum := eq.rhs[c]
+				for _, j := range eq.neighbours {
+					sum += values[j][c]
+				}
+				change := omega * (sum/float64(eq.count) - values[i][c])
+				values[i][c] += change
+				maxChange = math.Max(maxChange, math.Abs(change))
+			}
+		}
+		if maxChange <= options.Tolerance {
+			break
+		}
+	}
+
+	for i, p := range region {
+		for c := 0; c < 3; c++ {
+			res.Pix[p.Y*res.Stride+4*p.X+c] = uint8(utils.ClampF64(math.Round(values[i][c]), utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+type poissonEquation struct {
+	count      int
+	neighbours []int
+	rhs        [3]float64
+}
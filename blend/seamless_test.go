@@ -0,0 +1,112 @@
+package blend
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_SeamlessCloneRGBA(t *testing.T) {
+	dst, src, mask := setupTestCaseSeamless()
+	offset := image.Point{X: 20, Y: 12}
+	res, err := SeamlessCloneRGBA(dst, src, mask, offset)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	inRegion := func(x, y int) bool {
+		sx, sy := x-offset.X, y-offset.Y
+		return sx >= 0 && sy >= 0 && sx < 20 && sy < 16 && mask.GrayAt(sx, sy).Y != 0
+	}
+	steps, totalExcess := 0, 0.0
+	for x := 1; x < 60; x++ {
+		for y := 1; y < 40; y++ {
+			for _, q := range []image.Point{{X: x - 1, Y: y}, {X: x, Y: y - 1}} {
+				if inRegion(x, y) == inRegion(q.X, q.Y) {
+					continue
+				}
+				steps++
+				// allow the local gradient of the source and the destination, a pasted patch would give a step of ~170
+				for c := 0; c < 3; c++ {
+					d := math.Abs(float64(res.Pix[y*res.Stride+4*x+c]) - float64(res.Pix[q.Y*res.Stride+4*q.X+c]))
+					dd := math.Abs(float64(dst.Pix[y*dst.Stride+4*x+c]) - float64(dst.Pix[q.Y*dst.Stride+4*q.X+c]))
+					ds := math.Abs(float64(src.Pix[(y-offset.Y)*src.Stride+4*(x-offset.X)+c]) - float64(src.Pix[(q.Y-offset.Y)*src.Stride+4*(q.X-offset.X)+c]))
+					excess := math.Max(d-math.Max(dd, ds), 0)
+					totalExcess += excess
+					if excess > 3 {
+						t.Fatalf("Expected no intensity step across the mask boundary - actual: %f at: %d %d", d, x, y)
+					}
+				}
+			}
+		}
+	}
+	if steps == 0 {
+		t.Fatal("Expected the region to have a boundary")
+	}
+	if mean := totalExcess / float64(3*steps); mean > 1 {
+		t.Errorf("Expected a mean boundary discontinuity below 1 - actual: %f", mean)
+	}
+	// the texture of the source is kept inside of the region
+	a, b := res.RGBAAt(30, 20), res.RGBAAt(31, 20)
+	sa, sb := src.RGBAAt(10, 8), src.RGBAAt(11, 8)
+	if math.Abs(float64(int(b.R)-int(a.R))-float64(int(sb.R)-int(sa.R))) > 3 {
+		t.Errorf("Expected the source gradient to be kept - actual: %d %d, source: %d %d", a.R, b.R, sa.R, sb.R)
+	}
+	// the pixels outside of the region are not changed
+	if res.RGBAAt(5, 5) != dst.RGBAAt(5, 5) || res.RGBAAt(50, 35) != dst.RGBAAt(50, 35) {
+		t.Error("Expected the pixels outside of the region to be kept")
+	}
+}
+
+func Test_SeamlessCloneRGBA_Border(t *testing.T) {
+	dst, src, mask := setupTestCaseSeamless()
+	// the patch hangs over the top left corner of the destination
+	res, err := SeamlessCloneRGBAWithOptions(dst, src, mask, image.Point{X: -6, Y: -5}, PoissonOptions{MaxIterations: 2000, Tolerance: 0.05})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the region is anchored by its boundary with the destination on the right and the bottom
+	if d := math.Abs(float64(res.RGBAAt(13, 5).R) - float64(res.RGBAAt(14, 5).R)); d > 4 {
+		t.Errorf("Expected no intensity step across the mask boundary - actual: %f", d)
+	}
+	if res.RGBAAt(0, 0).A != 0xFF {
+		t.Errorf("Expected the alpha of the destination to be kept - actual: %d", res.RGBAAt(0, 0).A)
+	}
+}
+
+func Test_SeamlessCloneRGBA_Invalid(t *testing.T) {
+	dst, src, _ := setupTestCaseSeamless()
+	if _, err := SeamlessCloneRGBA(dst, src, image.NewGray(image.Rect(0, 0, 3, 3)), image.Point{}); err == nil {
+		t.Error("Expected error for mismatching mask size")
+	}
+	mask := image.NewGray(src.Bounds())
+	if _, err := SeamlessCloneRGBAWithOptions(dst, src, mask, image.Point{}, PoissonOptions{}); err == nil {
+		t.Error("Expected error for invalid solver options")
+	}
+}
+
+// -------------------------------------------------------------------------------
+// setupTestCaseSeamless returns a 60x40 dark horizontal gradient, a bright textured 20x16 patch and a mask which
+// selects the patch without its 2 pixel wide frame.
+func setupTestCaseSeamless() (*image.RGBA, *image.RGBA, *image.Gray) {
+	dst := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	for x := 0; x < 60; x++ {
+		for y := 0; y < 40; y++ {
+			v := uint8(30 + x)
+			dst.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v / 2, A: 0xFF})
+		}
+	}
+	src := image.NewRGBA(image.Rect(0, 0, 20, 16))
+	mask := image.NewGray(src.Bounds())
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 16; y++ {
+			v := uint8(200 + 8*math.Sin(float64(x)/3))
+			src.SetRGBA(x, y, color.RGBA{R: v, G: 220, B: v - 30, A: 0xFF})
+			if x >= 2 && x < 18 && y >= 2 && y < 14 {
+				mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	return dst, src, mask
+}
@@ -4,49 +4,255 @@ import (
 	"errors"
 	"github.com/yafeiliu/imger/convolution"
 	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"math"
 )
 
 // BoxGray applies average blur to a grayscale image. The amount of bluring effect depends on the kernel size, where
 // both width and height can be specified. The anchor point specifies a point inside the kernel. The pixel value
 // will be updated after the convolution was done for the given area.
-// Border types supported: see convolution package.
-func BoxGray(img *image.Gray, kernelSize image.Point, anchor image.Point, border padding.Border) (*image.Gray, float64, error) {
+// Border types supported: see convolution package. With utils.WithPool the intermediate images are taken from the pool.
+func BoxGray(img *image.Gray, kernelSize image.Point, anchor image.Point, border padding.Border, opts ...utils.Option) (*image.Gray, float64, error) {
 	kernel := generateBoxKernel(&kernelSize)
-	return convolution.ConvolveGray(img, kernel.Normalize(), anchor, border)
+	return convolution.ConvolveGray(img, kernel.Normalize(), anchor, border, opts...)
+}
+
+// BoxBlurGrayAnchored applies average blur to a grayscale image the same way as BoxGray, but the anchor is checked
+// before the image is padded. The anchor is interpreted the same way as in the convolution and padding packages: it is
+// a point inside the kernel which gets updated after every convolution step. An off-center anchor produces asymmetric
+// (e.g. causal) smoothing. Returns an error if the anchor is outside of the kernel.
+// Example of usage:
+//
+//	res, _, err := blur.BoxBlurGrayAnchored(img, image.Point{X: 5, Y: 1}, image.Point{X: 4, Y: 0}, padding.BorderReplicate)
+func BoxBlurGrayAnchored(img *image.Gray, kernelSize image.Point, anchor image.Point, border padding.Border) (*image.Gray, float64, error) {
+	if err := validateAnchor(kernelSize, anchor); err != nil {
+		return nil, 0, err
+	}
+	return BoxGray(img, kernelSize, anchor, border)
 }
 
 // BoxRGBA applies average blur to an RGBA image. The amount of bluring effect depends on the kernel size, where
 // both width and height can be specified. The anchor point specifies a point inside the kernel. The pixel value
 // will be updated after the convolution was done for the given area.
-// Border types supported: see convolution package.
-func BoxRGBA(img *image.RGBA, kernelSize image.Point, anchor image.Point, border padding.Border) (*image.RGBA, error) {
+// Border types supported: see convolution package. With utils.WithPool the intermediate images are taken from the pool.
+func BoxRGBA(img *image.RGBA, kernelSize image.Point, anchor image.Point, border padding.Border, opts ...utils.Option) (*image.RGBA, error) {
 	kernel := generateBoxKernel(&kernelSize)
-	return convolution.ConvolveRGBA(img, kernel.Normalize(), anchor, border)
+	return convolution.ConvolveRGBA(img, kernel.Normalize(), anchor, border, opts...)
 }
 
 // GaussianBlurGray applies average blur to a grayscale image. The amount of bluring effect depends on the kernel radius
 // and sigma value. The anchor point specifies a point inside the kernel. The pixel value  will be updated after the
-// convolution was done for the given area. For border types see convolution package.
-func GaussianBlurGray(img *image.Gray, radius float64, sigma float64, border padding.Border) (*image.Gray, float64, error) {
+// convolution was done for the given area. For border types see convolution package. With utils.WithPool the
+// intermediate images are taken from the pool.
+func GaussianBlurGray(img *image.Gray, radius float64, sigma float64, border padding.Border, opts ...utils.Option) (*image.Gray, float64, error) {
 	if radius <= 0 {
 		return nil, 0, errors.New("radius must be bigger then 0")
 	}
-	return convolution.ConvolveGray(img, generateGaussianKernel(radius, sigma).Normalize(), image.Point{X: int(math.Ceil(radius)), Y: int(math.Ceil(radius))}, border)
+	return convolution.ConvolveGray(img, generateGaussianKernel(radius, sigma).Normalize(), image.Point{X: int(math.Ceil(radius)), Y: int(math.Ceil(radius))}, border, opts...)
+}
+
+// GaussianBlurGrayROI applies Gaussian blur only to the given region of interest of a grayscale image, e.g. to blur a
+// detected face. The result is a copy of the input image where only the pixels inside of the ROI are blurred. The
+// neighbourhood of the ROI is read from the real pixels of the image and the border type is used only at the edges of
+// the image, see convolution.ConvolveGrayROI. Returns an error if the radius is not positive or the ROI is invalid.
+// Example of usage:
+//
+//	res, err := blur.GaussianBlurGrayROI(img, image.Rect(40, 30, 120, 110), 5, 2, padding.BorderReflect)
+func GaussianBlurGrayROI(img *image.Gray, roi image.Rectangle, radius float64, sigma float64, border padding.Border) (*image.Gray, error) {
+	if radius <= 0 {
+		return nil, errors.New("radius must be bigger then 0")
+	}
+	anchor := image.Point{X: int(math.Ceil(radius)), Y: int(math.Ceil(radius))}
+	return convolution.ConvolveGrayROI(img, generateGaussianKernel(radius, sigma).Normalize(), anchor, border, roi)
+}
+
+// BoxGrayROI applies average blur only to the given region of interest of a grayscale image, the other pixels of the
+// returned copy are left untouched. See BoxGray and convolution.ConvolveGrayROI.
+// Example of usage:
+//
+//	res, err := blur.BoxGrayROI(img, roi, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, padding.BorderReflect)
+func BoxGrayROI(img *image.Gray, roi image.Rectangle, kernelSize image.Point, anchor image.Point, border padding.Border) (*image.Gray, error) {
+	kernel := generateBoxKernel(&kernelSize)
+	return convolution.ConvolveGrayROI(img, kernel.Normalize(), anchor, border, roi)
+}
+
+// GaussianBlurGrayAnchored applies Gaussian blur to a grayscale image the same way as GaussianBlurGray, but the anchor
+// point of the kernel can be specified. The anchor is interpreted the same way as in the convolution and padding
+// packages: it is a point inside the (2 * ceil(radius) + 1) sized kernel which gets updated after every convolution
+// step. An off-center anchor produces asymmetric (e.g. causal) smoothing.
+// Example of usage:
+//
+//	res, _, err := blur.GaussianBlurGrayAnchored(img, 2, 1, image.Point{X: 4, Y: 2}, padding.BorderReflect)
+func GaussianBlurGrayAnchored(img *image.Gray, radius float64, sigma float64, anchor image.Point, border padding.Border) (*image.Gray, float64, error) {
+	if radius <= 0 {
+		return nil, 0, errors.New("radius must be bigger then 0")
+	}
+	kernel := generateGaussianKernel(radius, sigma)
+	if err := validateAnchor(kernel.Size(), anchor); err != nil {
+		return nil, 0, err
+	}
+	return convolution.ConvolveGray(img, kernel.Normalize(), anchor, border)
+}
+
+// GaussianBlurRGBAAnchored applies Gaussian blur to an RGBA image the same way as GaussianBlurRGBA, but the anchor
+// point of the kernel can be specified. The anchor is interpreted the same way as in the convolution and padding
+// packages: it is a point inside the (2 * ceil(radius) + 1) sized kernel which gets updated after every convolution
+// step. An off-center anchor produces asymmetric (e.g. causal) smoothing.
+func GaussianBlurRGBAAnchored(img *image.RGBA, radius float64, sigma float64, anchor image.Point, border padding.Border) (*image.RGBA, error) {
+	if radius <= 0 {
+		return nil, errors.New("radius must be bigger then 0")
+	}
+	kernel := generateGaussianKernel(radius, sigma)
+	if err := validateAnchor(kernel.Size(), anchor); err != nil {
+		return nil, err
+	}
+	return convolution.ConvolveRGBA(img, kernel.Normalize(), anchor, border)
 }
 
 // GaussianBlurRGBA applies average blur to an RGBA image. The amount of bluring effect depends on the kernel radius
 // and sigma value. The anchor point specifies a point inside the kernel. The pixel value  will be updated after the
-// convolution was done for the given area. For border types see convolution package.
-func GaussianBlurRGBA(img *image.RGBA, radius float64, sigma float64, border padding.Border) (*image.RGBA, error) {
+// convolution was done for the given area. For border types see convolution package. With utils.WithPool the
+// intermediate images are taken from the pool.
+func GaussianBlurRGBA(img *image.RGBA, radius float64, sigma float64, border padding.Border, opts ...utils.Option) (*image.RGBA, error) {
 	if radius <= 0 {
 		return nil, errors.New("radius must be bigger then 0")
 	}
-	return convolution.ConvolveRGBA(img, generateGaussianKernel(radius, sigma).Normalize(), image.Point{X: int(math.Ceil(radius)), Y: int(math.Ceil(radius))}, border)
+	return convolution.ConvolveRGBA(img, generateGaussianKernel(radius, sigma).Normalize(), image.Point{X: int(math.Ceil(radius)), Y: int(math.Ceil(radius))}, border, opts...)
+}
+
+// GaussianBlurRGBALinear applies Gaussian blur to an RGBA image in linear light. The sRGB encoded color channels are
+// converted to linear light, blurred and converted back to sRGB, which gives physically correct averaging at high
+// contrast edges. The alpha channel is left unchanged. The kernel size has to be a positive odd number, the border of
+// the image is handled as in BorderReflect.
+// Example of usage:
+//
+//	res, err := blur.GaussianBlurRGBALinear(img, 5, 1.5)
+func GaussianBlurRGBALinear(img *image.RGBA, ksize int, sigma float64) (*image.RGBA, error) {
+	if ksize <= 0 || ksize%2 == 0 {
+		return nil, errors.New("kernel size must be a positive odd number")
+	}
+	if sigma <= 0 {
+		return nil, errors.New("sigma must be bigger then 0")
+	}
+	size := img.Bounds().Size()
+	kernel := GaussianKernel1D(float64((ksize-1)/2), sigma)
+	res := image.NewRGBA(img.Bounds())
+	var planes [3][]float64
+	for c := range planes {
+		planes[c] = make([]float64, size.X*size.Y)
+	}
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			pixel := img.RGBAAt(x, y)
+			planes[0][y*size.X+x] = utils.SRGBToLinear(pixel.R)
+			planes[1][y*size.X+x] = utils.SRGBToLinear(pixel.G)
+			planes[2][y*size.X+x] = utils.SRGBToLinear(pixel.B)
+		}
+	}
+	for c := range planes {
+		planes[c], _ = SeparableBlurPlane(planes[c], size, kernel, kernel, padding.BorderReflect)
+	}
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		i := y*size.X + x
+		res.SetRGBA(x, y, color.RGBA{
+			R: utils.LinearToSRGB(planes[0][i]),
+			G: utils.LinearToSRGB(planes[1][i]),
+			B: utils.LinearToSRGB(planes[2][i]),
+			A: img.RGBAAt(x, y).A,
+		})
+	})
+	return res, nil
+}
+
+// GaussianKernel1D generates a normalized 1D Gaussian kernel of length 2 * ceil(radius) + 1, a non-integer radius is
+// rounded up, so the length is always odd and the kernel is centered. For whole radii the kernel has the same sampling
+// positions as the kernel used by GaussianBlurGray and GaussianBlurRGBA. The kernel can be computed once and passed to
+// GaussianBlurGrayWithKernel when many images are blurred with the same parameters.
+// Example of usage:
+//
+//	kernel := blur.GaussianKernel1D(2, 1)
+func GaussianKernel1D(radius float64, sigma float64) []float64 {
+	r := int(math.Ceil(radius))
+	kernel := make([]float64, 2*r+1)
+	var sum float64
+	for i := range kernel {
+		d := float64(i - r)
+		kernel[i] = math.Exp(-(d * d) / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
+
+// GaussianBlurGrayWithKernel applies Gaussian blur to a grayscale image using a precomputed, normalized 1D kernel
+// (see GaussianKernel1D). The kernel is applied separably, horizontally and then vertically. Compared to
+// GaussianBlurGray with the corresponding whole radius and sigma every pixel differs by at most one, because both
+// functions truncate the weighted sums and the different summation order can put a sum on either side of an integer,
+// e.g. on constant areas. The length of the kernel has to be odd. For border types see convolution package. With
+// utils.WithPool the padded copy of the image is taken from the pool.
+// Example of usage:
+//
+//	kernel := blur.GaussianKernel1D(2, 1)
+//	res, err := blur.GaussianBlurGrayWithKernel(img, kernel, padding.BorderReflect)
+func GaussianBlurGrayWithKernel(img *image.Gray, kernel1D []float64, border padding.Border, opts ...utils.Option) (*image.Gray, error) {
+	if len(kernel1D)%2 == 0 {
+		return nil, errors.New("kernel length must be an odd number")
+	}
+	return convolveSeparableGray(img, kernel1D, kernel1D, border, utils.ApplyOptions(opts).Pool)
+}
+
+// GaussianBlurGrayXY applies an anisotropic Gaussian blur to a grayscale image: separable 1D Gaussian kernels with
+// independent sizes and sigmas are applied along the x and the y axis, e.g. to smooth a horizontal motion. An axis is
+// not blurred if its kernel size is smaller then 2 or its sigma is not positive. Even kernel sizes are increased by
+// one. For border types see convolution package. Returns an error if the border type is unknown.
+// Example of usage:
+//
+//	res, err := blur.GaussianBlurGrayXY(img, 15, 3, 4, 0.8, padding.BorderReflect)
+func GaussianBlurGrayXY(img *image.Gray, ksizeX, ksizeY int, sigmaX, sigmaY float64, border padding.Border) (*image.Gray, error) {
+	return convolveSeparableGray(img, axisKernel(ksizeX, sigmaX), axisKernel(ksizeY, sigmaY), border, nil)
+}
+
+// SeparableBlurPlane convolves a row-major plane of float values of the given size with the 1D kernel kernelX
+// horizontally and then with kernelY vertically, e.g. with two kernels of GaussianKernel1D. The kernels are centered on
+// the pixels and a nil kernel skips its pass. The values outside of the plane are taken according to the border type
+// (BorderConstant treats them as 0) and the result is not clamped. The result is a new slice. Returns an error if the
+// length of the plane does not match the size or the border type is unknown.
+// Example of usage:
+//
+//	res, err := blur.SeparableBlurPlane(field, image.Point{X: 640, Y: 480}, kernel, kernel, padding.BorderReflect)
+func SeparableBlurPlane(plane []float64, size image.Point, kernelX, kernelY []float64, border padding.Border) ([]float64, error) {
+	if size.X < 0 || size.Y < 0 || len(plane) != size.X*size.Y {
+		return nil, errors.New("the length of the plane does not match the size")
+	}
+	if border != padding.BorderConstant && border != padding.BorderReplicate && border != padding.BorderReflect {
+		return nil, errors.New("unknown border type")
+	}
+	res := append([]float64{}, plane...)
+	if kernelX != nil {
+		res = separablePass(res, size, kernelX, false, border)
+	}
+	if kernelY != nil {
+		res = separablePass(res, size, kernelY, true, border)
+	}
+	return res, nil
 }
 
 // -------------------------------------------------------------------------------------------------------
+// validateAnchor returns an error if the anchor is not a point of a kernel of the given size.
+func validateAnchor(kernelSize image.Point, anchor image.Point) error {
+	if kernelSize.X <= 0 || kernelSize.Y <= 0 {
+		return errors.New("kernel size must be positive")
+	}
+	if !anchor.In(image.Rectangle{Max: kernelSize}) {
+		return errors.New("anchor value outside of the kernel")
+	}
+	return nil
+}
+
 func generateBoxKernel(kernelSize *image.Point) *convolution.Kernel {
 	kernel, _ := convolution.NewKernel(kernelSize.X, kernelSize.Y)
 	for x := 0; x < kernelSize.X; x++ {
@@ -72,3 +278,71 @@ func gaussianFunc(x, y, sigma float64) float64 {
 	sigSqr := sigma * sigma
 	return (1.0 / (2 * math.Pi * sigSqr)) * math.Exp(-(x*x+y*y)/(2*sigSqr))
 }
+
+// convolveSeparableGray convolves a grayscale image with the 1D kernelX horizontally and then with the 1D kernelY
+// vertically. The lengths of the kernels have to be odd, their centers are the anchors. The padded copy of the image
+// is taken from the pool, which may be nil.
+func convolveSeparableGray(img *image.Gray, kernelX, kernelY []float64, border padding.Border, pool *utils.BufferPool) (*image.Gray, error) {
+	radius := image.Point{X: len(kernelX) / 2, Y: len(kernelY) / 2}
+	kernelSize := image.Point{X: len(kernelX), Y: len(kernelY)}
+	padded, err := padding.PaddingGray(img, kernelSize, radius, border, utils.WithPool(pool))
+	if err != nil {
+		return nil, err
+	}
+	defer pool.PutGray(padded)
+	size := img.Bounds().Size()
+	paddedSize := padded.Bounds().Size()
+	tmp := make([]float64, size.X*paddedSize.Y)
+	for y := 0; y < paddedSize.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			var sum float64
+			for k, w := range kernelX {
+				sum += float64(padded.GrayAt(x+k, y).Y) * w
+			}
+			tmp[y*size.X+x] = sum
+		}
+	}
+	res := image.NewGray(img.Bounds())
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum float64
+		for k, w := range kernelY {
+			sum += tmp[(y+k)*size.X+x] * w
+		}
+		res.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(sum, utils.MinUint8, float64(utils.MaxUint8)))})
+	})
+	return res, nil
+}
+
+// axisKernel returns the 1D Gaussian kernel used by GaussianBlurGrayXY for one axis, {1} if the axis is not blurred.
+func axisKernel(ksize int, sigma float64) []float64 {
+	if ksize < 2 || sigma <= 0 {
+		return []float64{1}
+	}
+	return GaussianKernel1D(float64(ksize/2), sigma)
+}
+
+// separablePass convolves a row-major plane with a centered 1D kernel along the x or, if vertical is set, the y axis.
+func separablePass(plane []float64, size image.Point, kernel []float64, vertical bool, border padding.Border) []float64 {
+	radius := len(kernel) / 2
+	res := make([]float64, len(plane))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum float64
+		for k, w := range kernel {
+			if vertical {
+				if sy, ok := padding.BorderIndex(y+k-radius, size.Y, border); ok {
+					sum += plane[sy*size.X+x] * w
+				}
+			} else if sx, ok := padding.BorderIndex(x+k-radius, size.X, border); ok {
+				sum += plane[y*size.X+sx] * w
+			}
+		}
+		res[y*size.X+x] = sum
+	})
+	return res
+}
+
+// reflectIndex maps an index outside of [0, n) back inside the interval as in BorderReflect (cbabcdefgfed).
+func reflectIndex(i int, n int) int {
+	i, _ = padding.BorderIndex(i, n, padding.BorderReflect)
+	return i
+}
@@ -1,10 +1,14 @@
 package blur
 
 import (
+	"github.com/yafeiliu/imger/convolution"
 	"github.com/yafeiliu/imger/imgio"
 	"github.com/yafeiliu/imger/padding"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -46,7 +50,7 @@ func TestGrayGaussianBlurOneRadius(t *testing.T) {
 			0x3B, 0x59, 0x36,
 		},
 	}
-	result, err := GaussianBlurGray(&input, 1, 2, padding.BorderConstant)
+	result, _, err := GaussianBlurGray(&input, 1, 2, padding.BorderConstant)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,6 +84,367 @@ func TestRGBAGaussianBlurOneRadius(t *testing.T) {
 	utils.CompareRGBAImagesWithOffset(t, expected, actual, 1)
 }
 
+func setupImpulseGray() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 9, 9))
+	img.SetGray(4, 4, color.Gray{Y: 0xFF})
+	return img
+}
+
+func TestGrayBoxBlurOffCenterAnchor(t *testing.T) {
+	img := setupImpulseGray()
+	centered, _, err := BoxGray(img, image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, padding.BorderConstant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchored, _, err := BoxGray(img, image.Point{X: 3, Y: 3}, image.Point{X: 0, Y: 0}, padding.BorderConstant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// centered anchor: the impulse spreads symmetrically to [3, 5]
+	if centered.GrayAt(3, 4).Y == 0 || centered.GrayAt(5, 4).Y == 0 || centered.GrayAt(3, 4).Y != centered.GrayAt(5, 4).Y {
+		t.Errorf("Expected symmetric blur around the impulse")
+	}
+	// anchor in the top left corner: the impulse spreads only to the left and up, to [2, 4]
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			inside := x >= 2 && x <= 4 && y >= 2 && y <= 4
+			if inside && anchored.GrayAt(x, y).Y == 0 {
+				t.Errorf("Expected blurred value at: %d %d", x, y)
+			}
+			if !inside && anchored.GrayAt(x, y).Y != 0 {
+				t.Errorf("Expected 0 - actual: %d at: %d %d", anchored.GrayAt(x, y).Y, x, y)
+			}
+		}
+	}
+}
+
+func TestGrayGaussianBlurOffCenterAnchor(t *testing.T) {
+	img := setupImpulseGray()
+	centered, _, err := GaussianBlurGrayAnchored(img, 1, 1, image.Point{X: 1, Y: 1}, padding.BorderConstant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _, _ := GaussianBlurGray(img, 1, 1, padding.BorderConstant)
+	utils.CompareGrayImages(t, expected, centered)
+	anchored, _, err := GaussianBlurGrayAnchored(img, 1, 1, image.Point{X: 2, Y: 1}, padding.BorderConstant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// anchor on the right side of the kernel: the impulse is shifted to the right
+	if anchored.GrayAt(3, 4).Y != 0 || anchored.GrayAt(6, 4).Y == 0 || anchored.GrayAt(5, 4).Y <= anchored.GrayAt(4, 4).Y {
+		t.Errorf("Expected the blur to be shifted to the right")
+	}
+	for _, anchor := range []image.Point{{X: 3, Y: 1}, {X: -1, Y: 1}, {X: 1, Y: -1}} {
+		if _, _, err := GaussianBlurGrayAnchored(img, 1, 1, anchor, padding.BorderConstant); err == nil {
+			t.Errorf("Expected error for anchor %v outside of the kernel", anchor)
+		}
+		if _, err := GaussianBlurRGBAAnchored(image.NewRGBA(img.Bounds()), 1, 1, anchor, padding.BorderConstant); err == nil {
+			t.Errorf("Expected error for anchor %v outside of the kernel", anchor)
+		}
+	}
+}
+
+func TestGrayBoxBlurAnchored(t *testing.T) {
+	img := setupImpulseGray()
+	kernelSize := image.Point{X: 3, Y: 1}
+	centered, _, err := BoxBlurGrayAnchored(img, kernelSize, image.Point{X: 1, Y: 0}, padding.BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected, _, _ := BoxGray(img, kernelSize, image.Point{X: 1, Y: 0}, padding.BorderConstant)
+	utils.CompareGrayImages(t, expected, centered)
+	// anchor on the left side of the kernel: every pixel averages itself and the pixels on its right, so the impulse
+	// spreads only to the left
+	anchored, _, err := BoxBlurGrayAnchored(img, kernelSize, image.Point{X: 0, Y: 0}, padding.BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if anchored.GrayAt(2, 4).Y == 0 || anchored.GrayAt(3, 4).Y == 0 || anchored.GrayAt(5, 4).Y != 0 {
+		t.Errorf("Expected the blur to spread only to the left - actual: %v", anchored.Pix[4*anchored.Stride:5*anchored.Stride])
+	}
+	if centered.GrayAt(5, 4).Y == 0 || centered.GrayAt(2, 4).Y != 0 {
+		t.Errorf("Expected the centered blur to spread to both sides - actual: %v", centered.Pix[4*centered.Stride:5*centered.Stride])
+	}
+	for _, anchor := range []image.Point{{X: 3, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 0, Y: -1}} {
+		if _, _, err := BoxBlurGrayAnchored(img, kernelSize, anchor, padding.BorderConstant); err == nil {
+			t.Errorf("Expected error for anchor %v outside of the kernel", anchor)
+		}
+	}
+}
+
+func TestRGBAGaussianBlurLinearCheckerboard(t *testing.T) {
+	size := 16
+	input := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if (x+y)%2 == 0 {
+				input.SetRGBA(x, y, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+			} else {
+				input.SetRGBA(x, y, color.RGBA{A: 0xFF})
+			}
+		}
+	}
+	linear, err := GaussianBlurRGBALinear(input, 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gamma, err := GaussianBlurRGBA(input, 2, 2, padding.BorderReflect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var linearSum, gammaSum int
+	for x := 4; x < size-4; x++ {
+		for y := 4; y < size-4; y++ {
+			linearSum += int(linear.RGBAAt(x, y).R)
+			gammaSum += int(gamma.RGBAAt(x, y).R)
+			if linear.RGBAAt(x, y).A != 0xFF {
+				t.Errorf("Expected alpha: 255 - actual alpha: %d at: %d %d", linear.RGBAAt(x, y).A, x, y)
+			}
+		}
+	}
+	if linearSum <= gammaSum {
+		t.Errorf("Expected linear light blur to be lighter: linear sum: %d - gamma sum: %d", linearSum, gammaSum)
+	}
+}
+
+func TestRGBAGaussianBlurLinearInvalidKernelSize(t *testing.T) {
+	input := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	if _, err := GaussianBlurRGBALinear(input, 4, 1); err == nil {
+		t.Fatal("no error thrown")
+	}
+}
+
+func TestGrayGaussianBlurWithKernel(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 31, 23))
+	for i := range img.Pix {
+		img.Pix[i] = uint8((i*37 + i*i*11) % 256)
+	}
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReflect} {
+		expected, _, err := GaussianBlurGray(img, 2, 1.2, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual, err := GaussianBlurGrayWithKernel(img, GaussianKernel1D(2, 1.2), border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+	}
+	if _, err := GaussianBlurGrayWithKernel(img, []float64{0.5, 0.5}, padding.BorderReflect); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+func TestGrayGaussianBlurWithKernelTolerance(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	noise := image.NewGray(image.Rect(0, 0, 40, 30))
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(random.Intn(256))
+	}
+	// the truncated sums of constant images land on either side of the value
+	constant := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := range constant.Pix {
+		constant.Pix[i] = 8
+	}
+	for _, img := range []*image.Gray{noise, constant} {
+		for _, params := range [][2]float64{{1, 0.8}, {2, 1.2}, {3, 2}, {5, 3}} {
+			expected, _, err := GaussianBlurGray(img, params[0], params[1], padding.BorderReflect)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			actual, err := GaussianBlurGrayWithKernel(img, GaussianKernel1D(params[0], params[1]), padding.BorderReflect)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			for i := range expected.Pix {
+				if d := int(expected.Pix[i]) - int(actual.Pix[i]); d < -1 || d > 1 {
+					t.Errorf("Expected a difference of at most 1 - expected: %d, actual: %d at index %d with radius %v and sigma %v", expected.Pix[i], actual.Pix[i], i, params[0], params[1])
+				}
+			}
+		}
+	}
+}
+
+func TestGaussianKernel1DNonIntegerRadius(t *testing.T) {
+	kernel := GaussianKernel1D(1.5, 1)
+	if len(kernel) != 5 {
+		t.Fatalf("Expected a kernel of length 5 - actual: %d", len(kernel))
+	}
+	var sum float64
+	for i, v := range kernel {
+		sum += v
+		if !utils.IsEqualFloat64(v, kernel[len(kernel)-1-i]) {
+			t.Errorf("Expected a symmetric kernel - actual: %v", kernel)
+		}
+	}
+	if !utils.IsEqualFloat64(sum, 1) {
+		t.Errorf("Expected the kernel to sum to 1 - actual: %f", sum)
+	}
+	if _, err := GaussianBlurGrayWithKernel(image.NewGray(image.Rect(0, 0, 8, 8)), kernel, padding.BorderReflect); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+}
+
+func TestSeparableBlurPlane(t *testing.T) {
+	size := image.Point{X: 9, Y: 6}
+	plane := make([]float64, size.X*size.Y)
+	for i := range plane {
+		plane[i] = float64((i * 37) % 23)
+	}
+	kernelX, kernelY := GaussianKernel1D(2, 1), []float64{0.25, 0.5, 0.25}
+	kernel2D := make([][]float64, len(kernelX))
+	for x := range kernel2D {
+		kernel2D[x] = make([]float64, len(kernelY))
+		for y := range kernelY {
+			kernel2D[x][y] = kernelX[x] * kernelY[y]
+		}
+	}
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		actual, err := SeparableBlurPlane(plane, size, kernelX, kernelY, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		expected, err := convolution.ConvolvePlane(plane, size, kernel2D, image.Point{X: 2, Y: 1}, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for i := range expected {
+			if math.Abs(expected[i]-actual[i]) > 1e-9 {
+				t.Fatalf("Expected value: %f - actual value: %f at: %d, border: %d", expected[i], actual[i], i, border)
+			}
+		}
+	}
+	copied, err := SeparableBlurPlane(plane, size, nil, nil, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	copied[0] = -1
+	if plane[0] == -1 {
+		t.Error("Expected the result to be a new slice")
+	}
+	if _, err := SeparableBlurPlane(plane, image.Point{X: 5, Y: 5}, kernelX, kernelY, padding.BorderReflect); err == nil {
+		t.Error("Expected error for a plane which does not match the size")
+	}
+	if _, err := SeparableBlurPlane(plane, size, kernelX, kernelY, padding.Border(42)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
+func TestGrayGaussianBlurROI(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	full, _, err := GaussianBlurGray(img, 2, 1.5, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	roi := image.Rect(0, 4, 12, 17)
+	res, err := GaussianBlurGrayROI(img, roi, 2, 1.5, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		expected := img.GrayAt(x, y).Y
+		if (image.Point{X: x, Y: y}).In(roi) {
+			expected = full.GrayAt(x, y).Y
+		}
+		if actual := res.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected: %d - Actual: %d at %d, %d", expected, actual, x, y)
+		}
+	})
+	if _, err := GaussianBlurGrayROI(img, image.Rect(0, 0, 0, 0), 2, 1.5, padding.BorderReflect); err == nil {
+		t.Error("Expected error for empty ROI")
+	}
+}
+
+func TestGrayBoxBlurROI(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	kernelSize, anchor := image.Point{X: 5, Y: 5}, image.Point{X: 1, Y: 3}
+	full, _, err := BoxGray(img, kernelSize, anchor, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	roi := image.Rect(6, 3, 18, 11)
+	res, err := BoxGrayROI(img, roi, kernelSize, anchor, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		expected := img.GrayAt(x, y).Y
+		if (image.Point{X: x, Y: y}).In(roi) {
+			expected = full.GrayAt(x, y).Y
+		}
+		if actual := res.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected: %d - Actual: %d at %d, %d", expected, actual, x, y)
+		}
+	})
+}
+
+func TestGrayBoxBlurROI_ThinAtEdges(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	kernelSize, anchor := image.Point{X: 5, Y: 5}, image.Point{X: 1, Y: 3}
+	size := img.Bounds().Size()
+	rois := []image.Rectangle{
+		image.Rect(5, 0, 10, 1), image.Rect(5, size.Y-1, 10, size.Y), image.Rect(0, 5, 1, 10), image.Rect(size.X-1, 5, size.X, 10),
+	}
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		full, _, err := BoxGray(img, kernelSize, anchor, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for _, roi := range rois {
+			res, err := BoxGrayROI(img, roi, kernelSize, anchor, border)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			utils.ForEachPixel(roi.Size(), func(x, y int) {
+				p := roi.Min.Add(image.Point{X: x, Y: y})
+				if expected, actual := full.GrayAt(p.X, p.Y).Y, res.GrayAt(p.X, p.Y).Y; actual != expected {
+					t.Errorf("Expected: %d - Actual: %d at %v with ROI %v and border %d", expected, actual, p, roi, border)
+				}
+			})
+		}
+	}
+}
+
+func TestGrayGaussianBlurXY(t *testing.T) {
+	vertical := image.NewGray(image.Rect(0, 0, 21, 21))
+	horizontal := image.NewGray(image.Rect(0, 0, 21, 21))
+	for i := 0; i < 21; i++ {
+		vertical.SetGray(10, i, color.Gray{Y: 0xFF})
+		horizontal.SetGray(i, 10, color.Gray{Y: 0xFF})
+	}
+	// no vertical blur: the horizontal line stays sharp, the vertical line gets blurred along x
+	sharp, err := GaussianBlurGrayXY(horizontal, 9, 9, 2, 0, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	blurred, err := GaussianBlurGrayXY(vertical, 9, 9, 2, 0, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i := 0; i < 21; i++ {
+		for y := 0; y < 21; y++ {
+			if v := sharp.GrayAt(i, y).Y; (y == 10 && v < 0xFE) || (y != 10 && v != 0) {
+				t.Fatalf("Expected the horizontal line to stay sharp - actual: %d at %d, %d", v, i, y)
+			}
+		}
+		if center, side := blurred.GrayAt(10, i).Y, blurred.GrayAt(8, i).Y; center == 0xFF || side == 0 || side >= center {
+			t.Fatalf("Expected the vertical line to be blurred along x - actual: %d, %d on row %d", center, side, i)
+		}
+		if blurred.GrayAt(10, i) != blurred.GrayAt(10, 0) {
+			t.Fatalf("Expected the vertical line to stay constant along y - actual: %d on row %d", blurred.GrayAt(10, i).Y, i)
+		}
+	}
+	// the axes are swapped when only the vertical sigma is set
+	swapped, err := GaussianBlurGrayXY(vertical, 1, 9, 0, 2, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, vertical, swapped)
+	if _, err := GaussianBlurGrayXY(vertical, 9, 9, 2, 2, padding.Border(-1)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
 // ---------------------------------------------------------------------------------
 
 // -----------------------------Acceptance tests------------------------------------
@@ -110,7 +475,7 @@ func tearDownTestCase(t *testing.T, img image.Image, path string) {
 
 func Test_Acceptance_GrayBlurInt(t *testing.T) {
 	gray := setupTestCaseGray(t)
-	blured, _ := BoxGray(gray, image.Point{X: 15, Y: 15}, image.Point{X: 8, Y: 8}, padding.BorderReflect)
+	blured, _, _ := BoxGray(gray, image.Point{X: 15, Y: 15}, image.Point{X: 8, Y: 8}, padding.BorderReflect)
 	tearDownTestCase(t, blured, "../res/blur/grayBlur.jpg")
 }
 
@@ -122,7 +487,7 @@ func Test_Acceptance_RGBABlurInt(t *testing.T) {
 
 func Test_Acceptance_GrayGaussianBlurInt(t *testing.T) {
 	gray := setupTestCaseGray(t)
-	blured, _ := GaussianBlurGray(gray, 7, 6, padding.BorderReflect)
+	blured, _, _ := GaussianBlurGray(gray, 7, 6, padding.BorderReflect)
 	tearDownTestCase(t, blured, "../res/blur/grayGaussianBlur.jpg")
 }
 
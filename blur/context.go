@@ -0,0 +1,85 @@
+package blur
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// BlurContext holds the scratch buffers of repeated blurs of images of the same size, e.g. of the frames of a video,
+// so the blurs do not allocate intermediate images on every call. A context must not be used by several goroutines at
+// the same time, every goroutine needs its own context.
+type BlurContext struct {
+	size image.Point
+	// tmp holds the result of the horizontal pass, row-major
+	tmp []float64
+	// the kernel of the last call is reused while ksize and sigma do not change
+	kernel []float64
+	ksize  int
+	sigma  float64
+}
+
+// NewBlurContext creates a BlurContext for images of width x height pixels and preallocates its scratch buffers.
+// Returns an error if the size is not positive.
+// Example of usage:
+//
+//	ctx, err := blur.NewBlurContext(1920, 1080)
+func NewBlurContext(width, height int) (*BlurContext, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("the size of the context should be positive")
+	}
+	return &BlurContext{size: image.Point{X: width, Y: height}, tmp: make([]float64, width*height)}, nil
+}
+
+// GaussianBlurGray applies Gaussian blur with a ksize x ksize kernel to src and writes the result into dst, reusing the
+// scratch buffers of the context. The result is the same as the result of GaussianBlurGrayWithKernel with
+// GaussianKernel1D(ksize / 2, sigma) and padding.BorderReflect, but the border is reflected while reading the pixels
+// instead of building a padded copy of the image. The kernel is computed again only when ksize or sigma changes.
+// Returns an error if the size of src or dst does not match the size of the context, ksize is not a positive odd
+// number or sigma is not positive.
+// Example of usage:
+//
+//	for _, frame := range frames {
+//		err := ctx.GaussianBlurGray(blurred, frame, 5, 1.2)
+//		...
+//	}
+func (c *BlurContext) GaussianBlurGray(dst, src *image.Gray, ksize int, sigma float64) error {
+	if !src.Bounds().Size().Eq(c.size) || !dst.Bounds().Size().Eq(c.size) {
+		return errors.New("the size of the image does not match the size of the context")
+	}
+	if ksize <= 0 || ksize%2 == 0 {
+		return errors.New("kernel size must be a positive odd number")
+	}
+	if sigma <= 0 {
+		return errors.New("sigma must be bigger then 0")
+	}
+	if c.kernel == nil || c.ksize != ksize || c.sigma != sigma {
+		c.kernel = GaussianKernel1D(float64(ksize/2), sigma)
+		c.ksize, c.sigma = ksize, sigma
+	}
+	kernel := c.kernel
+	radius := ksize / 2
+	size := c.size
+	tmp := c.tmp
+	utils.ParallelForEachRow(size, func(y int) {
+		row := src.Pix[src.PixOffset(src.Rect.Min.X, src.Rect.Min.Y+y):]
+		for x := 0; x < size.X; x++ {
+			var sum float64
+			for k, w := range kernel {
+				sum += float64(row[reflectIndex(x+k-radius, size.X)]) * w
+			}
+			tmp[y*size.X+x] = sum
+		}
+	})
+	utils.ParallelForEachRow(size, func(y int) {
+		row := dst.Pix[dst.PixOffset(dst.Rect.Min.X, dst.Rect.Min.Y+y):]
+		for x := 0; x < size.X; x++ {
+			var sum float64
+			for k, w := range kernel {
+				sum += tmp[reflectIndex(y+k-radius, size.Y)*size.X+x] * w
+			}
+			row[x] = uint8(utils.ClampF64(sum, utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	})
+	return nil
+}
@@ -0,0 +1,109 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_BlurContext_GaussianBlurGray(t *testing.T) {
+	ctx, err := NewBlurContext(61, 43)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	rnd := rand.New(rand.NewSource(9))
+	dst := image.NewGray(image.Rect(0, 0, 61, 43))
+	// several frames and kernels through the same context
+	for _, c := range []struct {
+		ksize int
+		sigma float64
+	}{{5, 1.2}, {5, 1.2}, {9, 2.5}, {3, 0.6}} {
+		src := image.NewGray(image.Rect(0, 0, 61, 43))
+		rnd.Read(src.Pix)
+		if err := ctx.GaussianBlurGray(dst, src, c.ksize, c.sigma); err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		expected, err := GaussianBlurGrayWithKernel(src, GaussianKernel1D(float64(c.ksize/2), c.sigma), padding.BorderReflect)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, dst)
+	}
+}
+
+func Test_BlurContext_SubImages(t *testing.T) {
+	ctx, err := NewBlurContext(20, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	src := image.NewGray(image.Rect(0, 0, 20, 10))
+	rand.New(rand.NewSource(10)).Read(src.Pix)
+	canvas := image.NewGray(image.Rect(0, 0, 30, 30))
+	// dst is a sub-image of a larger canvas and src is a sub-image with a nonzero origin
+	dst := canvas.SubImage(image.Rect(5, 12, 25, 22)).(*image.Gray)
+	frame := image.NewGray(image.Rect(0, 0, 24, 14))
+	for y := 0; y < 10; y++ {
+		copy(frame.Pix[(y+2)*frame.Stride+3:], src.Pix[y*src.Stride:y*src.Stride+20])
+	}
+	if err := ctx.GaussianBlurGray(dst, frame.SubImage(image.Rect(3, 2, 23, 12)).(*image.Gray), 5, 1); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected, _ := GaussianBlurGrayWithKernel(src, GaussianKernel1D(2, 1), padding.BorderReflect)
+	utils.CompareGrayImages(t, expected, utils.CloneGray(dst))
+	if canvas.GrayAt(4, 12).Y != 0 || canvas.GrayAt(5, 22).Y != 0 {
+		t.Error("Expected the pixels of the canvas outside of dst to be untouched")
+	}
+}
+
+func Test_BlurContext_Invalid(t *testing.T) {
+	if _, err := NewBlurContext(0, 10); err == nil {
+		t.Error("Expected error for a width of 0")
+	}
+	ctx, _ := NewBlurContext(8, 6)
+	img := image.NewGray(image.Rect(0, 0, 8, 6))
+	other := image.NewGray(image.Rect(0, 0, 6, 8))
+	if err := ctx.GaussianBlurGray(img, other, 3, 1); err == nil {
+		t.Error("Expected error for a source of a different size")
+	}
+	if err := ctx.GaussianBlurGray(other, img, 3, 1); err == nil {
+		t.Error("Expected error for a destination of a different size")
+	}
+	if err := ctx.GaussianBlurGray(img, img, 4, 1); err == nil {
+		t.Error("Expected error for an even kernel size")
+	}
+	if err := ctx.GaussianBlurGray(img, img, 3, 0); err == nil {
+		t.Error("Expected error for a sigma of 0")
+	}
+}
+
+func Benchmark_GaussianBlurGray_Stateless(b *testing.B) {
+	frame := setupTestCaseFrame()
+	kernel := GaussianKernel1D(2, 1.2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = GaussianBlurGrayWithKernel(frame, kernel, padding.BorderReflect)
+	}
+}
+
+func Benchmark_GaussianBlurGray_Context(b *testing.B) {
+	frame := setupTestCaseFrame()
+	ctx, _ := NewBlurContext(640, 480)
+	dst := image.NewGray(frame.Bounds())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctx.GaussianBlurGray(dst, frame, 5, 1.2)
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseFrame() *image.Gray {
+	frame := image.NewGray(image.Rect(0, 0, 640, 480))
+	rand.New(rand.NewSource(11)).Read(frame.Pix)
+	return frame
+}
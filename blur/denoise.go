@@ -0,0 +1,57 @@
+package blur
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// DenoiseMethod is the filter used by DenoiseAutoGray.
+type DenoiseMethod int
+
+const (
+	// DenoiseGaussian smooths the image with a Gaussian blur, the sigma of the blur grows with the estimated noise.
+	DenoiseGaussian DenoiseMethod = iota
+	// DenoiseGuided smooths the image with the self-guided filter (see GuidedFilterGray), eps is set so the variations
+	// up to twice the estimated noise are smoothed out while the stronger edges are kept.
+	DenoiseGuided
+)
+
+// MinDenoiseNoise is the estimated noise (standard deviation in gray levels) below which DenoiseAutoGray leaves the
+// image unchanged.
+const MinDenoiseNoise = 1.0
+
+// DenoiseAutoGray estimates the noise of a grayscale image (see utils.EstimateNoiseGray) and denoises it with the given
+// method, using filter parameters derived from the estimate, so the users do not have to guess them. Returns the
+// denoised image and the estimated noise. Images with an estimated noise below MinDenoiseNoise are returned unchanged.
+// Returns an error if the method is unknown.
+// Example of usage:
+//
+//	res, noise, err := blur.DenoiseAutoGray(img, blur.DenoiseGuided)
+func DenoiseAutoGray(img *image.Gray, method DenoiseMethod) (*image.Gray, float64, error) {
+	if method != DenoiseGaussian && method != DenoiseGuided {
+		return nil, 0, errors.New("unknown denoise method")
+	}
+	noise := utils.EstimateNoiseGray(img)
+	if noise < MinDenoiseNoise {
+		return utils.CloneGray(img), noise, nil
+	}
+	if method == DenoiseGuided {
+		eps := math.Pow(2*noise/float64(utils.MaxUint8), 2)
+		res, err := GuidedFilterGray(img, img, 2, eps)
+		return res, noise, err
+	}
+	sigma := autoGaussianSigma(noise)
+	ksize := 2*int(math.Ceil(3*sigma)) + 1
+	res, err := GaussianBlurGrayXY(img, ksize, ksize, sigma, sigma, padding.BorderReflect)
+	return res, noise, err
+}
+
+// -------------------------------------------------------------------------------------------------------
+// autoGaussianSigma maps the estimated noise to the sigma of the Gaussian blur, from 0.5 for barely noisy images up to
+// 3 for very noisy ones.
+func autoGaussianSigma(noise float64) float64 {
+	return utils.ClampF64(noise/10, 0.5, 3)
+}
@@ -0,0 +1,92 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func TestGrayDenoiseAuto(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	images := denoiseTestImages()
+	for _, name := range []string{"flat", "gradient", "wave"} {
+		base := images[name]
+		for _, sigma := range []float64{5, 10, 25} {
+			noisy := addGaussianNoise(base, sigma, rng)
+			if estimate := utils.EstimateNoiseGray(noisy); math.Abs(estimate-sigma) > 0.15*sigma {
+				t.Errorf("Expected noise within 15%% of %f on the %s image - actual: %f", sigma, name, estimate)
+			}
+			before := meanSquaredError(base, noisy)
+			for _, method := range []DenoiseMethod{DenoiseGaussian, DenoiseGuided} {
+				res, noise, err := DenoiseAutoGray(noisy, method)
+				if err != nil {
+					t.Fatalf("Error should not be returned. Error value: %s", err)
+				}
+				if noise <= 0 {
+					t.Errorf("Expected the estimated noise to be returned - actual: %f", noise)
+				}
+				if after := meanSquaredError(base, res); after > before/2 {
+					t.Errorf("Expected the denoising to halve the error on the %s image with sigma %f and method %d - before: %f, after: %f", name, sigma, method, before, after)
+				}
+			}
+		}
+	}
+}
+
+func TestGrayDenoiseAutoClean(t *testing.T) {
+	img := denoiseTestImages()["gradient"]
+	res, noise, err := DenoiseAutoGray(img, DenoiseGaussian)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if noise >= MinDenoiseNoise {
+		t.Errorf("Expected no noise on a clean image - actual: %f", noise)
+	}
+	utils.CompareGrayImages(t, img, res)
+	if _, _, err := DenoiseAutoGray(img, DenoiseMethod(7)); err == nil {
+		t.Errorf("Expected error for an unknown method")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// denoiseTestImages returns clean 96x96 base images: a flat image, a gradient and a smooth wave.
+func denoiseTestImages() map[string]*image.Gray {
+	images := map[string]*image.Gray{}
+	for _, name := range []string{"flat", "gradient", "wave"} {
+		img := image.NewGray(image.Rect(0, 0, 96, 96))
+		utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			v := 128.0
+			switch name {
+			case "gradient":
+				v = 70 + float64(x+y)*0.6
+			case "wave":
+				v = 128 + 30*math.Sin(float64(x)/9)*math.Cos(float64(y)/13)
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(math.Round(v))})
+		})
+		images[name] = img
+	}
+	return images
+}
+
+func addGaussianNoise(img *image.Gray, sigma float64, rng *rand.Rand) *image.Gray {
+	res := image.NewGray(img.Bounds())
+	for i, v := range img.Pix {
+		res.Pix[i] = uint8(utils.ClampF64(math.Round(float64(v)+rng.NormFloat64()*sigma), 0, 255))
+	}
+	return res
+}
+
+func meanSquaredError(img1, img2 *image.Gray) float64 {
+	var sum float64
+	for i := range img1.Pix {
+		d := float64(img1.Pix[i]) - float64(img2.Pix[i])
+		sum += d * d
+	}
+	return sum / float64(len(img1.Pix))
+}
@@ -0,0 +1,64 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// AnisotropicDiffusionGray smooths a grayscale image with the Perona-Malik anisotropic diffusion, which smooths the
+// flat regions while the edges are kept sharp. Every iteration moves each pixel towards its four neighbours:
+// I += lambda * sum(c(d) * d), where d is the difference to the neighbour and c(d) = exp(-(d/kappa)^2) is the
+// conductance, so the differences much bigger then kappa barely diffuse. A larger kappa smooths across stronger edges.
+// The lambda is the step size, it is clamped to the [0, 0.25] interval where the diffusion is stable. The pixels at
+// the border of the image have no neighbour outside of it. Zero iterations, a kappa or a lambda smaller or equal to 0
+// return a copy of the image.
+// Example of usage:
+//
+//	res := blur.AnisotropicDiffusionGray(img, 20, 15, 0.2)
+func AnisotropicDiffusionGray(img *image.Gray, iterations int, kappa, lambda float64) *image.Gray {
+	if iterations <= 0 || kappa <= 0 || lambda <= 0 {
+		return utils.CloneGray(img)
+	}
+	lambda = math.Min(lambda, 0.25)
+	size := img.Bounds().Size()
+	min := img.Bounds().Min
+	plane := make([]float64, size.X*size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		plane[y*size.X+x] = float64(img.Pix[img.PixOffset(min.X+x, min.Y+y)])
+	})
+	next := make([]float64, len(plane))
+	conductance := func(d float64) float64 {
+		return math.Exp(-(d / kappa) * (d / kappa))
+	}
+	for i := 0; i < iterations; i++ {
+		utils.ParallelForEachPixel(size, func(x, y int) {
+			index := y*size.X + x
+			value := plane[index]
+			var flux float64
+			if x > 0 {
+				d := plane[index-1] - value
+				flux += conductance(d) * d
+			}
+			if x < size.X-1 {
+				d := plane[index+1] - value
+				flux += conductance(d) * d
+			}
+			if y > 0 {
+				d := plane[index-size.X] - value
+				flux += conductance(d) * d
+			}
+			if y < size.Y-1 {
+				d := plane[index+size.X] - value
+				flux += conductance(d) * d
+			}
+			next[index] = value + lambda*flux
+		})
+		plane, next = next, plane
+	}
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for i, v := range plane {
+		res.Pix[i] = uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+	}
+	return res
+}
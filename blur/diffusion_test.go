@@ -0,0 +1,50 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_AnisotropicDiffusionGray(t *testing.T) {
+	_, noisy := setupTestCaseNoisyStep()
+	// the flat regions on both sides of the step, without the columns next to it
+	flat := []image.Rectangle{image.Rect(0, 0, 17, 30), image.Rect(23, 0, 40, 30)}
+	flatVariance := func(img *image.Gray) float64 {
+		return varianceGray(img, flat[0]) + varianceGray(img, flat[1])
+	}
+	noise := flatVariance(noisy)
+	previous := noise
+	for _, iterations := range []int{5, 30} {
+		res := AnisotropicDiffusionGray(noisy, iterations, 25, 0.2)
+		variance := flatVariance(res)
+		if variance >= previous {
+			t.Errorf("Expected the flat regions to get smoother with %d iterations - variance: %f, before: %f", iterations, variance, previous)
+		}
+		previous = variance
+		// the step of 130 gray levels between the columns 19 and 20 stays sharp
+		for y := 0; y < 30; y++ {
+			step := int(res.GrayAt(20, y).Y) - int(res.GrayAt(19, y).Y)
+			if step < 100 {
+				t.Fatalf("Expected a sharp edge after %d iterations - actual step: %d at row: %d", iterations, step, y)
+			}
+		}
+	}
+	if previous > noise/10 {
+		t.Errorf("Expected the noise to be mostly removed - variance: %f, before: %f", previous, noise)
+	}
+}
+
+func Test_AnisotropicDiffusionGray_NoIterations(t *testing.T) {
+	_, noisy := setupTestCaseNoisyStep()
+	for _, iterations := range []int{0, -1} {
+		res := AnisotropicDiffusionGray(noisy, iterations, 25, 0.2)
+		utils.CompareGrayImages(t, noisy, res)
+		if &res.Pix[0] == &noisy.Pix[0] {
+			t.Errorf("Expected a copy of the image")
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
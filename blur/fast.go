@@ -0,0 +1,105 @@
+package blur
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// FastGaussianBlurGray approximates a Gaussian blur of a grayscale image by three successive box blurs, with the box
+// sizes chosen so the variance of the three boxes matches sigma^2 (P. Kovesi, "Fast Almost-Gaussian Filtering", 2010).
+// Every box blur uses running sums, so the runtime does not depend on sigma, which makes it much faster then
+// GaussianBlurGray for big sigmas. The border is handled as in BorderReflect. A sigma smaller or equal to 0 returns a
+// copy of the image.
+// Example of usage:
+//
+//	res := blur.FastGaussianBlurGray(img, 20)
+func FastGaussianBlurGray(img *image.Gray, sigma float64) *image.Gray {
+	if sigma <= 0 {
+		return utils.CloneGray(img)
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(img.Bounds())
+	plane := make([]float64, size.X*size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		plane[y*size.X+x] = float64(img.GrayAt(x, y).Y)
+	})
+	fastGaussianBlurPlane(plane, size, sigma)
+	utils.ForEachPixel(size, func(x, y int) {
+		res.Pix[y*res.Stride+x] = uint8(utils.ClampF64(math.Round(plane[y*size.X+x]), utils.MinUint8, float64(utils.MaxUint8)))
+	})
+	return res
+}
+
+// FastGaussianBlurPlane applies the same almost-Gaussian blur as FastGaussianBlurGray to a row-major plane of float
+// values of the given size, e.g. to smooth a displacement field without rounding it to 8 bits. The result is a new
+// slice. A sigma smaller or equal to 0 returns a copy of the plane. Returns an error if the length of the plane does
+// not match the size.
+// Example of usage:
+//
+//	res, err := blur.FastGaussianBlurPlane(field, image.Point{X: 640, Y: 480}, 8)
+func FastGaussianBlurPlane(plane []float64, size image.Point, sigma float64) ([]float64, error) {
+	if size.X < 0 || size.Y < 0 || len(plane) != size.X*size.Y {
+		return nil, errors.New("the length of the plane does not match the size")
+	}
+	res := append([]float64{}, plane...)
+	if sigma > 0 && len(res) > 0 {
+		fastGaussianBlurPlane(res, size, sigma)
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// fastGaussianBlurPlane blurs the row-major plane in place with three box blurs.
+func fastGaussianBlurPlane(plane []float64, size image.Point, sigma float64) {
+	tmp := make([]float64, len(plane))
+	for _, boxSize := range boxSizesForGauss(sigma, 3) {
+		radius := boxSize / 2
+		boxBlurAxis(plane, tmp, size.X, size.Y, 1, size.X, radius)
+		boxBlurAxis(tmp, plane, size.Y, size.X, size.X, 1, radius)
+	}
+}
+
+// boxSizesForGauss returns the odd sizes of n successive box filters whose combined variance is the closest to
+// sigma^2: m boxes of the size wl and n - m boxes of the size wl + 2.
+func boxSizesForGauss(sigma float64, n int) []int {
+	ideal := math.Sqrt(12*sigma*sigma/float64(n) + 1)
+	wl := int(math.Floor(ideal))
+	if wl%2 == 0 {
+		wl--
+	}
+	wu := wl + 2
+	fl := float64(wl)
+	m := int(math.Round((12*sigma*sigma - float64(n)*fl*fl - 4*float64(n)*fl - 3*float64(n)) / (-4*fl - 4)))
+	sizes := make([]int, n)
+	for i := range sizes {
+		if i < m {
+			sizes[i] = wl
+		} else {
+			sizes[i] = wu
+		}
+	}
+	return sizes
+}
+
+// boxBlurAxis computes the mean of every (2 * radius + 1) long window along one axis of a row-major plane with a
+// running sum. The plane is traversed as count lines of length pixels, step is the distance between two pixels of a
+// line and lineStep the distance between two lines. The border is handled as in BorderReflect.
+func boxBlurAxis(src, dst []float64, length, count, step, lineStep, radius int) {
+	norm := 1 / float64(2*radius+1)
+	utils.ParallelForEachRow(image.Point{X: length, Y: count}, func(line int) {
+		offset := line * lineStep
+		at := func(i int) float64 {
+			return src[offset+reflectIndex(i, length)*step]
+		}
+		var sum float64
+		for i := -radius; i <= radius; i++ {
+			sum += at(i)
+		}
+		for i := 0; i < length; i++ {
+			dst[offset+i*step] = sum * norm
+			sum += at(i+radius+1) - at(i-radius)
+		}
+	})
+}
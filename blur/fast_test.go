@@ -0,0 +1,106 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func TestGrayFastGaussianBlur(t *testing.T) {
+	img := fastBlurTestImage(128)
+	exact, err := GaussianBlurGrayXY(img, 121, 121, 20, 20, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	fast := FastGaussianBlurGray(img, 20)
+	var mse float64
+	for i := range exact.Pix {
+		d := float64(exact.Pix[i]) - float64(fast.Pix[i])
+		mse += d * d
+	}
+	mse /= float64(len(exact.Pix))
+	if mse > 2 {
+		t.Errorf("Expected a small mean squared error to the exact Gaussian blur - actual: %f", mse)
+	}
+}
+
+func TestGrayFastGaussianBlurBoxSizes(t *testing.T) {
+	for _, sigma := range []float64{1, 2.5, 20, 100} {
+		var variance float64
+		for _, size := range boxSizesForGauss(sigma, 3) {
+			if size%2 == 0 {
+				t.Fatalf("Expected odd box sizes - actual: %d", size)
+			}
+			variance += float64(size*size-1) / 12
+		}
+		if variance < sigma*sigma*0.9-1 || variance > sigma*sigma*1.1+1 {
+			t.Errorf("Expected a variance close to %f - actual: %f", sigma*sigma, variance)
+		}
+	}
+}
+
+func TestGrayFastGaussianBlurZeroSigma(t *testing.T) {
+	img := fastBlurTestImage(16)
+	utils.CompareGrayImages(t, img, FastGaussianBlurGray(img, 0))
+}
+
+func TestFastGaussianBlurPlane(t *testing.T) {
+	img := fastBlurTestImage(48)
+	size := img.Bounds().Size()
+	plane := make([]float64, size.X*size.Y)
+	for i, v := range img.Pix {
+		plane[i] = float64(v)
+	}
+	res, err := FastGaussianBlurPlane(plane, size, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := FastGaussianBlurGray(img, 3)
+	for i := range res {
+		if math.Round(res[i]) != float64(expected.Pix[i]) {
+			t.Fatalf("Expected value: %d - actual value: %f at: %d", expected.Pix[i], res[i], i)
+		}
+		if plane[i] != float64(img.Pix[i]) {
+			t.Fatalf("Expected the input plane to be unchanged at: %d", i)
+		}
+	}
+	if _, err := FastGaussianBlurPlane(plane, image.Point{X: 10, Y: 10}, 3); err == nil {
+		t.Error("Expected error for a plane which does not match the size")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func fastBlurTestImage(size int) *image.Gray {
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		value := x*160/size + rnd.Intn(64)
+		if y > size/2 {
+			value += 30
+		}
+		img.SetGray(x, y, color.Gray{Y: uint8(value)})
+	})
+	return img
+}
+
+func Benchmark_GaussianBlurGrayXY_Sigma20(b *testing.B) {
+	img := fastBlurTestImage(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GaussianBlurGrayXY(img, 121, 121, 20, 20, padding.BorderReflect)
+	}
+}
+
+func Benchmark_FastGaussianBlurGray_Sigma20(b *testing.B) {
+	img := fastBlurTestImage(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FastGaussianBlurGray(img, 20)
+	}
+}
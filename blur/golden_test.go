@@ -0,0 +1,27 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/internal/goldentest"
+	"github.com/yafeiliu/imger/padding"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Golden tests------------------------------------
+func Test_Golden(t *testing.T) {
+	registry := goldentest.NewRegistry("../res/golden/blur")
+	registry.Register("box5x5_reflect", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		res, _, err := BoxGray(img, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, padding.BorderReflect)
+		return res, err
+	})
+	registry.Register("gaussian_r3_s1_replicate", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		res, _, err := GaussianBlurGray(img, 3, 1, padding.BorderReplicate)
+		return res, err
+	})
+	registry.Register("fast_gaussian_s2", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		return FastGaussianBlurGray(img, 2), nil
+	})
+	registry.Run(t)
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,91 @@
+package blur
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// GuidedFilterGray applies the guided filter (He et al.) to a grayscale image, which smooths flat areas while it keeps
+// the edges of the guide image. The guide can be the image itself (self-guided filtering) or another image of the same
+// size, for example the grayscale version of a photo which guides the smoothing of a noisy depth map. The radius sets
+// the (2 * radius + 1) sized square window and eps regularizes the local linear model, the values are scaled to [0, 1]
+// so eps is the squared intensity of the edges which are smoothed out (e.g. 0.01 smooths variations smaller then 0.1).
+// The window means are computed on integral images, so the runtime does not depend on the radius.
+// Returns an error if the sizes of the images do not match or the radius is negative.
+// Example of usage:
+//
+//	res, err := blur.GuidedFilterGray(img, img, 4, 0.01)
+func GuidedFilterGray(img, guide *image.Gray, radius int, eps float64) (*image.Gray, error) {
+	size := img.Bounds().Size()
+	if guide.Bounds().Size() != size {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	if radius < 0 {
+		return nil, errors.New("radius must be positive")
+	}
+	n := size.X * size.Y
+	p := make([]float64, n)
+	g := make([]float64, n)
+	gg := make([]float64, n)
+	gp := make([]float64, n)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			i := y*size.X + x
+			p[i] = float64(img.Pix[y*img.Stride+x]) / float64(utils.MaxUint8)
+			g[i] = float64(guide.Pix[y*guide.Stride+x]) / float64(utils.MaxUint8)
+			gg[i] = g[i] * g[i]
+			gp[i] = g[i] * p[i]
+		}
+	}
+	meanG := boxMean(g, size, radius)
+	meanP := boxMean(p, size, radius)
+	corrG := boxMean(gg, size, radius)
+	corrGP := boxMean(gp, size, radius)
+
+	a := make([]float64, n)
+	b := make([]float64, n)
+	for i := range a {
+		varG := corrG[i] - meanG[i]*meanG[i]
+		covGP := corrGP[i] - meanG[i]*meanP[i]
+		a[i] = covGP / (varG + eps)
+		b[i] = meanP[i] - a[i]*meanG[i]
+	}
+	meanA := boxMean(a, size, radius)
+	meanB := boxMean(b, size, radius)
+
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			i := y*size.X + x
+			q := meanA[i]*g[i] + meanB[i]
+			res.Pix[y*res.Stride+x] = uint8(utils.ClampF64(q*float64(utils.MaxUint8)+0.5, utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// boxMean computes the mean of every (2 * radius + 1) sized window of a row-major plane using an integral image. The
+// windows are cropped at the borders of the plane and the mean is computed over the pixels inside of the plane.
+func boxMean(plane []float64, size image.Point, radius int) []float64 {
+	w := size.X + 1
+	integral := make([]float64, w*(size.Y+1))
+	for y := 0; y < size.Y; y++ {
+		var rowSum float64
+		for x := 0; x < size.X; x++ {
+			rowSum += plane[y*size.X+x]
+			integral[(y+1)*w+x+1] = integral[y*w+x+1] + rowSum
+		}
+	}
+	res := make([]float64, len(plane))
+	for y := 0; y < size.Y; y++ {
+		y0, y1 := utils.ClampInt(y-radius, 0, size.Y), utils.ClampInt(y+radius+1, 0, size.Y)
+		for x := 0; x < size.X; x++ {
+			x0, x1 := utils.ClampInt(x-radius, 0, size.X), utils.ClampInt(x+radius+1, 0, size.X)
+			sum := integral[y1*w+x1] - integral[y0*w+x1] - integral[y1*w+x0] + integral[y0*w+x0]
+			res[y*size.X+x] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+	return res
+}
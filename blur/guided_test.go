@@ -0,0 +1,192 @@
+package blur
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseNoisyStep returns a vertical step edge between the columns 19 and 20 with and without additive noise.
+func setupTestCaseNoisyStep() (*image.Gray, *image.Gray) {
+	rnd := rand.New(rand.NewSource(1))
+	clean := image.NewGray(image.Rect(0, 0, 40, 30))
+	noisy := image.NewGray(image.Rect(0, 0, 40, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			v := 60
+			if x >= 20 {
+				v = 190
+			}
+			clean.Pix[y*clean.Stride+x] = uint8(v)
+			noisy.Pix[y*noisy.Stride+x] = uint8(v + rnd.Intn(41) - 20)
+		}
+	}
+	return clean, noisy
+}
+
+func varianceGray(img *image.Gray, rect image.Rectangle) float64 {
+	var sum, sumSqr, n float64
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			v := float64(img.GrayAt(x, y).Y)
+			sum += v
+			sumSqr += v * v
+			n++
+		}
+	}
+	mean := sum / n
+	return sumSqr/n - mean*mean
+}
+
+func Test_GuidedFilterGray_SelfGuided(t *testing.T) {
+	_, noisy := setupTestCaseNoisyStep()
+	res, err := GuidedFilterGray(noisy, noisy, 4, 0.01)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for _, flat := range []image.Rectangle{image.Rect(2, 2, 14, 28), image.Rect(26, 2, 38, 28)} {
+		before, after := varianceGray(noisy, flat), varianceGray(res, flat)
+		if after > before/4 {
+			t.Errorf("Expected the variance of %v to drop at least 4 times - before: %f, after: %f", flat, before, after)
+		}
+	}
+	for y := 0; y < 30; y++ {
+		if res.GrayAt(18, y).Y >= 125 || res.GrayAt(21, y).Y <= 125 {
+			t.Errorf("Expected the edge to stay between the columns 19 and 20 - actual: %d %d at row %d",
+				res.GrayAt(18, y).Y, res.GrayAt(21, y).Y, y)
+		}
+	}
+}
+
+func Test_GuidedFilterGray_Guide(t *testing.T) {
+	clean, noisy := setupTestCaseNoisyStep()
+	res, err := GuidedFilterGray(noisy, clean, 3, 0.0001)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	var maxDiff float64
+	for i := range res.Pix {
+		maxDiff = math.Max(maxDiff, math.Abs(float64(res.Pix[i])-float64(clean.Pix[i])))
+	}
+	if maxDiff > 15 {
+		t.Errorf("Expected the result to follow the clean guide within 15 levels - actual max difference: %f", maxDiff)
+	}
+}
+
+func Test_GuidedFilterGray_Monotonic(t *testing.T) {
+	_, noisy := setupTestCaseNoisyStep()
+	// the region is further then two radiuses from the edge, so the edge does not leak into it
+	flat := image.Rect(1, 2, 8, 28)
+	filter := func(radius int, eps float64) *image.Gray {
+		res, err := GuidedFilterGray(noisy, noisy, radius, eps)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		return res
+	}
+	// edge contrast between the columns next to the step
+	contrast := func(img *image.Gray) float64 {
+		var sum float64
+		for y := 0; y < 30; y++ {
+			sum += float64(img.GrayAt(21, y).Y) - float64(img.GrayAt(18, y).Y)
+		}
+		return sum / 30
+	}
+	previous := varianceGray(noisy, flat)
+	for _, radius := range []int{1, 2, 3, 5} {
+		variance := varianceGray(filter(radius, 0.01), flat)
+		if variance >= previous {
+			t.Errorf("Expected the variance of the flat region to drop with the radius - radius %d: %f, before: %f", radius, variance, previous)
+		}
+		previous = variance
+	}
+	previousVariance, previousContrast := varianceGray(noisy, flat), contrast(noisy)
+	for _, eps := range []float64{0.0001, 0.001, 0.01, 0.1, 1} {
+		res := filter(4, eps)
+		variance, edge := varianceGray(res, flat), contrast(res)
+		if variance > previousVariance || edge > previousContrast+1 {
+			t.Errorf("Expected a bigger eps to smooth more - eps %f: variance %f, contrast %f, before: %f, %f", eps, variance, edge, previousVariance, previousContrast)
+		}
+		previousVariance, previousContrast = variance, edge
+	}
+	if previousContrast > 100 {
+		t.Errorf("Expected a large eps to blur the edge like a box filter - actual contrast: %f", previousContrast)
+	}
+}
+
+func Test_GuidedFilterGray_Invalid(t *testing.T) {
+	_, noisy := setupTestCaseNoisyStep()
+	if _, err := GuidedFilterGray(noisy, image.NewGray(image.Rect(0, 0, 3, 3)), 2, 0.01); err == nil {
+		t.Error("Expected error for different sizes")
+	}
+	if _, err := GuidedFilterGray(noisy, noisy, -1, 0.01); err == nil {
+		t.Error("Expected error for negative radius")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// setupBenchmarkGuidedInput repeats the noisy step into a 512x512 image, the guided and the bilateral filter
+// benchmarks run on the same input.
+func setupBenchmarkGuidedInput() *image.Gray {
+	_, noisy := setupTestCaseNoisyStep()
+	img := image.NewGray(image.Rect(0, 0, 512, 512))
+	for y := 0; y < 512; y++ {
+		copy(img.Pix[y*img.Stride:y*img.Stride+40], noisy.Pix[(y%30)*noisy.Stride:(y%30)*noisy.Stride+40])
+	}
+	return img
+}
+
+// referenceBilateralFilterGray is a brute force bilateral filter with reflected borders (BorderReflect), it visits the
+// whole (2 * radius + 1)^2 window of every pixel.
+func referenceBilateralFilterGray(img *image.Gray, radius int, sigmaSpace, sigmaRange float64) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(img.Bounds())
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			center := float64(img.Pix[y*img.Stride+x])
+			var sum, weights float64
+			for ky := -radius; ky <= radius; ky++ {
+				row := reflectIndex(y+ky, size.Y) * img.Stride
+				for kx := -radius; kx <= radius; kx++ {
+					v := float64(img.Pix[row+reflectIndex(x+kx, size.X)])
+					w := math.Exp(-float64(kx*kx+ky*ky)/(2*sigmaSpace*sigmaSpace) - (v-center)*(v-center)/(2*sigmaRange*sigmaRange))
+					sum += v * w
+					weights += w
+				}
+			}
+			res.Pix[y*res.Stride+x] = uint8(sum/weights + 0.5)
+		}
+	}
+	return res
+}
+
+// The runtime of the guided filter does not depend on the radius, unlike the rank, Gaussian or bilateral filters.
+func benchmarkGuidedFilterGray(b *testing.B, radius int) {
+	img := setupBenchmarkGuidedInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GuidedFilterGray(img, img, radius, 0.01)
+	}
+}
+
+// The runtime of the bilateral filter grows with the area of the window.
+func benchmarkBilateralFilterGray(b *testing.B, radius int) {
+	img := setupBenchmarkGuidedInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceBilateralFilterGray(img, radius, float64(radius), 25)
+	}
+}
+
+func Benchmark_GuidedFilterGray_Radius2(b *testing.B) { benchmarkGuidedFilterGray(b, 2) }
+
+func Benchmark_GuidedFilterGray_Radius16(b *testing.B) { benchmarkGuidedFilterGray(b, 16) }
+
+func Benchmark_GuidedFilterGray_Radius64(b *testing.B) { benchmarkGuidedFilterGray(b, 64) }
+
+func Benchmark_BilateralFilterGray_Radius2(b *testing.B) { benchmarkBilateralFilterGray(b, 2) }
+
+func Benchmark_BilateralFilterGray_Radius16(b *testing.B) { benchmarkBilateralFilterGray(b, 16) }
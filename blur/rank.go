@@ -0,0 +1,60 @@
+package blur
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"image"
+	"image/color"
+)
+
+// RankFilterGray replaces every pixel of a grayscale image with the value of the given rank among the sorted values of
+// the ksize x ksize window centered on it. Rank 0 gives the minimum, rank ksize*ksize/2 gives the median and rank
+// ksize*ksize-1 gives the maximum. The window is moved with a sliding histogram so the cost per pixel grows linearly
+// with the kernel size. Supported border types are: BorderConstant, BorderReplicate, BorderReflect.
+// Example of usage:
+//
+//	res, err := blur.RankFilterGray(img, 5, 12, padding.BorderReflect)
+func RankFilterGray(img *image.Gray, ksize int, rank int, border padding.Border) (*image.Gray, error) {
+	if ksize < 1 || ksize%2 == 0 {
+		return nil, errors.New("kernel size must be a positive odd number")
+	}
+	if rank < 0 || rank >= ksize*ksize {
+		return nil, errors.New("rank must be in the [0, ksize*ksize) interval")
+	}
+	padded, err := padding.PaddingGray(img, image.Point{X: ksize, Y: ksize}, image.Point{X: ksize / 2, Y: ksize / 2}, border)
+	if err != nil {
+		return nil, err
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		var hist [256]int
+		for ky := 0; ky < ksize; ky++ {
+			for kx := 0; kx < ksize; kx++ {
+				hist[padded.GrayAt(kx, y+ky).Y]++
+			}
+		}
+		for x := 0; x < size.X; x++ {
+			if x > 0 {
+				for ky := 0; ky < ksize; ky++ {
+					hist[padded.GrayAt(x-1, y+ky).Y]--
+					hist[padded.GrayAt(x+ksize-1, y+ky).Y]++
+				}
+			}
+			res.SetGray(x, y, color.Gray{Y: histogramRank(&hist, rank)})
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func histogramRank(hist *[256]int, rank int) uint8 {
+	count := 0
+	for v, h := range hist {
+		count += h
+		if count > rank {
+			return uint8(v)
+		}
+	}
+	return 255
+}
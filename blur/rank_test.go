@@ -0,0 +1,84 @@
+package blur
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"sort"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func setupTestCaseNoiseGray() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 23, 17))
+	for i := range img.Pix {
+		img.Pix[i] = uint8((i*97 + i*i*13) % 256)
+	}
+	return img
+}
+
+// referenceRankFilterGray sorts every window, it uses reflected borders (BorderReflect).
+func referenceRankFilterGray(img *image.Gray, ksize int, pick func(values []int) int) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(img.Bounds())
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			var values []int
+			for ky := -ksize / 2; ky <= ksize/2; ky++ {
+				for kx := -ksize / 2; kx <= ksize/2; kx++ {
+					sx, sy := reflectIndex(x+kx, size.X), reflectIndex(y+ky, size.Y)
+					values = append(values, int(img.GrayAt(sx, sy).Y))
+				}
+			}
+			sort.Ints(values)
+			res.SetGray(x, y, color.Gray{Y: uint8(pick(values))})
+		}
+	}
+	return res
+}
+
+func Test_RankFilterGray_Min(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	actual, err := RankFilterGray(img, 3, 0, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := referenceRankFilterGray(img, 3, func(values []int) int { return values[0] })
+	utils.CompareGrayImages(t, expected, actual)
+}
+
+func Test_RankFilterGray_Median(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	actual, err := RankFilterGray(img, 5, 12, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := referenceRankFilterGray(img, 5, func(values []int) int { return values[len(values)/2] })
+	utils.CompareGrayImages(t, expected, actual)
+}
+
+func Test_RankFilterGray_Max(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	actual, err := RankFilterGray(img, 3, 8, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := referenceRankFilterGray(img, 3, func(values []int) int { return values[len(values)-1] })
+	utils.CompareGrayImages(t, expected, actual)
+}
+
+func Test_RankFilterGray_Invalid(t *testing.T) {
+	img := setupTestCaseNoiseGray()
+	if _, err := RankFilterGray(img, 4, 0, padding.BorderReplicate); err == nil {
+		t.Error("Should not reach this point")
+	}
+	if _, err := RankFilterGray(img, 3, 9, padding.BorderReplicate); err == nil {
+		t.Error("Should not reach this point")
+	}
+	if _, err := RankFilterGray(img, 3, 4, padding.Border(-1)); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -1,74 +1,189 @@
 package convolution
 
 import (
+	"errors"
 	"github.com/yafeiliu/imger/padding"
 	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
 )
 
-// ConvolveGray applies a convolution matrix (kernel) to a grayscale image.
+// ConvolveGray applies a convolution matrix (kernel) to a grayscale image. The result is clamped to the [0, 255]
+// interval, use ConvolveGrayFloat to get the unclamped response.
 // Example of usage:
 //
 //	res, err := convolution.ConvolveGray(img, kernel, {1, 1}, BorderReflect)
 //
 // Note: the anchor represents a point inside the area of the kernel. After every step of the convolution the position
-// specified by the anchor point gets updated on the result image.
-func ConvolveGray(img *image.Gray, kernel *Kernel, anchor image.Point, border padding.Border) (*image.Gray, float64, error) {
-	kernelSize := kernel.Size()
-	padded, error := padding.PaddingGray(img, kernelSize, anchor, border)
-	if error != nil {
-		return nil, 0, error
-	}
+// specified by the anchor point gets updated on the result image. With utils.WithPool the padded copy of the image is
+// taken from the pool.
+func ConvolveGray(img *image.Gray, kernel *Kernel, anchor image.Point, border padding.Border, opts ...utils.Option) (*image.Gray, float64, error) {
 	originalSize := img.Bounds().Size()
 	resultImage := image.NewGray(img.Bounds())
-	var score float64
-	utils.ParallelForEachPixel(originalSize, func(x int, y int) {
-		sum := float64(0)
-		for ky := 0; ky < kernelSize.Y; ky++ {
-			for kx := 0; kx < kernelSize.X; kx++ {
-				pixel := padded.GrayAt(x+kx, y+ky)
-				kE := kernel.At(kx, ky)
-				sum += float64(pixel.Y) * kE
-			}
-		}
+	// every row is processed by a single worker, so the scores are accumulated per row
+	rowScores := make([]float64, originalSize.Y)
+	err := convolveGray(img, kernel.Content, anchor, border, utils.ApplyOptions(opts).Pool, func(x int, y int, sum float64) {
 		sum = utils.ClampF64(sum, utils.MinUint8, float64(utils.MaxUint8))
-		score += sum
-		resultImage.Set(x, y, color.Gray{uint8(sum)})
+		rowScores[y] += sum
+		resultImage.Pix[y*resultImage.Stride+x] = uint8(sum)
 	})
+	if err != nil {
+		return nil, 0, err
+	}
+	var score float64
+	for _, s := range rowScores {
+		score += s
+	}
 	return resultImage, score, nil
 }
 
-// ConvolveRGBA applies a convolution matrix (kernel) to an RGBA image.
+// ConvolveGrayFloat applies a convolution matrix to a grayscale image and returns the raw response without clamping or
+// rounding, e.g. the negative values of a high-pass filter are kept. The kernel and the result are indexed as
+// kernel[x][y] and res[x][y], like the Content of a Kernel. Returns an error if the kernel is empty or not rectangular,
+// or the anchor is outside of the kernel. With utils.WithPool the padded copy of the image is taken from the pool.
+// Example of usage:
+//
+//	res, err := convolution.ConvolveGrayFloat(img, kernel.Content, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+func ConvolveGrayFloat(img *image.Gray, kernel [][]float64, anchor image.Point, border padding.Border, opts ...utils.Option) ([][]float64, error) {
+	originalSize := img.Bounds().Size()
+	res := make([][]float64, originalSize.X)
+	for x := range res {
+		res[x] = make([]float64, originalSize.Y)
+	}
+	err := convolveGray(img, kernel, anchor, border, utils.ApplyOptions(opts).Pool, func(x int, y int, sum float64) {
+		res[x][y] = sum
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ConvolveRGBA applies a convolution matrix (kernel) to an RGBA image. Only the color channels are filtered, the alpha
+// channel is copied from the input image.
 // Example of usage:
 //
 //	res, err := convolution.ConvolveRGBA(img, kernel, {1, 1}, BorderReflect)
 //
 // Note: the anchor represents a point inside the area of the kernel. After every step of the convolution the position
-// specified by the anchor point gets updated on the result image.
-func ConvolveRGBA(img *image.RGBA, kernel *Kernel, anchor image.Point, border padding.Border) (*image.RGBA, error) {
+// specified by the anchor point gets updated on the result image. With utils.WithPool the padded copy of the image is
+// taken from the pool.
+func ConvolveRGBA(img *image.RGBA, kernel *Kernel, anchor image.Point, border padding.Border, opts ...utils.Option) (*image.RGBA, error) {
+	return convolveRGBA(img, kernel, anchor, border, false, utils.ApplyOptions(opts).Pool)
+}
+
+// RGBAOptions selects which parts of an RGBA image are filtered by ConvolveRGBAWithOptions.
+type RGBAOptions struct {
+	// SkipAlpha copies the alpha channel through untouched, otherwise it is convolved like the color channels.
+	SkipAlpha bool
+	// LuminanceOnly converts the image to YCbCr, convolves only the Y channel and converts the result back to RGB, which
+	// avoids color fringing when sharpening.
+	LuminanceOnly bool
+}
+
+// ConvolveRGBAWithOptions applies a convolution matrix (kernel) to an RGBA image like ConvolveRGBA, but the filtered
+// channels are selected by the given options. ConvolveRGBA is equivalent to RGBAOptions{SkipAlpha: true}.
+// Example of usage:
+//
+//	res, err := convolution.ConvolveRGBAWithOptions(img, kernel, {1, 1}, BorderReflect, convolution.RGBAOptions{LuminanceOnly: true})
+func ConvolveRGBAWithOptions(img *image.RGBA, kernel *Kernel, anchor image.Point, border padding.Border, options RGBAOptions) (*image.RGBA, error) {
+	if !options.LuminanceOnly {
+		return convolveRGBA(img, kernel, anchor, border, !options.SkipAlpha, nil)
+	}
+	size := img.Bounds().Size()
+	luma := image.NewGray(img.Bounds())
+	cb := make([]uint8, size.X*size.Y)
+	cr := make([]uint8, size.X*size.Y)
+	utils.ForEachPixel(size, func(x int, y int) {
+		c := img.RGBAAt(x, y)
+		yy, u, v := color.RGBToYCbCr(c.R, c.G, c.B)
+		luma.SetGray(x, y, color.Gray{Y: yy})
+		cb[y*size.X+x], cr[y*size.X+x] = u, v
+	})
+	luma, _, err := ConvolveGray(luma, kernel, anchor, border)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, alpha := utils.SplitRGBA(img)
+	if !options.SkipAlpha {
+		if alpha, _, err = ConvolveGray(alpha, kernel, anchor, border); err != nil {
+			return nil, err
+		}
+	}
+	resultImage := image.NewRGBA(img.Bounds())
+	utils.ForEachPixel(size, func(x int, y int) {
+		r, g, b := color.YCbCrToRGB(luma.GrayAt(x, y).Y, cb[y*size.X+x], cr[y*size.X+x])
+		resultImage.SetRGBA(x, y, color.RGBA{R: r, G: g, B: b, A: alpha.GrayAt(x, y).Y})
+	})
+	return resultImage, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func validateKernel(kernel [][]float64) error {
+	if len(kernel) == 0 || len(kernel[0]) == 0 {
+		return errors.New("empty kernel")
+	}
+	for _, column := range kernel {
+		if len(column) != len(kernel[0]) {
+			return errors.New("the kernel is not rectangular")
+		}
+	}
+	return nil
+}
+
+// convolveGray pads the image and passes the raw response of the kernel at every pixel to store. The pixels of a row
+// are passed by a single worker.
+func convolveGray(img *image.Gray, kernel [][]float64, anchor image.Point, border padding.Border, pool *utils.BufferPool, store func(x int, y int, sum float64)) error {
+	if err := validateKernel(kernel); err != nil {
+		return err
+	}
+	kernelSize := image.Point{X: len(kernel), Y: len(kernel[0])}
+	padded, err := padding.PaddingGray(img, kernelSize, anchor, border, utils.WithPool(pool))
+	if err != nil {
+		return err
+	}
+	defer pool.PutGray(padded)
+	utils.ParallelForEachPixel(img.Bounds().Size(), func(x int, y int) {
+		sum := float64(0)
+		for ky := 0; ky < kernelSize.Y; ky++ {
+			row := padded.Pix[(y+ky)*padded.Stride+x:]
+			for kx := 0; kx < kernelSize.X; kx++ {
+				sum += float64(row[kx]) * kernel[kx][ky]
+			}
+		}
+		store(x, y, sum)
+	})
+	return nil
+}
+
+func convolveRGBA(img *image.RGBA, kernel *Kernel, anchor image.Point, border padding.Border, withAlpha bool, pool *utils.BufferPool) (*image.RGBA, error) {
 	kernelSize := kernel.Size()
-	padded, err := padding.PaddingRGBA(img, kernelSize, anchor, border)
+	padded, err := padding.PaddingRGBA(img, kernelSize, anchor, border, utils.WithPool(pool))
 	if err != nil {
 		return nil, err
 	}
+	defer pool.PutRGBA(padded)
 	originalSize := img.Bounds().Size()
 	resultImage := image.NewRGBA(img.Bounds())
 	utils.ParallelForEachPixel(originalSize, func(x int, y int) {
-		sumR, sumG, sumB := 0.0, 0.0, 0.0
+		sumR, sumG, sumB, sumA := 0.0, 0.0, 0.0, 0.0
 		for kx := 0; kx < kernelSize.X; kx++ {
 			for ky := 0; ky < kernelSize.Y; ky++ {
 				pixel := padded.RGBAAt(x+kx, y+ky)
 				sumR += float64(pixel.R) * kernel.At(kx, ky)
 				sumG += float64(pixel.G) * kernel.At(kx, ky)
 				sumB += float64(pixel.B) * kernel.At(kx, ky)
+				sumA += float64(pixel.A) * kernel.At(kx, ky)
 			}
 		}
 		sumR = utils.ClampF64(sumR, utils.MinUint8, float64(utils.MaxUint8))
 		sumG = utils.ClampF64(sumG, utils.MinUint8, float64(utils.MaxUint8))
 		sumB = utils.ClampF64(sumB, utils.MinUint8, float64(utils.MaxUint8))
-		rgba := img.RGBAAt(x, y)
-		resultImage.Set(x, y, color.RGBA{uint8(sumR), uint8(sumG), uint8(sumB), rgba.A})
+		alpha := img.RGBAAt(x, y).A
+		if withAlpha {
+			alpha = uint8(utils.ClampF64(sumA, utils.MinUint8, float64(utils.MaxUint8)))
+		}
+		resultImage.Set(x, y, color.RGBA{uint8(sumR), uint8(sumG), uint8(sumB), alpha})
 	})
 	return resultImage, nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/yafeiliu/imger/padding"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"testing"
 )
 
@@ -181,4 +182,232 @@ func Test_ConvoleRGBA_11Kernel(t *testing.T) {
 	})
 }
 
+func setupTestCaseSharpen() *Kernel {
+	return &Kernel{[][]float64{
+		{0, -1, 0},
+		{-1, 5, -1},
+		{0, -1, 0},
+	}, 3, 3}
+}
+
+func Test_ConvolveRGBAWithOptions_SkipAlpha(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 8; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 16), G: uint8((x * y * 7) % 256), B: uint8(y * 30), A: uint8(x*15 + 10)})
+		}
+	}
+	for _, options := range []RGBAOptions{{SkipAlpha: true}, {SkipAlpha: true, LuminanceOnly: true}} {
+		res, err := ConvolveRGBAWithOptions(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderReflect, options)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			if res.RGBAAt(x, y).A != img.RGBAAt(x, y).A {
+				t.Errorf("Expected alpha: %d - actual alpha: %d at %d, %d", img.RGBAAt(x, y).A, res.RGBAAt(x, y).A, x, y)
+			}
+		})
+	}
+	// the alpha gradient is filtered without SkipAlpha, the sharpening kernel changes its border columns
+	res, err := ConvolveRGBAWithOptions(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderConstant, RGBAOptions{})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.RGBAAt(0, 0).A == img.RGBAAt(0, 0).A {
+		t.Errorf("Expected the alpha channel to be convolved - actual alpha: %d", res.RGBAAt(0, 0).A)
+	}
+}
+
+func Test_ConvolveRGBAWithOptions_LuminanceOnly(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 6))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 6; y++ {
+			if x < 5 {
+				img.SetRGBA(x, y, color.RGBA{R: 0xFF, A: 0xFF})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{B: 0xFF, A: 0xFF})
+			}
+		}
+	}
+	res, err := ConvolveRGBAWithOptions(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderReflect, RGBAOptions{LuminanceOnly: true})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		c := res.RGBAAt(x, y)
+		if c.G > c.R && c.G > c.B {
+			t.Errorf("Expected no green pixels - actual: %v at %d, %d", c, x, y)
+		}
+		if x < 4 && c.R < c.B || x > 5 && c.B < c.R {
+			t.Errorf("Expected the hue to be preserved - actual: %v at %d, %d", c, x, y)
+		}
+	})
+}
+
+func Test_ConvolveRGBAWithOptions_Default(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 6, 6))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 13)
+	}
+	expected, err := ConvolveRGBA(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	actual, err := ConvolveRGBAWithOptions(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderReflect, RGBAOptions{SkipAlpha: true})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImages(t, expected, actual)
+}
+
+func setupTestCaseROIGray() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 31, 23))
+	for i := range img.Pix {
+		img.Pix[i] = uint8((i*97 + i*i*13) % 256)
+	}
+	return img
+}
+
+func Test_ConvolveGrayROI(t *testing.T) {
+	img := setupTestCaseROIGray()
+	kernel := &Kernel{[][]float64{
+		{1, 2, 0, 1, 1},
+		{0, 1, 3, 1, 0},
+		{2, 1, 1, 0, 1},
+		{0, 0, 1, 2, 1},
+		{1, 3, 0, 1, 2},
+	}, 5, 5}
+	anchor := image.Point{X: 1, Y: 3}
+	full, _, err := ConvolveGray(img, kernel.Normalize(), anchor, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for _, roi := range []image.Rectangle{image.Rect(5, 4, 20, 15), image.Rect(0, 0, 8, 6), image.Rect(20, 10, 31, 23), img.Bounds()} {
+		res, err := ConvolveGrayROI(img, kernel.Normalize(), anchor, padding.BorderReflect, roi)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			expected := img.GrayAt(x, y).Y
+			if (image.Point{X: x, Y: y}).In(roi) {
+				expected = full.GrayAt(x, y).Y
+			}
+			if actual := res.GrayAt(x, y).Y; actual != expected {
+				t.Errorf("Expected: %d - Actual: %d at %d, %d with ROI %v", expected, actual, x, y, roi)
+			}
+		})
+	}
+}
+
+func Test_ConvolveGrayROI_ThinAtEdges(t *testing.T) {
+	img := setupTestCaseROIGray()
+	kernel := &Kernel{[][]float64{
+		{1, 2, 0, 1, 1},
+		{0, 1, 3, 1, 0},
+		{2, 1, 1, 0, 1},
+		{0, 0, 1, 2, 1},
+		{1, 3, 0, 1, 2},
+	}, 5, 5}
+	rois := []image.Rectangle{
+		image.Rect(5, 0, 10, 1), image.Rect(5, 22, 10, 23), image.Rect(0, 5, 1, 10), image.Rect(30, 5, 31, 10),
+		image.Rect(0, 0, 1, 1), image.Rect(30, 22, 31, 23), image.Rect(0, 11, 31, 12), image.Rect(15, 0, 16, 23),
+	}
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		for _, anchor := range []image.Point{{X: 1, Y: 3}, {X: 0, Y: 0}, {X: 4, Y: 4}, {X: 3, Y: 1}} {
+			full, _, err := ConvolveGray(img, kernel.Normalize(), anchor, border)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			for _, roi := range rois {
+				res, err := ConvolveGrayROI(img, kernel.Normalize(), anchor, border, roi)
+				if err != nil {
+					t.Fatalf("Error should not be returned. Error value: %s", err)
+				}
+				utils.ForEachPixel(roi.Size(), func(x, y int) {
+					p := roi.Min.Add(image.Point{X: x, Y: y})
+					if expected, actual := full.GrayAt(p.X, p.Y).Y, res.GrayAt(p.X, p.Y).Y; actual != expected {
+						t.Errorf("Expected: %d - Actual: %d at %v with ROI %v, anchor %v and border %d", expected, actual, p, roi, anchor, border)
+					}
+				})
+			}
+		}
+	}
+}
+
+func Test_ConvolveGrayROIInPlace(t *testing.T) {
+	img := setupTestCaseROIGray()
+	roi := image.Rect(3, 2, 12, 9)
+	expected, err := ConvolveGrayROI(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderConstant, roi)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if err := ConvolveGrayROIInPlace(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderConstant, roi); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, img)
+	if _, err := ConvolveGrayROI(img, setupTestCaseSharpen(), image.Point{X: 1, Y: 1}, padding.BorderConstant, image.Rect(25, 0, 40, 5)); err == nil {
+		t.Error("Expected error for ROI outside of the bounds")
+	}
+}
+
+func Test_ConvolveGrayFloat_LaplacianStep(t *testing.T) {
+	// a vertical step from 50 to 200 between x = 4 and x = 5
+	img := image.NewGray(image.Rect(0, 0, 10, 6))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		value := uint8(50)
+		if x >= 5 {
+			value = 200
+		}
+		img.SetGray(x, y, color.Gray{Y: value})
+	})
+	laplacian := [][]float64{
+		{0, 1, 0},
+		{1, -4, 1},
+		{0, 1, 0},
+	}
+	response, err := ConvolveGrayFloat(img, laplacian, image.Point{X: 1, Y: 1}, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	clamped, _, err := ConvolveGray(img, &Kernel{laplacian, 3, 3}, image.Point{X: 1, Y: 1}, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 10; x++ {
+			expected := 0.0
+			if x == 4 {
+				expected = 150
+			} else if x == 5 {
+				expected = -150
+			}
+			if !utils.IsEqualFloat64(response[x][y], expected) {
+				t.Fatalf("Expected: %f - Actual: %f at %d, %d", expected, response[x][y], x, y)
+			}
+			if expected := uint8(utils.ClampF64(expected, 0, 255)); clamped.GrayAt(x, y).Y != expected {
+				t.Fatalf("Expected the clamped response %d - Actual: %d at %d, %d", expected, clamped.GrayAt(x, y).Y, x, y)
+			}
+		}
+	}
+	if _, err := ConvolveGrayFloat(img, [][]float64{{1, 2}, {3}}, image.Point{}, padding.BorderReplicate); err == nil {
+		t.Error("Expected error for a kernel which is not rectangular")
+	}
+	if _, err := ConvolveGrayFloat(img, nil, image.Point{}, padding.BorderReplicate); err == nil {
+		t.Error("Expected error for an empty kernel")
+	}
+}
+
 // -------------------------------------------------------------------------------
+
+func Benchmark_ConvolveGray_3x3(b *testing.B) {
+	img := image.NewGray(image.Rect(0, 0, 1024, 1024))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7)
+	}
+	kernel := setupTestCaseSharpen()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ConvolveGray(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	}
+}
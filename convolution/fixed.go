@@ -0,0 +1,50 @@
+package convolution
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// ConvolveGrayFixed applies an integer convolution matrix to a grayscale image using only integer arithmetic, so the
+// result is exactly the same on every platform. The kernel is indexed as kernel[x][y] and anchored at its center. The
+// weighted sum of every pixel is rounded and shifted right by shift bits, which divides it by 2^shift, and clamped to
+// the [0, 255] interval. A normalized kernel is given by weights which sum up to 2^shift, e.g. a 4x4 box kernel of
+// ones with a shift of 4. Returns an error if the kernel is empty or not rectangular, or the border type is unknown.
+// Example of usage:
+//
+//	res, err := convolution.ConvolveGrayFixed(img, [][]int{{1, 2, 1}, {2, 4, 2}, {1, 2, 1}}, 4, padding.BorderReflect)
+func ConvolveGrayFixed(img *image.Gray, kernel [][]int, shift uint, border padding.Border) (*image.Gray, error) {
+	if len(kernel) == 0 || len(kernel[0]) == 0 {
+		return nil, errors.New("empty kernel")
+	}
+	for _, column := range kernel {
+		if len(column) != len(kernel[0]) {
+			return nil, errors.New("the kernel is not rectangular")
+		}
+	}
+	kernelSize := image.Point{X: len(kernel), Y: len(kernel[0])}
+	anchor := image.Point{X: kernelSize.X / 2, Y: kernelSize.Y / 2}
+	padded, err := padding.PaddingGray(img, kernelSize, anchor, border)
+	if err != nil {
+		return nil, err
+	}
+	var rounding int64
+	if shift > 0 {
+		rounding = 1 << (shift - 1)
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(img.Bounds())
+	utils.ParallelForEachPixel(size, func(x int, y int) {
+		var sum int64
+		for ky := 0; ky < kernelSize.Y; ky++ {
+			row := padded.Pix[(y+ky)*padded.Stride+x:]
+			for kx := 0; kx < kernelSize.X; kx++ {
+				sum += int64(row[kx]) * int64(kernel[kx][ky])
+			}
+		}
+		res.Pix[y*res.Stride+x] = uint8(utils.ClampInt(int((sum+rounding)>>shift), utils.MinUint8, int(utils.MaxUint8)))
+	})
+	return res, nil
+}
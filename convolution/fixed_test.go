@@ -0,0 +1,83 @@
+package convolution
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ConvolveGrayFixed_Box(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 41, 33))
+	rand.New(rand.NewSource(7)).Read(img.Pix)
+	for _, size := range []int{2, 4, 8} {
+		ones := make([][]int, size)
+		for x := range ones {
+			ones[x] = make([]int, size)
+			for y := range ones[x] {
+				ones[x][y] = 1
+			}
+		}
+		var shift uint
+		for 1<<shift < size*size {
+			shift++
+		}
+		res, err := ConvolveGrayFixed(img, ones, shift, padding.BorderReflect)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		box, _ := NewBoxKernel(size)
+		expected, _, err := ConvolveGray(img, box, image.Point{X: size / 2, Y: size / 2}, padding.BorderReflect)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if res.Bounds() != expected.Bounds() {
+			t.Fatalf("Expected bounds: %v - actual bounds: %v", expected.Bounds(), res.Bounds())
+		}
+		for i := range res.Pix {
+			if d := int(res.Pix[i]) - int(expected.Pix[i]); d < -1 || d > 1 {
+				t.Fatalf("Expected value: %d - actual value: %d at index: %d with box size: %d", expected.Pix[i], res.Pix[i], i, size)
+			}
+		}
+	}
+}
+
+func Test_ConvolveGrayFixed_Clamp(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(60 * (i % 5))
+	}
+	// a horizontal derivative with both negative and too large responses
+	res, err := ConvolveGrayFixed(img, [][]int{{-4}, {0}, {4}}, 1, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	inverse, err := ConvolveGrayFixed(img, [][]int{{4}, {0}, {-4}}, 1, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// 2 * (right - left) with the replicated border
+	expected := []uint8{120, 240, 240, 240, 120}
+	stronger, _ := ConvolveGrayFixed(img, [][]int{{-4}, {0}, {4}}, 0, padding.BorderReplicate)
+	for x, v := range expected {
+		if actual := res.GrayAt(x, 2).Y; actual != v {
+			t.Errorf("Expected value: %d - actual value: %d at: %d 2", v, actual, x)
+		}
+		if actual := stronger.GrayAt(x, 2).Y; int(actual) != utils.ClampInt(2*int(v), 0, 255) {
+			t.Errorf("Expected value: %d - actual value: %d at: %d 2 without the shift", utils.ClampInt(2*int(v), 0, 255), actual, x)
+		}
+		if actual := inverse.GrayAt(x, 2).Y; actual != 0 {
+			t.Errorf("Expected the negative response clamped to 0 - actual value: %d at: %d 2", actual, x)
+		}
+	}
+	if _, err := ConvolveGrayFixed(img, [][]int{}, 0, padding.BorderReflect); err == nil {
+		t.Error("Expected error for an empty kernel")
+	}
+	if _, err := ConvolveGrayFixed(img, [][]int{{1, 1}, {1}}, 0, padding.BorderReflect); err == nil {
+		t.Error("Expected error for a kernel which is not rectangular")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,92 @@
+package convolution
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"image"
+	"math"
+)
+
+// NewGaborKernel creates a size x size kernel from the real part of the Gabor function:
+//
+//	exp(-(x'^2 + gamma^2 * y'^2) / (2 * sigma^2)) * cos(2 * pi * x' / lambda + psi)
+//	x' = x * cos(theta) + y * sin(theta), y' = -x * sin(theta) + y * cos(theta)
+//
+// where theta (in radians) is the direction of the normal of the stripes the kernel responds to, lambda is their
+// wavelength in pixels, sigma is the size of the Gaussian envelope, gamma is its aspect ratio and psi is the phase
+// offset. The mean of the kernel is subtracted, so flat regions give zero response, and the kernel is normalized by its
+// absolute sum. The size has to be a positive odd number, sigma, lambda and gamma have to be positive.
+// Example of usage:
+//
+//	kernel, err := convolution.NewGaborKernel(21, 4.5, math.Pi/4, 8, 0.5, 0)
+func NewGaborKernel(size int, sigma, theta, lambda, gamma, psi float64) (*Kernel, error) {
+	if size <= 0 || size%2 == 0 {
+		return nil, errors.New("kernel size must be a positive odd number")
+	}
+	if sigma <= 0 || lambda <= 0 || gamma <= 0 {
+		return nil, errors.New("sigma, lambda and gamma must be bigger then 0")
+	}
+	kernel, _ := NewKernel(size, size)
+	sin, cos := math.Sincos(theta)
+	half := size / 2
+	var sum float64
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			dx, dy := float64(x-half), float64(y-half)
+			xr := dx*cos + dy*sin
+			yr := -dx*sin + dy*cos
+			v := math.Exp(-(xr*xr+gamma*gamma*yr*yr)/(2*sigma*sigma)) * math.Cos(2*math.Pi*xr/lambda+psi)
+			kernel.Set(x, y, v)
+			sum += v
+		}
+	}
+	mean := sum / float64(size*size)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			kernel.Set(x, y, kernel.At(x, y)-mean)
+		}
+	}
+	return kernel.Normalize(), nil
+}
+
+// GaborBank creates a bank of Gabor kernels for every combination of the given sizes, orientations (in radians) and
+// wavelengths. The kernels are ordered by size, then by orientation and then by wavelength. Every kernel uses a
+// Gaussian envelope with sigma = 0.56 * lambda (one octave bandwidth), aspect ratio 0.5 and phase 0. See
+// NewGaborKernel.
+// Example of usage:
+//
+//	bank, err := convolution.GaborBank([]int{21}, []float64{0, math.Pi / 4, math.Pi / 2, 3 * math.Pi / 4}, []float64{6, 12})
+func GaborBank(sizes []int, thetas, lambdas []float64) ([]*Kernel, error) {
+	var bank []*Kernel
+	for _, size := range sizes {
+		for _, theta := range thetas {
+			for _, lambda := range lambdas {
+				kernel, err := NewGaborKernel(size, 0.56*lambda, theta, lambda, 0.5, 0)
+				if err != nil {
+					return nil, err
+				}
+				bank = append(bank, kernel)
+			}
+		}
+	}
+	return bank, nil
+}
+
+// ApplyGaborBankGray convolves a grayscale image with every kernel of a Gabor bank (see GaborBank) and returns the
+// response images in the order of the bank. The kernels are centered on the output pixels, the border of the image is
+// handled as in BorderReflect and the negative responses are clamped to 0.
+// Example of usage:
+//
+//	responses, err := convolution.ApplyGaborBankGray(img, bank)
+func ApplyGaborBankGray(img *image.Gray, bank []*Kernel) ([]*image.Gray, error) {
+	res := make([]*image.Gray, len(bank))
+	for i, kernel := range bank {
+		anchor := image.Point{X: kernel.Width / 2, Y: kernel.Height / 2}
+		response, _, err := ConvolveGray(img, kernel, anchor, padding.BorderReflect)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = response
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package convolution
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_NewGaborKernel(t *testing.T) {
+	kernel, err := NewGaborKernel(15, 3, math.Pi/6, 6, 0.5, 0.3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	var sum float64
+	for x := 0; x < 15; x++ {
+		for y := 0; y < 15; y++ {
+			sum += kernel.At(x, y)
+		}
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("Expected a zero-mean kernel - actual sum: %f", sum)
+	}
+	if !utils.IsEqualFloat64(kernel.AbSum(), 1) {
+		t.Errorf("Expected a kernel with absolute sum 1 - actual: %f", kernel.AbSum())
+	}
+	for _, args := range [][6]float64{{4, 3, 0, 6, 0.5, 0}, {5, 0, 0, 6, 0.5, 0}, {5, 3, 0, 0, 0.5, 0}, {5, 3, 0, 6, 0, 0}} {
+		if _, err := NewGaborKernel(int(args[0]), args[1], args[2], args[3], args[4], args[5]); err == nil {
+			t.Errorf("Expected error for the arguments: %v", args)
+		}
+	}
+}
+
+func Test_ApplyGaborBankGray_Grating(t *testing.T) {
+	// vertical stripes: the intensity changes along x with a wavelength of 8 pixels
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: uint8(128 + 100*math.Cos(2*math.Pi*float64(x)/8))})
+	})
+	bank, err := GaborBank([]int{21}, []float64{0, math.Pi / 2}, []float64{8, 3})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(bank) != 4 {
+		t.Fatalf("Expected 4 kernels - actual: %d", len(bank))
+	}
+	responses, err := ApplyGaborBankGray(img, bank)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	mean := func(res *image.Gray) float64 {
+		var sum float64
+		for x := 16; x < 48; x++ {
+			for y := 16; y < 48; y++ {
+				sum += float64(res.GrayAt(x, y).Y)
+			}
+		}
+		return sum / (32 * 32)
+	}
+	// order: {0, 8}, {0, 3}, {pi/2, 8}, {pi/2, 3}
+	matched := mean(responses[0])
+	for i := 1; i < 4; i++ {
+		if m := mean(responses[i]); m >= matched/4 {
+			t.Errorf("Expected the matching kernel to respond the most - matched: %f, kernel %d: %f", matched, i, m)
+		}
+	}
+	if orthogonal := mean(responses[2]); orthogonal > 1 {
+		t.Errorf("Expected a near zero response to the orthogonal kernel - actual: %f", orthogonal)
+	}
+}
+
+func Test_ApplyGaborBankGray_Flat(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 20))
+	for i := range img.Pix {
+		img.Pix[i] = 0x90
+	}
+	bank, err := GaborBank([]int{9, 11}, []float64{0, math.Pi / 3}, []float64{5})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	responses, err := ApplyGaborBankGray(img, bank)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, res := range responses {
+		for _, v := range res.Pix {
+			if v != 0 {
+				t.Fatalf("Expected zero response on a flat image - actual: %d for kernel %d", v, i)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -19,13 +19,14 @@ type Kernel struct {
 }
 
 // NewKernel creates a new Kernel with the given width and height. The value for every position of the kernel is 0.
+// The content is indexed as Content[x][y], like in the convolution functions.
 func NewKernel(width int, height int) (*Kernel, error) {
 	if width < 0 || height < 0 {
 		return nil, errors.New("negative kernel size")
 	}
-	m := make([][]float64, height)
+	m := make([][]float64, width)
 	for i := range m {
-		m[i] = make([]float64, width)
+		m[i] = make([]float64, height)
 	}
 	return &Kernel{Content: m, Width: width, Height: height}, nil
 }
@@ -48,8 +49,8 @@ func (k *Kernel) Size() image.Point {
 // AbSum returns the sum of every absolute value from a kernel.
 func (k *Kernel) AbSum() float64 {
 	var sum float64
-	for x := 0; x < k.Height; x++ {
-		for y := 0; y < k.Width; y++ {
+	for x := 0; x < k.Width; x++ {
+		for y := 0; y < k.Height; y++ {
 			sum += math.Abs(k.At(x, y))
 		}
 	}
@@ -64,10 +65,132 @@ func (k *Kernel) Normalize() *Kernel {
 		sum = 1
 
 	}
-	for x := 0; x < k.Height; x++ {
-		for y := 0; y < k.Width; y++ {
+	for x := 0; x < k.Width; x++ {
+		for y := 0; y < k.Height; y++ {
 			normalized.Set(x, y, k.At(x, y)/sum)
 		}
 	}
 	return normalized
 }
+
+// NewGaussianKernel creates a size x size Gaussian kernel with the given sigma. The size has to be a positive odd
+// number and sigma has to be positive. The kernel is normalized after sampling, so the sum of its values is 1 even for
+// small sigma values.
+// Example of usage:
+//
+//	kernel, err := convolution.NewGaussianKernel(5, 1.0)
+func NewGaussianKernel(size int, sigma float64) (*Kernel, error) {
+	if size <= 0 || size%2 == 0 {
+		return nil, errors.New("kernel size must be a positive odd number")
+	}
+	if sigma <= 0 {
+		return nil, errors.New("sigma must be bigger then 0")
+	}
+	kernel, _ := NewKernel(size, size)
+	center := float64(size / 2)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			dx := float64(x) - center
+			dy := float64(y) - center
+			kernel.Set(x, y, math.Exp(-(dx*dx+dy*dy)/(2*sigma*sigma)))
+		}
+	}
+	return kernel.Normalize(), nil
+}
+
+// NewBoxKernel creates a size x size normalized box (average) kernel, where every value is 1 / (size * size).
+func NewBoxKernel(size int) (*Kernel, error) {
+	if size <= 0 {
+		return nil, errors.New("kernel size must be positive")
+	}
+	kernel, _ := NewKernel(size, size)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			kernel.Set(x, y, 1.0/float64(size*size))
+		}
+	}
+	return kernel, nil
+}
+
+// NewLaplacianKernel creates a 3x3 Laplacian kernel. The variant specifies the connectivity of the kernel:
+//
+//	4: {0, 1, 0}, {1, -4, 1}, {0, 1, 0}
+//	8: {1, 1, 1}, {1, -8, 1}, {1, 1, 1}
+func NewLaplacianKernel(variant int) (*Kernel, error) {
+	switch variant {
+	case 4:
+		return &Kernel{Content: [][]float64{
+			{0, 1, 0},
+			{1, -4, 1},
+			{0, 1, 0},
+		}, Width: 3, Height: 3}, nil
+	case 8:
+		return &Kernel{Content: [][]float64{
+			{1, 1, 1},
+			{1, -8, 1},
+			{1, 1, 1},
+		}, Width: 3, Height: 3}, nil
+	}
+	return nil, errors.New("invalid laplacian kernel variant")
+}
+
+// NewSobelXKernel creates the 3x3 Sobel kernel which computes the gradient along the x axis, the value at position
+// {x, y} is given by At(x, y).
+func NewSobelXKernel() *Kernel {
+	return &Kernel{Content: [][]float64{
+		{-1, -2, -1},
+		{0, 0, 0},
+		{1, 2, 1},
+	}, Width: 3, Height: 3}
+}
+
+// NewSobelYKernel creates the 3x3 Sobel kernel which computes the gradient along the y axis, the value at position
+// {x, y} is given by At(x, y).
+func NewSobelYKernel() *Kernel {
+	return &Kernel{Content: [][]float64{
+		{-1, 0, 1},
+		{-2, 0, 2},
+		{-1, 0, 1},
+	}, Width: 3, Height: 3}
+}
+
+// NewSharpenKernel creates the 3x3 sharpen kernel: {0, -1, 0}, {-1, 5, -1}, {0, -1, 0}.
+func NewSharpenKernel() *Kernel {
+	return &Kernel{Content: [][]float64{
+		{0, -1, 0},
+		{-1, 5, -1},
+		{0, -1, 0},
+	}, Width: 3, Height: 3}
+}
+
+// ComposeKernels combines two kernels indexed as kernel[x][y] into one equivalent kernel, the full convolution of the
+// two: convolving an image with the result gives the same as convolving it with a and then with b, up to the border
+// effects, with one pass instead of two. The size of the result is the sum of the sizes minus 1 and the anchor of the
+// result is the sum of the anchors of a and b, e.g. the centers for odd sized kernels. Returns an error if one of the
+// kernels is empty or not rectangular.
+// Example of usage:
+//
+//	kernel, err := convolution.ComposeKernels(box.Content, sharpen.Content)
+func ComposeKernels(a, b [][]float64) ([][]float64, error) {
+	if err := validateKernel(a); err != nil {
+		return nil, err
+	}
+	if err := validateKernel(b); err != nil {
+		return nil, err
+	}
+	width, height := len(a)+len(b)-1, len(a[0])+len(b[0])-1
+	res := make([][]float64, width)
+	for x := range res {
+		res[x] = make([]float64, height)
+	}
+	for ax := range a {
+		for ay, av := range a[ax] {
+			for bx := range b {
+				for by, bv := range b[bx] {
+					res[ax+bx][ay+by] += av * bv
+				}
+			}
+		}
+	}
+	return res, nil
+}
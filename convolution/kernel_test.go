@@ -0,0 +1,196 @@
+package convolution
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func kernelSum(k *Kernel) float64 {
+	var sum float64
+	for x := 0; x < k.Width; x++ {
+		for y := 0; y < k.Height; y++ {
+			sum += k.At(x, y)
+		}
+	}
+	return sum
+}
+
+func Test_NewKernel_NonSquare(t *testing.T) {
+	kernel, err := NewKernel(3, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 3; x++ {
+		kernel.Set(x, 0, float64(x+1))
+	}
+	normalized := kernel.Normalize()
+	if len(normalized.Content) != 3 || len(normalized.Content[0]) != 1 || !utils.IsEqualFloat64(kernelSum(normalized), 1) {
+		t.Errorf("Invalid normalized kernel: %v", normalized.Content)
+	}
+	if !utils.IsEqualFloat64(normalized.At(2, 0), 0.5) {
+		t.Errorf("Expected: 0.5 - actual: %f at 2, 0", normalized.At(2, 0))
+	}
+}
+
+func Test_NewGaussianKernel(t *testing.T) {
+	for _, sigma := range []float64{0.1, 0.5, 1, 3} {
+		kernel, err := NewGaussianKernel(5, sigma)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if !utils.IsEqualFloat64(kernelSum(kernel), 1) {
+			t.Errorf("Expected sum: 1 - actual sum: %f for sigma: %f", kernelSum(kernel), sigma)
+		}
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				v := kernel.At(x, y)
+				if !utils.IsEqualFloat64(v, kernel.At(4-x, y)) || !utils.IsEqualFloat64(v, kernel.At(x, 4-y)) ||
+					!utils.IsEqualFloat64(v, kernel.At(y, x)) {
+					t.Errorf("Kernel is not symmetric at: %d %d for sigma: %f", x, y, sigma)
+				}
+			}
+		}
+	}
+	kernel, _ := NewGaussianKernel(3, 1)
+	e := math.Exp(-0.5)
+	sum := 1 + 4*e + 4*e*e
+	expected := [][]float64{
+		{e * e / sum, e / sum, e * e / sum},
+		{e / sum, 1 / sum, e / sum},
+		{e * e / sum, e / sum, e * e / sum},
+	}
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if !utils.IsEqualFloat64(expected[x][y], kernel.At(x, y)) {
+				t.Errorf("Expected: %f - actual: %f at: %d %d", expected[x][y], kernel.At(x, y), x, y)
+			}
+		}
+	}
+}
+
+func Test_NewGaussianKernel_Invalid(t *testing.T) {
+	if _, err := NewGaussianKernel(4, 1); err == nil {
+		t.Error("Expected error for even kernel size")
+	}
+	if _, err := NewGaussianKernel(3, 0); err == nil {
+		t.Error("Expected error for zero sigma")
+	}
+}
+
+func Test_NewBoxKernel(t *testing.T) {
+	kernel, err := NewBoxKernel(3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !utils.IsEqualFloat64(kernelSum(kernel), 1) || !utils.IsEqualFloat64(kernel.At(1, 2), 1.0/9) {
+		t.Errorf("Invalid box kernel: %v", kernel.Content)
+	}
+	if _, err := NewBoxKernel(0); err == nil {
+		t.Error("Expected error for zero kernel size")
+	}
+}
+
+func Test_NewLaplacianKernel(t *testing.T) {
+	k4, _ := NewLaplacianKernel(4)
+	k8, _ := NewLaplacianKernel(8)
+	if k4.At(1, 1) != -4 || k4.At(0, 0) != 0 || k4.At(0, 1) != 1 || kernelSum(k4) != 0 {
+		t.Errorf("Invalid Laplacian K4 kernel: %v", k4.Content)
+	}
+	if k8.At(1, 1) != -8 || k8.At(0, 0) != 1 || kernelSum(k8) != 0 {
+		t.Errorf("Invalid Laplacian K8 kernel: %v", k8.Content)
+	}
+	if _, err := NewLaplacianKernel(6); err == nil {
+		t.Error("Expected error for invalid variant")
+	}
+}
+
+func Test_NewSobelKernels(t *testing.T) {
+	sx := NewSobelXKernel()
+	sy := NewSobelYKernel()
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if sx.At(x, y) != sy.At(y, x) {
+				t.Errorf("Sobel X should be the transpose of Sobel Y at: %d %d", x, y)
+			}
+		}
+	}
+	if sx.At(2, 1) != 2 || sx.At(0, 1) != -2 || sx.At(1, 1) != 0 || kernelSum(sx) != 0 {
+		t.Errorf("Invalid Sobel X kernel: %v", sx.Content)
+	}
+}
+
+func Test_NewSharpenKernel(t *testing.T) {
+	k := NewSharpenKernel()
+	if k.At(1, 1) != 5 || k.At(0, 1) != -1 || k.At(0, 0) != 0 || kernelSum(k) != 1 {
+		t.Errorf("Invalid sharpen kernel: %v", k.Content)
+	}
+}
+
+func Test_ComposeKernels_Boxes(t *testing.T) {
+	box, _ := NewBoxKernel(3)
+	res, err := ComposeKernels(box.Content, box.Content)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// two box filters give the triangular kernel 1 2 3 2 1 in both directions
+	triangle := []float64{1, 2, 3, 2, 1}
+	if len(res) != 5 || len(res[0]) != 5 {
+		t.Fatalf("Expected a 5x5 kernel - actual: %dx%d", len(res), len(res[0]))
+	}
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			if expected := triangle[x] * triangle[y] / 81; !utils.IsEqualFloat64(expected, res[x][y]) {
+				t.Errorf("Expected value: %f - actual value: %f at: %d %d", expected, res[x][y], x, y)
+			}
+		}
+	}
+	if _, err := ComposeKernels(box.Content, [][]float64{}); err == nil {
+		t.Error("Expected error for an empty kernel")
+	}
+	if _, err := ComposeKernels([][]float64{{1, 2}, {3}}, box.Content); err == nil {
+		t.Error("Expected error for a kernel which is not rectangular")
+	}
+}
+
+func Test_ComposeKernels_Sequential(t *testing.T) {
+	size := image.Point{X: 21, Y: 17}
+	rnd := rand.New(rand.NewSource(9))
+	plane := make([]float64, size.X*size.Y)
+	for i := range plane {
+		plane[i] = rnd.Float64() * 255
+	}
+	a := [][]float64{{1, 2, 0.5}, {-1, 3, 2}, {0, 1, -2}}
+	b := [][]float64{{0.5, -1, 2}, {1, 0.25, -0.5}}
+	anchorA, anchorB := image.Point{X: 1, Y: 1}, image.Point{X: 0, Y: 2}
+	composed, err := ComposeKernels(a, b)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	first, err := ConvolvePlane(plane, size, a, anchorA, padding.BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	sequential, err := ConvolvePlane(first, size, b, anchorB, padding.BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	once, err := ConvolvePlane(plane, size, composed, anchorA.Add(anchorB), padding.BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the results differ only near the border, where the first result was padded
+	for y := 4; y < size.Y-4; y++ {
+		for x := 4; x < size.X-4; x++ {
+			if i := y*size.X + x; math.Abs(sequential[i]-once[i]) > 1e-9 {
+				t.Fatalf("Expected value: %f - actual value: %f at: %d %d", sequential[i], once[i], x, y)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,52 @@
+package convolution
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// ConvolvePlane applies a convolution matrix to a single channel plane of floating point values stored in row-major
+// order, e.g. a channel of a multispectral image. It works like ConvolveGrayFloat: the kernel is indexed as
+// kernel[x][y], the anchor is the position of the kernel which is placed on the output pixel and the values are not
+// clamped. The values outside of the plane are taken according to the border type (BorderConstant treats them as 0).
+// Returns an error if the length of the plane does not match its size, the kernel is empty or not rectangular, the
+// anchor is outside of the kernel or the border type is unknown.
+// Example of usage:
+//
+//	res, err := convolution.ConvolvePlane(plane, image.Point{X: 640, Y: 480}, kernel.Content, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+func ConvolvePlane(plane []float64, size image.Point, kernel [][]float64, anchor image.Point, border padding.Border) ([]float64, error) {
+	if size.X < 0 || size.Y < 0 || len(plane) != size.X*size.Y {
+		return nil, errors.New("the length of the plane does not match its size")
+	}
+	if err := validateKernel(kernel); err != nil {
+		return nil, err
+	}
+	kernelSize := image.Point{X: len(kernel), Y: len(kernel[0])}
+	if anchor.X < 0 || anchor.Y < 0 || anchor.X >= kernelSize.X || anchor.Y >= kernelSize.Y {
+		return nil, errors.New("anchor value outside of the kernel")
+	}
+	if border != padding.BorderConstant && border != padding.BorderReplicate && border != padding.BorderReflect {
+		return nil, errors.New("unknown border type")
+	}
+	res := make([]float64, len(plane))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum float64
+		for ky := 0; ky < kernelSize.Y; ky++ {
+			sy, ok := padding.BorderIndex(y+ky-anchor.Y, size.Y, border)
+			if !ok {
+				continue
+			}
+			for kx := 0; kx < kernelSize.X; kx++ {
+				sx, ok := padding.BorderIndex(x+kx-anchor.X, size.X, border)
+				if !ok {
+					continue
+				}
+				sum += plane[sy*size.X+sx] * kernel[kx][ky]
+			}
+		}
+		res[y*size.X+x] = sum
+	})
+	return res, nil
+}
@@ -0,0 +1,60 @@
+package convolution
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ConvolvePlane_MatchesGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 13, 9))
+	rand.New(rand.NewSource(3)).Read(img.Pix)
+	plane := make([]float64, len(img.Pix))
+	for i, v := range img.Pix {
+		plane[i] = float64(v)
+	}
+	kernel := [][]float64{{1, -2, 0.5}, {0, 3, -1}}
+	size := img.Bounds().Size()
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		for _, anchor := range []image.Point{{X: 0, Y: 0}, {X: 1, Y: 2}} {
+			expected, err := ConvolveGrayFloat(img, kernel, anchor, border)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			actual, err := ConvolvePlane(plane, size, kernel, anchor, border)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			for x := 0; x < size.X; x++ {
+				for y := 0; y < size.Y; y++ {
+					if !utils.IsEqualFloat64(expected[x][y], actual[y*size.X+x]) {
+						t.Fatalf("Expected value: %f - actual value: %f at: %d %d (border %d, anchor %v)", expected[x][y], actual[y*size.X+x], x, y, border, anchor)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_ConvolvePlane_Invalid(t *testing.T) {
+	plane := make([]float64, 6)
+	size := image.Point{X: 3, Y: 2}
+	kernel := [][]float64{{1}}
+	if _, err := ConvolvePlane(plane, image.Point{X: 4, Y: 2}, kernel, image.Point{}, padding.BorderReflect); err == nil {
+		t.Error("Expected error for a size mismatch")
+	}
+	if _, err := ConvolvePlane(plane, size, [][]float64{}, image.Point{}, padding.BorderReflect); err == nil {
+		t.Error("Expected error for an empty kernel")
+	}
+	if _, err := ConvolvePlane(plane, size, kernel, image.Point{X: 1}, padding.BorderReflect); err == nil {
+		t.Error("Expected error for an anchor outside of the kernel")
+	}
+	if _, err := ConvolvePlane(plane, size, kernel, image.Point{}, padding.Border(-1)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
+// -------------------------------------------------------------------------------
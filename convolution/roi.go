@@ -0,0 +1,71 @@
+package convolution
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// ConvolveGrayROI applies a convolution matrix (kernel) only to the given region of interest of a grayscale image. The
+// result is a copy of the input image where the pixels inside of the ROI are filtered and all the other pixels are
+// left untouched. The neighbourhood of the ROI is read from the real pixels of the image, the border type is used only
+// at the edges of the image, so the ROI gets the same values as a convolution of the whole image would give.
+// Returns an error if the ROI is empty or it is not inside of the bounds of the image.
+// Example of usage:
+//
+//	res, err := convolution.ConvolveGrayROI(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect, roi)
+func ConvolveGrayROI(img *image.Gray, kernel *Kernel, anchor image.Point, border padding.Border, roi image.Rectangle) (*image.Gray, error) {
+	res := image.NewGray(img.Bounds())
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		copy(res.Pix[y*res.Stride:y*res.Stride+size.X], img.Pix[y*img.Stride:y*img.Stride+size.X])
+	}
+	if err := ConvolveGrayROIInPlace(res, kernel, anchor, border, roi); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ConvolveGrayROIInPlace works like ConvolveGrayROI, but it writes the filtered pixels of the ROI directly into the
+// input image.
+// Example of usage:
+//
+//	err := convolution.ConvolveGrayROIInPlace(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect, roi)
+func ConvolveGrayROIInPlace(img *image.Gray, kernel *Kernel, anchor image.Point, border padding.Border, roi image.Rectangle) error {
+	if err := utils.ValidateROI(img.Bounds(), roi); err != nil {
+		return err
+	}
+	// the context is expanded by the same margin on every side, because at the edges of the image the border type
+	// (e.g. BorderReflect) mirrors the taps of one side into the pixels on the other side of the ROI
+	kernelSize := kernel.Size()
+	margin := image.Point{X: kernelMargin(kernelSize.X, anchor.X), Y: kernelMargin(kernelSize.Y, anchor.Y)}
+	context := image.Rectangle{Min: roi.Min.Sub(margin), Max: roi.Max.Add(margin)}.Intersect(img.Bounds())
+	filtered, _, err := ConvolveGray(cropGray(img, context), kernel, anchor, border)
+	if err != nil {
+		return err
+	}
+	offset := roi.Min.Sub(context.Min)
+	for y := 0; y < roi.Dy(); y++ {
+		src := filtered.Pix[(offset.Y+y)*filtered.Stride+offset.X:]
+		copy(img.Pix[img.PixOffset(roi.Min.X, roi.Min.Y+y):img.PixOffset(roi.Max.X, roi.Min.Y+y)], src[:roi.Dx()])
+	}
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// kernelMargin returns the larger of the two distances between the anchor and the ends of the kernel.
+func kernelMargin(kernelSize int, anchor int) int {
+	if after := kernelSize - anchor - 1; after > anchor {
+		return after
+	}
+	return anchor
+}
+
+// cropGray copies the given rectangle of the image into a new image which starts at the origin.
+func cropGray(img *image.Gray, rect image.Rectangle) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	for y := 0; y < rect.Dy(); y++ {
+		copy(res.Pix[y*res.Stride:y*res.Stride+rect.Dx()], img.Pix[img.PixOffset(rect.Min.X, rect.Min.Y+y):])
+	}
+	return res
+}
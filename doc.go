@@ -1,2 +1,7 @@
 // Package Imger contains a collection of image processing algorithms written in pure Go.
+//
+// The functions never modify their input images and their results never share memory with the inputs, so the same
+// decoded image can be processed from multiple goroutines at once. The only exceptions are the functions documented to
+// write into one of their arguments, like convolution.ConvolveGrayROIInPlace, resize.ResizeIntoRGBA and
+// utils.CopyToGray. Use utils.CloneGray, utils.CloneRGBA or utils.CloneNRGBA to get a private copy of an image.
 package Imger
@@ -3,63 +3,148 @@ package edgedetection
 import (
 	"errors"
 	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/convolution"
 	"github.com/yafeiliu/imger/grayscale"
 	"github.com/yafeiliu/imger/padding"
 	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
 	"math"
+	"sync"
 )
 
 // CannyGray computes the edges of a given grayscale image using the Canny edge detection algorithm. The returned image
 // is a grayscale image represented on 8 bits.
 func CannyGray(img *image.Gray, lower float64, upper float64, kernelSize uint) (*image.Gray, error) {
+	return CannyGrayWithSigma(img, lower, upper, kernelSize, 1)
+}
 
-	// blur the image using Gaussian filter
-	blurred, _, err := blur.GaussianBlurGray(img, float64(kernelSize), 1, padding.BorderConstant)
-	if err != nil {
+// CannyGrayWithSigma works like CannyGray, but the sigma of the Gaussian blur applied before the gradient computation
+// can be chosen. A sigma smaller or equal to 0 skips the blur step, which is useful when the image is already
+// denoised and gives sharper edges.
+// Example of usage:
+//
+//	res, err := edgedetection.CannyGrayWithSigma(img, 15, 45, 5, 0)
+func CannyGrayWithSigma(img *image.Gray, lower float64, upper float64, kernelSize uint, sigma float64) (*image.Gray, error) {
+	return cannyGray(img, lower, upper, kernelSize, sigma, 3)
+}
+
+// CannyGrayWithAperture works like CannyGray, but the size of the Sobel operator used for the gradient computation can
+// be chosen. The supported aperture sizes are 3, 5 and 7, the larger apertures smooth the image along the edges, which
+// gives fewer spurious edges on noisy images. The larger Sobel kernels are scaled to the weight of the 3x3 one, so the
+// same thresholds can be used for every aperture.
+// Example of usage:
+//
+//	res, err := edgedetection.CannyGrayWithAperture(img, 15, 45, 5, 5)
+func CannyGrayWithAperture(img *image.Gray, lower float64, upper float64, kernelSize uint, apertureSize int) (*image.Gray, error) {
+	return cannyGray(img, lower, upper, kernelSize, 1, apertureSize)
+}
+
+// CannyRGBA computes the edges of a given RGBA image using the Canny edge detection algorithm. The returned image is a
+// grayscale image represented on 8 bits.
+func CannyRGBA(img *image.RGBA, lower float64, upper float64, kernelSize uint) (*image.Gray, error) {
+	return CannyGray(grayscale.Grayscale(img), lower, upper, kernelSize)
+}
+
+// CannyGrayInto works like CannyGray, but the edges are written into dst instead of a newly allocated image, which
+// must have the same size as src. The intermediate images of the pipeline are recycled between the calls, so running
+// the detector on every frame of a video stream allocates much less. Returns an error if the sizes do not match.
+// Example of usage:
+//
+//	dst := image.NewGray(src.Bounds())
+//	err := edgedetection.CannyGrayInto(dst, src, 15, 45, 5)
+func CannyGrayInto(dst *image.Gray, src *image.Gray, lower float64, upper float64, kernelSize uint) error {
+	if !dst.Bounds().Size().Eq(src.Bounds().Size()) {
+		return errors.New("the size of the two image does not match")
+	}
+	return cannyGrayInto(dst, src, lower, upper, kernelSize, 1, 3)
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// cannyPool and cannyFloatPool hold the buffers of the intermediate images and of the gradient maps of the Canny
+// pipeline, they are shared by all the calls.
+var (
+	cannyPool      = utils.NewBufferPool()
+	cannyFloatPool sync.Pool
+)
+
+func cannyGray(img *image.Gray, lower float64, upper float64, kernelSize uint, sigma float64, apertureSize int) (*image.Gray, error) {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	if err := cannyGrayInto(res, img, lower, upper, kernelSize, sigma, apertureSize); err != nil {
 		return nil, err
 	}
+	return res, nil
+}
+
+func cannyGrayInto(dst *image.Gray, img *image.Gray, lower float64, upper float64, kernelSize uint, sigma float64, apertureSize int) error {
+	sobelX, sobelY, err := sobelKernels(apertureSize)
+	if err != nil {
+		return err
+	}
+	anchor := image.Point{X: apertureSize / 2, Y: apertureSize / 2}
+	withPool := utils.WithPool(cannyPool)
+
+	// blur the image using Gaussian filter
+	blurred := img
+	if sigma > 0 {
+		blurred, _, err = blur.GaussianBlurGray(img, float64(kernelSize), sigma, padding.BorderConstant, withPool)
+		if err != nil {
+			return err
+		}
+		defer cannyPool.PutGray(blurred)
+	}
 
 	// get vertical and horizontal edges using Sobel filter
-	vertical, _, err := VerticalSobelGray(blurred, padding.BorderConstant)
+	vertical, _, err := convolution.ConvolveGray(blurred, sobelY, anchor, padding.BorderConstant, withPool)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	horizontal, _, err := HorizontalSobelGray(blurred, padding.BorderConstant)
+	defer cannyPool.PutGray(vertical)
+	horizontal, _, err := convolution.ConvolveGray(blurred, sobelX, anchor, padding.BorderConstant, withPool)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer cannyPool.PutGray(horizontal)
 
 	// calculate the gradient values and orientation angles for each pixel
-	g, theta, err := gradientAndOrientation(vertical, horizontal)
+	size := blurred.Bounds().Size()
+	values := getCannyFloats(2 * size.X * size.Y)
+	defer cannyFloatPool.Put(&values)
+	g, theta, err := gradientAndOrientation(vertical, horizontal, values)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// "thin" the edges using non-max suppression procedure
-	thinEdges := nonMaxSuppression(blurred, g, theta)
+	thinEdges := nonMaxSuppression(blurred, g, theta, cannyPool)
+	defer cannyPool.PutGray(thinEdges)
 
 	// hysteresis
-	hist := threshold(thinEdges, g, lower, upper)
-	//_ = threshold(thinEdges, g, lower, upper)
-
-	return hist, nil
+	suppressMagnitude(thinEdges, g)
+	hysteresisInto(dst, g, lower, upper)
+	return nil
 }
 
-// CannyRGBA computes the edges of a given RGBA image using the Canny edge detection algorithm. The returned image is a
-// grayscale image represented on 8 bits.
-func CannyRGBA(img *image.RGBA, lower float64, upper float64, kernelSize uint) (*image.Gray, error) {
-	return CannyGray(grayscale.Grayscale(img), lower, upper, kernelSize)
+// getCannyFloats returns a buffer of n float64 values from cannyFloatPool, its content is undefined.
+func getCannyFloats(n int) []float64 {
+	if buf, ok := cannyFloatPool.Get().(*[]float64); ok && cap(*buf) >= n {
+		return (*buf)[:n]
+	}
+	return make([]float64, n)
 }
 
-func gradientAndOrientation(vertical *image.Gray, horizontal *image.Gray) ([][]float64, [][]float64, error) {
+// gradientAndOrientation computes the gradient and the orientation maps, their columns are slices of values, which
+// holds twice the number of pixels.
+func gradientAndOrientation(vertical *image.Gray, horizontal *image.Gray, values []float64) ([][]float64, [][]float64, error) {
 	size := vertical.Bounds().Size()
 	theta := make([][]float64, size.X)
 	g := make([][]float64, size.X)
+	gValues, thetaValues := values[:size.X*size.Y], values[size.X*size.Y:]
 	for x := 0; x < size.X; x++ {
-		theta[x] = make([]float64, size.Y)
-		g[x] = make([]float64, size.Y)
+		theta[x] = thetaValues[x*size.Y : (x+1)*size.Y]
+		g[x] = gValues[x*size.Y : (x+1)*size.Y]
 		err := errors.New("none")
 		for y := 0; y < size.Y; y++ {
 			px := float64(vertical.GrayAt(x, y).Y)
@@ -102,9 +187,9 @@ func isBiggerThenNeighbours(val float64, neighbour1 float64, neighbour2 float64)
 	return val > neighbour1 && val > neighbour2
 }
 
-func nonMaxSuppression(img *image.Gray, g [][]float64, theta [][]float64) *image.Gray {
+func nonMaxSuppression(img *image.Gray, g [][]float64, theta [][]float64, pool *utils.BufferPool) *image.Gray {
 	size := img.Bounds().Size()
-	thinEdges := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	thinEdges := pool.NewGray(image.Rect(0, 0, size.X, size.Y))
 	utils.ParallelForEachPixel(size, func(x, y int) {
 		isLocalMax := false
 		if x > 0 && x < size.X-1 && y > 0 && y < size.Y-1 {
@@ -134,36 +219,117 @@ func nonMaxSuppression(img *image.Gray, g [][]float64, theta [][]float64) *image
 	return thinEdges
 }
 
-func threshold(img *image.Gray, g [][]float64, lowerBound float64, upperBound float64) *image.Gray {
-	size := img.Bounds().Size()
-	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
-	utils.ParallelForEachPixel(size, func(x int, y int) {
-		p := img.GrayAt(x, y)
-		if p.Y == utils.MaxUint8 {
-			if g[x][y] < lowerBound {
-				res.SetGray(x, y, color.Gray{Y: utils.MinUint8})
-			}
-			if g[x][y] > upperBound {
-				res.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+// HysteresisThreshold applies the hysteresis step of the Canny edge detector on a gradient magnitude map indexed as
+// magnitude[x][y]. Pixels with a magnitude of at least high are strong edges, pixels with a magnitude of at least low
+// are kept only if they are 8-connected to a strong edge, directly or through other kept pixels. All the other pixels
+// are discarded. Edges are marked with 255 in the returned image.
+// Example of usage:
+//
+//	res := edgedetection.HysteresisThreshold(magnitude, 20, 60)
+func HysteresisThreshold(magnitude [][]float64, low, high float64) *image.Gray {
+	width := len(magnitude)
+	height := 0
+	if width > 0 {
+		height = len(magnitude[0])
+	}
+	res := image.NewGray(image.Rect(0, 0, width, height))
+	hysteresisInto(res, magnitude, low, high)
+	return res
+}
+
+// hysteresisInto writes the result of HysteresisThreshold into dst, which has the size of the magnitude map. Every
+// pixel of dst is overwritten.
+func hysteresisInto(dst *image.Gray, magnitude [][]float64, low, high float64) {
+	size := dst.Bounds().Size()
+	min := dst.Bounds().Min
+	for y := 0; y < size.Y; y++ {
+		row := dst.Pix[dst.PixOffset(min.X, min.Y+y) : dst.PixOffset(min.X, min.Y+y)+size.X]
+		for x := range row {
+			row[x] = 0
+		}
+	}
+	var stack []image.Point
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			if magnitude[x][y] >= high {
+				dst.Pix[dst.PixOffset(min.X+x, min.Y+y)] = utils.MaxUint8
+				stack = append(stack, image.Point{X: x, Y: y})
 			}
 		}
-	})
-	utils.ParallelForEachPixel(size, func(x int, y int) {
-		p := img.GrayAt(x, y)
-		if p.Y == utils.MaxUint8 && x > 0 && x < size.X-1 && y > 0 && y < size.Y-1 {
-			if g[x][y] >= lowerBound && g[x][y] <= upperBound {
-				if checkNeighbours(x, y, res) {
-					res.SetGray(x, y, color.Gray{Y: utils.MinUint8})
+	}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				nx, ny := p.X+dx, p.Y+dy
+				if nx < 0 || ny < 0 || nx >= size.X || ny >= size.Y {
+					continue
+				}
+				i := dst.PixOffset(min.X+nx, min.Y+ny)
+				if dst.Pix[i] != utils.MaxUint8 && magnitude[nx][ny] >= low {
+					dst.Pix[i] = utils.MaxUint8
+					stack = append(stack, image.Point{X: nx, Y: ny})
 				}
 			}
 		}
-	})
-	return res
+	}
 }
 
-func checkNeighbours(x, y int, img *image.Gray) bool {
-	return img.GrayAt(x-1, y-1).Y == utils.MaxUint8 || img.GrayAt(x-1, y).Y == utils.MaxUint8 ||
-		img.GrayAt(x-1, y+1).Y == utils.MaxUint8 || img.GrayAt(x, y-1).Y == utils.MaxUint8 ||
-		img.GrayAt(x, y+1).Y == utils.MaxUint8 || img.GrayAt(x+1, y-1).Y == utils.MaxUint8 ||
-		img.GrayAt(x+1, y).Y == utils.MaxUint8 || img.GrayAt(x+1, y+1).Y == utils.MaxUint8
+// suppressMagnitude keeps the gradient magnitude in place only for the pixels which survived the non-max suppression,
+// the other pixels get -Inf so they are never kept by the hysteresis.
+func suppressMagnitude(thinEdges *image.Gray, g [][]float64) {
+	for x := range g {
+		for y := range g[x] {
+			if thinEdges.GrayAt(x, y).Y != utils.MaxUint8 {
+				g[x][y] = math.Inf(-1)
+			}
+		}
+	}
+}
+
+// sobelKernels builds the horizontal and the vertical Sobel kernels of the given aperture size as the outer product of
+// a binomial smoothing kernel and a central difference of a binomial kernel. The kernels are scaled to the absolute sum
+// of the 3x3 Sobel kernels, so the aperture 3 gives the same kernels as horizontalKernel and verticalKernel.
+func sobelKernels(apertureSize int) (*convolution.Kernel, *convolution.Kernel, error) {
+	if apertureSize != 3 && apertureSize != 5 && apertureSize != 7 {
+		return nil, nil, errors.New("aperture size must be 3, 5 or 7")
+	}
+	smooth := binomial(apertureSize - 1)
+	inner := binomial(apertureSize - 3)
+	derivative := make([]float64, apertureSize)
+	for i, v := range inner {
+		derivative[i] -= v
+		derivative[i+2] += v
+	}
+	horizontal, _ := convolution.NewKernel(apertureSize, apertureSize)
+	vertical, _ := convolution.NewKernel(apertureSize, apertureSize)
+	for x := 0; x < apertureSize; x++ {
+		for y := 0; y < apertureSize; y++ {
+			horizontal.Set(x, y, smooth[x]*derivative[y])
+			vertical.Set(x, y, derivative[x]*smooth[y])
+		}
+	}
+	scale := 8 / horizontal.AbSum()
+	for x := 0; x < apertureSize; x++ {
+		for y := 0; y < apertureSize; y++ {
+			horizontal.Set(x, y, horizontal.At(x, y)*scale)
+			vertical.Set(x, y, vertical.At(x, y)*scale)
+		}
+	}
+	return horizontal, vertical, nil
+}
+
+// binomial returns the coefficients of the binomial expansion of the given order, e.g. 1 2 1 for order 2.
+func binomial(order int) []float64 {
+	res := []float64{1}
+	for i := 0; i < order; i++ {
+		next := make([]float64, len(res)+1)
+		for j, v := range res {
+			next[j] += v
+			next[j+1] += v
+		}
+		res = next
+	}
+	return res
 }
@@ -2,10 +2,245 @@ package edgedetection
 
 import (
 	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
+	"math"
+	"math/rand"
 	"testing"
 )
 
+// ---------------------------------Unit tests------------------------------------
+func Test_HysteresisThreshold(t *testing.T) {
+	// magnitude[x][y] of a 7x5 map: a chain of weak pixels bridges two strong pixels on the second row, a weak
+	// pixel on the last row is isolated and a weak pixel touches the chain diagonally
+	values := [][]float64{
+		{0, 0, 0, 0, 0, 0, 0},
+		{90, 30, 35, 40, 30, 100, 0},
+		{0, 0, 0, 0, 0, 0, 25},
+		{0, 0, 10, 0, 0, 0, 0},
+		{0, 0, 0, 45, 0, 0, 0},
+	}
+	magnitude := make([][]float64, 7)
+	for x := range magnitude {
+		magnitude[x] = make([]float64, 5)
+		for y := range magnitude[x] {
+			magnitude[x][y] = values[y][x]
+		}
+	}
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 7, 5),
+		Stride: 7,
+		Pix: []uint8{
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	}
+	actual := HysteresisThreshold(magnitude, 20, 80)
+	utils.CompareGrayImages(t, expected, actual)
+}
+
+// Test_CannyGray_StrongEdgesUnchanged pins the output of CannyGray from before it used HysteresisThreshold, which was
+// stored as canny_15_45_5_strong_only.png. That hysteresis step kept only the strong edges (magnitude above the upper
+// threshold) and dropped every weak edge, even the ones connected to a strong edge.
+func Test_CannyGray_StrongEdgesUnchanged(t *testing.T) {
+	img, err := imgio.ImreadGray("../res/golden/input.png")
+	if err != nil {
+		t.Fatalf("Could not read input image: %s", err)
+	}
+	before, err := imgio.ImreadGray("../res/golden/edge/canny_15_45_5_strong_only.png")
+	if err != nil {
+		t.Fatalf("Could not read pinned image: %s", err)
+	}
+	// without a weak band only the strong edges are kept, which gives exactly the previous output
+	above := math.Nextafter(45, math.Inf(1))
+	strongOnly, err := CannyGray(img, above, above, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, before, strongOnly)
+	// with the weak band every previous edge is kept and only weak edges are added
+	res, err := CannyGray(img, 15, 45, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	var added int
+	for i := range before.Pix {
+		if before.Pix[i] == utils.MaxUint8 && res.Pix[i] != utils.MaxUint8 {
+			t.Errorf("Expected the strong edge at index %d to be kept", i)
+		}
+		if before.Pix[i] != utils.MaxUint8 && res.Pix[i] == utils.MaxUint8 {
+			added++
+		}
+	}
+	if added == 0 {
+		t.Error("Expected the weak edges connected to strong edges to be added")
+	}
+}
+
+func Test_CannyGrayWithSigma_SkipBlur(t *testing.T) {
+	// two small steps which are 4 pixels apart
+	steps := []uint8{100, 110, 120, 120, 120, 130, 140}
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: steps[utils.ClampInt(x-14, 0, len(steps)-1)]})
+	})
+	edgesOnRow := func(res *image.Gray, y int) []int {
+		var xs []int
+		for x := 0; x < 40; x++ {
+			if res.GrayAt(x, y).Y != 0 {
+				xs = append(xs, x)
+			}
+		}
+		return xs
+	}
+	sharp, err := CannyGrayWithSigma(img, 5, 15, 5, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	blurred, err := CannyGrayWithSigma(img, 5, 15, 5, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 5; y < 35; y++ {
+		if xs := edgesOnRow(sharp, y); len(xs) != 2 || xs[0] != 15 || xs[1] != 19 {
+			t.Fatalf("Expected edges at x = 15 and x = 19 without blur - actual: %v on row %d", xs, y)
+		}
+		if xs := edgesOnRow(blurred, y); len(xs) > 1 {
+			t.Fatalf("Expected the blur to merge the two steps - actual edges: %v on row %d", xs, y)
+		}
+	}
+	// tiny images do not panic without the blur
+	for _, size := range []int{1, 2, 3} {
+		if _, err := CannyGrayWithSigma(image.NewGray(image.Rect(0, 0, size, size)), 5, 15, 3, 0); err != nil {
+			t.Errorf("Error should not be returned. Error value: %s", err)
+		}
+	}
+}
+
+func Test_sobelKernels(t *testing.T) {
+	horizontal, vertical, err := sobelKernels(3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if horizontal.At(x, y) != horizontalKernel.At(x, y) || vertical.At(x, y) != verticalKernel.At(x, y) {
+				t.Fatalf("Expected the 3x3 Sobel kernels at (%d, %d)", x, y)
+			}
+		}
+	}
+}
+
+func Test_CannyGrayWithAperture_Noise(t *testing.T) {
+	// a vertical step edge with uniform noise
+	rnd := rand.New(rand.NewSource(7))
+	img := image.NewGray(image.Rect(0, 0, 60, 60))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		value := 70
+		if x >= 30 {
+			value = 180
+		}
+		img.SetGray(x, y, color.Gray{Y: uint8(value + rnd.Intn(81) - 40)})
+	})
+	// edges further than 2 pixels from the step are spurious, the border of the image is skipped
+	countEdges := func(res *image.Gray) (spurious int, step int) {
+		utils.ForEachPixel(res.Bounds().Size(), func(x, y int) {
+			if x < 4 || y < 4 || x > 55 || y > 55 || res.GrayAt(x, y).Y == 0 {
+				return
+			}
+			if x < 28 || x > 32 {
+				spurious++
+			} else {
+				step++
+			}
+		})
+		return spurious, step
+	}
+	small, err := CannyGrayWithAperture(img, 30, 60, 3, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	large, err := CannyGrayWithAperture(img, 30, 60, 3, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	spuriousSmall, _ := countEdges(small)
+	spuriousLarge, step := countEdges(large)
+	if spuriousLarge*3 > spuriousSmall*2 {
+		t.Errorf("Expected at least a third fewer spurious edges with aperture 5 - aperture 3: %d, aperture 5: %d", spuriousSmall, spuriousLarge)
+	}
+	if step < 20 {
+		t.Errorf("Expected the step edge to be found with aperture 5 - actual edge pixels: %d", step)
+	}
+	for _, aperture := range []int{1, 4, 9} {
+		if _, err := CannyGrayWithAperture(img, 30, 60, 3, aperture); err == nil {
+			t.Errorf("Expected error for aperture size %d", aperture)
+		}
+	}
+}
+
+func Test_CannyGrayInto(t *testing.T) {
+	img := setupTestCaseGray(t)
+	expected, err := CannyGray(img, 15, 45, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	dst := image.NewGray(img.Bounds())
+	// the second call runs with the recycled buffers and must not be affected by the previous result in dst
+	for i := 0; i < 2; i++ {
+		if err := CannyGrayInto(dst, img, 15, 45, 5); err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, dst)
+	}
+	// dst can be a part of a larger image, the pixels around it are left untouched
+	size := img.Bounds().Size()
+	canvas := image.NewGray(image.Rect(0, 0, size.X+6, size.Y+4))
+	for i := range canvas.Pix {
+		canvas.Pix[i] = 77
+	}
+	sub := canvas.SubImage(image.Rect(3, 2, size.X+3, size.Y+2)).(*image.Gray)
+	if err := CannyGrayInto(sub, img, 15, 45, 5); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, utils.CloneGray(sub))
+	if canvas.GrayAt(0, 0).Y != 77 || canvas.GrayAt(size.X+5, size.Y+3).Y != 77 {
+		t.Errorf("Expected the pixels outside of dst to be kept")
+	}
+	if err := CannyGrayInto(image.NewGray(image.Rect(0, 0, 10, 10)), img, 15, 45, 5); err == nil {
+		t.Error("Expected error for a destination of a different size")
+	}
+}
+
+func Benchmark_CannyGray(b *testing.B) {
+	img := setupTestCaseRandomGraySobel(512, 512)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CannyGray(img, 15, 45, 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_CannyGrayInto(b *testing.B) {
+	img := setupTestCaseRandomGraySobel(512, 512)
+	dst := image.NewGray(img.Bounds())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := CannyGrayInto(dst, img, 15, 45, 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/engine.png"
@@ -0,0 +1,26 @@
+package edgedetection
+
+import (
+	"github.com/yafeiliu/imger/internal/goldentest"
+	"github.com/yafeiliu/imger/padding"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Golden tests------------------------------------
+func Test_Golden(t *testing.T) {
+	registry := goldentest.NewRegistry("../res/golden/edge")
+	registry.Register("sobel_replicate", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		return SobelGray(img, padding.BorderReplicate)
+	})
+	registry.Register("laplacian_k8_reflect", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		res, _, err := LaplacianGray(img, padding.BorderReflect, K8)
+		return res, err
+	})
+	registry.Register("canny_15_45_5", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		return CannyGray(img, 15, 45, 5)
+	})
+	registry.Run(t)
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,126 @@
+package edgedetection
+
+import (
+	"image"
+)
+
+// LinkEdges traces the edge pixels of a binary edge image (e.g. the output of CannyGray) into polylines. Every nonzero
+// pixel is an edge pixel and the pixels are connected with 8-connectivity, a diagonal neighbour is ignored when it is
+// already connected through a common horizontal or vertical neighbour, so the corners of the staircase lines do not
+// count as branches. A polyline runs between two end points or junctions, where a junction is an edge pixel with more
+// then two neighbours. The junction is part of every polyline meeting there, so the polylines of a T-junction share
+// their first or last point. The points of a polyline are ordered along the curve. Closed curves without end points
+// are returned with the first point repeated at the end, an isolated edge pixel is returned as a single point.
+// Example of usage:
+//
+//	edges, err := edgedetection.CannyGray(img, 15, 45, 5)
+//	polylines := edgedetection.LinkEdges(edges)
+func LinkEdges(edges *image.Gray) [][]image.Point {
+	l := edgeLinker{edges: edges, bounds: edges.Bounds(), visited: map[image.Point]bool{}}
+	var polylines [][]image.Point
+	// open curves are traced from their end points first, then the branches leaving the junctions and finally the
+	// closed curves which are left over
+	for _, pass := range []func(degree int) bool{
+		func(degree int) bool { return degree <= 1 },
+		func(degree int) bool { return degree > 2 },
+		func(degree int) bool { return degree == 2 },
+	} {
+		for y := l.bounds.Min.Y; y < l.bounds.Max.Y; y++ {
+			for x := l.bounds.Min.X; x < l.bounds.Max.X; x++ {
+				p := image.Point{X: x, Y: y}
+				if !l.isEdge(p) || l.visited[p] {
+					continue
+				}
+				neighbours := l.neighbours(p)
+				if !pass(len(neighbours)) {
+					continue
+				}
+				if len(neighbours) == 0 {
+					l.visited[p] = true
+					polylines = append(polylines, []image.Point{p})
+					continue
+				}
+				if len(neighbours) == 2 {
+					// a closed curve, it is traced in one direction only
+					polylines = append(polylines, l.trace(p, neighbours[0]))
+					continue
+				}
+				for _, n := range neighbours {
+					if l.visited[n] || (len(neighbours) > 2 && len(l.neighbours(n)) > 2 && !pointLess(p, n)) {
+						// already traced, or a junction next to a junction which is linked from its other side
+						continue
+					}
+					polylines = append(polylines, l.trace(p, n))
+				}
+			}
+		}
+	}
+	return polylines
+}
+
+// -------------------------------------------------------------------------------------------------------
+type edgeLinker struct {
+	edges  *image.Gray
+	bounds image.Rectangle
+	// visited marks the traced pixels, the junctions are never marked as they are shared by several polylines
+	visited map[image.Point]bool
+}
+
+var linkOffsets = []image.Point{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: -1, Y: 1}, {X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: -1}, {X: 1, Y: -1}}
+
+func (l *edgeLinker) isEdge(p image.Point) bool {
+	return p.In(l.bounds) && l.edges.GrayAt(p.X, p.Y).Y != 0
+}
+
+// neighbours returns the connected edge pixels around p, the diagonal ones are skipped when they can be reached through
+// a horizontal or vertical neighbour.
+func (l *edgeLinker) neighbours(p image.Point) []image.Point {
+	var res []image.Point
+	for _, o := range linkOffsets {
+		if !l.isEdge(p.Add(o)) {
+			continue
+		}
+		if o.X != 0 && o.Y != 0 && (l.isEdge(image.Point{X: p.X + o.X, Y: p.Y}) || l.isEdge(image.Point{X: p.X, Y: p.Y + o.Y})) {
+			continue
+		}
+		res = append(res, p.Add(o))
+	}
+	return res
+}
+
+// trace follows the curve from start through next until an end point, a junction or the start of a closed curve is
+// reached.
+func (l *edgeLinker) trace(start, next image.Point) []image.Point {
+	polyline := []image.Point{start}
+	if len(l.neighbours(start)) <= 2 {
+		l.visited[start] = true
+	}
+	prev, current := start, next
+	for {
+		polyline = append(polyline, current)
+		neighbours := l.neighbours(current)
+		if len(neighbours) != 2 {
+			if len(neighbours) < 2 {
+				l.visited[current] = true
+			}
+			return polyline
+		}
+		l.visited[current] = true
+		following := neighbours[0]
+		if following == prev {
+			following = neighbours[1]
+		}
+		if following == start {
+			// back at the start of a closed curve, or of a loop leaving and entering the same junction
+			return append(polyline, start)
+		}
+		if l.visited[following] {
+			return polyline
+		}
+		prev, current = current, following
+	}
+}
+
+func pointLess(a, b image.Point) bool {
+	return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+}
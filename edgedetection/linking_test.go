@@ -0,0 +1,85 @@
+package edgedetection
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_LinkEdges_TwoCurves(t *testing.T) {
+	curve1 := []image.Point{{2, 3}, {3, 3}, {4, 3}, {5, 3}, {6, 3}, {7, 4}, {8, 5}, {9, 5}, {10, 6}, {10, 7}}
+	// a staircase with corner pixels which touch their neighbours both directly and diagonally
+	curve2 := []image.Point{{3, 12}, {4, 12}, {4, 13}, {5, 13}, {5, 14}, {6, 14}, {6, 15}, {7, 15}}
+	edges := setupTestCaseEdges(20, 20, curve1, curve2)
+	polylines := LinkEdges(edges)
+	if len(polylines) != 2 {
+		t.Fatalf("Expected 2 polylines - actual: %d %v", len(polylines), polylines)
+	}
+	comparePolylines(t, curve1, polylines[0])
+	comparePolylines(t, curve2, polylines[1])
+}
+
+func Test_LinkEdges_TJunction(t *testing.T) {
+	var bar, stem []image.Point
+	for x := 2; x <= 12; x++ {
+		bar = append(bar, image.Point{X: x, Y: 4})
+	}
+	for y := 5; y <= 10; y++ {
+		stem = append(stem, image.Point{X: 7, Y: y})
+	}
+	edges := setupTestCaseEdges(16, 14, bar, stem)
+	polylines := LinkEdges(edges)
+	junction := image.Point{X: 7, Y: 4}
+	// every segment is traced from its end point, which are found in row-major order
+	expected := [][]image.Point{
+		{{2, 4}, {3, 4}, {4, 4}, {5, 4}, {6, 4}, junction},
+		{{12, 4}, {11, 4}, {10, 4}, {9, 4}, {8, 4}, junction},
+		append(reverse(stem), junction),
+	}
+	if !reflect.DeepEqual(expected, polylines) {
+		t.Errorf("Expected polylines: %v - actual polylines: %v", expected, polylines)
+	}
+}
+
+func Test_LinkEdges_ClosedCurve(t *testing.T) {
+	ring := []image.Point{{3, 3}, {4, 3}, {5, 3}, {5, 4}, {5, 5}, {4, 5}, {3, 5}, {3, 4}}
+	edges := setupTestCaseEdges(10, 10, ring, []image.Point{{8, 8}})
+	polylines := LinkEdges(edges)
+	if len(polylines) != 2 {
+		t.Fatalf("Expected 2 polylines - actual: %d %v", len(polylines), polylines)
+	}
+	if !reflect.DeepEqual([]image.Point{{8, 8}}, polylines[0]) {
+		t.Errorf("Expected the isolated pixel as a single point - actual: %v", polylines[0])
+	}
+	comparePolylines(t, append(ring, ring[0]), polylines[1])
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseEdges(width, height int, curves ...[]image.Point) *image.Gray {
+	edges := image.NewGray(image.Rect(0, 0, width, height))
+	for _, curve := range curves {
+		for _, p := range curve {
+			edges.SetGray(p.X, p.Y, color.Gray{Y: 255})
+		}
+	}
+	return edges
+}
+
+// comparePolylines accepts the expected polyline in either direction.
+func comparePolylines(t *testing.T, expected, actual []image.Point) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) && !reflect.DeepEqual(reverse(expected), actual) {
+		t.Errorf("Expected polyline: %v - actual polyline: %v", expected, actual)
+	}
+}
+
+func reverse(points []image.Point) []image.Point {
+	res := make([]image.Point, len(points))
+	for i, p := range points {
+		res[len(points)-1-i] = p
+	}
+	return res
+}
@@ -1,12 +1,142 @@
 package edgedetection
 
 import (
+	"github.com/yafeiliu/imger/blend"
 	"github.com/yafeiliu/imger/imgio"
 	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
+	"math"
+	"math/rand"
 	"testing"
 )
 
+// ---------------------------------Unit tests------------------------------------
+func Test_SobelGrayMagAngle(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 30, 30))
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			if x+y >= 30 {
+				img.SetGray(x, y, color.Gray{Y: 0xC8})
+			}
+		}
+	}
+	magnitude, angle, err := SobelGrayMagAngle(img, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			nearEdge := x+y >= 28 && x+y <= 31
+			m := magnitude.GrayAt(x, y).Y
+			if !nearEdge && m != 0 {
+				t.Errorf("Expected magnitude: 0 - actual magnitude: %d at: %d %d", m, x, y)
+			}
+			if nearEdge && m == 0 {
+				t.Errorf("Expected nonzero magnitude at: %d %d", x, y)
+			}
+			if nearEdge && x > 0 && y > 0 && x < 29 && y < 29 && math.Abs(angle[x][y]-math.Pi/4) > 1e-9 {
+				t.Errorf("Expected angle: %f - actual angle: %f at: %d %d", math.Pi/4, angle[x][y], x, y)
+			}
+		}
+	}
+	if _, _, err := SobelGrayMagAngle(img, padding.Border(42)); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+func Test_SobelGray16_NoClipping(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 10))
+	for y := 0; y < 10; y++ {
+		for x := 10; x < 20; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	magnitude, err := SobelGray16(img, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	clamped, _, err := SobelGrayMagAngle(img, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			m := magnitude.Gray16At(x, y).Y
+			if x == 9 || x == 10 {
+				// the maximal black/white step gives 4 * 255
+				if m != 1020 {
+					t.Errorf("Expected magnitude: 1020 - actual magnitude: %d at: %d %d", m, x, y)
+				}
+				if clamped.GrayAt(x, y).Y != 255 {
+					t.Errorf("Expected the 8 bit magnitude to saturate at: %d %d", x, y)
+				}
+			} else if m != 0 {
+				t.Errorf("Expected magnitude: 0 in the flat region - actual magnitude: %d at: %d %d", m, x, y)
+			}
+		}
+	}
+	if _, err := SobelGray16(img, padding.Border(42)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
+func Test_SobelGray16_MatchesMagAngle(t *testing.T) {
+	img := setupTestCaseRandomGraySobel(31, 23)
+	magnitude, err := SobelGray16(img, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	clamped, _, err := SobelGrayMagAngle(img, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		// the 8 bit magnitude is truncated, the 16 bit one is rounded
+		expected := math.Min(float64(magnitude.Gray16At(x, y).Y), 255)
+		if actual := float64(clamped.GrayAt(x, y).Y); math.Abs(expected-actual) > 1 {
+			t.Errorf("Expected magnitude: %f - actual magnitude: %f at: %d %d", expected, actual, x, y)
+		}
+	})
+}
+
+func Test_SobelGray_MatchesConvolution(t *testing.T) {
+	img := setupTestCaseRandomGraySobel(67, 45)
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		expected, err := sobelGray2D(img, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual, err := SobelGray(img, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+	}
+	if _, err := SobelGray(img, padding.Border(-1)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
+func Benchmark_SobelGray(b *testing.B) {
+	img := setupTestCaseRandomGraySobel(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = SobelGray(img, padding.BorderReflect)
+	}
+}
+
+func Benchmark_SobelGray_2D(b *testing.B) {
+	img := setupTestCaseRandomGraySobel(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = sobelGray2D(img, padding.BorderReflect)
+	}
+}
+
+// -------------------------------------------------------------------------------
+
 // -----------------------------Acceptance tests------------------------------------
 
 func setupTestCaseGraySobel(t *testing.T) *image.Gray {
@@ -71,3 +201,22 @@ func Test_Acceptance_SobelRGBA(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------------
+
+// sobelGray2D is the reference implementation of SobelGray with two full 2D convolutions.
+func sobelGray2D(img *image.Gray, border padding.Border) (*image.Gray, error) {
+	horizontal, _, err := HorizontalSobelGray(img, border)
+	if err != nil {
+		return nil, err
+	}
+	vertical, _, err := VerticalSobelGray(img, border)
+	if err != nil {
+		return nil, err
+	}
+	return blend.AddGrayWeighted(horizontal, 0.5, vertical, 0.5)
+}
+
+func setupTestCaseRandomGraySobel(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(5)).Read(img.Pix)
+	return img
+}
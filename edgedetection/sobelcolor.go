@@ -0,0 +1,68 @@
+package edgedetection
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// SobelColorRGBA computes the Sobel gradients of a grayscale image and visualizes them as a color image: the
+// direction of the gradient is mapped to the hue and the magnitude of the gradient (relative to the strongest
+// gradient of the image) is mapped to the value of an HSV color. Flat regions are black.
+// Example of usage:
+//
+//	res := edgedetection.SobelColorRGBA(img)
+func SobelColorRGBA(img *image.Gray) *image.RGBA {
+	size := img.Bounds().Size()
+	gx, gy := sobelGradients(img)
+	magnitude := make([][]float64, size.X)
+	maxMagnitude := 0.0
+	for x := 0; x < size.X; x++ {
+		magnitude[x] = make([]float64, size.Y)
+		for y := 0; y < size.Y; y++ {
+			magnitude[x][y] = math.Hypot(gx[x][y], gy[x][y])
+			maxMagnitude = math.Max(maxMagnitude, magnitude[x][y])
+		}
+	}
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		if magnitude[x][y] == 0 {
+			res.SetRGBA(x, y, color.RGBA{A: utils.MaxUint8})
+			return
+		}
+		hue := math.Atan2(gy[x][y], gx[x][y]) * 180 / math.Pi
+		if hue < 0 {
+			hue += 360
+		}
+		res.SetRGBA(x, y, hsvToRGBA(hue, 1, magnitude[x][y]/maxMagnitude))
+	})
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------------
+// sobelGradients computes the signed horizontal and vertical Sobel gradients (indexed as [x][y]) of a grayscale
+// image. The pixels outside of the image are replicated from the nearest border pixel.
+func sobelGradients(img *image.Gray) ([][]float64, [][]float64) {
+	size := img.Bounds().Size()
+	gx := make([][]float64, size.X)
+	gy := make([][]float64, size.X)
+	at := func(x, y int) float64 {
+		return float64(img.GrayAt(utils.ClampInt(x, 0, size.X-1), utils.ClampInt(y, 0, size.Y-1)).Y)
+	}
+	for x := 0; x < size.X; x++ {
+		gx[x] = make([]float64, size.Y)
+		gy[x] = make([]float64, size.Y)
+		for y := 0; y < size.Y; y++ {
+			gx[x][y] = at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy[x][y] = at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+		}
+	}
+	return gx, gy
+}
+
+// hsvToRGBA converts a HSV color (hue in degrees, saturation and value in [0, 1]) to an opaque RGBA color.
+func hsvToRGBA(h, s, v float64) color.RGBA {
+	r, g, b := utils.HSVToRGB(h, s, v)
+	return color.RGBA{R: r, G: g, B: b, A: utils.MaxUint8}
+}
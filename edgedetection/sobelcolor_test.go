@@ -0,0 +1,45 @@
+package edgedetection
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_SobelColorRGBA(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 20))
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			// vertical edge at x = 5 in the top half, horizontal edge at y = 15 in the bottom half
+			if (y < 10 && x >= 5) || (y >= 15 && x >= 10) {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	res := SobelColorRGBA(img)
+	black := color.RGBA{A: 0xFF}
+	if c := res.RGBAAt(15, 5); c != black {
+		t.Errorf("Expected black for flat region - actual: %v", c)
+	}
+	if c := res.RGBAAt(2, 5); c != black {
+		t.Errorf("Expected black for flat region - actual: %v", c)
+	}
+	verticalEdge := res.RGBAAt(5, 4)
+	for y := 2; y < 8; y++ {
+		if c := res.RGBAAt(5, y); c != verticalEdge {
+			t.Errorf("Expected consistent color along vertical edge: %v - actual: %v at: %d %d", verticalEdge, c, 5, y)
+		}
+	}
+	horizontalEdge := res.RGBAAt(15, 15)
+	for x := 12; x < 18; x++ {
+		if c := res.RGBAAt(x, 15); c != horizontalEdge {
+			t.Errorf("Expected consistent color along horizontal edge: %v - actual: %v at: %d %d", horizontalEdge, c, x, 15)
+		}
+	}
+	if verticalEdge == black || horizontalEdge == black || verticalEdge == horizontalEdge {
+		t.Errorf("Expected distinct edge colors - vertical: %v horizontal: %v", verticalEdge, horizontalEdge)
+	}
+}
+
+// -------------------------------------------------------------------------------
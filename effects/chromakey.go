@@ -0,0 +1,77 @@
+package effects
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ChromaKeyRGBA extracts the foreground of an image shot in front of a uniform colored (e.g. green) background. The
+// distance of every pixel to the key color is measured in the chroma plane (Cb, Cr) of the YCbCr color space, so
+// shadows and highlights of the background are keyed as well. Pixels closer then tolerance to the key are fully
+// transparent, pixels farther then tolerance + softness are fully opaque and the alpha is feathered linearly in the
+// softness band between them. The distances are in the 0 - 255 range of the chroma channels. Returns the foreground
+// with the alpha applied and the alpha matte. Returns an error if tolerance or softness is negative.
+// Example of usage:
+//
+//	fg, matte, err := effects.ChromaKeyRGBA(img, color.RGBA{G: 0xFF, A: 0xFF}, 40, 30)
+func ChromaKeyRGBA(img *image.RGBA, key color.RGBA, tolerance, softness float64) (*image.NRGBA, *image.Gray, error) {
+	return chromaKey(img, key, tolerance, softness, false)
+}
+
+// ChromaKeyRGBASuppressSpill works like ChromaKeyRGBA, but it also removes the key color which spills onto the
+// semi-transparent pixels of the foreground (e.g. the green fringe of hair in front of a green screen): the chroma of
+// these pixels is desaturated along the direction of the key color while their luminance is kept.
+// Example of usage:
+//
+//	fg, matte, err := effects.ChromaKeyRGBASuppressSpill(img, color.RGBA{G: 0xFF, A: 0xFF}, 40, 30)
+func ChromaKeyRGBASuppressSpill(img *image.RGBA, key color.RGBA, tolerance, softness float64) (*image.NRGBA, *image.Gray, error) {
+	return chromaKey(img, key, tolerance, softness, true)
+}
+
+// -------------------------------------------------------------------------------------------------------
+func chromaKey(img *image.RGBA, key color.RGBA, tolerance, softness float64, suppressSpill bool) (*image.NRGBA, *image.Gray, error) {
+	if tolerance < 0 || softness < 0 {
+		return nil, nil, errors.New("tolerance and softness should not be negative")
+	}
+	_, keyCb, keyCr := color.RGBToYCbCr(key.R, key.G, key.B)
+	// direction of the key color in the chroma plane, relative to the neutral gray
+	keyX, keyY := float64(keyCb)-128, float64(keyCr)-128
+	if n := math.Hypot(keyX, keyY); n > 0 {
+		keyX, keyY = keyX/n, keyY/n
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	fg := image.NewNRGBA(image.Rect(0, 0, size.X, size.Y))
+	matte := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		// the RGBA pixels are alpha premultiplied
+		c := color.NRGBAModel.Convert(img.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+		lum, cb, cr := color.RGBToYCbCr(c.R, c.G, c.B)
+		d := math.Hypot(float64(cb)-float64(keyCb), float64(cr)-float64(keyCr))
+		alpha := 1.0
+		switch {
+		case d <= tolerance:
+			alpha = 0
+		case d < tolerance+softness:
+			alpha = (d - tolerance) / softness
+		}
+		a := uint8(math.Round(alpha * float64(utils.MaxUint8)))
+		matte.SetGray(x, y, color.Gray{Y: a})
+		if suppressSpill && a > 0 && a < utils.MaxUint8 {
+			// remove the chroma component pointing toward the key color
+			cx, cy := float64(cb)-128, float64(cr)-128
+			if p := cx*keyX + cy*keyY; p > 0 {
+				cx, cy = cx-p*keyX, cy-p*keyY
+			}
+			toUint8 := func(v float64) uint8 {
+				return uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+			}
+			c.R, c.G, c.B = color.YCbCrToRGB(lum, toUint8(cx+128), toUint8(cy+128))
+		}
+		fg.SetNRGBA(x, y, color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(uint16(a) * uint16(c.A) / uint16(utils.MaxUint8))})
+	})
+	return fg, matte, nil
+}
@@ -0,0 +1,119 @@
+package effects
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ChromaKeyRGBA(t *testing.T) {
+	img, coverage := setupTestCaseGreenScreen()
+	key := color.RGBA{G: 0xFF, A: 0xFF}
+	fg, matte, err := ChromaKeyRGBA(img, key, 40, 40)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	size := img.Bounds().Size()
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			a := matte.GrayAt(x, y).Y
+			if fg.NRGBAAt(x, y).A != a {
+				t.Fatalf("Expected the foreground alpha to match the matte - actual: %d %d", fg.NRGBAAt(x, y).A, a)
+			}
+			switch c := coverage[x][y]; {
+			case c == 0 && a != 0:
+				t.Errorf("Expected alpha 0 for the background - actual: %d at: %d %d", a, x, y)
+			case c == 1 && a != 0xFF:
+				t.Errorf("Expected alpha 255 for the subject - actual: %d at: %d %d", a, x, y)
+			case a != 0 && a != 0xFF && (c == 0 || c == 1):
+				t.Errorf("Expected intermediate alpha only on the edge - actual: %d at: %d %d", a, x, y)
+			}
+		}
+	}
+	if fg.NRGBAAt(20, 20) != (color.NRGBA{R: 0xC8, G: 0x50, B: 0x3C, A: 0xFF}) {
+		t.Errorf("Expected the subject color to be kept - actual: %v", fg.NRGBAAt(20, 20))
+	}
+}
+
+func Test_ChromaKeyRGBASuppressSpill(t *testing.T) {
+	img, coverage := setupTestCaseGreenScreen()
+	key := color.RGBA{G: 0xFF, A: 0xFF}
+	plain, _, err := ChromaKeyRGBA(img, key, 40, 40)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	suppressed, matte, err := ChromaKeyRGBASuppressSpill(img, key, 40, 40)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	edges := 0
+	for x := range coverage {
+		for y := range coverage[x] {
+			a := matte.GrayAt(x, y).Y
+			p, s := plain.NRGBAAt(x, y), suppressed.NRGBAAt(x, y)
+			if a == 0 || a == 0xFF {
+				if p != s {
+					t.Errorf("Expected only semi-transparent pixels to change - actual: %v %v at: %d %d", p, s, x, y)
+				}
+				continue
+			}
+			edges++
+			if greenExcess(s) >= greenExcess(p) {
+				t.Errorf("Expected less green spill - actual: %v, without suppression: %v at: %d %d", s, p, x, y)
+			}
+		}
+	}
+	if edges == 0 {
+		t.Fatal("Expected semi-transparent edge pixels")
+	}
+}
+
+func Test_ChromaKeyRGBA_Invalid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	if _, _, err := ChromaKeyRGBA(img, color.RGBA{G: 0xFF, A: 0xFF}, -1, 10); err == nil {
+		t.Error("Expected error for negative tolerance")
+	}
+	if _, _, err := ChromaKeyRGBA(img, color.RGBA{G: 0xFF, A: 0xFF}, 10, -1); err == nil {
+		t.Error("Expected error for negative softness")
+	}
+}
+
+// -------------------------------------------------------------------------------
+// setupTestCaseGreenScreen draws an anti-aliased disk on a pure green background and returns the coverage of the
+// disk for every pixel, indexed as [x][y].
+func setupTestCaseGreenScreen() (*image.RGBA, [][]float64) {
+	const size, samples = 40, 4
+	subject := [3]float64{0xC8, 0x50, 0x3C}
+	green := [3]float64{0, 0xFF, 0}
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	coverage := make([][]float64, size)
+	for x := 0; x < size; x++ {
+		coverage[x] = make([]float64, size)
+		for y := 0; y < size; y++ {
+			inside := 0
+			for sx := 0; sx < samples; sx++ {
+				for sy := 0; sy < samples; sy++ {
+					px := float64(x) + (float64(sx)+0.5)/samples
+					py := float64(y) + (float64(sy)+0.5)/samples
+					if math.Hypot(px-20, py-20) < 12 {
+						inside++
+					}
+				}
+			}
+			c := float64(inside) / (samples * samples)
+			coverage[x][y] = c
+			var mixed [3]uint8
+			for i := range mixed {
+				mixed[i] = uint8(math.Round(c*subject[i] + (1-c)*green[i]))
+			}
+			img.SetRGBA(x, y, color.RGBA{R: mixed[0], G: mixed[1], B: mixed[2], A: 0xFF})
+		}
+	}
+	return img, coverage
+}
+
+func greenExcess(c color.NRGBA) int {
+	return int(c.G) - int(math.Max(float64(c.R), float64(c.B)))
+}
@@ -89,7 +89,7 @@ func EmbossGray(img *image.Gray) (*image.Gray, error) {
 		{0, 1, 1},
 	}, Width: 3, Height: 3}
 
-	conv, err := convolution.ConvolveGray(img, &kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	conv, _, err := convolution.ConvolveGray(img, &kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +106,8 @@ func EmbossRGBA(img *image.RGBA) (*image.Gray, error) {
 // SharpenGray takes a grayscale image and returns another grayscale image where each edge is added to the original
 // image.
 func SharpenGray(img *image.Gray) (*image.Gray, error) {
-	return convolution.ConvolveGray(img, &sharpenKernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	res, _, err := convolution.ConvolveGray(img, &sharpenKernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	return res, err
 }
 
 // SharpenRGBA takes an RGBA image and returns another RGBA image where each edge is added to the original image.
@@ -114,13 +115,44 @@ func SharpenRGBA(img *image.RGBA) (*image.RGBA, error) {
 	return convolution.ConvolveRGBA(img, &sharpenKernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
 }
 
+// SharpenGrayAmount sharpens a grayscale image with an adjustable strength: the result is the original image plus
+// amount times the edges added by SharpenGray, so 0 returns the original image, 1 gives the result of SharpenGray and
+// bigger values exaggerate the edges. Returns an error if the amount is negative.
+// Example of usage:
+//
+//	res, err := effects.SharpenGrayAmount(img, 0.5)
+func SharpenGrayAmount(img *image.Gray, amount float64) (*image.Gray, error) {
+	kernel, err := sharpenKernelAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	res, _, err := convolution.ConvolveGray(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	return res, err
+}
+
+// SharpenRGBAAmount sharpens the color channels of an RGBA image with an adjustable strength like SharpenGrayAmount,
+// the alpha channel is copied from the input image.
+// Example of usage:
+//
+//	res, err := effects.SharpenRGBAAmount(img, 1.5)
+func SharpenRGBAAmount(img *image.RGBA, amount float64) (*image.RGBA, error) {
+	kernel, err := sharpenKernelAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	return convolution.ConvolveRGBA(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+}
+
 // InvertGray takes a grayscale image and return its inverted grayscale image.
 func InvertGray(img *image.Gray) *image.Gray {
 	size := img.Bounds().Size()
 	inverted := image.NewGray(img.Rect)
-	utils.ParallelForEachPixel(size, func(x, y int) {
-		original := img.GrayAt(x, y).Y
-		inverted.SetGray(x, y, color.Gray{Y: utils.MaxUint8 - original})
+	utils.ParallelForEachRow(size, func(y int) {
+		src := img.Pix[y*img.Stride : y*img.Stride+size.X]
+		dst := inverted.Pix[y*inverted.Stride : y*inverted.Stride+size.X]
+		for x, v := range src {
+			dst[x] = utils.MaxUint8 - v
+		}
 	})
 	return inverted
 }
@@ -129,13 +161,34 @@ func InvertGray(img *image.Gray) *image.Gray {
 func InvertRGBA(img *image.RGBA) *image.RGBA {
 	size := img.Bounds().Size()
 	inverted := image.NewRGBA(img.Rect)
-	utils.ParallelForEachPixel(size, func(x, y int) {
-		originalColor := img.RGBAAt(x, y)
-		invertedColor := color.RGBA{R: utils.MaxUint8 - originalColor.R,
-			G: utils.MaxUint8 - originalColor.G,
-			B: utils.MaxUint8 - originalColor.B,
-			A: originalColor.A}
-		inverted.SetRGBA(x, y, invertedColor)
+	utils.ParallelForEachRow(size, func(y int) {
+		src := img.Pix[y*img.Stride : y*img.Stride+4*size.X]
+		dst := inverted.Pix[y*inverted.Stride : y*inverted.Stride+4*size.X]
+		for i := 0; i < len(src); i += 4 {
+			dst[i] = utils.MaxUint8 - src[i]
+			dst[i+1] = utils.MaxUint8 - src[i+1]
+			dst[i+2] = utils.MaxUint8 - src[i+2]
+			dst[i+3] = src[i+3]
+		}
 	})
 	return inverted
 }
+
+// -------------------------------------------------------------------------------------------------------
+// sharpenKernelAmount blends the identity kernel and the sharpen kernel: identity + amount * (sharpen - identity).
+func sharpenKernelAmount(amount float64) (*convolution.Kernel, error) {
+	if amount < 0 {
+		return nil, errors.New("the amount should not be negative")
+	}
+	kernel, _ := convolution.NewKernel(3, 3)
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			identity := 0.0
+			if x == 1 && y == 1 {
+				identity = 1
+			}
+			kernel.Set(x, y, identity+amount*(sharpenKernel.At(x, y)-identity))
+		}
+	}
+	return kernel, nil
+}
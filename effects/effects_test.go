@@ -4,6 +4,7 @@ import (
 	"github.com/yafeiliu/imger/imgio"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"testing"
 )
 
@@ -65,6 +66,59 @@ func Test_InvertedRGBA(t *testing.T) {
 	utils.CompareRGBAImages(t, &expected, actual)
 }
 
+func Test_InvertedGray_MapGray(t *testing.T) {
+	gray := setupTestCaseGray(t)
+	mapped := utils.MapGray(gray, func(x, y int, v uint8) uint8 {
+		return utils.MaxUint8 - v
+	})
+	utils.CompareGrayImages(t, InvertGray(gray), mapped)
+}
+
+func Test_SharpenAmount(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 12, 9))
+	rgba := image.NewRGBA(image.Rect(0, 0, 12, 9))
+	utils.ForEachPixel(gray.Bounds().Size(), func(x, y int) {
+		v := uint8((x*53 + y*97 + x*y*11) % 256)
+		gray.SetGray(x, y, color.Gray{Y: v})
+		rgba.SetRGBA(x, y, color.RGBA{R: v, G: 255 - v, B: uint8(x * 20), A: uint8(100 + y)})
+	})
+	original, err := SharpenGrayAmount(gray, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, gray, original)
+	full, err := SharpenGrayAmount(gray, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expectedGray, _ := SharpenGray(gray)
+	utils.CompareGrayImages(t, expectedGray, full)
+
+	originalRGBA, err := SharpenRGBAAmount(rgba, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImages(t, rgba, originalRGBA)
+	fullRGBA, err := SharpenRGBAAmount(rgba, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expectedRGBA, _ := SharpenRGBA(rgba)
+	utils.CompareRGBAImages(t, expectedRGBA, fullRGBA)
+
+	// a stronger amount increases the contrast at the edges
+	exaggerated, err := SharpenGrayAmount(gray, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if utils.LaplacianVarianceGray(exaggerated) <= utils.LaplacianVarianceGray(full) {
+		t.Errorf("Expected a sharper result with amount 3 then with amount 1")
+	}
+	if _, err := SharpenGrayAmount(gray, -0.5); err == nil {
+		t.Error("Expected error for a negative amount")
+	}
+}
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/girl.jpg"
@@ -0,0 +1,95 @@
+package effects
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/histogram"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// GrayWorldBalanceRGBA applies automatic white balance to an RGBA image using the gray world assumption: each color
+// channel is scaled so the means of the red, green and blue channels become equal to their common average. The
+// results are clamped to [0, 255], the alpha channel is left unchanged. A channel which is completely black is left
+// unscaled and it is not taken into account when computing the common average.
+// Example of usage:
+//
+//	res := effects.GrayWorldBalanceRGBA(img)
+func GrayWorldBalanceRGBA(img *image.RGBA) *image.RGBA {
+	var sums [3]float64
+	utils.ForEachRGBAPixel(img, func(pixel color.RGBA) {
+		sums[0] += float64(pixel.R)
+		sums[1] += float64(pixel.G)
+		sums[2] += float64(pixel.B)
+	})
+	// black channels are excluded from the common average
+	var gray, channels float64
+	for _, sum := range sums {
+		if sum != 0 {
+			gray += sum
+			channels++
+		}
+	}
+	if channels > 0 {
+		gray /= channels
+	}
+	var scales [3]float64
+	for c := range scales {
+		scales[c] = 1
+		if sums[c] != 0 {
+			scales[c] = gray / sums[c]
+		}
+	}
+	return scaleChannelsRGBA(img, scales)
+}
+
+// WhitePatchBalanceRGBA applies automatic white balance to an RGBA image using the white patch assumption: each color
+// channel is scaled so that the brightest given percent of its pixels map to 255. For example a percentile of 1 maps the
+// value at the 99th percentile of each channel to 255. The percentile has to be in the [0, 100) interval. The results
+// are clamped to [0, 255], the alpha channel is left unchanged. A channel which is completely black is left unscaled.
+// Example of usage:
+//
+//	res, err := effects.WhitePatchBalanceRGBA(img, 1.0)
+func WhitePatchBalanceRGBA(img *image.RGBA, percentile float64) (*image.RGBA, error) {
+	if percentile < 0 || percentile >= 100 {
+		return nil, errors.New("percentile should be in the [0, 100) interval")
+	}
+	hist := histogram.HistogramRGBA(img)
+	size := img.Bounds().Size()
+	total := float64(size.X * size.Y)
+	var scales [3]float64
+	for c := range scales {
+		scales[c] = 1
+		// find the lowest value for which at most percentile percent of the pixels are brighter
+		limit := total * percentile / 100
+		var brighter float64
+		white := 255
+		for white > 0 && brighter+float64(hist[c][white]) <= limit {
+			brighter += float64(hist[c][white])
+			white--
+		}
+		if white > 0 {
+			scales[c] = float64(utils.MaxUint8) / float64(white)
+		}
+	}
+	return scaleChannelsRGBA(img, scales), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func scaleChannelsRGBA(img *image.RGBA, scales [3]float64) *image.RGBA {
+	res := image.NewRGBA(img.Rect)
+	scale := func(value uint8, s float64) uint8 {
+		return uint8(utils.ClampF64(math.Round(float64(value)*s), utils.MinUint8, float64(utils.MaxUint8)))
+	}
+	utils.ParallelForEachPixel(img.Bounds().Size(), func(x, y int) {
+		pixel := img.RGBAAt(x, y)
+		res.SetRGBA(x, y, color.RGBA{
+			R: scale(pixel.R, scales[0]),
+			G: scale(pixel.G, scales[1]),
+			B: scale(pixel.B, scales[2]),
+			A: pixel.A,
+		})
+	})
+	return res
+}
@@ -0,0 +1,73 @@
+package effects
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// --------------------------------Unit tests---------------------------------------
+func setupBlueCastRamp() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 8))
+	for x := 0; x < 64; x++ {
+		v := 20 + 2.5*float64(x)
+		for y := 0; y < 8; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(v * 0.85), G: uint8(v * 0.95), B: uint8(v * 1.3), A: 0x80})
+		}
+	}
+	return img
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func Test_GrayWorldBalanceRGBA(t *testing.T) {
+	res := GrayWorldBalanceRGBA(setupBlueCastRamp())
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 8; y++ {
+			p := res.RGBAAt(x, y)
+			if absDiff(p.R, p.G) > 2 || absDiff(p.G, p.B) > 2 || absDiff(p.R, p.B) > 2 {
+				t.Errorf("Expected neutral pixel - actual: %v at: %d %d", p, x, y)
+			}
+			if p.A != 0x80 {
+				t.Errorf("Expected alpha: %d - actual alpha: %d at: %d %d", 0x80, p.A, x, y)
+			}
+		}
+	}
+}
+
+func Test_GrayWorldBalanceRGBA_BlackChannel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 100, G: 50, A: 0xFF})
+	img.SetRGBA(1, 0, color.RGBA{R: 50, G: 100, A: 0xFF})
+	res := GrayWorldBalanceRGBA(img)
+	if p := res.RGBAAt(0, 0); p.B != 0 || p.R != 100 || p.G != 50 {
+		t.Errorf("Expected [100 50 0] - actual: %v", p)
+	}
+}
+
+func Test_WhitePatchBalanceRGBA(t *testing.T) {
+	img := setupBlueCastRamp()
+	res, err := WhitePatchBalanceRGBA(img, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	brightest := res.RGBAAt(63, 0)
+	if brightest.R != 255 || brightest.G != 255 || brightest.B != 255 {
+		t.Errorf("Expected the brightest pixel to become white - actual: %v", brightest)
+	}
+	if brightest.A != 0x80 {
+		t.Errorf("Expected alpha: %d - actual alpha: %d", 0x80, brightest.A)
+	}
+	clipped, _ := WhitePatchBalanceRGBA(img, 10)
+	if p := clipped.RGBAAt(58, 0); p.R != 255 || p.B != 255 {
+		t.Errorf("Expected the top 10 percent to be mapped to white - actual: %v", p)
+	}
+	if _, err := WhitePatchBalanceRGBA(img, 100); err == nil {
+		t.Error("Expected error for invalid percentile")
+	}
+}
@@ -0,0 +1,32 @@
+package effects
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// WindowLevelGray applies the window/level display mapping of medical (DICOM) images to a grayscale image. The values
+// of the window [center - width/2, center + width/2] are stretched linearly to the [0, 255] interval, the values below
+// the window are clipped to 0 and the values above it to 255, so the contrast of the tissue of interest is maximized.
+// The center of the window maps to 128. A width of 0 turns the mapping into a binary threshold at the center.
+// Example of usage:
+//
+//	res := effects.WindowLevelGray(img, 100, 60)
+func WindowLevelGray(img *image.Gray, center, width uint8) *image.Gray {
+	low := float64(center) - float64(width)/2
+	high := float64(center) + float64(width)/2
+	var lut [256]uint8
+	for i := range lut {
+		v := float64(i)
+		switch {
+		case v < low:
+			lut[i] = utils.MinUint8
+		case v >= high:
+			lut[i] = utils.MaxUint8
+		default:
+			lut[i] = uint8(math.Round((v - low) / float64(width) * float64(utils.MaxUint8)))
+		}
+	}
+	return utils.ApplyLUTGray(img, lut)
+}
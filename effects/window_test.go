@@ -0,0 +1,60 @@
+package effects
+
+import (
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_WindowLevelGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 256, 1))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	res := WindowLevelGray(img, 100, 60)
+	for v := 0; v < 256; v++ {
+		actual := res.Pix[v]
+		switch {
+		case v <= 70 && actual != 0:
+			t.Errorf("Expected value: 0 below the window - actual value: %d for: %d", actual, v)
+		case v >= 130 && actual != 255:
+			t.Errorf("Expected value: 255 above the window - actual value: %d for: %d", actual, v)
+		case v > 0 && actual < res.Pix[v-1]:
+			t.Errorf("Expected a monotonic mapping - actual values: %d %d for: %d", res.Pix[v-1], actual, v)
+		}
+	}
+	if actual := res.Pix[100]; actual != 128 {
+		t.Errorf("Expected value: 128 at the center of the window - actual value: %d", actual)
+	}
+	// 10 levels into the window of 60 levels
+	if actual := res.Pix[80]; actual != 43 {
+		t.Errorf("Expected value: 43 - actual value: %d", actual)
+	}
+}
+
+func Test_WindowLevelGray_Limits(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 256, 1))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	// a zero width is a threshold at the center
+	res := WindowLevelGray(img, 50, 0)
+	for v := 0; v < 256; v++ {
+		expected := uint8(0)
+		if v >= 50 {
+			expected = 255
+		}
+		if res.Pix[v] != expected {
+			t.Fatalf("Expected value: %d - actual value: %d for: %d", expected, res.Pix[v], v)
+		}
+	}
+	// the full window keeps the image
+	res = WindowLevelGray(img, 128, 255)
+	for v := 0; v < 256; v++ {
+		if d := int(res.Pix[v]) - v; d < -1 || d > 1 {
+			t.Fatalf("Expected value: %d - actual value: %d", v, res.Pix[v])
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
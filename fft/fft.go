@@ -0,0 +1,165 @@
+package fft
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// FFT computes the discrete Fourier transform of the input using the fast Fourier transform. Inputs with a length
+// which is a power of two use the radix-2 Cooley-Tukey algorithm, all other lengths use Bluestein's algorithm. The
+// input is not modified.
+// Example of usage:
+//
+//	spectrum := fft.FFT(signal)
+func FFT(x []complex128) []complex128 {
+	res := make([]complex128, len(x))
+	copy(res, x)
+	transform(res, false)
+	return res
+}
+
+// IFFT computes the inverse discrete Fourier transform of the input, the result is scaled by 1/N so IFFT(FFT(x)) is
+// equal to x. The input is not modified.
+// Example of usage:
+//
+//	signal := fft.IFFT(spectrum)
+func IFFT(x []complex128) []complex128 {
+	res := make([]complex128, len(x))
+	copy(res, x)
+	transform(res, true)
+	scale := complex(1/float64(len(res)), 0)
+	for i := range res {
+		res[i] *= scale
+	}
+	return res
+}
+
+// FFT2D computes the 2D discrete Fourier transform of a row-major matrix with the given width and height by
+// transforming its rows and then its columns.
+// Example of usage:
+//
+//	spectrum, err := fft.FFT2D(data, 512, 512)
+func FFT2D(data []complex128, width int, height int) ([]complex128, error) {
+	return transform2D(data, width, height, false)
+}
+
+// IFFT2D computes the inverse 2D discrete Fourier transform of a row-major matrix with the given width and height, the
+// result is scaled by 1/(width*height).
+// Example of usage:
+//
+//	data, err := fft.IFFT2D(spectrum, 512, 512)
+func IFFT2D(data []complex128, width int, height int) ([]complex128, error) {
+	return transform2D(data, width, height, true)
+}
+
+// -------------------------------------------------------------------------------------------------------
+func transform2D(data []complex128, width int, height int, inverse bool) ([]complex128, error) {
+	if width <= 0 || height <= 0 || len(data) != width*height {
+		return nil, errors.New("the size of the data does not match the given width and height")
+	}
+	res := make([]complex128, len(data))
+	copy(res, data)
+	for y := 0; y < height; y++ {
+		transform(res[y*width:(y+1)*width], inverse)
+	}
+	column := make([]complex128, height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			column[y] = res[y*width+x]
+		}
+		transform(column, inverse)
+		for y := 0; y < height; y++ {
+			res[y*width+x] = column[y]
+		}
+	}
+	if inverse {
+		scale := complex(1/float64(width*height), 0)
+		for i := range res {
+			res[i] *= scale
+		}
+	}
+	return res, nil
+}
+
+// transform computes the unscaled forward or inverse transform in place.
+func transform(x []complex128, inverse bool) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+	if n&(n-1) == 0 {
+		radix2(x, inverse)
+		return
+	}
+	bluestein(x, inverse)
+}
+
+// radix2 is the iterative Cooley-Tukey algorithm, the length of x has to be a power of two.
+func radix2(x []complex128, inverse bool) {
+	n := len(x)
+	shift := 64 - uint(bits.TrailingZeros(uint(n)))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a := x[start+k]
+				b := x[start+k+size/2] * w
+				x[start+k] = a + b
+				x[start+k+size/2] = a - b
+				w *= step
+			}
+		}
+	}
+}
+
+// bluestein computes a transform of arbitrary length as a convolution of power of two length.
+func bluestein(x []complex128, inverse bool) {
+	n := len(x)
+	m := 1
+	for m < 2*n-1 {
+		m <<= 1
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	chirp := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		// k*k is reduced modulo 2n to keep the angle accurate for long inputs
+		angle := sign * math.Pi * float64((k*k)%(2*n)) / float64(n)
+		chirp[k] = cmplx.Rect(1, angle)
+	}
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * chirp[k]
+	}
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[m-k] = b[k]
+	}
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+	scale := complex(1/float64(m), 0)
+	for k := 0; k < n; k++ {
+		x[k] = a[k] * scale * chirp[k]
+	}
+}
@@ -0,0 +1,77 @@
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func naiveDFT(x []complex128) []complex128 {
+	n := len(x)
+	res := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		for j := 0; j < n; j++ {
+			res[k] += x[j] * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(n))
+		}
+	}
+	return res
+}
+
+func setupTestCaseSignal(n int) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Sin(float64(i)*0.7)+float64(i%5), math.Cos(float64(i)*0.3))
+	}
+	return x
+}
+
+func Test_FFT(t *testing.T) {
+	for _, n := range []int{1, 2, 8, 64, 3, 12, 17, 100} {
+		x := setupTestCaseSignal(n)
+		expected := naiveDFT(x)
+		actual := FFT(x)
+		for i := range expected {
+			if cmplx.Abs(expected[i]-actual[i]) > 1e-9 {
+				t.Errorf("Expected: %v - actual: %v at: %d for length: %d", expected[i], actual[i], i, n)
+			}
+		}
+		restored := IFFT(actual)
+		for i := range x {
+			if cmplx.Abs(x[i]-restored[i]) > 1e-9 {
+				t.Errorf("Expected: %v - actual: %v at: %d for length: %d", x[i], restored[i], i, n)
+			}
+		}
+	}
+}
+
+func Test_FFT2D(t *testing.T) {
+	width, height := 6, 4
+	data := setupTestCaseSignal(width * height)
+	spectrum, err := FFT2D(data, width, height)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the DC component is the sum of all values
+	var sum complex128
+	for _, v := range data {
+		sum += v
+	}
+	if cmplx.Abs(spectrum[0]-sum) > 1e-9 {
+		t.Errorf("Expected DC: %v - actual DC: %v", sum, spectrum[0])
+	}
+	restored, err := IFFT2D(spectrum, width, height)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i := range data {
+		if cmplx.Abs(data[i]-restored[i]) > 1e-9 {
+			t.Errorf("Expected: %v - actual: %v at: %d", data[i], restored[i], i)
+		}
+	}
+	if _, err := FFT2D(data, 5, 4); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,217 @@
+package fitting
+
+import (
+	"github.com/yafeiliu/imger/geometry"
+	"image"
+	"math"
+	"math/rand"
+)
+
+// ransacSeed seeds the random sampling of the RANSAC functions, so the same input always gives the same result.
+const ransacSeed = 1
+
+// FitLineRANSAC fits a line a*x + b*y + c = 0 to a set of points which can contain outliers, e.g. the points of a
+// contour. In every iteration two random points define a candidate line and the points closer to it then the distance
+// threshold are its inliers. The candidate with the most inliers is refined by a total least squares fit of its inliers
+// and the inliers of the refined line are returned as indices into points. The line is normalized so that
+// a^2 + b^2 = 1 and the first non-zero of a and b is positive, so c is the signed distance of the origin.
+// The random sampling uses a fixed seed, so the result is reproducible. Returns zeros and no inliers if there are less
+// then 2 distinct points or the number of iterations is not positive.
+// Example of usage:
+//
+//	a, b, c, inliers := fitting.FitLineRANSAC(points, 200, 1.5)
+func FitLineRANSAC(points []image.Point, iterations int, threshold float64) (a, b, c float64, inliers []int) {
+	if len(points) < 2 || iterations <= 0 {
+		return 0, 0, 0, nil
+	}
+	rng := rand.New(rand.NewSource(ransacSeed))
+	var best []int
+	for i := 0; i < iterations; i++ {
+		p, q := points[rng.Intn(len(points))], points[rng.Intn(len(points))]
+		la, lb, lc, ok := lineThrough(p, q)
+		if !ok {
+			continue
+		}
+		if candidate := lineInliers(points, la, lb, lc, threshold); len(candidate) > len(best) {
+			best = candidate
+			a, b, c = la, lb, lc
+		}
+	}
+	if best == nil {
+		return 0, 0, 0, nil
+	}
+	if ra, rb, rc, ok := fitLineTLS(points, best); ok {
+		if refined := lineInliers(points, ra, rb, rc, threshold); len(refined) >= len(best) {
+			a, b, c, best = ra, rb, rc, refined
+		}
+	}
+	return a, b, c, best
+}
+
+// FitCircleRANSAC fits a circle to a set of points which can contain outliers. In every iteration three random points
+// define a candidate circle and the points closer to its perimeter then the distance threshold are its inliers. The
+// candidate with the most inliers is refined by an algebraic least squares fit (Kasa) of its inliers and the inliers of
+// the refined circle are returned as indices into points. The random sampling uses a fixed seed, so the result is
+// reproducible. Returns zeros and no inliers if there are less then 3 points which are not collinear or the number of
+// iterations is not positive.
+// Example of usage:
+//
+//	center, radius, inliers := fitting.FitCircleRANSAC(points, 500, 1.5)
+func FitCircleRANSAC(points []image.Point, iterations int, threshold float64) (center geometry.Point2f, radius float64, inliers []int) {
+	if len(points) < 3 || iterations <= 0 {
+		return geometry.Point2f{}, 0, nil
+	}
+	rng := rand.New(rand.NewSource(ransacSeed))
+	var best []int
+	for i := 0; i < iterations; i++ {
+		c, r, ok := circleThrough(points[rng.Intn(len(points))], points[rng.Intn(len(points))], points[rng.Intn(len(points))])
+		if !ok {
+			continue
+		}
+		if candidate := circleInliers(points, c, r, threshold); len(candidate) > len(best) {
+			best = candidate
+			center, radius = c, r
+		}
+	}
+	if best == nil {
+		return geometry.Point2f{}, 0, nil
+	}
+	if c, r, ok := fitCircleKasa(points, best); ok {
+		if refined := circleInliers(points, c, r, threshold); len(refined) >= len(best) {
+			center, radius, best = c, r, refined
+		}
+	}
+	return center, radius, best
+}
+
+// -------------------------------------------------------------------------------------------------------
+// lineThrough returns the normalized line through two points, false if the points are the same.
+func lineThrough(p, q image.Point) (a, b, c float64, ok bool) {
+	a, b = float64(q.Y-p.Y), float64(p.X-q.X)
+	return normalizeLine(a, b, -a*float64(p.X)-b*float64(p.Y))
+}
+
+// normalizeLine scales a line so that a^2 + b^2 = 1 and the first non-zero of a and b is positive.
+func normalizeLine(a, b, c float64) (float64, float64, float64, bool) {
+	norm := math.Hypot(a, b)
+	if norm < 1e-12 {
+		return 0, 0, 0, false
+	}
+	if a < 0 || (a == 0 && b < 0) {
+		norm = -norm
+	}
+	return a / norm, b / norm, c / norm, true
+}
+
+func lineInliers(points []image.Point, a, b, c, threshold float64) []int {
+	var inliers []int
+	for i, p := range points {
+		if math.Abs(a*float64(p.X)+b*float64(p.Y)+c) <= threshold {
+			inliers = append(inliers, i)
+		}
+	}
+	return inliers
+}
+
+// fitLineTLS fits a line to the selected points by total least squares: the line goes through the centroid and its
+// normal is the direction of the smallest variance.
+func fitLineTLS(points []image.Point, selected []int) (a, b, c float64, ok bool) {
+	var mx, my float64
+	for _, i := range selected {
+		mx += float64(points[i].X)
+		my += float64(points[i].Y)
+	}
+	n := float64(len(selected))
+	mx, my = mx/n, my/n
+	var sxx, sxy, syy float64
+	for _, i := range selected {
+		dx, dy := float64(points[i].X)-mx, float64(points[i].Y)-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	// direction of the largest variance, the normal is perpendicular to it
+	theta := 0.5 * math.Atan2(2*sxy, sxx-syy)
+	a, b = -math.Sin(theta), math.Cos(theta)
+	return normalizeLine(a, b, -a*mx-b*my)
+}
+
+// circleThrough returns the circle through three points, false if the points are collinear.
+func circleThrough(p1, p2, p3 image.Point) (geometry.Point2f, float64, bool) {
+	ax, ay := float64(p1.X), float64(p1.Y)
+	bx, by := float64(p2.X), float64(p2.Y)
+	cx, cy := float64(p3.X), float64(p3.Y)
+	d := 2 * (ax*(by-cy) + bx*(cy-ay) + cx*(ay-by))
+	if math.Abs(d) < 1e-12 {
+		return geometry.Point2f{}, 0, false
+	}
+	a2, b2, c2 := ax*ax+ay*ay, bx*bx+by*by, cx*cx+cy*cy
+	center := geometry.Point2f{
+		X: (a2*(by-cy) + b2*(cy-ay) + c2*(ay-by)) / d,
+		Y: (a2*(cx-bx) + b2*(ax-cx) + c2*(bx-ax)) / d,
+	}
+	return center, math.Hypot(ax-center.X, ay-center.Y), true
+}
+
+func circleInliers(points []image.Point, center geometry.Point2f, radius, threshold float64) []int {
+	var inliers []int
+	for i, p := range points {
+		if math.Abs(math.Hypot(float64(p.X)-center.X, float64(p.Y)-center.Y)-radius) <= threshold {
+			inliers = append(inliers, i)
+		}
+	}
+	return inliers
+}
+
+// fitCircleKasa fits a circle to the selected points by solving the linear least squares problem
+// x^2 + y^2 + D*x + E*y + F = 0, relative to the centroid for numerical stability.
+func fitCircleKasa(points []image.Point, selected []int) (geometry.Point2f, float64, bool) {
+	var mx, my float64
+	for _, i := range selected {
+		mx += float64(points[i].X)
+		my += float64(points[i].Y)
+	}
+	n := float64(len(selected))
+	mx, my = mx/n, my/n
+	// normal equations of [x y 1] * [D E F]^T = -(x^2 + y^2)
+	var m [3][4]float64
+	for _, i := range selected {
+		x, y := float64(points[i].X)-mx, float64(points[i].Y)-my
+		row := [3]float64{x, y, 1}
+		rhs := -(x*x + y*y)
+		for r := 0; r < 3; r++ {
+			for k := 0; k < 3; k++ {
+				m[r][k] += row[r] * row[k]
+			}
+			m[r][3] += row[r] * rhs
+		}
+	}
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < 3; col++ {
+		pivot := col
+		for r := col + 1; r < 3; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return geometry.Point2f{}, 0, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for r := 0; r < 3; r++ {
+			if r == col {
+				continue
+			}
+			f := m[r][col] / m[col][col]
+			for k := col; k < 4; k++ {
+				m[r][k] -= f * m[col][k]
+			}
+		}
+	}
+	d, e, f := m[0][3]/m[0][0], m[1][3]/m[1][1], m[2][3]/m[2][2]
+	r2 := d*d/4 + e*e/4 - f
+	if r2 <= 0 {
+		return geometry.Point2f{}, 0, false
+	}
+	return geometry.Point2f{X: mx - d/2, Y: my - e/2}, math.Sqrt(r2), true
+}
@@ -0,0 +1,93 @@
+package fitting
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_FitLineRANSAC(t *testing.T) {
+	// 100 points on the line y = 0.5 * x + 10 (0.5 * x - y + 10 = 0) followed by 40 outliers
+	rng := rand.New(rand.NewSource(5))
+	var points []image.Point
+	for x := 0; x < 100; x++ {
+		points = append(points, image.Point{X: x, Y: int(math.Round(0.5*float64(x) + 10))})
+	}
+	for len(points) < 140 {
+		p := image.Point{X: rng.Intn(100), Y: rng.Intn(100)}
+		if math.Abs(0.5*float64(p.X)-float64(p.Y)+10) > 10 {
+			points = append(points, p)
+		}
+	}
+	a, b, c, inliers := FitLineRANSAC(points, 200, 1)
+	norm := math.Sqrt(1.25)
+	if math.Abs(a-0.5/norm) > 0.01 || math.Abs(b+1/norm) > 0.01 || math.Abs(c-10/norm) > 0.3 {
+		t.Errorf("Expected the line %f %f %f - actual: %f %f %f", 0.5/norm, -1/norm, 10/norm, a, b, c)
+	}
+	if len(inliers) != 100 {
+		t.Errorf("Expected 100 inliers - actual: %d", len(inliers))
+	}
+	for _, i := range inliers {
+		if i >= 100 {
+			t.Errorf("Expected the outlier %v to be excluded", points[i])
+		}
+	}
+}
+
+func Test_FitLineRANSAC_Vertical(t *testing.T) {
+	points := []image.Point{{X: 7, Y: 0}, {X: 7, Y: 5}, {X: 7, Y: 9}, {X: 7, Y: 20}, {X: 30, Y: 2}}
+	a, b, c, inliers := FitLineRANSAC(points, 50, 0.5)
+	if math.Abs(a-1) > 1e-9 || math.Abs(b) > 1e-9 || math.Abs(c+7) > 1e-9 {
+		t.Errorf("Expected the line x - 7 = 0 - actual: %f %f %f", a, b, c)
+	}
+	if len(inliers) != 4 {
+		t.Errorf("Expected 4 inliers - actual: %d", len(inliers))
+	}
+}
+
+func Test_FitLineRANSAC_Degenerate(t *testing.T) {
+	if _, _, _, inliers := FitLineRANSAC([]image.Point{{X: 1, Y: 1}}, 10, 1); inliers != nil {
+		t.Errorf("Expected no inliers for a single point")
+	}
+	if _, _, _, inliers := FitLineRANSAC([]image.Point{{X: 1, Y: 1}, {X: 1, Y: 1}}, 10, 1); inliers != nil {
+		t.Errorf("Expected no inliers for identical points")
+	}
+	if _, _, _, inliers := FitLineRANSAC([]image.Point{{X: 1, Y: 1}, {X: 2, Y: 1}}, 0, 1); inliers != nil {
+		t.Errorf("Expected no inliers without iterations")
+	}
+}
+
+func Test_FitCircleRANSAC(t *testing.T) {
+	// 90 points on the circle with center (50, 40) and radius 25 followed by 30 outliers
+	rng := rand.New(rand.NewSource(9))
+	var points []image.Point
+	for i := 0; i < 90; i++ {
+		angle := 2 * math.Pi * float64(i) / 90
+		points = append(points, image.Point{X: int(math.Round(50 + 25*math.Cos(angle))), Y: int(math.Round(40 + 25*math.Sin(angle)))})
+	}
+	for len(points) < 120 {
+		p := image.Point{X: rng.Intn(100), Y: rng.Intn(80)}
+		if math.Abs(math.Hypot(float64(p.X-50), float64(p.Y-40))-25) > 8 {
+			points = append(points, p)
+		}
+	}
+	center, radius, inliers := FitCircleRANSAC(points, 300, 1)
+	if math.Abs(center.X-50) > 0.3 || math.Abs(center.Y-40) > 0.3 || math.Abs(radius-25) > 0.3 {
+		t.Errorf("Expected the circle (50, 40) 25 - actual: (%f, %f) %f", center.X, center.Y, radius)
+	}
+	if len(inliers) != 90 {
+		t.Errorf("Expected 90 inliers - actual: %d", len(inliers))
+	}
+	for _, i := range inliers {
+		if i >= 90 {
+			t.Errorf("Expected the outlier %v to be excluded", points[i])
+		}
+	}
+	if _, _, inliers := FitCircleRANSAC([]image.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}, 20, 1); inliers != nil {
+		t.Errorf("Expected no inliers for collinear points")
+	}
+}
+
+// -------------------------------------------------------------------------------
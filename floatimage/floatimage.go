@@ -0,0 +1,98 @@
+// Package floatimage provides an image type with any number of channels holding floating point values, e.g. for
+// multispectral or scientific data which does not fit into the image types of the standard library.
+package floatimage
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/convolution"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// FloatImage is an image with Channels planes of floating point values. Every plane is stored in row-major order, the
+// value of the channel c at the {x, y} position is Planes[c][y*Width+x].
+type FloatImage struct {
+	Planes   [][]float64
+	Width    int
+	Height   int
+	Channels int
+}
+
+// NewFloatImage creates a new FloatImage with the given size and number of channels where every value is 0. Returns an
+// error if the size or the number of channels is negative.
+// Example of usage:
+//
+//	img, err := floatimage.NewFloatImage(640, 480, 8)
+func NewFloatImage(width, height, channels int) (*FloatImage, error) {
+	if width < 0 || height < 0 || channels < 0 {
+		return nil, errors.New("negative size")
+	}
+	planes := make([][]float64, channels)
+	for c := range planes {
+		planes[c] = make([]float64, width*height)
+	}
+	return &FloatImage{Planes: planes, Width: width, Height: height, Channels: channels}, nil
+}
+
+// At returns the value of the channel c at the {x, y} position of the image.
+func (f *FloatImage) At(x, y, c int) float64 {
+	return f.Planes[c][y*f.Width+x]
+}
+
+// Set sets the value of the channel c at the {x, y} position of the image.
+func (f *FloatImage) Set(x, y, c int, value float64) {
+	f.Planes[c][y*f.Width+x] = value
+}
+
+// Size returns the size of the image.
+func (f *FloatImage) Size() image.Point {
+	return image.Point{X: f.Width, Y: f.Height}
+}
+
+// Clone returns a deep copy of the image.
+func (f *FloatImage) Clone() *FloatImage {
+	planes := make([][]float64, len(f.Planes))
+	for c, plane := range f.Planes {
+		planes[c] = append([]float64(nil), plane...)
+	}
+	return &FloatImage{Planes: planes, Width: f.Width, Height: f.Height, Channels: f.Channels}
+}
+
+// ConvolvePlane applies a convolution matrix to the channel c and returns a copy of the image where only that channel
+// is replaced by the unclamped response, see convolution.ConvolvePlane. Returns an error if the channel does not exist
+// or the convolution fails.
+// Example of usage:
+//
+//	res, err := img.ConvolvePlane(3, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+func (f *FloatImage) ConvolvePlane(c int, kernel *convolution.Kernel, anchor image.Point, border padding.Border) (*FloatImage, error) {
+	if c < 0 || c >= len(f.Planes) {
+		return nil, errors.New("channel index out of range")
+	}
+	plane, err := convolution.ConvolvePlane(f.Planes[c], f.Size(), kernel.Content, anchor, border)
+	if err != nil {
+		return nil, err
+	}
+	res := f.Clone()
+	res.Planes[c] = plane
+	return res, nil
+}
+
+// ToGray converts the channel c to a grayscale image, the values are rounded and clamped to the [0, 255] interval.
+// Returns an error if the channel does not exist.
+// Example of usage:
+//
+//	gray, err := img.ToGray(0)
+func (f *FloatImage) ToGray(c int) (*image.Gray, error) {
+	if c < 0 || c >= len(f.Planes) {
+		return nil, errors.New("channel index out of range")
+	}
+	res := image.NewGray(image.Rect(0, 0, f.Width, f.Height))
+	utils.ParallelForEachPixel(f.Size(), func(x, y int) {
+		v := utils.ClampF64(math.Round(f.At(x, y, c)), utils.MinUint8, float64(utils.MaxUint8))
+		res.SetGray(x, y, color.Gray{Y: uint8(v)})
+	})
+	return res, nil
+}
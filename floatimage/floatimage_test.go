@@ -0,0 +1,84 @@
+package floatimage
+
+import (
+	"github.com/yafeiliu/imger/convolution"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_FloatImage_ConvolvePlane(t *testing.T) {
+	img, err := NewFloatImage(9, 7, 2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 9; x++ {
+			img.Set(x, y, 0, float64(x*y)-10.5)
+			img.Set(x, y, 1, float64(x+y)/3)
+		}
+	}
+	before := img.Clone()
+	box, _ := convolution.NewKernel(3, 3)
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			box.Set(x, y, 1.0/9)
+		}
+	}
+	res, err := img.ConvolvePlane(0, box, image.Point{X: 1, Y: 1}, padding.BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the mean of the 3x3 neighbourhood of an inner pixel
+	var expected float64
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			expected += img.At(4+dx, 3+dy, 0) / 9
+		}
+	}
+	if !utils.IsEqualFloat64(expected, res.At(4, 3, 0)) {
+		t.Errorf("Expected value: %f - actual value: %f", expected, res.At(4, 3, 0))
+	}
+	// the corner replicates its own value
+	expected = (4*img.At(0, 0, 0) + 2*img.At(1, 0, 0) + 2*img.At(0, 1, 0) + img.At(1, 1, 0)) / 9
+	if !utils.IsEqualFloat64(expected, res.At(0, 0, 0)) {
+		t.Errorf("Expected value: %f - actual value: %f", expected, res.At(0, 0, 0))
+	}
+	for i, v := range before.Planes[1] {
+		if res.Planes[1][i] != v {
+			t.Fatalf("Expected the other channel to be untouched - actual value: %f at: %d", res.Planes[1][i], i)
+		}
+	}
+	for c := range before.Planes {
+		for i, v := range before.Planes[c] {
+			if img.Planes[c][i] != v {
+				t.Fatalf("Expected the input to be unchanged - channel %d at: %d", c, i)
+			}
+		}
+	}
+	if _, err := img.ConvolvePlane(2, box, image.Point{X: 1, Y: 1}, padding.BorderReplicate); err == nil {
+		t.Error("Expected error for a channel out of range")
+	}
+}
+
+func Test_FloatImage_ToGray(t *testing.T) {
+	img, _ := NewFloatImage(4, 1, 1)
+	for x, v := range []float64{-3, 12.4, 12.6, 300} {
+		img.Set(x, 0, 0, v)
+	}
+	res, err := img.ToGray(0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, &image.Gray{Rect: image.Rect(0, 0, 4, 1), Stride: 4, Pix: []uint8{0, 12, 13, 255}}, res)
+	if _, err := img.ToGray(1); err == nil {
+		t.Error("Expected error for a channel out of range")
+	}
+	if _, err := NewFloatImage(2, -1, 3); err == nil {
+		t.Error("Expected error for a negative size")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,72 @@
+package generate
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// CheckerboardGray generates a grayscale checkerboard with square cells of the given size, the top left cell has the
+// color c1 and the cells alternate between c1 and c2 in both directions. The cells at the right and bottom borders are
+// cropped if the size is not a multiple of the cell size. A cell size smaller then 1 is treated as 1. It gives
+// deterministic inputs with sharp edges, e.g. for tests.
+// Example of usage:
+//
+//	img := generate.CheckerboardGray(image.Point{X: 64, Y: 48}, 8, color.Gray{Y: 0}, color.Gray{Y: 255})
+func CheckerboardGray(size image.Point, cellSize int, c1, c2 color.Gray) *image.Gray {
+	if cellSize < 1 {
+		cellSize = 1
+	}
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			c := c1
+			if (x/cellSize+y/cellSize)%2 == 1 {
+				c = c2
+			}
+			res.Pix[y*res.Stride+x] = c.Y
+		}
+	}
+	return res
+}
+
+// GradientGray generates a grayscale gradient going from 0 at the left (H) or top (V) border to 255 at the opposite
+// border, the values grow linearly and are rounded to the nearest integer.
+// Example of usage:
+//
+//	img := generate.GradientGray(image.Point{X: 256, Y: 32}, generate.H)
+func GradientGray(size image.Point, direction Direction) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	length := size.X
+	if direction == V {
+		length = size.Y
+	}
+	value := func(i int) uint8 {
+		if length < 2 {
+			return 0
+		}
+		return uint8(math.Round(255 * float64(i) / float64(length-1)))
+	}
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if direction == V {
+				res.Pix[y*res.Stride+x] = value(y)
+			} else {
+				res.Pix[y*res.Stride+x] = value(x)
+			}
+		}
+	}
+	return res
+}
+
+// SolidGray generates a grayscale image filled with the value v.
+// Example of usage:
+//
+//	img := generate.SolidGray(image.Point{X: 64, Y: 48}, 128)
+func SolidGray(size image.Point, v uint8) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for i := range res.Pix {
+		res.Pix[i] = v
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_CheckerboardGray(t *testing.T) {
+	c1, c2 := color.Gray{Y: 20}, color.Gray{Y: 230}
+	img := CheckerboardGray(image.Point{X: 13, Y: 10}, 4, c1, c2)
+	if expected := image.Rect(0, 0, 13, 10); img.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, img.Bounds())
+	}
+	cases := []struct {
+		x, y     int
+		expected color.Gray
+	}{
+		{0, 0, c1}, {3, 3, c1}, {4, 0, c2}, {3, 4, c2}, {4, 4, c1}, {7, 7, c1}, {8, 3, c1}, {12, 9, c2}, {12, 0, c2},
+	}
+	for _, c := range cases {
+		if actual := img.GrayAt(c.x, c.y); actual != c.expected {
+			t.Errorf("Expected value: %d - actual value: %d at: %d %d", c.expected.Y, actual.Y, c.x, c.y)
+		}
+	}
+	// a cell size of 0 alternates every pixel
+	pixels := CheckerboardGray(image.Point{X: 3, Y: 2}, 0, c1, c2)
+	if expected := []uint8{20, 230, 20, 230, 20, 230}; string(pixels.Pix) != string(expected) {
+		t.Errorf("Expected pixels: %v - actual pixels: %v", expected, pixels.Pix)
+	}
+}
+
+func Test_GradientGray(t *testing.T) {
+	for _, direction := range []Direction{H, V} {
+		img := GradientGray(image.Point{X: 40, Y: 30}, direction)
+		size := img.Bounds().Size()
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				along, across := x, y
+				if direction == V {
+					along, across = y, x
+				}
+				if along > 0 {
+					previous := img.GrayAt(x-1, y)
+					if direction == V {
+						previous = img.GrayAt(x, y-1)
+					}
+					if img.GrayAt(x, y).Y <= previous.Y {
+						t.Fatalf("Expected increasing values along direction %d at: %d %d", direction, x, y)
+					}
+				}
+				if across > 0 {
+					neighbour := img.GrayAt(x, y-1)
+					if direction == V {
+						neighbour = img.GrayAt(x-1, y)
+					}
+					if img.GrayAt(x, y) != neighbour {
+						t.Fatalf("Expected constant values across direction %d at: %d %d", direction, x, y)
+					}
+				}
+			}
+		}
+		last := img.GrayAt(size.X-1, 0).Y
+		if direction == V {
+			last = img.GrayAt(0, size.Y-1).Y
+		}
+		if img.GrayAt(0, 0).Y != 0 || last != 255 {
+			t.Errorf("Expected the gradient to go from 0 to 255 - actual: %d to %d", img.GrayAt(0, 0).Y, last)
+		}
+	}
+}
+
+func Test_SolidGray(t *testing.T) {
+	img := SolidGray(image.Point{X: 5, Y: 3}, 77)
+	if img.Bounds() != image.Rect(0, 0, 5, 3) {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", image.Rect(0, 0, 5, 3), img.Bounds())
+	}
+	for i, v := range img.Pix {
+		if v != 77 {
+			t.Fatalf("Expected value: 77 - actual value: %d at: %d", v, i)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
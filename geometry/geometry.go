@@ -0,0 +1,211 @@
+package geometry
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// Point2f is a point with floating point coordinates.
+type Point2f struct {
+	X float64
+	Y float64
+}
+
+// RotatedRect is a rectangle which can be rotated around its center. The Angle is given in degrees and it is always
+// inside the [0, 90) interval. The Width is measured along the direction given by the Angle, the Height is measured
+// perpendicular to it.
+type RotatedRect struct {
+	Center Point2f
+	Width  float64
+	Height float64
+	Angle  float64
+}
+
+// ConvexHull computes the convex hull of a set of points using Andrew's monotone chain algorithm. The vertices of the
+// hull are returned in counterclockwise order (with the y axis pointing up), starting from the leftmost point.
+// Collinear points on the edges of the hull are not included. For less then 3 distinct points the distinct points are
+// returned, for collinear points the two endpoints are returned.
+// Example of usage:
+//
+//	hull := geometry.ConvexHull(points)
+func ConvexHull(points []image.Point) []image.Point {
+	sorted := make([]image.Point, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+	hull := make([]image.Point, 0, 2*len(unique))
+	// lower hull
+	for _, p := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// upper hull
+	lowerLen := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lowerLen && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
+
+// MinAreaRect computes the rotated rectangle with the minimum area which contains every point using the rotating
+// calipers method on the convex hull of the points. An empty input returns a zero RotatedRect.
+// Example of usage:
+//
+//	rect := geometry.MinAreaRect(points)
+func MinAreaRect(points []image.Point) RotatedRect {
+	hull := ConvexHull(points)
+	switch len(hull) {
+	case 0:
+		return RotatedRect{}
+	case 1:
+		return RotatedRect{Center: Point2f{X: float64(hull[0].X), Y: float64(hull[0].Y)}}
+	}
+	best := RotatedRect{}
+	minArea := math.Inf(1)
+	for i := range hull {
+		p1 := hull[i]
+		p2 := hull[(i+1)%len(hull)]
+		dx := float64(p2.X - p1.X)
+		dy := float64(p2.Y - p1.Y)
+		length := math.Hypot(dx, dy)
+		ux, uy := dx/length, dy/length
+		vx, vy := -uy, ux
+		minU, maxU := math.Inf(1), math.Inf(-1)
+		minV, maxV := math.Inf(1), math.Inf(-1)
+		for _, p := range hull {
+			u := float64(p.X)*ux + float64(p.Y)*uy
+			v := float64(p.X)*vx + float64(p.Y)*vy
+			minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+			minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+		}
+		area := (maxU - minU) * (maxV - minV)
+		if area < minArea {
+			minArea = area
+			cu := (minU + maxU) / 2
+			cv := (minV + maxV) / 2
+			best = normalizeRotatedRect(RotatedRect{
+				Center: Point2f{X: cu*ux + cv*vx, Y: cu*uy + cv*vy},
+				Width:  maxU - minU,
+				Height: maxV - minV,
+				Angle:  math.Atan2(uy, ux) * 180 / math.Pi,
+			})
+		}
+	}
+	return best
+}
+
+// MinEnclosingCircle computes the smallest circle which contains every point using Welzl's algorithm. The center of
+// the circle is rounded to the nearest pixel and the radius is adjusted so that every point is still inside the
+// circle. An empty input returns a zero center and radius.
+// Example of usage:
+//
+//	center, radius := geometry.MinEnclosingCircle(points)
+func MinEnclosingCircle(points []image.Point) (image.Point, float64) {
+	hull := ConvexHull(points)
+	if len(hull) == 0 {
+		return image.Point{}, 0
+	}
+	pts := make([]Point2f, len(hull))
+	for i, p := range hull {
+		pts[i] = Point2f{X: float64(p.X), Y: float64(p.Y)}
+	}
+	c, r := circleFrom1(pts[0])
+	for i := 1; i < len(pts); i++ {
+		if isInCircle(c, r, pts[i]) {
+			continue
+		}
+		c, r = circleFrom1(pts[i])
+		for j := 0; j < i; j++ {
+			if isInCircle(c, r, pts[j]) {
+				continue
+			}
+			c, r = circleFrom2(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if !isInCircle(c, r, pts[k]) {
+					c, r = circleFrom3(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	center := image.Point{X: int(math.Round(c.X)), Y: int(math.Round(c.Y))}
+	var radius float64
+	for _, p := range hull {
+		radius = math.Max(radius, math.Hypot(float64(p.X-center.X), float64(p.Y-center.Y)))
+	}
+	return center, radius
+}
+
+// -------------------------------------------------------------------------------------------------------
+func cross(o, a, b image.Point) int {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+func normalizeRotatedRect(r RotatedRect) RotatedRect {
+	r.Angle = math.Mod(r.Angle, 180)
+	if r.Angle < 0 {
+		r.Angle += 180
+	}
+	if r.Angle >= 90 {
+		r.Angle -= 90
+		r.Width, r.Height = r.Height, r.Width
+	}
+	return r
+}
+
+func isInCircle(c Point2f, r float64, p Point2f) bool {
+	return math.Hypot(p.X-c.X, p.Y-c.Y) <= r+1e-9
+}
+
+func circleFrom1(p Point2f) (Point2f, float64) {
+	return p, 0
+}
+
+func circleFrom2(a, b Point2f) (Point2f, float64) {
+	c := Point2f{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+	return c, math.Hypot(a.X-c.X, a.Y-c.Y)
+}
+
+func circleFrom3(a, b, c Point2f) (Point2f, float64) {
+	d := 2 * (a.X*(b.Y-c.Y) + b.X*(c.Y-a.Y) + c.X*(a.Y-b.Y))
+	if math.Abs(d) < 1e-12 {
+		// collinear points, the circle is defined by the two farthest points
+		c1, r1 := circleFrom2(a, b)
+		c2, r2 := circleFrom2(a, c)
+		c3, r3 := circleFrom2(b, c)
+		if r1 >= r2 && r1 >= r3 {
+			return c1, r1
+		}
+		if r2 >= r3 {
+			return c2, r2
+		}
+		return c3, r3
+	}
+	a2 := a.X*a.X + a.Y*a.Y
+	b2 := b.X*b.X + b.Y*b.Y
+	c2 := c.X*c.X + c.Y*c.Y
+	center := Point2f{
+		X: (a2*(b.Y-c.Y) + b2*(c.Y-a.Y) + c2*(a.Y-b.Y)) / d,
+		Y: (a2*(c.X-b.X) + b2*(a.X-c.X) + c2*(b.X-a.X)) / d,
+	}
+	return center, math.Hypot(a.X-center.X, a.Y-center.Y)
+}
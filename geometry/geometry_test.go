@@ -0,0 +1,96 @@
+package geometry
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ConvexHull(t *testing.T) {
+	points := []image.Point{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
+		{X: 2, Y: 2}, {X: 1, Y: 3}, {X: 2, Y: 0}, {X: 3, Y: 1}, {X: 4, Y: 4},
+	}
+	expected := []image.Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+	hull := ConvexHull(points)
+	if len(hull) != len(expected) {
+		t.Fatalf("Expected %d hull vertices - actual: %d (%v)", len(expected), len(hull), hull)
+	}
+	for i := range expected {
+		if hull[i] != expected[i] {
+			t.Errorf("Expected vertex: %v - actual vertex: %v at: %d", expected[i], hull[i], i)
+		}
+	}
+}
+
+func Test_ConvexHull_Degenerate(t *testing.T) {
+	if hull := ConvexHull(nil); len(hull) != 0 {
+		t.Errorf("Expected empty hull - actual: %v", hull)
+	}
+	if hull := ConvexHull([]image.Point{{X: 3, Y: 3}, {X: 3, Y: 3}}); len(hull) != 1 {
+		t.Errorf("Expected a single vertex - actual: %v", hull)
+	}
+	hull := ConvexHull([]image.Point{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 1, Y: 1}, {X: 3, Y: 3}})
+	if len(hull) != 2 || hull[0] != (image.Point{X: 0, Y: 0}) || hull[1] != (image.Point{X: 3, Y: 3}) {
+		t.Errorf("Expected the endpoints of the segment - actual: %v", hull)
+	}
+}
+
+func Test_MinAreaRect_AxisAligned(t *testing.T) {
+	points := []image.Point{{X: 2, Y: 1}, {X: 10, Y: 1}, {X: 10, Y: 5}, {X: 2, Y: 5}}
+	rect := MinAreaRect(points)
+	if !utils.IsEqualFloat64(rect.Angle, 0) {
+		t.Errorf("Expected angle: 0 - actual angle: %f", rect.Angle)
+	}
+	if !utils.IsEqualFloat64(rect.Width, 8) || !utils.IsEqualFloat64(rect.Height, 4) {
+		t.Errorf("Expected size: 8x4 - actual size: %fx%f", rect.Width, rect.Height)
+	}
+	if !utils.IsEqualFloat64(rect.Center.X, 6) || !utils.IsEqualFloat64(rect.Center.Y, 3) {
+		t.Errorf("Expected center: 6 3 - actual center: %f %f", rect.Center.X, rect.Center.Y)
+	}
+}
+
+func Test_MinAreaRect_Rotated(t *testing.T) {
+	points := []image.Point{{X: 0, Y: 2}, {X: 2, Y: 0}, {X: 4, Y: 2}, {X: 2, Y: 4}, {X: 2, Y: 2}}
+	rect := MinAreaRect(points)
+	if !utils.IsEqualFloat64(rect.Angle, 45) {
+		t.Errorf("Expected angle: 45 - actual angle: %f", rect.Angle)
+	}
+	if !utils.IsEqualFloat64(rect.Width*rect.Height, 8) {
+		t.Errorf("Expected area: 8 - actual area: %f", rect.Width*rect.Height)
+	}
+}
+
+func Test_MinAreaRect_Degenerate(t *testing.T) {
+	if rect := MinAreaRect(nil); rect != (RotatedRect{}) {
+		t.Errorf("Expected zero rectangle - actual: %v", rect)
+	}
+	rect := MinAreaRect([]image.Point{{X: 0, Y: 0}, {X: 6, Y: 0}, {X: 3, Y: 0}})
+	if !utils.IsEqualFloat64(rect.Width, 6) || !utils.IsEqualFloat64(rect.Height, 0) {
+		t.Errorf("Expected size: 6x0 - actual size: %fx%f", rect.Width, rect.Height)
+	}
+}
+
+func Test_MinEnclosingCircle(t *testing.T) {
+	points := []image.Point{{X: 0, Y: 5}, {X: 10, Y: 5}, {X: 5, Y: 0}, {X: 5, Y: 10}, {X: 4, Y: 6}}
+	center, radius := MinEnclosingCircle(points)
+	if center != (image.Point{X: 5, Y: 5}) {
+		t.Errorf("Expected center: 5 5 - actual center: %v", center)
+	}
+	if !utils.IsEqualFloat64(radius, 5) {
+		t.Errorf("Expected radius: 5 - actual radius: %f", radius)
+	}
+	for _, p := range points {
+		if math.Hypot(float64(p.X-center.X), float64(p.Y-center.Y)) > radius {
+			t.Errorf("Point %v is outside of the circle", p)
+		}
+	}
+	center, radius = MinEnclosingCircle(nil)
+	if center != (image.Point{}) || radius != 0 {
+		t.Errorf("Expected zero circle - actual: %v %f", center, radius)
+	}
+}
+
+// -------------------------------------------------------------------------------
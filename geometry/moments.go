@@ -0,0 +1,159 @@
+package geometry
+
+import (
+	"image"
+	"math"
+)
+
+// Moments contains the spatial (M), central (Mu) and normalized central (Nu) moments of an image up to the third
+// order. The definitions match the ones used by OpenCV, so Mji is the sum of I(x, y) * x^j * y^i over all pixels.
+type Moments struct {
+	M00, M10, M01, M20, M11, M02, M30, M21, M12, M03 float64
+	Mu20, Mu11, Mu02, Mu30, Mu21, Mu12, Mu03         float64
+	Nu20, Nu11, Nu02, Nu30, Nu21, Nu12, Nu03         float64
+}
+
+// ImageMoments computes the moments of a grayscale image where the value of every pixel is used as its weight. For
+// the moments of a binary shape the image should contain 0 for the background and a constant value for the shape.
+// If the sum of the pixel values is 0, only zero moments are returned.
+// Example of usage:
+//
+//	m := geometry.ImageMoments(img)
+func ImageMoments(img *image.Gray) Moments {
+	return imageMoments(img, func(v uint8) float64 {
+		return float64(v)
+	})
+}
+
+// BinaryImageMoments computes the moments of a grayscale image where every non-zero pixel has the weight 1, so M00 is
+// the number of non-zero pixels (the area of the shape) and (M10 / M00, M01 / M00) is its centroid.
+// Example of usage:
+//
+//	m := geometry.BinaryImageMoments(mask)
+//	area, centroid, orientation := m.M00, m.Centroid(), m.Orientation()
+func BinaryImageMoments(img *image.Gray) Moments {
+	return imageMoments(img, func(v uint8) float64 {
+		if v == 0 {
+			return 0
+		}
+		return 1
+	})
+}
+
+// Centroid returns the center of mass (M10 / M00, M01 / M00). The zero moments give the origin.
+func (m Moments) Centroid() Point2f {
+	if m.M00 == 0 {
+		return Point2f{}
+	}
+	return Point2f{X: m.M10 / m.M00, Y: m.M01 / m.M00}
+}
+
+// Orientation returns the angle of the major axis in radians, measured from the x axis towards the y axis, in the
+// (-Pi/2, Pi/2] interval. It is 0 for shapes without a dominant direction (e.g. a square or a disk).
+func (m Moments) Orientation() float64 {
+	return 0.5 * math.Atan2(2*m.Mu11, m.Mu20-m.Mu02)
+}
+
+// HuMoments computes the seven Hu invariants from the normalized central moments. The invariants do not change when
+// the shape is translated, scaled or rotated, except the seventh one which changes its sign under reflection.
+// Example of usage:
+//
+//	hu := geometry.HuMoments(geometry.ImageMoments(img))
+func HuMoments(m Moments) [7]float64 {
+	var hu [7]float64
+	t0 := m.Nu30 + m.Nu12
+	t1 := m.Nu21 + m.Nu03
+	q0 := t0 * t0
+	q1 := t1 * t1
+	n4 := 4 * m.Nu11
+	s := m.Nu20 + m.Nu02
+	d := m.Nu20 - m.Nu02
+
+	hu[0] = s
+	hu[1] = d*d + n4*m.Nu11
+	hu[3] = q0 + q1
+	hu[5] = d*(q0-q1) + n4*t0*t1
+
+	t0 *= q0 - 3*q1
+	t1 *= 3*q0 - q1
+	q0 = m.Nu30 - 3*m.Nu12
+	q1 = 3*m.Nu21 - m.Nu03
+
+	hu[2] = q0*q0 + q1*q1
+	hu[4] = q0*t0 + q1*t1
+	hu[6] = q1*t0 - q0*t1
+	return hu
+}
+
+// MatchShapes compares two shapes by their Hu moments (see HuMoments) and returns a distance which is 0 for identical
+// shapes and grows as the shapes differ. The moments are compared on a logarithmic scale, as their magnitudes differ
+// by many orders: d = sum(|1/m_a - 1/m_b|) where m = sign(h) * log10(|h|), the moments which are almost 0 in either
+// shape are skipped. This is the first method of the shape matching of OpenCV.
+// Example of usage:
+//
+//	d := geometry.MatchShapes(geometry.HuMoments(geometry.BinaryImageMoments(a)), geometry.HuMoments(geometry.BinaryImageMoments(b)))
+func MatchShapes(a, b [7]float64) float64 {
+	const eps = 1e-5
+	var d float64
+	for i := range a {
+		if math.Abs(a[i]) <= eps || math.Abs(b[i]) <= eps {
+			continue
+		}
+		ma := math.Copysign(math.Log10(math.Abs(a[i])), a[i])
+		mb := math.Copysign(math.Log10(math.Abs(b[i])), b[i])
+		d += math.Abs(1/ma - 1/mb)
+	}
+	return d
+}
+
+// -------------------------------------------------------------------------------------------------------
+func imageMoments(img *image.Gray, weight func(v uint8) float64) Moments {
+	var m Moments
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			v := weight(img.GrayAt(x, y).Y)
+			if v == 0 {
+				continue
+			}
+			fx, fy := float64(x), float64(y)
+			m.M00 += v
+			m.M10 += v * fx
+			m.M01 += v * fy
+			m.M20 += v * fx * fx
+			m.M11 += v * fx * fy
+			m.M02 += v * fy * fy
+			m.M30 += v * fx * fx * fx
+			m.M21 += v * fx * fx * fy
+			m.M12 += v * fx * fy * fy
+			m.M03 += v * fy * fy * fy
+		}
+	}
+	if m.M00 == 0 {
+		return Moments{}
+	}
+
+	cx, cy := m.M10/m.M00, m.M01/m.M00
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			v := weight(img.GrayAt(x, y).Y)
+			if v == 0 {
+				continue
+			}
+			dx, dy := float64(x)-cx, float64(y)-cy
+			m.Mu20 += v * dx * dx
+			m.Mu11 += v * dx * dy
+			m.Mu02 += v * dy * dy
+			m.Mu30 += v * dx * dx * dx
+			m.Mu21 += v * dx * dx * dy
+			m.Mu12 += v * dx * dy * dy
+			m.Mu03 += v * dy * dy * dy
+		}
+	}
+
+	s2 := 1 / (m.M00 * m.M00)
+	s3 := s2 / math.Sqrt(m.M00)
+	m.Nu20, m.Nu11, m.Nu02 = m.Mu20*s2, m.Mu11*s2, m.Mu02*s2
+	m.Nu30, m.Nu21, m.Nu12, m.Nu03 = m.Mu30*s3, m.Mu21*s3, m.Mu12*s3, m.Mu03*s3
+	return m
+}
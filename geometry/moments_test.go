@@ -0,0 +1,136 @@
+package geometry
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseBlob draws an asymmetric L shape with a tail, scaled by the given factor and translated by offset.
+func setupTestCaseBlob(scale int, offset image.Point) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 100, 100))
+	inShape := func(x, y int) bool {
+		return (x >= 2 && x < 12 && y >= 2 && y < 5) || (x >= 2 && x < 5 && y >= 5 && y < 16) || (x >= 5 && x < 8 && y >= 13 && y < 15)
+	}
+	for x := 0; x < 20*scale; x++ {
+		for y := 0; y < 20*scale; y++ {
+			if inShape(x/scale, y/scale) {
+				img.SetGray(x+offset.X, y+offset.Y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	return img
+}
+
+// rotate90Gray rotates the image counterclockwise by 90 degrees.
+func rotate90Gray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.Y, size.X))
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			res.SetGray(y, size.X-1-x, img.GrayAt(x, y))
+		}
+	}
+	return res
+}
+
+func Test_ImageMoments(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	img.SetGray(1, 1, color.Gray{Y: 2})
+	img.SetGray(3, 1, color.Gray{Y: 2})
+	m := ImageMoments(img)
+	expected := Moments{M00: 4, M10: 8, M01: 4, M20: 20, M11: 8, M02: 4, M30: 56, M21: 20, M12: 8, M03: 4,
+		Mu20: 4, Nu20: 0.25}
+	if m != expected {
+		t.Errorf("Expected moments: %+v - actual moments: %+v", expected, m)
+	}
+}
+
+func Test_ImageMoments_Empty(t *testing.T) {
+	m := ImageMoments(image.NewGray(image.Rect(0, 0, 5, 5)))
+	if m != (Moments{}) {
+		t.Errorf("Expected zero moments - actual moments: %+v", m)
+	}
+	for i, h := range HuMoments(m) {
+		if h != 0 {
+			t.Errorf("Expected zero Hu moment - actual: %f at: %d", h, i)
+		}
+	}
+}
+
+func Test_BinaryImageMoments_Rectangle(t *testing.T) {
+	// a filled 12x5 rectangle with varying non-zero values
+	img := image.NewGray(image.Rect(0, 0, 30, 20))
+	for x := 6; x < 18; x++ {
+		for y := 4; y < 9; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(1 + x*y)})
+		}
+	}
+	m := BinaryImageMoments(img)
+	if m.M00 != 60 || int(m.M00) != utils.CountNonZeroGray(img) {
+		t.Errorf("Expected M00 to be the pixel count 60 - actual: %f", m.M00)
+	}
+	if c := m.Centroid(); !utils.IsEqualFloat64(c.X, 11.5) || !utils.IsEqualFloat64(c.Y, 6) {
+		t.Errorf("Expected the centroid at the center of the rectangle (11.5, 6) - actual: %v", c)
+	}
+	if orientation := m.Orientation(); !utils.IsEqualFloat64(orientation, 0) {
+		t.Errorf("Expected a horizontal major axis - actual orientation: %f", orientation)
+	}
+	if orientation := BinaryImageMoments(rotate90Gray(img)).Orientation(); !utils.IsEqualFloat64(math.Abs(orientation), math.Pi/2) {
+		t.Errorf("Expected a vertical major axis - actual orientation: %f", orientation)
+	}
+	if c := BinaryImageMoments(image.NewGray(image.Rect(0, 0, 3, 3))).Centroid(); c.X != 0 || c.Y != 0 {
+		t.Errorf("Expected the origin as the centroid of an empty image - actual: %v", c)
+	}
+}
+
+func Test_HuMoments_Invariance(t *testing.T) {
+	expected := HuMoments(ImageMoments(setupTestCaseBlob(1, image.Point{X: 10, Y: 10})))
+	cases := []struct {
+		name      string
+		img       *image.Gray
+		tolerance float64
+	}{
+		{"translation", setupTestCaseBlob(1, image.Point{X: 57, Y: 31}), 1e-9},
+		{"rotation", rotate90Gray(setupTestCaseBlob(1, image.Point{X: 10, Y: 10})), 1e-9},
+		{"scaling", setupTestCaseBlob(2, image.Point{X: 10, Y: 10}), 0.02},
+	}
+	for _, c := range cases {
+		actual := HuMoments(ImageMoments(c.img))
+		for i := range expected {
+			if math.Abs(actual[i]-expected[i]) > c.tolerance*math.Abs(expected[i]) && !utils.IsEqualFloat64(actual[i], expected[i]) {
+				t.Errorf("%s: expected Hu moment: %g - actual: %g at: %d", c.name, expected[i], actual[i], i)
+			}
+		}
+	}
+}
+
+func Test_MatchShapes(t *testing.T) {
+	shape := HuMoments(BinaryImageMoments(setupTestCaseBlob(2, image.Point{X: 10, Y: 10})))
+	transformed := HuMoments(BinaryImageMoments(rotate90Gray(setupTestCaseBlob(3, image.Point{X: 30, Y: 5}))))
+	disk := image.NewGray(image.Rect(0, 0, 100, 100))
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 100; y++ {
+			if math.Hypot(float64(x-50), float64(y-40)) < 20 {
+				disk.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	other := HuMoments(BinaryImageMoments(disk))
+	if d := MatchShapes(shape, shape); d != 0 {
+		t.Errorf("Expected 0 distance for the same shape - actual: %f", d)
+	}
+	same := MatchShapes(shape, transformed)
+	different := MatchShapes(shape, other)
+	if same > 0.05 {
+		t.Errorf("Expected a small distance for the rotated and scaled shape - actual: %f", same)
+	}
+	if different < 10*same || different < 0.1 {
+		t.Errorf("Expected a larger distance for a different shape - actual: %f (same shape: %f)", different, same)
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -4,6 +4,7 @@ import (
 	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
+	"math"
 )
 
 // Grayscale takes an image on any type and returns the equivalent grayscale image represented on 8 bits.
@@ -25,3 +26,82 @@ func Grayscale16(img image.Image) *image.Gray16 {
 	})
 	return gray
 }
+
+// GrayscaleWeighted takes an RGBA image and returns a grayscale image where every pixel is computed using custom
+// weights for each color channel: gray = wr * R + wg * G + wb * B. The weights do not have to sum to 1, the result is
+// clamped to the [0, 255] interval.
+// Example of usage:
+//
+//	res := grayscale.GrayscaleWeighted(img, 0.2126, 0.7152, 0.0722)
+func GrayscaleWeighted(img *image.RGBA, wr, wg, wb float64) *image.Gray {
+	gray := image.NewGray(img.Bounds())
+	size := img.Bounds().Size()
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		pixel := img.RGBAAt(x, y)
+		value := wr*float64(pixel.R) + wg*float64(pixel.G) + wb*float64(pixel.B)
+		gray.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(value, utils.MinUint8, float64(utils.MaxUint8)))})
+	})
+	return gray
+}
+
+// GrayscaleRGBAAlphaWeighted converts an RGBA image to grayscale taking the alpha channel into account: every pixel is
+// composited over a gray background of the given value, so the luminance of a pixel is weighted by its alpha and the
+// background fills in the rest (gray = alpha * luminance + (1 - alpha) * background). Fully transparent pixels get the
+// background value, whatever color is left in them, and opaque pixels get their luminance, the same as Grayscale.
+// The luminance uses the weights of color.GrayModel and it is limited to the alpha of the pixel, as it is for valid
+// premultiplied colors.
+// Example of usage:
+//
+//	res := grayscale.GrayscaleRGBAAlphaWeighted(img, 255)
+func GrayscaleRGBAAlphaWeighted(img *image.RGBA, background uint8) *image.Gray {
+	gray := image.NewGray(img.Bounds())
+	size := img.Bounds().Size()
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		pixel := img.RGBAAt(x, y)
+		// the color channels are premultiplied by alpha, so the weighted luminance is at most alpha
+		luminance := math.Min(0.299*float64(pixel.R)+0.587*float64(pixel.G)+0.114*float64(pixel.B), float64(pixel.A))
+		value := luminance + float64(utils.MaxUint8-pixel.A)*float64(background)/float64(utils.MaxUint8)
+		gray.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(math.Round(value), utils.MinUint8, float64(utils.MaxUint8)))})
+	})
+	return gray
+}
+
+// GrayFromGray16 converts a 16-bit grayscale image to 8 bits. Without dithering every value is rounded to the nearest
+// 8-bit value (e.g. 0x80FF becomes 0x81) instead of dropping the low byte. With dithering the rounding error is
+// diffused to the neighbouring pixels using Floyd-Steinberg error diffusion, so smooth gradients keep their average
+// level instead of showing bands.
+// Example of usage:
+//
+//	res := grayscale.GrayFromGray16(img, true)
+func GrayFromGray16(img *image.Gray16, dither bool) *image.Gray {
+	bounds := img.Bounds()
+	size := bounds.Size()
+	gray := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	if !dither {
+		utils.ParallelForEachPixel(size, func(x, y int) {
+			value := (int(img.Gray16At(bounds.Min.X+x, bounds.Min.Y+y).Y) + 0x80) >> 8
+			gray.SetGray(x, y, color.Gray{Y: uint8(utils.ClampInt(value, utils.MinUint8, int(utils.MaxUint8)))})
+		})
+		return gray
+	}
+	// errors diffused to the current and to the next row, indexed by x+1 so the neighbours of the border pixels fit
+	current := make([]float64, size.X+2)
+	next := make([]float64, size.X+2)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			value := float64(img.Gray16At(bounds.Min.X+x, bounds.Min.Y+y).Y)/256 + current[x+1]
+			quantized := utils.ClampF64(math.Round(value), utils.MinUint8, float64(utils.MaxUint8))
+			gray.SetGray(x, y, color.Gray{Y: uint8(quantized)})
+			diff := value - quantized
+			current[x+2] += diff * 7 / 16
+			next[x] += diff * 3 / 16
+			next[x+1] += diff * 5 / 16
+			next[x+2] += diff * 1 / 16
+		}
+		current, next = next, current
+		for i := range next {
+			next[i] = 0
+		}
+	}
+	return gray
+}
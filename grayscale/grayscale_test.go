@@ -2,10 +2,107 @@ package grayscale
 
 import (
 	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
+	"math"
 	"testing"
 )
 
+// ---------------------------------Unit tests------------------------------------
+func Test_GrayscaleWeighted_RedChannel(t *testing.T) {
+	rgba := image.RGBA{
+		Rect:   image.Rect(0, 0, 3, 1),
+		Stride: 3 * 4,
+		Pix: []uint8{
+			0x10, 0x80, 0xFF, 0xFF, 0x80, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0x01, 0xFF,
+		},
+	}
+	expected := image.Gray{
+		Rect:   image.Rect(0, 0, 3, 1),
+		Stride: 3,
+		Pix: []uint8{
+			0x10, 0x80, 0xFF,
+		},
+	}
+	actual := GrayscaleWeighted(&rgba, 1, 0, 0)
+	utils.CompareGrayImages(t, &expected, actual)
+}
+
+func Test_GrayscaleRGBAAlphaWeighted(t *testing.T) {
+	// opaque white, opaque red, a fully transparent magenta and a half transparent (premultiplied) gray
+	rgba := image.RGBA{
+		Rect:   image.Rect(0, 0, 4, 1),
+		Stride: 4 * 4,
+		Pix: []uint8{
+			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0xFF, 0xFF, 0x00, 0xFF, 0x00, 0x40, 0x40, 0x40, 0x80,
+		},
+	}
+	expected := image.Gray{
+		Rect:   image.Rect(0, 0, 4, 1),
+		Stride: 4,
+		Pix: []uint8{
+			// 0x40 + 0x7F * 200 / 255 = 163.6
+			0xFF, 0x4C, 0xC8, 0xA4,
+		},
+	}
+	actual := GrayscaleRGBAAlphaWeighted(&rgba, 200)
+	utils.CompareGrayImages(t, &expected, actual)
+	// the opaque pixels match the plain conversion
+	plain := Grayscale(&rgba)
+	for x := 0; x < 2; x++ {
+		if plain.GrayAt(x, 0) != actual.GrayAt(x, 0) {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d", plain.GrayAt(x, 0).Y, actual.GrayAt(x, 0).Y, x)
+		}
+	}
+}
+
+func Test_GrayFromGray16(t *testing.T) {
+	img := &image.Gray16{
+		Rect:   image.Rect(0, 0, 6, 1),
+		Stride: 12,
+		Pix:    []uint8{0x00, 0x00, 0x00, 0x7F, 0x00, 0x80, 0x80, 0x7F, 0x80, 0xFF, 0xFF, 0xFF},
+	}
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 6, 1),
+		Stride: 6,
+		Pix:    []uint8{0x00, 0x00, 0x01, 0x80, 0x81, 0xFF},
+	}
+	utils.CompareGrayImages(t, expected, GrayFromGray16(img, false))
+}
+
+func Test_GrayFromGray16_Dither(t *testing.T) {
+	// a flat region half way between two 8-bit values
+	const width, height = 40, 20
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray16(x, y, color.Gray16{Y: 0x8080})
+	})
+	rounded := GrayFromGray16(img, false)
+	for _, v := range rounded.Pix {
+		if v != 0x81 {
+			t.Fatalf("Expected gray: %d - actual gray: %d", 0x81, v)
+		}
+	}
+	dithered := GrayFromGray16(img, true)
+	var low, high int
+	for _, v := range dithered.Pix {
+		switch v {
+		case 0x80:
+			low++
+		case 0x81:
+			high++
+		default:
+			t.Fatalf("Expected only the two nearest values in the dithered image - actual gray: %d", v)
+		}
+	}
+	if low == 0 || high == 0 || math.Abs(float64(low-high)) > 0.05*width*height {
+		t.Errorf("Expected a dithered pattern with an average of 128.5 - low: %d, high: %d", low, high)
+	}
+}
+
+// ---------------------------------------------------------------------------------
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseRGBA(t *testing.T) *image.RGBA {
 	path := "../res/girl.jpg"
@@ -0,0 +1,168 @@
+package hdr
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/pyramid"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// wellExposedSigma is the standard deviation of the Gaussian curve centered at 0.5 used for the well-exposedness
+// measure.
+const wellExposedSigma = 0.2
+
+// MertensFusionRGBA fuses a stack of bracketed exposures into a single image using the exposure fusion algorithm of
+// Mertens et al. Every image gets a weight map computed from its contrast (absolute value of the Laplacian of the
+// grayscale image), saturation (standard deviation of the R, G and B channels) and well-exposedness (closeness of
+// each channel to the middle of the range), each measure raised to the power of contrastW, saturationW and
+// exposednessW respectively. The weight maps are normalized so they sum to 1 at each pixel and the images are blended
+// using their Laplacian pyramids and the Gaussian pyramids of the weights. The alpha channel of the result is the
+// average alpha of the inputs.
+// At least two images of the same size are required.
+// Example of usage:
+//
+//	res, err := hdr.MertensFusionRGBA([]*image.RGBA{under, normal, over}, 1, 1, 1)
+func MertensFusionRGBA(imgs []*image.RGBA, contrastW, saturationW, exposednessW float64) (*image.RGBA, error) {
+	if len(imgs) < 2 {
+		return nil, errors.New("at least two images are required")
+	}
+	size := imgs[0].Bounds().Size()
+	for _, img := range imgs[1:] {
+		if img.Bounds().Size() != size {
+			return nil, errors.New("the size of the images does not match")
+		}
+	}
+
+	weights := make([]*pyramid.Plane, len(imgs))
+	for i, img := range imgs {
+		weights[i] = mertensWeights(img, contrastW, saturationW, exposednessW)
+	}
+	normalizeWeights(weights)
+
+	levels := pyramidLevels(size)
+	var channels [3]*pyramid.Plane
+	for c := 0; c < 3; c++ {
+		var blended []*pyramid.Plane
+		for i, img := range imgs {
+			lp, err := pyramid.LaplacianPyramid(channelPlane(img, c), levels)
+			if err != nil {
+				return nil, err
+			}
+			gw, err := pyramid.GaussianPyramid(weights[i], levels)
+			if err != nil {
+				return nil, err
+			}
+			if blended == nil {
+				blended = make([]*pyramid.Plane, len(lp))
+				for l := range lp {
+					blended[l] = pyramid.NewPlane(lp[l].Width, lp[l].Height)
+				}
+			}
+			for l := range lp {
+				for j := range lp[l].Pix {
+					blended[l].Pix[j] += lp[l].Pix[j] * gw[l].Pix[j]
+				}
+			}
+		}
+		res, err := pyramid.CollapseLaplacianPyramid(blended)
+		if err != nil {
+			return nil, err
+		}
+		channels[c] = res
+	}
+
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var alpha float64
+		for _, img := range imgs {
+			alpha += float64(img.RGBAAt(x, y).A)
+		}
+		res.SetRGBA(x, y, color.RGBA{
+			R: toUint8(channels[0].At(x, y) * float64(utils.MaxUint8)),
+			G: toUint8(channels[1].At(x, y) * float64(utils.MaxUint8)),
+			B: toUint8(channels[2].At(x, y) * float64(utils.MaxUint8)),
+			A: toUint8(alpha / float64(len(imgs))),
+		})
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func mertensWeights(img *image.RGBA, contrastW, saturationW, exposednessW float64) *pyramid.Plane {
+	size := img.Bounds().Size()
+	gray := pyramid.NewPlane(size.X, size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		c := img.RGBAAt(x, y)
+		gray.Set(x, y, (0.299*float64(c.R)+0.587*float64(c.G)+0.114*float64(c.B))/float64(utils.MaxUint8))
+	})
+
+	res := pyramid.NewPlane(size.X, size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		lap := gray.At(clamp(x-1, size.X), y) + gray.At(clamp(x+1, size.X), y) +
+			gray.At(x, clamp(y-1, size.Y)) + gray.At(x, clamp(y+1, size.Y)) - 4*gray.At(x, y)
+		contrast := math.Abs(lap)
+
+		c := img.RGBAAt(x, y)
+		rgb := [3]float64{
+			float64(c.R) / float64(utils.MaxUint8),
+			float64(c.G) / float64(utils.MaxUint8),
+			float64(c.B) / float64(utils.MaxUint8),
+		}
+		mean := (rgb[0] + rgb[1] + rgb[2]) / 3
+		var variance float64
+		exposedness := 1.0
+		for _, v := range rgb {
+			variance += (v - mean) * (v - mean)
+			exposedness *= math.Exp(-(v - 0.5) * (v - 0.5) / (2 * wellExposedSigma * wellExposedSigma))
+		}
+		saturation := math.Sqrt(variance / 3)
+
+		res.Set(x, y, math.Pow(contrast, contrastW)*math.Pow(saturation, saturationW)*math.Pow(exposedness, exposednessW)+1e-12)
+	})
+	return res
+}
+
+func normalizeWeights(weights []*pyramid.Plane) {
+	for j := range weights[0].Pix {
+		var sum float64
+		for _, w := range weights {
+			sum += w.Pix[j]
+		}
+		for _, w := range weights {
+			w.Pix[j] /= sum
+		}
+	}
+}
+
+func channelPlane(img *image.RGBA, channel int) *pyramid.Plane {
+	size := img.Bounds().Size()
+	res := pyramid.NewPlane(size.X, size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		offset := img.PixOffset(x, y)
+		res.Set(x, y, float64(img.Pix[offset+channel])/float64(utils.MaxUint8))
+	})
+	return res
+}
+
+func pyramidLevels(size image.Point) int {
+	minSide := size.X
+	if size.Y < minSide {
+		minSide = size.Y
+	}
+	levels := 1
+	for minSide > 1 && levels < 8 {
+		minSide /= 2
+		levels++
+	}
+	return levels
+}
+
+func clamp(i int, n int) int {
+	return utils.ClampInt(i, 0, n-1)
+}
+
+func toUint8(v float64) uint8 {
+	return uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+}
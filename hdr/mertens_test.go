@@ -0,0 +1,69 @@
+package hdr
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func setupTestCaseExposures(gain float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 256, 16))
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 16; y++ {
+			scale := func(v float64) uint8 {
+				v *= gain
+				if v > 255 {
+					v = 255
+				}
+				return uint8(v)
+			}
+			v := float64(x)
+			img.SetRGBA(x, y, color.RGBA{R: scale(v), G: scale(v * 0.9), B: scale(v * 0.8), A: 0xFF})
+		}
+	}
+	return img
+}
+
+func Test_MertensFusionRGBA(t *testing.T) {
+	under := setupTestCaseExposures(0.5)
+	over := setupTestCaseExposures(2.0)
+	// a linear gradient has no contrast, so only saturation and well-exposedness are used
+	res, err := MertensFusionRGBA([]*image.RGBA{under, over}, 0, 1, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	var hist [256]int
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 16; y++ {
+			hist[res.RGBAAt(x, y).R]++
+		}
+	}
+	min, max := 0, 255
+	for hist[min] == 0 {
+		min++
+	}
+	for hist[max] == 0 {
+		max--
+	}
+	if hist[0] != 0 || hist[255] != 0 {
+		t.Errorf("Expected no clipped pixels - actual: %d at 0 and %d at 255", hist[0], hist[255])
+	}
+	// the under-exposed image spans [0, 127], the over-exposed one clips half of the gradient
+	if max-min < 160 {
+		t.Errorf("Expected the fused image to span a wider range - actual: [%d, %d]", min, max)
+	}
+}
+
+func Test_MertensFusionRGBA_Invalid(t *testing.T) {
+	img := setupTestCaseExposures(1)
+	if _, err := MertensFusionRGBA([]*image.RGBA{img}, 1, 1, 1); err == nil {
+		t.Error("Should not reach this point")
+	}
+	small := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	if _, err := MertensFusionRGBA([]*image.RGBA{img, small}, 1, 1, 1); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
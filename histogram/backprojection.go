@@ -0,0 +1,166 @@
+package histogram
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	// DefaultMinSaturation is the saturation below which CalcBackProjectHSV and HueHistogramRGBA ignore a pixel,
+	// because the hue of near-gray pixels is unreliable.
+	DefaultMinSaturation = 0.15
+	// DefaultMinValue is the value (brightness) below which CalcBackProjectHSV and HueHistogramRGBA ignore a pixel,
+	// because the hue of near-black pixels is unreliable.
+	DefaultMinValue = 0.15
+)
+
+// HueHistogramRGBA computes the hue histogram of a region of interest of an RGBA image with the given number of bins.
+// The pixels with a saturation below minSaturation or a value below minValue are not counted. The histogram is
+// normalized so its highest bin is 1, which makes it ready to be used with CalcBackProjectHSV. Returns an error if the
+// number of bins is not positive or the ROI is invalid.
+// Example of usage:
+//
+//	hist, err := histogram.HueHistogramRGBA(img, target, 30, histogram.DefaultMinSaturation, histogram.DefaultMinValue)
+func HueHistogramRGBA(img *image.RGBA, roi image.Rectangle, bins int, minSaturation, minValue float64) ([]float64, error) {
+	if bins <= 0 {
+		return nil, errors.New("the number of bins should be positive")
+	}
+	if err := utils.ValidateROI(img.Bounds(), roi); err != nil {
+		return nil, err
+	}
+	hist := make([]float64, bins)
+	for y := roi.Min.Y; y < roi.Max.Y; y++ {
+		for x := roi.Min.X; x < roi.Max.X; x++ {
+			if bin, ok := hueBin(img.RGBAAt(x, y), bins, minSaturation, minValue); ok {
+				hist[bin]++
+			}
+		}
+	}
+	var max float64
+	for _, h := range hist {
+		max = math.Max(max, h)
+	}
+	if max > 0 {
+		for i := range hist {
+			hist[i] /= max
+		}
+	}
+	return hist, nil
+}
+
+// CalcBackProjectHSV computes the back-projection of a hue histogram onto an RGBA image: every pixel gets the value of
+// the histogram bin of its hue, scaled from [0, 1] to [0, 255], so the result shows how likely it is that a pixel
+// belongs to the object described by the histogram. The histogram has to be normalized to [0, 1], see
+// HueHistogramRGBA. The near-gray and near-black pixels are suppressed (set to 0) using DefaultMinSaturation and
+// DefaultMinValue. Returns an error if the number of bins does not match the length of the histogram.
+// Example of usage:
+//
+//	prob, err := histogram.CalcBackProjectHSV(frame, hist, 30)
+func CalcBackProjectHSV(img *image.RGBA, hist []float64, bins int) (*image.Gray, error) {
+	return CalcBackProjectHSVWithThresholds(img, hist, bins, DefaultMinSaturation, DefaultMinValue)
+}
+
+// CalcBackProjectHSVWithThresholds works like CalcBackProjectHSV, but the saturation and the value below which the
+// pixels are suppressed can be specified.
+// Example of usage:
+//
+//	prob, err := histogram.CalcBackProjectHSVWithThresholds(frame, hist, 30, 0.3, 0.2)
+func CalcBackProjectHSVWithThresholds(img *image.RGBA, hist []float64, bins int, minSaturation, minValue float64) (*image.Gray, error) {
+	if bins <= 0 || len(hist) != bins {
+		return nil, errors.New("the number of bins does not match the size of the histogram")
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	min := img.Bounds().Min
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		bin, ok := hueBin(img.RGBAAt(min.X+x, min.Y+y), bins, minSaturation, minValue)
+		if !ok {
+			return
+		}
+		p := utils.ClampF64(hist[bin]*float64(utils.MaxUint8)+0.5, utils.MinUint8, float64(utils.MaxUint8))
+		res.SetGray(x, y, color.Gray{Y: uint8(p)})
+	})
+	return res, nil
+}
+
+// HueSaturationHistogramRGBA computes the 2D hue-saturation histogram of a region of interest of an RGBA image, e.g.
+// of a reference object for BackProjectRGBA. The hue and the saturation are both split into the given number of bins
+// and hist[h][s] counts the pixels of the hue bin h and the saturation bin s. Unlike HueHistogramRGBA no pixel is
+// skipped, the near-gray pixels fall into the lowest saturation bins. Returns an error if the number of bins is not
+// positive or the ROI is invalid.
+// Example of usage:
+//
+//	hist, err := histogram.HueSaturationHistogramRGBA(img, target, 30)
+func HueSaturationHistogramRGBA(img *image.RGBA, roi image.Rectangle, bins int) ([][]int, error) {
+	if bins <= 0 {
+		return nil, errors.New("the number of bins should be positive")
+	}
+	if err := utils.ValidateROI(img.Bounds(), roi); err != nil {
+		return nil, err
+	}
+	hist := make([][]int, bins)
+	for i := range hist {
+		hist[i] = make([]int, bins)
+	}
+	for y := roi.Min.Y; y < roi.Max.Y; y++ {
+		for x := roi.Min.X; x < roi.Max.X; x++ {
+			h, s := hueSaturationBins(img.RGBAAt(x, y), bins)
+			hist[h][s]++
+		}
+	}
+	return hist, nil
+}
+
+// BackProjectRGBA computes the back-projection of a 2D hue-saturation histogram (see HueSaturationHistogramRGBA) onto
+// an RGBA image: every pixel gets the count of the histogram bin of its hue and saturation, scaled so the highest bin
+// of the histogram gives 255. Using the saturation as well separates the pale and the vivid colors of the same hue,
+// while the value (brightness) is ignored, so the shadowed parts of the object light up too. Returns an error if the
+// histogram is not a bins x bins matrix.
+// Example of usage:
+//
+//	prob, err := histogram.BackProjectRGBA(frame, hist, 30)
+func BackProjectRGBA(img *image.RGBA, refHist [][]int, bins int) (*image.Gray, error) {
+	if bins <= 0 || len(refHist) != bins {
+		return nil, errors.New("the number of bins does not match the size of the histogram")
+	}
+	max := 0
+	for _, row := range refHist {
+		if len(row) != bins {
+			return nil, errors.New("the number of bins does not match the size of the histogram")
+		}
+		for _, v := range row {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	if max == 0 {
+		return res, nil
+	}
+	min := img.Bounds().Min
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		h, s := hueSaturationBins(img.RGBAAt(min.X+x, min.Y+y), bins)
+		p := utils.ClampF64(float64(refHist[h][s])/float64(max)*float64(utils.MaxUint8)+0.5, utils.MinUint8, float64(utils.MaxUint8))
+		res.SetGray(x, y, color.Gray{Y: uint8(p)})
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func hueBin(c color.RGBA, bins int, minSaturation, minValue float64) (int, bool) {
+	h, s, v := utils.RGBToHSV(c.R, c.G, c.B)
+	if s < minSaturation || v < minValue {
+		return 0, false
+	}
+	return utils.ClampInt(int(h/360*float64(bins)), 0, bins-1), true
+}
+
+func hueSaturationBins(c color.RGBA, bins int) (int, int) {
+	h, s, _ := utils.RGBToHSV(c.R, c.G, c.B)
+	return utils.ClampInt(int(h/360*float64(bins)), 0, bins-1), utils.ClampInt(int(s*float64(bins)), 0, bins-1)
+}
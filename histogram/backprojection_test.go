@@ -0,0 +1,167 @@
+package histogram
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// --------------------------------Unit tests---------------------------------------
+
+func fillRGBA(img *image.RGBA, rect image.Rectangle, c func(x, y int) color.RGBA) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c(x, y))
+		}
+	}
+}
+
+func Test_CalcBackProjectHSV(t *testing.T) {
+	red := func(x, y int) color.RGBA {
+		return color.RGBA{R: uint8(200 + (x+y)%40), G: uint8(10 + x%20), B: uint8(5 + y%10), A: 0xFF}
+	}
+	target := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	fillRGBA(target, target.Bounds(), red)
+	hist, err := HueHistogramRGBA(target, target.Bounds(), 30, DefaultMinSaturation, DefaultMinValue)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	fillRGBA(frame, frame.Bounds(), func(x, y int) color.RGBA {
+		return color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}
+	})
+	redRect := image.Rect(35, 10, 55, 30)
+	fillRGBA(frame, redRect, red)
+	fillRGBA(frame, image.Rect(5, 5, 20, 20), func(x, y int) color.RGBA {
+		return color.RGBA{G: 0xC0, B: 0x20, A: 0xFF}
+	})
+	fillRGBA(frame, image.Rect(5, 25, 20, 35), func(x, y int) color.RGBA {
+		return color.RGBA{R: 0x20, G: 0x30, B: 0xE0, A: 0xFF}
+	})
+	// dark reddish pixels are suppressed by the value threshold
+	fillRGBA(frame, image.Rect(25, 30, 30, 35), func(x, y int) color.RGBA {
+		return color.RGBA{R: 0x20, A: 0xFF}
+	})
+
+	prob, err := CalcBackProjectHSV(frame, hist, 30)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 60; x++ {
+		for y := 0; y < 40; y++ {
+			p := prob.GrayAt(x, y).Y
+			if (image.Point{X: x, Y: y}).In(redRect) {
+				if p == 0 {
+					t.Errorf("Expected the red region to light up at: %d %d", x, y)
+				}
+			} else if p != 0 {
+				t.Errorf("Expected probability: 0 - actual probability: %d at: %d %d", p, x, y)
+			}
+		}
+	}
+	for _, p := range []image.Point{{X: 40, Y: 15}, {X: 45, Y: 20}} {
+		if v := prob.GrayAt(p.X, p.Y).Y; v < 128 {
+			t.Errorf("Expected a high probability inside of the red region - actual: %d at: %v", v, p)
+		}
+	}
+
+	// a sub-image is sampled from its own origin
+	sub := frame.SubImage(image.Rect(30, 5, 60, 35)).(*image.RGBA)
+	subProb, err := CalcBackProjectHSV(sub, hist, 30)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			if expected, actual := prob.GrayAt(30+x, 5+y).Y, subProb.GrayAt(x, y).Y; actual != expected {
+				t.Errorf("Expected probability: %d - actual probability: %d at: %d %d of the sub-image", expected, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_BackProjectRGBA(t *testing.T) {
+	red := func(x, y int) color.RGBA {
+		return color.RGBA{R: uint8(200 + (x+y)%40), G: uint8(10 + x%20), B: uint8(5 + y%10), A: 0xFF}
+	}
+	reference := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	fillRGBA(reference, reference.Bounds(), red)
+	hist, err := HueSaturationHistogramRGBA(reference, reference.Bounds(), 16)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	total := 0
+	for _, row := range hist {
+		for _, v := range row {
+			total += v
+		}
+	}
+	if total != 400 {
+		t.Errorf("Expected every pixel of the reference to be counted - actual: %d", total)
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	fillRGBA(frame, frame.Bounds(), func(x, y int) color.RGBA {
+		return color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}
+	})
+	redRect := image.Rect(35, 10, 55, 30)
+	fillRGBA(frame, redRect, red)
+	fillRGBA(frame, image.Rect(5, 5, 20, 20), func(x, y int) color.RGBA {
+		return color.RGBA{G: 0xC0, B: 0x20, A: 0xFF}
+	})
+	// a pale red has the hue of the reference, but a much lower saturation
+	fillRGBA(frame, image.Rect(5, 25, 20, 35), func(x, y int) color.RGBA {
+		return color.RGBA{R: 0xF0, G: 0xA0, B: 0xA0, A: 0xFF}
+	})
+
+	prob, err := BackProjectRGBA(frame, hist, 16)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 60; x++ {
+		for y := 0; y < 40; y++ {
+			p := prob.GrayAt(x, y).Y
+			if (image.Point{X: x, Y: y}).In(redRect) {
+				if p == 0 {
+					t.Errorf("Expected the red region to light up at: %d %d", x, y)
+				}
+			} else if p != 0 {
+				t.Errorf("Expected probability: 0 - actual probability: %d at: %d %d", p, x, y)
+			}
+		}
+	}
+	var maxProb uint8
+	for _, p := range prob.Pix {
+		if p > maxProb {
+			maxProb = p
+		}
+	}
+	if maxProb != 255 {
+		t.Errorf("Expected the most frequent bin of the reference to give 255 - actual: %d", maxProb)
+	}
+	if _, err := BackProjectRGBA(frame, hist, 12); err == nil {
+		t.Error("Expected error for a wrong number of bins")
+	}
+	if _, err := BackProjectRGBA(frame, [][]int{{1, 2}, {3}}, 2); err == nil {
+		t.Error("Expected error for a histogram which is not square")
+	}
+	if _, err := HueSaturationHistogramRGBA(frame, frame.Bounds(), 0); err == nil {
+		t.Error("Expected error for a non-positive number of bins")
+	}
+}
+
+func Test_CalcBackProjectHSV_Invalid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if _, err := CalcBackProjectHSV(img, make([]float64, 10), 12); err == nil {
+		t.Error("Expected error for mismatching number of bins")
+	}
+	if _, err := HueHistogramRGBA(img, image.Rect(0, 0, 4, 4), 0, 0, 0); err == nil {
+		t.Error("Expected error for zero bins")
+	}
+	if _, err := HueHistogramRGBA(img, image.Rect(2, 2, 8, 8), 10, 0, 0); err == nil {
+		t.Error("Expected error for ROI outside of the image")
+	}
+}
+
+// ---------------------------------------------------------------------------------
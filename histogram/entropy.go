@@ -0,0 +1,29 @@
+package histogram
+
+import (
+	"image"
+	"math"
+)
+
+// EntropyGray computes the Shannon entropy of the histogram of a grayscale image in bits: -sum(p * log2(p)) over the
+// probabilities p of the gray levels. A constant image has an entropy of 0 and an image where every gray level is
+// equally frequent has the maximum entropy of 8 bits. An empty image has an entropy of 0.
+// Example of usage:
+//
+//	entropy := histogram.EntropyGray(img)
+func EntropyGray(img *image.Gray) float64 {
+	hist := HistogramGray(img)
+	var total uint64
+	for _, v := range hist {
+		total += v
+	}
+	var res float64
+	for _, v := range hist {
+		if v == 0 {
+			continue
+		}
+		p := float64(v) / float64(total)
+		res -= p * math.Log2(p)
+	}
+	return res
+}
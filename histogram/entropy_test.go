@@ -0,0 +1,35 @@
+package histogram
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// --------------------------------Unit tests---------------------------------------
+
+func Test_EntropyGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = 93
+	}
+	if actual := EntropyGray(img); actual != 0 {
+		t.Errorf("Expected entropy 0 for a constant image - actual: %f", actual)
+	}
+	// two equally frequent levels give one bit
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i % 2 * 200)
+	}
+	if actual := EntropyGray(img); actual != 1 {
+		t.Errorf("Expected entropy 1 for two levels - actual: %f", actual)
+	}
+	rand.New(rand.NewSource(5)).Read(img.Pix)
+	if actual := EntropyGray(img); actual < 7.9 || actual > 8 {
+		t.Errorf("Expected entropy close to 8 for a uniform random image - actual: %f", actual)
+	}
+	if actual := EntropyGray(image.NewGray(image.Rect(0, 0, 0, 0))); actual != 0 {
+		t.Errorf("Expected entropy 0 for an empty image - actual: %f", actual)
+	}
+}
+
+// ---------------------------------------------------------------------------------
@@ -0,0 +1,82 @@
+package histogram
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// EqualizeGray applies histogram equalization to a grayscale image. The pixel values are remapped using the
+// cumulative distribution function of the histogram, so the values of the result are spread over the whole [0, 255]
+// interval.
+// More info about histogram equalization: https://en.wikipedia.org/wiki/Histogram_equalization
+func EqualizeGray(img *image.Gray) *image.Gray {
+	hist := HistogramGray(img)
+	var total, cdfMin, cdf uint64
+	for _, bin := range hist {
+		total += bin
+		if cdfMin == 0 {
+			cdfMin = bin
+		}
+	}
+	var lut [hsize]uint8
+	for i, bin := range hist {
+		cdf += bin
+		if total == cdfMin {
+			lut[i] = uint8(i)
+			continue
+		}
+		value := float64(cdf-cdfMin) / float64(total-cdfMin) * float64(utils.MaxUint8)
+		lut[i] = uint8(utils.ClampF64(math.Round(value), utils.MinUint8, float64(utils.MaxUint8)))
+	}
+	return utils.ApplyLUTGray(img, lut)
+}
+
+// BBHEGray applies brightness-preserving bi-histogram equalization to a grayscale image. The histogram is split at the
+// mean of the image and the two halves are equalized independently: the lower half into [0, mean] and the upper half
+// into [mean + 1, 255]. Compared to plain histogram equalization this keeps the overall brightness of the image much
+// closer to the original.
+// More info: Y.-T. Kim, "Contrast enhancement using brightness preserving bi-histogram equalization", 1997.
+func BBHEGray(img *image.Gray) *image.Gray {
+	hist := HistogramGray(img)
+	var total, sum uint64
+	for i, bin := range hist {
+		total += bin
+		sum += uint64(i) * bin
+	}
+	if total == 0 {
+		return utils.ApplyLUTGray(img, identityLUT())
+	}
+	mean := int(sum / total)
+	var lowerTotal, upperTotal uint64
+	for i, bin := range hist {
+		if i <= mean {
+			lowerTotal += bin
+		} else {
+			upperTotal += bin
+		}
+	}
+	// every bin is mapped using the middle of its cumulative range, so an evenly spread sub-histogram keeps its mean
+	var lut [hsize]uint8
+	var cdf float64
+	for i := 0; i <= mean; i++ {
+		cdf += float64(hist[i])
+		lut[i] = uint8(math.Round(float64(mean) * (cdf - float64(hist[i])/2) / float64(lowerTotal)))
+	}
+	cdf = 0
+	for i := mean + 1; i < hsize; i++ {
+		cdf += float64(hist[i])
+		lower := float64(mean + 1)
+		lut[i] = uint8(math.Round(lower + (float64(utils.MaxUint8)-lower)*(cdf-float64(hist[i])/2)/float64(upperTotal)))
+	}
+	return utils.ApplyLUTGray(img, lut)
+}
+
+// ---------------------------------------------------------------------------------------------
+func identityLUT() [hsize]uint8 {
+	var lut [hsize]uint8
+	for i := range lut {
+		lut[i] = uint8(i)
+	}
+	return lut
+}
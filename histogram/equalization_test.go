@@ -0,0 +1,83 @@
+package histogram
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// --------------------------------Unit tests---------------------------------------
+
+// setupTestCaseBimodal creates an image where a given fraction of the pixels is spread evenly over [lowMin, lowMax]
+// and the rest over [highMin, highMax].
+func setupTestCaseBimodal(lowFraction float64, lowMin, lowMax, highMin, highMax int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	lowCount := int(lowFraction * float64(len(img.Pix)))
+	for i := range img.Pix {
+		if i < lowCount {
+			img.Pix[i] = uint8(lowMin + i%(lowMax-lowMin+1))
+		} else {
+			img.Pix[i] = uint8(highMin + i%(highMax-highMin+1))
+		}
+	}
+	return img
+}
+
+func meanGray(img *image.Gray) float64 {
+	var sum float64
+	for _, p := range img.Pix {
+		sum += float64(p)
+	}
+	return sum / float64(len(img.Pix))
+}
+
+func Test_BBHEGray_PreservesBrightness(t *testing.T) {
+	images := []*image.Gray{
+		setupTestCaseBimodal(0.75, 40, 64, 65, 140),
+		setupTestCaseBimodal(0.25, 75, 192, 193, 230),
+		setupTestCaseBimodal(0.6, 50, 104, 105, 185),
+		setupTestCaseBimodal(0.5, 100, 128, 129, 155),
+	}
+	for i, img := range images {
+		inputMean := meanGray(img)
+		bbheMean := meanGray(BBHEGray(img))
+		if math.Abs(bbheMean-inputMean) > 2.5 {
+			t.Errorf("Image %d: expected BBHE mean close to %f - actual mean: %f", i, inputMean, bbheMean)
+		}
+	}
+	for i, img := range images[:3] {
+		inputMean := meanGray(img)
+		heMean := meanGray(EqualizeGray(img))
+		if math.Abs(heMean-inputMean) < 20 {
+			t.Errorf("Image %d: expected plain equalization to shift the mean %f - actual mean: %f", i, inputMean, heMean)
+		}
+	}
+}
+
+func Test_BBHEGray_Ranges(t *testing.T) {
+	img := setupTestCaseBimodal(0.5, 100, 120, 140, 160)
+	res := BBHEGray(img)
+	mean := int(meanGray(img))
+	for i, p := range img.Pix {
+		if int(p) <= mean && int(res.Pix[i]) > mean {
+			t.Fatalf("Expected lower half to stay in [0, %d] - actual: %d", mean, res.Pix[i])
+		}
+		if int(p) > mean && int(res.Pix[i]) <= mean {
+			t.Fatalf("Expected upper half to stay in [%d, 255] - actual: %d", mean+1, res.Pix[i])
+		}
+	}
+}
+
+func Test_EqualizeGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 1))
+	for x, v := range []uint8{10, 20, 30, 40} {
+		img.SetGray(x, 0, color.Gray{Y: v})
+	}
+	res := EqualizeGray(img)
+	for x, expected := range []uint8{0, 85, 170, 255} {
+		if actual := res.GrayAt(x, 0).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d", expected, actual, x)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package histogram
 
 import (
+	"errors"
 	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
@@ -10,15 +11,40 @@ const hsize = 256
 const channels = 3
 
 // HistogramGray computes the histogram for a grayscale image. Returns an array of 256 uint64 values containing
-// distribution of the pixel values.
+// distribution of the pixel values. The rows of the image are read directly from the Pix slice, so sub-images are
+// supported.
 func HistogramGray(img *image.Gray) [hsize]uint64 {
 	var res [hsize]uint64
-	utils.ForEachGrayPixel(img, func(pixel color.Gray) {
-		res[pixel.Y]++
-	})
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		offset := img.PixOffset(bounds.Min.X, y)
+		for _, v := range img.Pix[offset : offset+width] {
+			res[v]++
+		}
+	}
 	return res
 }
 
+// HistogramGrayMasked computes the histogram for a grayscale image taking into account only the pixels where the
+// mask is not zero. Returns an error if the size of the mask does not match the size of the image.
+// Example of usage:
+//
+//	hist, err := histogram.HistogramGrayMasked(img, mask)
+func HistogramGrayMasked(img *image.Gray, mask *image.Gray) ([hsize]uint64, error) {
+	var res [hsize]uint64
+	size := img.Bounds().Size()
+	if !size.Eq(mask.Bounds().Size()) {
+		return res, errors.New("the size of the mask does not match the size of the image")
+	}
+	utils.ForEachPixel(size, func(x, y int) {
+		if mask.GrayAt(x, y).Y != 0 {
+			res[img.GrayAt(x, y).Y]++
+		}
+	})
+	return res, nil
+}
+
 // HistogramRGBARed computes the histogram for red channel from an RGBA image. Returns an array of 256 uint64 values
 // containing distribution of the pixel values.
 func HistogramRGBARed(img *image.RGBA) [hsize]uint64 {
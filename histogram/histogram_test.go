@@ -3,11 +3,52 @@ package histogram
 import (
 	"github.com/yafeiliu/imger/imgio"
 	"image"
+	"image/color"
+	"math/rand"
 	"testing"
 )
 
 // --------------------------------Unit tests---------------------------------------
 
+func Test_HistogramGrayMasked(t *testing.T) {
+	gray := image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x01, 0x02, 0x80,
+			0x80, 0x02, 0xFF,
+		},
+	}
+	mask := image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0xFF, 0x01,
+			0x00, 0xFF, 0x00,
+		},
+	}
+	hist, err := HistogramGrayMasked(&gray, &mask)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, h := range hist {
+		expected := uint64(0)
+		switch i {
+		case 0x02:
+			expected = 2
+		case 0x80:
+			expected = 1
+		}
+		if h != expected {
+			t.Errorf("Histogram value for %d should be %d - actual: %d", i, expected, h)
+		}
+	}
+	invalidMask := image.NewGray(image.Rect(0, 0, 2, 2))
+	if _, err := HistogramGrayMasked(&gray, invalidMask); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
 func Test_Histogram_GrayScale(t *testing.T) {
 	gray := image.Gray{
 		Rect:   image.Rect(0, 0, 3, 3),
@@ -218,6 +259,65 @@ func Test_Histogram_RGBABlue(t *testing.T) {
 	}
 }
 
+func Test_HistogramGray_Pix(t *testing.T) {
+	img := setupTestCaseRandomGray(97, 61)
+	compareHistograms(t, histogramGrayAt(img), HistogramGray(img))
+	sub := img.SubImage(image.Rect(13, 7, 80, 50)).(*image.Gray)
+	expected := histogramGrayAt(sub)
+	compareHistograms(t, expected, HistogramGray(sub))
+	var total uint64
+	for _, bin := range expected {
+		total += bin
+	}
+	if total != 67*43 {
+		t.Errorf("Expected %d pixels in the histogram of the sub-image - actual: %d", 67*43, total)
+	}
+}
+
+func Benchmark_HistogramGray(b *testing.B) {
+	img := setupTestCaseRandomGray(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = HistogramGray(img)
+	}
+}
+
+func Benchmark_HistogramGray_At(b *testing.B) {
+	img := setupTestCaseRandomGray(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = histogramGrayAt(img)
+	}
+}
+
+// ---------------------------------------------------------------------------------
+
+func setupTestCaseRandomGray(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(31)).Read(img.Pix)
+	return img
+}
+
+// histogramGrayAt is the reference histogram which reads every pixel through At.
+func histogramGrayAt(img *image.Gray) [hsize]uint64 {
+	var res [hsize]uint64
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			res[color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y]++
+		}
+	}
+	return res
+}
+
+func compareHistograms(t *testing.T, expected, actual [hsize]uint64) {
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("Expected count: %d - actual count: %d at: %d", expected[i], actual[i], i)
+		}
+	}
+}
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/girl.jpg"
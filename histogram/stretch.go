@@ -0,0 +1,45 @@
+package histogram
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// StretchContrastGray stretches the contrast of a grayscale image between two percentiles of its histogram. The value
+// below which lowPercentile percent of the pixels lie is mapped to 0, the value above which 100 - highPercentile percent
+// of the pixels lie is mapped to 255, the values between them are stretched linearly and the values outside of them are
+// clipped. Unlike a plain min-max normalization a few outlier pixels do not dictate the mapping. The percentiles are
+// clamped to the [0, 100] interval, 0 and 100 give the minimum and the maximum of the image. If the two values
+// coincide (e.g. for a flat image) an unchanged copy of the image is returned.
+// Example of usage:
+//
+//	res := histogram.StretchContrastGray(img, 2, 98)
+func StretchContrastGray(img *image.Gray, lowPercentile, highPercentile float64) *image.Gray {
+	hist := HistogramGray(img)
+	var total uint64
+	for _, bin := range hist {
+		total += bin
+	}
+	lowLimit := float64(total) * utils.ClampF64(lowPercentile, 0, 100) / 100
+	highLimit := float64(total) * (100 - utils.ClampF64(highPercentile, 0, 100)) / 100
+	// the lowest value with more then lowLimit pixels at or below it
+	low := 0
+	for cdf := float64(hist[0]); low < hsize-1 && cdf <= lowLimit; cdf += float64(hist[low]) {
+		low++
+	}
+	// the highest value with more then highLimit pixels at or above it
+	high := hsize - 1
+	for cdf := float64(hist[high]); high > 0 && cdf <= highLimit; cdf += float64(hist[high]) {
+		high--
+	}
+	if high <= low {
+		return utils.ApplyLUTGray(img, identityLUT())
+	}
+	var lut [hsize]uint8
+	for i := range lut {
+		value := float64(i-low) * float64(utils.MaxUint8) / float64(high-low)
+		lut[i] = uint8(utils.ClampF64(math.Round(value), utils.MinUint8, float64(utils.MaxUint8)))
+	}
+	return utils.ApplyLUTGray(img, lut)
+}
@@ -0,0 +1,63 @@
+package histogram
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_StretchContrastGray_Outliers(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 100, 50))
+	for i := range img.Pix {
+		// the bulk of the image is spread over [80, 160]
+		img.Pix[i] = uint8(80 + i%81)
+	}
+	// a few extreme pixels which would dictate a min-max normalization
+	for _, p := range []image.Point{{3, 4}, {50, 20}, {97, 44}} {
+		img.SetGray(p.X, p.Y, color.Gray{Y: 0})
+	}
+	for _, p := range []image.Point{{10, 40}, {60, 2}} {
+		img.SetGray(p.X, p.Y, color.Gray{Y: 255})
+	}
+	res := StretchContrastGray(img, 2, 98)
+	lut := map[uint8]uint8{}
+	for i, v := range img.Pix {
+		lut[v] = res.Pix[i]
+	}
+	if lut[0] != 0 || lut[255] != 255 {
+		t.Errorf("Expected the outliers to be clipped - actual: %d %d", lut[0], lut[255])
+	}
+	if lut[80] != 0 || lut[160] != 255 {
+		t.Errorf("Expected the bulk to be clipped at its percentiles - actual: %d %d", lut[80], lut[160])
+	}
+	// the values between the percentiles are stretched over the whole range
+	if lut[83] > 10 || lut[157] < 245 {
+		t.Errorf("Expected the bulk to span almost the whole range - actual: %d %d", lut[83], lut[157])
+	}
+	for v := 81; v <= 160; v++ {
+		if lut[uint8(v)] < lut[uint8(v-1)] {
+			t.Fatalf("Expected a monotonic mapping - actual: %d -> %d, %d -> %d", v-1, lut[uint8(v-1)], v, lut[uint8(v)])
+		}
+	}
+}
+
+func Test_StretchContrastGray_MinMax(t *testing.T) {
+	img := &image.Gray{Rect: image.Rect(0, 0, 4, 1), Stride: 4, Pix: []uint8{50, 100, 150, 200}}
+	res := StretchContrastGray(img, 0, 100)
+	expected := []uint8{0, 85, 170, 255}
+	for i := range expected {
+		if res.Pix[i] != expected[i] {
+			t.Errorf("Expected value: %d - actual value: %d at: %d", expected[i], res.Pix[i], i)
+		}
+	}
+	flat := &image.Gray{Rect: image.Rect(0, 0, 2, 2), Stride: 2, Pix: []uint8{7, 7, 7, 7}}
+	res = StretchContrastGray(flat, 2, 98)
+	for i := range flat.Pix {
+		if res.Pix[i] != 7 {
+			t.Errorf("Expected a flat image to be unchanged - actual value: %d at: %d", res.Pix[i], i)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
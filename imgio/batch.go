@@ -0,0 +1,93 @@
+package imgio
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileError is the error of a single file processed by ProcessDirectory.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path of the file together with its error.
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// BatchError collects the errors of the files which could not be processed by ProcessDirectory.
+type BatchError struct {
+	Errors []FileError
+}
+
+// Error lists the errors of every failed file.
+func (e *BatchError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d file(s) could not be processed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// ProcessDirectory reads every supported image file (jpg, jpeg, png) of inputDir in alphabetical order, applies fn on
+// it and writes the result to outputDir with the same file name and format. The output directory is created if it
+// does not exist. Subdirectories and files with other extensions are skipped. A failing file does not stop the run:
+// the errors of the files are collected and returned together as a *BatchError. Returns an error right away if the
+// input directory can not be listed or the output directory can not be created.
+// Example of usage:
+//
+//	err := imgio.ProcessDirectory("photos", "gray", func(img image.Image) (image.Image, error) {
+//		return grayscale.Grayscale(img), nil
+//	})
+func ProcessDirectory(inputDir, outputDir string, fn func(image.Image) (image.Image, error)) error {
+	entries, err := os.ReadDir(inputDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+	var failed []FileError
+	for _, entry := range entries {
+		if entry.IsDir() || !isSupportedExtension(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(inputDir, entry.Name())
+		if err := processFile(path, filepath.Join(outputDir, entry.Name()), fn); err != nil {
+			failed = append(failed, FileError{Path: path, Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return &BatchError{Errors: failed}
+	}
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func processFile(inputPath, outputPath string, fn func(image.Image) (image.Image, error)) error {
+	img, err := decode(inputPath)
+	if err != nil {
+		return err
+	}
+	res, err := fn(img)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return errors.New("no image was returned")
+	}
+	return encode(res, outputPath)
+}
+
+func isSupportedExtension(name string) bool {
+	switch filepath.Ext(name) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package imgio
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ProcessDirectory(t *testing.T) {
+	input, output := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	for i, name := range []string{"a.png", "b.png"} {
+		img := image.NewGray(image.Rect(0, 0, 4+i, 3))
+		for j := range img.Pix {
+			img.Pix[j] = uint8(10 * (i + 1))
+		}
+		if err := Imwrite(img, filepath.Join(input, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(input, "notes.txt"), []byte("skipped"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invert := func(img image.Image) (image.Image, error) {
+		bounds := img.Bounds()
+		res := image.NewGray(bounds)
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				res.SetGray(x, y, color.Gray{Y: 0xFF - color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y})
+			}
+		}
+		return res, nil
+	}
+	if err := ProcessDirectory(input, output, invert); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, name := range []string{"a.png", "b.png"} {
+		res, err := ImreadGray(filepath.Join(output, name))
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if res.Bounds().Dx() != 4+i || res.GrayAt(0, 0).Y != uint8(0xFF-10*(i+1)) {
+			t.Errorf("Unexpected result for %s: %v, %d", name, res.Bounds(), res.GrayAt(0, 0).Y)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(output, "notes.txt")); !os.IsNotExist(err) {
+		t.Error("Expected unsupported files to be skipped")
+	}
+}
+
+func Test_ProcessDirectory_Errors(t *testing.T) {
+	input, output := t.TempDir(), t.TempDir()
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		if err := Imwrite(image.NewGray(image.Rect(0, 0, 2, 2)), filepath.Join(input, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(input, "broken.png"), []byte("not a png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	processed := 0
+	err := ProcessDirectory(input, output, func(img image.Image) (image.Image, error) {
+		processed++
+		if processed == 2 {
+			return nil, errors.New("failed")
+		}
+		return img, nil
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a BatchError - actual: %v", err)
+	}
+	// the broken file and the second decoded file fail, the others are still written
+	if len(batchErr.Errors) != 2 || processed != 3 {
+		t.Fatalf("Expected 2 failed files and 3 processed files - actual: %v, %d", batchErr.Errors, processed)
+	}
+	if filepath.Base(batchErr.Errors[0].Path) != "b.png" || filepath.Base(batchErr.Errors[1].Path) != "broken.png" {
+		t.Errorf("Unexpected failed files: %v", batchErr.Errors)
+	}
+	for _, name := range []string{"a.png", "c.png"} {
+		if _, err := os.Stat(filepath.Join(output, name)); err != nil {
+			t.Errorf("Expected %s to be written", name)
+		}
+	}
+	if err := ProcessDirectory(filepath.Join(input, "missing"), output, nil); err == nil {
+		t.Error("Expected error for a missing input directory")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,150 @@
+package imgio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+)
+
+// exifOrientationTag is the id of the Orientation tag in the first IFD of the EXIF data.
+const exifOrientationTag = 0x0112
+
+// ImreadRGBAOriented reads the image from the given path like ImreadRGBA and applies the EXIF Orientation tag of JPEG
+// files, so the returned image is upright (e.g. the image of orientation 6 is rotated by 90 degrees clockwise).
+// The image is returned as stored if the file has no EXIF data, no Orientation tag or the EXIF data is malformed.
+// Returns an error if the path is not readable or the specified resource does not exist.
+// Example of usage:
+//
+//	img, err := imgio.ImreadRGBAOriented("photo.jpg")
+func ImreadRGBAOriented(path string) (*image.RGBA, error) {
+	img, err := decode(path)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	extension := filepath.Ext(path)
+	if extension != ".jpg" && extension != ".jpeg" {
+		return rgba, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return orientRGBA(rgba, jpegOrientation(data)), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// jpegOrientation returns the EXIF orientation (1-8) of the JPEG data, or 1 if it can not be found.
+func jpegOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	// walk the marker segments until the start of the scan
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// fill byte
+			i++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the Orientation tag from the first IFD of the TIFF structure of the EXIF data.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for e := 0; e < entries; e++ {
+		entry := offset + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// the orientation is a single SHORT value stored in the entry
+		if order.Uint16(tiff[entry:]) == exifOrientationTag && order.Uint16(tiff[entry+2:]) == 3 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orientRGBA transforms the stored image of the given EXIF orientation to the upright image.
+func orientRGBA(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	size := img.Bounds().Size()
+	w, h := size.X, size.Y
+	resSize := size
+	if orientation >= 5 {
+		resSize = image.Point{X: h, Y: w}
+	}
+	res := image.NewRGBA(image.Rectangle{Max: resSize})
+	// source returns the position of the stored pixel which is moved to (x, y)
+	var source func(x, y int) (int, int)
+	switch orientation {
+	case 2:
+		source = func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3:
+		source = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4:
+		source = func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5:
+		source = func(x, y int) (int, int) { return y, x }
+	case 6:
+		source = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7:
+		source = func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8:
+		source = func(x, y int) (int, int) { return w - 1 - y, x }
+	}
+	for y := 0; y < resSize.Y; y++ {
+		for x := 0; x < resSize.X; x++ {
+			sx, sy := source(x, y)
+			res.SetRGBA(x, y, img.RGBAAt(sx, sy))
+		}
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+package imgio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ImreadRGBAOriented(t *testing.T) {
+	// a 32x16 image with a uniform color in each quadrant
+	red := color.RGBA{R: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			quadrant := []color.RGBA{red, blue, green, white}[x/16+2*(y/8)]
+			img.SetRGBA(x, y, quadrant)
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	stored := buf.Bytes()
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		// insert the APP1 segment right after the start of image marker
+		data := append(append(append([]byte{}, stored[:2]...), exifSegment(order, 6)...), stored[2:]...)
+		path := filepath.Join(t.TempDir(), "photo.jpg")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		res, err := ImreadRGBAOriented(path)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if res.Bounds() != image.Rect(0, 0, 16, 32) {
+			t.Fatalf("Expected bounds [0 0 16 32] - actual: %v", res.Bounds())
+		}
+		// rotated by 90 degrees clockwise, the top left quadrant moves to the top right
+		checks := []struct {
+			x, y     int
+			expected color.RGBA
+		}{{11, 8, red}, {11, 24, blue}, {3, 8, green}, {3, 24, white}}
+		for _, check := range checks {
+			if !similarRGBA(res.RGBAAt(check.x, check.y), check.expected) {
+				t.Errorf("Expected %v at (%d, %d) - actual: %v", check.expected, check.x, check.y, res.RGBAAt(check.x, check.y))
+			}
+		}
+	}
+
+	// without EXIF data the image is returned as stored
+	path := filepath.Join(t.TempDir(), "plain.jpg")
+	if err := os.WriteFile(path, stored, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ImreadRGBAOriented(path)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != image.Rect(0, 0, 32, 16) || !similarRGBA(res.RGBAAt(4, 4), red) {
+		t.Errorf("Expected the image as stored - actual bounds: %v", res.Bounds())
+	}
+}
+
+func Test_orientRGBA(t *testing.T) {
+	// the stored 3x2 image has a single marked pixel at the top left corner
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	marker := color.RGBA{R: 255, A: 255}
+	img.SetRGBA(0, 0, marker)
+	expected := map[int]image.Point{
+		1: {X: 0, Y: 0}, 2: {X: 2, Y: 0}, 3: {X: 2, Y: 1}, 4: {X: 0, Y: 1},
+		5: {X: 0, Y: 0}, 6: {X: 1, Y: 0}, 7: {X: 1, Y: 2}, 8: {X: 0, Y: 2},
+	}
+	for orientation := 1; orientation <= 8; orientation++ {
+		res := orientRGBA(img, orientation)
+		if orientation >= 5 && res.Bounds().Size() != (image.Point{X: 2, Y: 3}) {
+			t.Errorf("Expected swapped dimensions for orientation %d - actual: %v", orientation, res.Bounds())
+		}
+		if p := expected[orientation]; res.RGBAAt(p.X, p.Y) != marker {
+			t.Errorf("Expected the marker at %v for orientation %d", p, orientation)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// exifSegment builds an APP1 marker segment with a single Orientation entry.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func similarRGBA(c1, c2 color.RGBA) bool {
+	near := func(v1, v2 uint8) bool {
+		return int(v1)-int(v2) <= 16 && int(v2)-int(v1) <= 16
+	}
+	return near(c1.R, c2.R) && near(c1.G, c2.G) && near(c1.B, c2.B) && c1.A == c2.A
+}
@@ -0,0 +1,140 @@
+package imgio
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/quantize"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"os"
+)
+
+// ImreadGIFFrames reads an animated GIF from the given path and returns every frame as an RGBA image together with the
+// delay of each frame in hundredths of a second. The frames are composited on a canvas of the size of the GIF
+// according to their disposal methods, so every returned frame is the full image which would be displayed. Returns an
+// error if the path is not readable or the resource is not a valid GIF.
+// Example of usage:
+//
+//	frames, delays, err := imgio.ImreadGIFFrames("animation.gif")
+func ImreadGIFFrames(path string) ([]*image.RGBA, []int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	frames := make([]*image.RGBA, 0, len(g.Image))
+	delays := make([]int, 0, len(g.Image))
+	for i, paletted := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+		draw.Draw(canvas, paletted.Bounds(), paletted, paletted.Bounds().Min, draw.Over)
+		frames = append(frames, cloneRGBA(canvas))
+		delay := 0
+		if i < len(g.Delay) {
+			delay = g.Delay[i]
+		}
+		delays = append(delays, delay)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, paletted.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames, delays, nil
+}
+
+// ImwriteGIF encodes a sequence of RGBA frames as an animated GIF which is repeated forever and saves it under the
+// location specified by the path. The delays are given in hundredths of a second. The frames are encoded the same way
+// as by WriteAnimatedGIF. Returns an error if the number of frames and delays differ, if the frames do not share the
+// same size or if the location is not writable.
+// Example of usage:
+//
+//	err := imgio.ImwriteGIF(frames, []int{10, 10, 10}, "animation.gif")
+func ImwriteGIF(frames []*image.RGBA, delays []int, path string) error {
+	images := make([]image.Image, len(frames))
+	for i, frame := range frames {
+		images[i] = frame
+	}
+	return WriteAnimatedGIF(path, images, delays, 0)
+}
+
+// WriteAnimatedGIF encodes a sequence of frames as an animated GIF and saves it under the location specified by the
+// path. Grayscale frames are written with a 256 shades of gray palette, so they are stored without any loss. All the
+// other frames are quantized to an own palette of at most 256 opaque colors computed with the median cut algorithm,
+// opaque frames with at most 256 distinct colors are stored without any loss. The delays are given in hundredths of a
+// second, loop is the number of times the animation is repeated (0 means forever, -1 means the animation is shown only
+// once). Returns an error if the number of frames and delays differ, if the frames do not share the same size or if
+// the location is not writable.
+// Example of usage:
+//
+//	err := imgio.WriteAnimatedGIF("pipeline.gif", []image.Image{img, blurred, edges}, []int{100, 100, 100}, 0)
+func WriteAnimatedGIF(path string, frames []image.Image, delaysCentisec []int, loop int) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to write")
+	}
+	if len(frames) != len(delaysCentisec) {
+		return errors.New("the number of frames and delays does not match")
+	}
+	size := frames[0].Bounds().Size()
+	anim := &gif.GIF{Config: image.Config{Width: size.X, Height: size.Y}, LoopCount: loop}
+	for i, frame := range frames {
+		if !frame.Bounds().Size().Eq(size) {
+			return errors.New("the frames have different sizes")
+		}
+		anim.Image = append(anim.Image, palettedFrame(frame))
+		anim.Delay = append(anim.Delay, delaysCentisec[i])
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return gif.EncodeAll(file, anim)
+}
+
+// -------------------------------------------------------------------------------------------------------
+// grayPalette contains the 256 shades of gray, the index of every color is equal to its value.
+var grayPalette = func() color.Palette {
+	p := make(color.Palette, 256)
+	for i := range p {
+		p[i] = color.Gray{Y: uint8(i)}
+	}
+	return p
+}()
+
+// palettedFrame converts a frame to a paletted image, grayscale frames keep their values.
+func palettedFrame(frame image.Image) *image.Paletted {
+	bounds := frame.Bounds()
+	rect := image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+	if gray, ok := frame.(*image.Gray); ok {
+		res := image.NewPaletted(rect, grayPalette)
+		for y := 0; y < bounds.Dy(); y++ {
+			copy(res.Pix[y*res.Stride:y*res.Stride+bounds.Dx()], gray.Pix[gray.PixOffset(bounds.Min.X, bounds.Min.Y+y):])
+		}
+		return res
+	}
+	res := image.NewPaletted(rect, quantize.MedianCutPalette(frame, 256))
+	draw.Draw(res, rect, frame, bounds.Min, draw.Src)
+	return res
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	res := image.NewRGBA(img.Bounds())
+	copy(res.Pix, img.Pix)
+	return res
+}
@@ -0,0 +1,196 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ImreadGIFFrames(t *testing.T) {
+	palette := color.Palette{color.Transparent, color.RGBA{R: 0xFF, A: 0xFF}, color.RGBA{B: 0xFF, A: 0xFF}}
+	full := image.NewPaletted(image.Rect(0, 0, 6, 4), palette)
+	for i := range full.Pix {
+		full.Pix[i] = 1
+	}
+	// the second frame covers only a part of the canvas
+	partial := image.NewPaletted(image.Rect(2, 1, 4, 3), palette)
+	for i := range partial.Pix {
+		partial.Pix[i] = 2
+	}
+	third := image.NewPaletted(image.Rect(0, 0, 1, 1), palette)
+	third.Pix[0] = 2
+	anim := &gif.GIF{
+		Image:    []*image.Paletted{full, partial, third},
+		Delay:    []int{10, 20, 30},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+		Config:   image.Config{ColorModel: palette, Width: 6, Height: 4},
+	}
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.EncodeAll(file, anim); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	frames, delays, err := ImreadGIFFrames(path)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(frames) != 3 || len(delays) != 3 {
+		t.Fatalf("Expected 3 frames and delays - actual: %d frames, %d delays", len(frames), len(delays))
+	}
+	for i, frame := range frames {
+		if frame.Bounds() != image.Rect(0, 0, 6, 4) {
+			t.Errorf("Expected frame bounds [0 0 6 4] - actual: %v", frame.Bounds())
+		}
+		if delays[i] != anim.Delay[i] {
+			t.Errorf("Expected delay: %d - actual delay: %d", anim.Delay[i], delays[i])
+		}
+	}
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	if frames[1].RGBAAt(0, 0) != red || frames[1].RGBAAt(2, 1) != blue {
+		t.Errorf("Expected the second frame to be composited over the first one")
+	}
+	if frames[2].RGBAAt(2, 1) != (color.RGBA{}) || frames[2].RGBAAt(5, 3) != red || frames[2].RGBAAt(0, 0) != blue {
+		t.Errorf("Expected the area of the second frame to be cleared by its disposal")
+	}
+}
+
+func Test_ImreadGIFFrames_InvalidPath(t *testing.T) {
+	if _, _, err := ImreadGIFFrames("../res/notexisting.gif"); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+func Test_ImwriteGIF_RoundTrip(t *testing.T) {
+	first := image.NewRGBA(image.Rect(0, 0, 5, 3))
+	second := image.NewRGBA(image.Rect(0, 0, 5, 3))
+	for i := 0; i < len(first.Pix); i += 4 {
+		copy(first.Pix[i:i+4], []uint8{0xFF, 0x00, 0x00, 0xFF})
+		copy(second.Pix[i:i+4], []uint8{0x00, 0x00, 0xFF, 0xFF})
+	}
+	path := filepath.Join(t.TempDir(), "out.gif")
+	if err := ImwriteGIF([]*image.RGBA{first, second}, []int{15, 40}, path); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	frames, delays, err := ImreadGIFFrames(path)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(frames) != 2 || delays[0] != 15 || delays[1] != 40 {
+		t.Fatalf("Expected 2 frames with delays [15 40] - actual: %d frames, delays %v", len(frames), delays)
+	}
+	if frames[0].RGBAAt(2, 1) != (color.RGBA{R: 0xFF, A: 0xFF}) || frames[1].RGBAAt(2, 1) != (color.RGBA{B: 0xFF, A: 0xFF}) {
+		t.Errorf("Expected the colors of the frames to survive - actual: %v %v", frames[0].RGBAAt(2, 1), frames[1].RGBAAt(2, 1))
+	}
+}
+
+func Test_ImwriteGIF_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	a := image.NewRGBA(image.Rect(0, 0, 5, 3))
+	b := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	if err := ImwriteGIF([]*image.RGBA{a, a}, []int{10}, path); err == nil {
+		t.Error("Expected error for mismatching number of delays")
+	}
+	if err := ImwriteGIF([]*image.RGBA{a, b}, []int{10, 10}, path); err == nil {
+		t.Error("Expected error for different frame sizes")
+	}
+}
+
+func Test_WriteAnimatedGIF_RoundTrip(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i)
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			rgba.SetRGBA(x, y, color.RGBA{R: uint8(x * 16), G: uint8(y * 16), B: uint8((x + y) * 8), A: 0xFF})
+		}
+	}
+	path := filepath.Join(t.TempDir(), "pipeline.gif")
+	if err := WriteAnimatedGIF(path, []image.Image{gray, rgba}, []int{50, 120}, 3); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	defer file.Close()
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(decoded.Image) != 2 || decoded.Delay[0] != 50 || decoded.Delay[1] != 120 || decoded.LoopCount != 3 {
+		t.Fatalf("Expected 2 frames with delays [50 120] and loop 3 - actual: %d frames, delays %v, loop %d",
+			len(decoded.Image), decoded.Delay, decoded.LoopCount)
+	}
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			expected := gray.GrayAt(x, y).Y
+			if actual := color.GrayModel.Convert(decoded.Image[0].At(x, y)).(color.Gray).Y; actual != expected {
+				t.Fatalf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+			}
+			// 256 distinct colors fit into the palette of the second frame
+			if actual := color.RGBAModel.Convert(decoded.Image[1].At(x, y)); actual != rgba.RGBAAt(x, y) {
+				t.Fatalf("Expected color: %v - actual color: %v at: %d %d", rgba.RGBAAt(x, y), actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_WriteAnimatedGIF_MedianCut(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			rgba.SetRGBA(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: uint8(255 - x*2 - y*2), A: 0xFF})
+		}
+	}
+	path := filepath.Join(t.TempDir(), "gradient.gif")
+	if err := WriteAnimatedGIF(path, []image.Image{rgba}, []int{10}, 0); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	frames, _, err := ImreadGIFFrames(path)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	abs := func(a, b uint8) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+	var sum int
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			e, a := rgba.RGBAAt(x, y), frames[0].RGBAAt(x, y)
+			sum += abs(e.R, a.R) + abs(e.G, a.G) + abs(e.B, a.B)
+		}
+	}
+	if mean := float64(sum) / (64 * 64 * 3); mean > 6 {
+		t.Errorf("Expected a mean quantization error below 6 - actual: %f", mean)
+	}
+}
+
+func Test_WriteAnimatedGIF_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	a := image.NewGray(image.Rect(0, 0, 5, 3))
+	b := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	if err := WriteAnimatedGIF(path, []image.Image{a, a}, []int{10}, 0); err == nil {
+		t.Error("Expected error for mismatching number of delays")
+	}
+	if err := WriteAnimatedGIF(path, []image.Image{a, b}, []int{10, 10}, 0); err == nil {
+		t.Error("Expected error for different frame sizes")
+	}
+	if err := WriteAnimatedGIF(path, nil, nil, 0); err == nil {
+		t.Error("Expected error for no frames")
+	}
+}
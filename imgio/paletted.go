@@ -0,0 +1,44 @@
+package imgio
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/quantize"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// ToPaletted reduces the colors of an RGBA image to a palette of at most maxColors colors with the median cut
+// algorithm (see quantize.QuantizeRGBA), so it can be written as an indexed PNG with ImwritePaletted. Images which
+// contain at most maxColors distinct colors are reproduced exactly. Returns an error if maxColors is not in the
+// [2, 256] interval.
+// Example of usage:
+//
+//	paletted, err := imgio.ToPaletted(img, 16)
+func ToPaletted(img *image.RGBA, maxColors int) (*image.Paletted, error) {
+	return quantize.QuantizeRGBA(img, maxColors)
+}
+
+// ImwritePaletted saves a paletted image as an indexed-color PNG under the location specified by the path. Every
+// pixel is stored as an index into the palette instead of its full color, which makes the files of images with few
+// colors much smaller. Returns an error if the extension of the path is not png, the palette is empty or has more
+// then 256 colors, or the location is not writable.
+// Example of usage:
+//
+//	err := imgio.ImwritePaletted(paletted, "labels.png")
+func ImwritePaletted(img *image.Paletted, path string) error {
+	if filepath.Ext(path) != ".png" {
+		return errors.New("unsupported extension")
+	}
+	if len(img.Palette) == 0 || len(img.Palette) > 256 {
+		return errors.New("the palette should have between 1 and 256 colors")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	return encoder.Encode(file, img)
+}
@@ -0,0 +1,89 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ImwritePaletted_RoundTrip(t *testing.T) {
+	colors := []color.RGBA{
+		{R: 0xFF, A: 0xFF},
+		{G: 0x80, B: 0x40, A: 0xFF},
+		{R: 0x20, G: 0x20, B: 0xFF, A: 0xFF},
+		{R: 0xF0, G: 0xE0, B: 0x10, A: 0xFF},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	rnd := rand.New(rand.NewSource(3))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetRGBA(x, y, colors[rnd.Intn(len(colors))])
+		}
+	}
+	paletted, err := ToPaletted(img, 4)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(paletted.Palette) != 4 {
+		t.Fatalf("Expected a palette of 4 colors - actual: %d", len(paletted.Palette))
+	}
+	dir := t.TempDir()
+	indexedPath := filepath.Join(dir, "indexed.png")
+	if err := ImwritePaletted(paletted, indexedPath); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	res, err := ImreadRGBA(indexedPath)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			if expected, actual := img.RGBAAt(x, y), res.RGBAAt(x, y); expected != actual {
+				t.Fatalf("Expected value: %v - actual value: %v at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+	// the file is an indexed PNG and it is smaller then the truecolor one
+	file, err := os.Open(indexedPath)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	defer file.Close()
+	config, err := png.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if _, ok := config.ColorModel.(color.Palette); !ok {
+		t.Errorf("Expected an indexed color model - actual: %T", config.ColorModel)
+	}
+	truecolorPath := filepath.Join(dir, "truecolor.png")
+	if err := Imwrite(img, truecolorPath); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	indexed, _ := os.Stat(indexedPath)
+	truecolor, _ := os.Stat(truecolorPath)
+	if indexed.Size() >= truecolor.Size() {
+		t.Errorf("Expected the indexed PNG to be smaller - indexed: %d bytes, truecolor: %d bytes", indexed.Size(), truecolor.Size())
+	}
+}
+
+func Test_ImwritePaletted_Invalid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if _, err := ToPaletted(img, 1); err == nil {
+		t.Error("Expected error for less then 2 colors")
+	}
+	paletted := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black})
+	if err := ImwritePaletted(paletted, filepath.Join(t.TempDir(), "out.jpg")); err == nil {
+		t.Error("Expected error for an extension other then png")
+	}
+	if err := ImwritePaletted(image.NewPaletted(image.Rect(0, 0, 4, 4), nil), filepath.Join(t.TempDir(), "out.png")); err == nil {
+		t.Error("Expected error for an empty palette")
+	}
+}
+
+// -------------------------------------------------------------------------------
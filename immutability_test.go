@@ -0,0 +1,902 @@
+package Imger
+
+import (
+	"github.com/yafeiliu/imger/analysis"
+	"github.com/yafeiliu/imger/blend"
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/convolution"
+	"github.com/yafeiliu/imger/edgedetection"
+	"github.com/yafeiliu/imger/effects"
+	"github.com/yafeiliu/imger/fft"
+	"github.com/yafeiliu/imger/fitting"
+	"github.com/yafeiliu/imger/floatimage"
+	"github.com/yafeiliu/imger/geometry"
+	"github.com/yafeiliu/imger/grayscale"
+	"github.com/yafeiliu/imger/hdr"
+	"github.com/yafeiliu/imger/histogram"
+	"github.com/yafeiliu/imger/inpaint"
+	"github.com/yafeiliu/imger/matching"
+	"github.com/yafeiliu/imger/morphology"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/pyramid"
+	"github.com/yafeiliu/imger/quantize"
+	"github.com/yafeiliu/imger/registration"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/rle"
+	"github.com/yafeiliu/imger/segmentation"
+	"github.com/yafeiliu/imger/stitch"
+	"github.com/yafeiliu/imger/texture"
+	"github.com/yafeiliu/imger/threshold"
+	"github.com/yafeiliu/imger/tiling"
+	"github.com/yafeiliu/imger/tracking"
+	"github.com/yafeiliu/imger/transform"
+	"github.com/yafeiliu/imger/utils"
+	"github.com/yafeiliu/imger/visualize"
+	"image"
+	"image/color"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// Test_InputsAreNotModified runs the exported functions which take images (or other buffers) on random inputs and
+// checks that the inputs are unchanged afterwards, and that they are still unchanged after the results are
+// overwritten, so the results do not share memory with the inputs either. Every new function taking an image should
+// be added to the table, only the functions documented to write into their arguments (e.g. the InPlace and Into
+// variants) are left out.
+func Test_InputsAreNotModified(t *testing.T) {
+	g := func(img *image.Gray, _ float64, err error) []interface{} { return []interface{}{img, err} }
+	pool := utils.NewBufferPool()
+	cases := []struct {
+		name string
+		run  func(in *testInputs) []interface{}
+	}{
+		// blend
+		{"blend.AddScalarToGray", func(in *testInputs) []interface{} { return outputs(blend.AddScalarToGray(in.gray, 40)) }},
+		{"blend.AddGray", func(in *testInputs) []interface{} { return outputs(blend.AddGray(in.gray, in.gray2)) }},
+		{"blend.AddGrayWithMode", func(in *testInputs) []interface{} {
+			return outputs(blend.AddGrayWithMode(in.gray, in.gray2, blend.OverflowWrap))
+		}},
+		{"blend.SubtractGray", func(in *testInputs) []interface{} { return outputs(blend.SubtractGray(in.gray, in.gray2)) }},
+		{"blend.SubtractGrayWithMode", func(in *testInputs) []interface{} {
+			return outputs(blend.SubtractGrayWithMode(in.gray, in.gray2, blend.OverflowWrap))
+		}},
+		{"blend.AddGrayWeighted", func(in *testInputs) []interface{} {
+			return outputs(blend.AddGrayWeighted(in.gray, 0.3, in.gray2, 0.7))
+		}},
+		{"blend.BlendGrayMap", func(in *testInputs) []interface{} { return outputs(blend.BlendGrayMap(in.gray, in.gray2, in.mask)) }},
+		{"blend.SeamlessCloneRGBA", func(in *testInputs) []interface{} {
+			return outputs(blend.SeamlessCloneRGBA(in.rgba, in.rgba2, in.mask, image.Point{}))
+		}},
+		{"blend.OverlayRGBA", func(in *testInputs) []interface{} {
+			return outputs(blend.OverlayRGBA(in.rgba, in.small, blend.OverlayBottomRight, image.Point{X: 2, Y: 2}, 0.6))
+		}},
+		{"blend.OverlayRGBAFit", func(in *testInputs) []interface{} {
+			return outputs(blend.OverlayRGBAFit(in.rgba, in.rgba2, blend.OverlayCenter, image.Point{X: 4, Y: 4}, 0.6))
+		}},
+		{"blend.TileWatermarkRGBA", func(in *testInputs) []interface{} {
+			return outputs(blend.TileWatermarkRGBA(in.rgba, in.small, image.Point{X: 3, Y: 3}, 0.4))
+		}},
+		// blur
+		{"blur.BoxGray", func(in *testInputs) []interface{} {
+			return g(blur.BoxGray(in.gray, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 1}, padding.BorderReflect))
+		}},
+		{"blur.BoxRGBA", func(in *testInputs) []interface{} {
+			return outputs(blur.BoxRGBA(in.rgba, image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, padding.BorderReplicate))
+		}},
+		{"blur.BoxGrayROI", func(in *testInputs) []interface{} {
+			return outputs(blur.BoxGrayROI(in.gray, image.Rect(3, 4, 20, 15), image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, padding.BorderReflect))
+		}},
+		{"blur.GaussianBlurGray", func(in *testInputs) []interface{} {
+			return g(blur.GaussianBlurGray(in.gray, 2, 1.2, padding.BorderReflect))
+		}},
+		{"blur.GaussianBlurGray with pool", func(in *testInputs) []interface{} {
+			return g(blur.GaussianBlurGray(in.gray, 2, 1.2, padding.BorderReflect, utils.WithPool(pool)))
+		}},
+		{"blur.GaussianBlurGrayROI", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurGrayROI(in.gray, image.Rect(5, 5, 25, 20), 2, 1.2, padding.BorderReflect))
+		}},
+		{"blur.BoxBlurGrayAnchored", func(in *testInputs) []interface{} {
+			return g(blur.BoxBlurGrayAnchored(in.gray, image.Point{X: 5, Y: 3}, image.Point{X: 4, Y: 0}, padding.BorderReplicate))
+		}},
+		{"blur.GaussianBlurGrayAnchored", func(in *testInputs) []interface{} {
+			return g(blur.GaussianBlurGrayAnchored(in.gray, 2, 1.2, image.Point{X: 1, Y: 3}, padding.BorderConstant))
+		}},
+		{"blur.GaussianBlurRGBA", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurRGBA(in.rgba, 2, 1.2, padding.BorderReflect))
+		}},
+		{"blur.GaussianBlurRGBAAnchored", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurRGBAAnchored(in.rgba, 1, 1, image.Point{X: 0, Y: 2}, padding.BorderReplicate))
+		}},
+		{"blur.GaussianBlurRGBALinear", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurRGBALinear(in.rgba, 5, 1.5))
+		}},
+		{"blur.GaussianBlurGrayWithKernel", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurGrayWithKernel(in.gray, []float64{0.25, 0.5, 0.25}, padding.BorderReflect))
+		}},
+		{"blur.GaussianBlurGrayXY", func(in *testInputs) []interface{} {
+			return outputs(blur.GaussianBlurGrayXY(in.gray, 5, 3, 1.5, 0.8, padding.BorderReflect))
+		}},
+		{"blur.FastGaussianBlurGray", func(in *testInputs) []interface{} { return outputs(blur.FastGaussianBlurGray(in.gray, 2.5)) }},
+		{"blur.AnisotropicDiffusionGray", func(in *testInputs) []interface{} {
+			return outputs(blur.AnisotropicDiffusionGray(in.gray, 5, 20, 0.2))
+		}},
+		{"blur.FastGaussianBlurPlane", func(in *testInputs) []interface{} {
+			return outputs(blur.FastGaussianBlurPlane(in.plane.Pix, image.Point{X: in.plane.Width, Y: in.plane.Height}, 2.5))
+		}},
+		{"blur.SeparableBlurPlane", func(in *testInputs) []interface{} {
+			kernel := blur.GaussianKernel1D(2, 1)
+			return outputs(blur.SeparableBlurPlane(in.plane.Pix, image.Point{X: in.plane.Width, Y: in.plane.Height}, kernel, kernel, padding.BorderReflect))
+		}},
+		{"blur.GuidedFilterGray", func(in *testInputs) []interface{} {
+			return outputs(blur.GuidedFilterGray(in.gray, in.gray2, 2, 100))
+		}},
+		{"blur.RankFilterGray", func(in *testInputs) []interface{} {
+			return outputs(blur.RankFilterGray(in.gray, 3, 4, padding.BorderReflect))
+		}},
+		{"blur.DenoiseAutoGray", func(in *testInputs) []interface{} { return g(blur.DenoiseAutoGray(in.gray, blur.DenoiseGuided)) }},
+		// convolution
+		{"convolution.ConvolveGray", func(in *testInputs) []interface{} {
+			return g(convolution.ConvolveGray(in.gray, convolution.NewSharpenKernel(), image.Point{X: 1, Y: 1}, padding.BorderReflect))
+		}},
+		{"convolution.ConvolveGrayFloat", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolveGrayFloat(in.gray, in.kernel.Content, image.Point{X: 1, Y: 1}, padding.BorderReflect))
+		}},
+		{"convolution.ConvolveGrayFixed", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolveGrayFixed(in.gray, [][]int{{1, 2, 1}, {2, 4, 2}, {1, 2, 1}}, 4, padding.BorderReflect))
+		}},
+		{"convolution.ConvolveRGBA", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolveRGBA(in.rgba, in.kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect))
+		}},
+		{"convolution.ConvolveRGBAWithOptions", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolveRGBAWithOptions(in.rgba, in.kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect, convolution.RGBAOptions{LuminanceOnly: true}))
+		}},
+		{"convolution.ConvolveGrayROI", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolveGrayROI(in.gray, in.kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect, image.Rect(2, 2, 20, 18)))
+		}},
+		{"convolution.ConvolvePlane", func(in *testInputs) []interface{} {
+			return outputs(convolution.ConvolvePlane(in.plane.Pix, in.plane.Size(), in.kernel.Content, image.Point{X: 1, Y: 1}, padding.BorderReflect))
+		}},
+		{"convolution.ComposeKernels", func(in *testInputs) []interface{} {
+			return outputs(convolution.ComposeKernels(in.kernel.Content, in.kernel.Content))
+		}},
+		{"convolution.ApplyGaborBankGray", func(in *testInputs) []interface{} {
+			bank, err := convolution.GaborBank([]int{7}, []float64{0, 1}, []float64{4})
+			if err != nil {
+				return outputs(err)
+			}
+			return outputs(convolution.ApplyGaborBankGray(in.gray, bank))
+		}},
+		// edgedetection
+		{"edgedetection.CannyGray", func(in *testInputs) []interface{} { return outputs(edgedetection.CannyGray(in.gray, 20, 60, 5)) }},
+		{"edgedetection.CannyGrayWithSigma", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.CannyGrayWithSigma(in.gray, 20, 60, 5, 0))
+		}},
+		{"edgedetection.CannyGrayWithAperture", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.CannyGrayWithAperture(in.gray, 20, 60, 3, 5))
+		}},
+		{"edgedetection.CannyRGBA", func(in *testInputs) []interface{} { return outputs(edgedetection.CannyRGBA(in.rgba, 20, 60, 3)) }},
+		{"edgedetection.HysteresisThreshold", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.HysteresisThreshold(in.floats, 60, 180))
+		}},
+		{"edgedetection.HorizontalSobelGray", func(in *testInputs) []interface{} {
+			return g(edgedetection.HorizontalSobelGray(in.gray, padding.BorderReflect))
+		}},
+		{"edgedetection.VerticalSobelGray", func(in *testInputs) []interface{} {
+			return g(edgedetection.VerticalSobelGray(in.gray, padding.BorderReflect))
+		}},
+		{"edgedetection.SobelGray", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.SobelGray(in.gray, padding.BorderReflect))
+		}},
+		{"edgedetection.SobelGray16", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.SobelGray16(in.gray, padding.BorderReflect))
+		}},
+		{"edgedetection.SobelGrayMagAngle", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.SobelGrayMagAngle(in.gray, padding.BorderReflect))
+		}},
+		{"edgedetection.HorizontalSobelRGBA", func(in *testInputs) []interface{} {
+			return g(edgedetection.HorizontalSobelRGBA(in.rgba, padding.BorderReflect))
+		}},
+		{"edgedetection.VerticalSobelRGBA", func(in *testInputs) []interface{} {
+			return g(edgedetection.VerticalSobelRGBA(in.rgba, padding.BorderReflect))
+		}},
+		{"edgedetection.SobelRGBA", func(in *testInputs) []interface{} {
+			return outputs(edgedetection.SobelRGBA(in.rgba, padding.BorderReflect))
+		}},
+		{"edgedetection.SobelColorRGBA", func(in *testInputs) []interface{} { return outputs(edgedetection.SobelColorRGBA(in.gray)) }},
+		{"edgedetection.LaplacianGray", func(in *testInputs) []interface{} {
+			return g(edgedetection.LaplacianGray(in.gray, padding.BorderReflect, edgedetection.K8))
+		}},
+		{"edgedetection.LaplacianRGBA", func(in *testInputs) []interface{} {
+			return g(edgedetection.LaplacianRGBA(in.rgba, padding.BorderReflect, edgedetection.K4))
+		}},
+		{"edgedetection.LinkEdges", func(in *testInputs) []interface{} { return outputs(edgedetection.LinkEdges(in.mask)) }},
+		// floatimage
+		{"floatimage.FloatImage.ConvolvePlane", func(in *testInputs) []interface{} {
+			img := &floatimage.FloatImage{Planes: [][]float64{in.plane.Pix}, Width: in.plane.Width, Height: in.plane.Height, Channels: 1}
+			return outputs(img.ConvolvePlane(0, in.kernel, image.Point{X: 1, Y: 1}, padding.BorderReplicate))
+		}},
+		{"floatimage.FloatImage.ToGray", func(in *testInputs) []interface{} {
+			img := &floatimage.FloatImage{Planes: [][]float64{in.plane.Pix}, Width: in.plane.Width, Height: in.plane.Height, Channels: 1}
+			return outputs(img.ToGray(0))
+		}},
+		// effects
+		{"effects.GrayWorldBalanceRGBA", func(in *testInputs) []interface{} { return outputs(effects.GrayWorldBalanceRGBA(in.rgba)) }},
+		{"effects.WhitePatchBalanceRGBA", func(in *testInputs) []interface{} {
+			return outputs(effects.WhitePatchBalanceRGBA(in.rgba, 0.95))
+		}},
+		{"effects.ChromaKeyRGBA", func(in *testInputs) []interface{} {
+			return outputs(effects.ChromaKeyRGBA(in.rgba, color.RGBA{G: 255, A: 255}, 80, 40))
+		}},
+		{"effects.ChromaKeyRGBASuppressSpill", func(in *testInputs) []interface{} {
+			return outputs(effects.ChromaKeyRGBASuppressSpill(in.rgba, color.RGBA{G: 255, A: 255}, 80, 40))
+		}},
+		{"effects.PixelateGray", func(in *testInputs) []interface{} { return outputs(effects.PixelateGray(in.gray, 4)) }},
+		{"effects.PixelateRGBA", func(in *testInputs) []interface{} { return outputs(effects.PixelateRGBA(in.rgba, 3)) }},
+		{"effects.Sepia", func(in *testInputs) []interface{} { return outputs(effects.Sepia(in.rgba)) }},
+		{"effects.EmbossGray", func(in *testInputs) []interface{} { return outputs(effects.EmbossGray(in.gray)) }},
+		{"effects.EmbossRGBA", func(in *testInputs) []interface{} { return outputs(effects.EmbossRGBA(in.rgba)) }},
+		{"effects.SharpenGray", func(in *testInputs) []interface{} { return outputs(effects.SharpenGray(in.gray)) }},
+		{"effects.SharpenRGBA", func(in *testInputs) []interface{} { return outputs(effects.SharpenRGBA(in.rgba)) }},
+		{"effects.SharpenGrayAmount", func(in *testInputs) []interface{} { return outputs(effects.SharpenGrayAmount(in.gray, 0)) }},
+		{"effects.SharpenRGBAAmount", func(in *testInputs) []interface{} { return outputs(effects.SharpenRGBAAmount(in.rgba, 2)) }},
+		{"effects.InvertGray", func(in *testInputs) []interface{} { return outputs(effects.InvertGray(in.gray)) }},
+		{"effects.InvertRGBA", func(in *testInputs) []interface{} { return outputs(effects.InvertRGBA(in.rgba)) }},
+		{"effects.WindowLevelGray", func(in *testInputs) []interface{} { return outputs(effects.WindowLevelGray(in.gray, 100, 60)) }},
+		// fft
+		{"fft.FFT", func(in *testInputs) []interface{} { return outputs(fft.FFT(in.signal)) }},
+		{"fft.IFFT", func(in *testInputs) []interface{} { return outputs(fft.IFFT(in.signal)) }},
+		{"fft.FFT2D", func(in *testInputs) []interface{} { return outputs(fft.FFT2D(in.signal, 5, 6)) }},
+		{"fft.IFFT2D", func(in *testInputs) []interface{} { return outputs(fft.IFFT2D(in.signal, 6, 5)) }},
+		// fitting and geometry
+		{"fitting.FitLineRANSAC", func(in *testInputs) []interface{} {
+			a, b, c, inliers := fitting.FitLineRANSAC(in.points, 50, 2)
+			return outputs(a, b, c, inliers)
+		}},
+		{"fitting.FitCircleRANSAC", func(in *testInputs) []interface{} {
+			center, radius, inliers := fitting.FitCircleRANSAC(in.points, 50, 2)
+			return outputs(center, radius, inliers)
+		}},
+		{"geometry.ConvexHull", func(in *testInputs) []interface{} { return outputs(geometry.ConvexHull(in.points)) }},
+		{"geometry.MinAreaRect", func(in *testInputs) []interface{} { return outputs(geometry.MinAreaRect(in.points)) }},
+		{"geometry.MinEnclosingCircle", func(in *testInputs) []interface{} {
+			return outputs(geometry.MinEnclosingCircle(in.points))
+		}},
+		{"geometry.ImageMoments", func(in *testInputs) []interface{} { return outputs(geometry.ImageMoments(in.gray)) }},
+		{"geometry.BinaryImageMoments", func(in *testInputs) []interface{} {
+			return outputs(geometry.BinaryImageMoments(in.mask))
+		}},
+		// grayscale
+		{"grayscale.Grayscale", func(in *testInputs) []interface{} { return outputs(grayscale.Grayscale(in.rgba)) }},
+		{"grayscale.Grayscale16", func(in *testInputs) []interface{} { return outputs(grayscale.Grayscale16(in.nrgba)) }},
+		{"grayscale.GrayscaleWeighted", func(in *testInputs) []interface{} {
+			return outputs(grayscale.GrayscaleWeighted(in.rgba, 0.5, 0.3, 0.2))
+		}},
+		{"grayscale.GrayFromGray16", func(in *testInputs) []interface{} {
+			return outputs(grayscale.GrayFromGray16(in.gray16, false), grayscale.GrayFromGray16(in.gray16, true))
+		}},
+		{"grayscale.GrayscaleRGBAAlphaWeighted", func(in *testInputs) []interface{} {
+			return outputs(grayscale.GrayscaleRGBAAlphaWeighted(in.rgba, 200))
+		}},
+		// hdr
+		{"hdr.MertensFusionRGBA", func(in *testInputs) []interface{} {
+			return outputs(hdr.MertensFusionRGBA([]*image.RGBA{in.rgba, in.rgba2}, 1, 1, 1))
+		}},
+		// histogram
+		{"histogram.EqualizeGray", func(in *testInputs) []interface{} { return outputs(histogram.EqualizeGray(in.gray)) }},
+		{"histogram.BBHEGray", func(in *testInputs) []interface{} { return outputs(histogram.BBHEGray(in.gray)) }},
+		{"histogram.StretchContrastGray", func(in *testInputs) []interface{} { return outputs(histogram.StretchContrastGray(in.gray, 2, 98)) }},
+		{"histogram.EntropyGray", func(in *testInputs) []interface{} { return outputs(histogram.EntropyGray(in.gray)) }},
+		{"histogram.HueHistogramRGBA", func(in *testInputs) []interface{} {
+			return outputs(histogram.HueHistogramRGBA(in.rgba, image.Rect(4, 4, 20, 20), 16, 0.1, 0.1))
+		}},
+		{"histogram.CalcBackProjectHSV", func(in *testInputs) []interface{} {
+			return outputs(histogram.CalcBackProjectHSV(in.rgba, in.hist, len(in.hist)))
+		}},
+		{"histogram.CalcBackProjectHSVWithThresholds", func(in *testInputs) []interface{} {
+			return outputs(histogram.CalcBackProjectHSVWithThresholds(in.rgba, in.hist, len(in.hist), 0.2, 0.2))
+		}},
+		{"histogram.HueSaturationHistogramRGBA", func(in *testInputs) []interface{} {
+			return outputs(histogram.HueSaturationHistogramRGBA(in.rgba, image.Rect(3, 4, 20, 25), 8))
+		}},
+		{"histogram.BackProjectRGBA", func(in *testInputs) []interface{} {
+			hist, err := histogram.HueSaturationHistogramRGBA(in.rgba2, in.rgba2.Bounds(), 8)
+			if err != nil {
+				return outputs(err)
+			}
+			return outputs(histogram.BackProjectRGBA(in.rgba, hist, 8))
+		}},
+		{"histogram.HistogramGray", func(in *testInputs) []interface{} { return outputs(histogram.HistogramGray(in.gray)) }},
+		{"histogram.HistogramGrayMasked", func(in *testInputs) []interface{} {
+			return outputs(histogram.HistogramGrayMasked(in.gray, in.mask))
+		}},
+		{"histogram.HistogramRGBA", func(in *testInputs) []interface{} { return outputs(histogram.HistogramRGBA(in.rgba)) }},
+		{"histogram.DrawHistogramGray", func(in *testInputs) []interface{} {
+			return outputs(histogram.DrawHistogramGray(in.gray, image.Point{X: 64, Y: 32}))
+		}},
+		{"histogram.DrawHistogramRGBA", func(in *testInputs) []interface{} {
+			return outputs(histogram.DrawHistogramRGBA(in.rgba, image.Point{X: 64, Y: 32}))
+		}},
+		// inpaint
+		{"inpaint.InpaintGray", func(in *testInputs) []interface{} {
+			return outputs(inpaint.InpaintGray(in.gray, in.mask, 3, inpaint.InpaintTelea))
+		}},
+		{"inpaint.InpaintRGBA", func(in *testInputs) []interface{} {
+			return outputs(inpaint.InpaintRGBA(in.rgba, in.mask, 3, inpaint.InpaintTelea))
+		}},
+		// matching
+		{"matching.MatchTemplateGray", func(in *testInputs) []interface{} {
+			template := in.gray2.SubImage(image.Rect(3, 4, 11, 10)).(*image.Gray)
+			return outputs(matching.MatchTemplateGray(in.gray, template, matching.MatchCCoeffNormed))
+		}},
+		{"matching.MatchTemplateMultiScaleGray", func(in *testInputs) []interface{} {
+			template := in.gray2.SubImage(image.Rect(3, 4, 11, 10)).(*image.Gray)
+			return outputs(matching.MatchTemplateMultiScaleGray(in.gray, template, []float64{0.5, 1, 1.5}, matching.MatchSqDiff))
+		}},
+		// morphology
+		{"morphology.CorrectIlluminationGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.CorrectIlluminationGray(in.gray, 7))
+		}},
+		{"morphology.DilateGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.DilateGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.ErodeGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.ErodeGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.DilateGrayAnchored", func(in *testInputs) []interface{} {
+			return outputs(morphology.DilateGrayAnchored(in.gray, morphology.Square3x3(), image.Point{}))
+		}},
+		{"morphology.ErodeGrayAnchored", func(in *testInputs) []interface{} {
+			return outputs(morphology.ErodeGrayAnchored(in.gray, morphology.Square3x3(), image.Point{X: 2, Y: 2}))
+		}},
+		{"morphology.OpenGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.OpenGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.CloseGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.CloseGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.ReconstructByDilationGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.ReconstructByDilationGray(in.gray2, in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.ReconstructByErosionGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.ReconstructByErosionGray(in.gray2, in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.OpeningByReconstructionGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.OpeningByReconstructionGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.ClosingByReconstructionGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.ClosingByReconstructionGray(in.gray, morphology.Square3x3()))
+		}},
+		{"morphology.ZhangSuenThinGray", func(in *testInputs) []interface{} { return outputs(morphology.ZhangSuenThinGray(in.mask)) }},
+		{"morphology.ThinningGray", func(in *testInputs) []interface{} {
+			return outputs(morphology.ThinningGray(in.mask, morphology.ThinningGuoHall))
+		}},
+		{"morphology.FillHolesGray", func(in *testInputs) []interface{} { return outputs(morphology.FillHolesGray(in.mask)) }},
+		// padding
+		{"padding.PaddingGray", func(in *testInputs) []interface{} {
+			return outputs(padding.PaddingGray(in.gray, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, padding.BorderReflect))
+		}},
+		{"padding.PaddingGrayWithInfo", func(in *testInputs) []interface{} {
+			return outputs(padding.PaddingGrayWithInfo(in.gray, image.Point{X: 3, Y: 5}, image.Point{X: 1, Y: 1}, padding.BorderReplicate))
+		}},
+		{"padding.PaddingGrayPerAxis", func(in *testInputs) []interface{} {
+			return outputs(padding.PaddingGrayPerAxis(in.gray, image.Point{X: 5, Y: 3}, image.Point{X: 2, Y: 1}, padding.BorderReflect, padding.BorderReplicate))
+		}},
+		{"padding.PaddingRGBA", func(in *testInputs) []interface{} {
+			return outputs(padding.PaddingRGBA(in.rgba, image.Point{X: 5, Y: 3}, image.Point{X: 2, Y: 1}, padding.BorderConstant))
+		}},
+		{"padding.PadToSizeGray", func(in *testInputs) []interface{} {
+			return outputs(padding.PadToSizeGray(in.gray, 40, 32, padding.BorderReflect))
+		}},
+		{"padding.AddBorderGray", func(in *testInputs) []interface{} {
+			return outputs(padding.AddBorderGray(in.gray, 2, 3, 4, 5, padding.BorderConstant, color.Gray{Y: 255}))
+		}},
+		// pyramid
+		{"pyramid.PlaneFromGray", func(in *testInputs) []interface{} { return outputs(pyramid.PlaneFromGray(in.gray)) }},
+		{"pyramid.PyrDown", func(in *testInputs) []interface{} { return outputs(pyramid.PyrDown(in.plane)) }},
+		{"pyramid.PyrUp", func(in *testInputs) []interface{} {
+			return outputs(pyramid.PyrUp(in.plane, image.Point{X: 2 * in.plane.Width, Y: 2 * in.plane.Height}))
+		}},
+		{"pyramid.GaussianPyramid", func(in *testInputs) []interface{} { return outputs(pyramid.GaussianPyramid(in.plane, 3)) }},
+		{"pyramid.LaplacianPyramid", func(in *testInputs) []interface{} { return outputs(pyramid.LaplacianPyramid(in.plane, 1)) }},
+		{"pyramid.CollapseLaplacianPyramid", func(in *testInputs) []interface{} {
+			return outputs(pyramid.CollapseLaplacianPyramid([]*pyramid.Plane{in.plane}))
+		}},
+		{"pyramid.PyrDownGray", func(in *testInputs) []interface{} { return outputs(pyramid.PyrDownGray(in.gray)) }},
+		{"pyramid.PyrUpGray", func(in *testInputs) []interface{} {
+			return outputs(pyramid.PyrUpGray(in.gray, image.Point{X: 60, Y: 50}))
+		}},
+		{"pyramid.GaussianPyramidGray", func(in *testInputs) []interface{} {
+			return outputs(pyramid.GaussianPyramidGray(in.gray, 3))
+		}},
+		// quantize
+		{"quantize.OrderedDitherGray", func(in *testInputs) []interface{} { return outputs(quantize.OrderedDitherGray(in.gray, 4)) }},
+		{"quantize.OrderedDitherGrayLevels", func(in *testInputs) []interface{} {
+			return outputs(quantize.OrderedDitherGrayLevels(in.gray, 8, 4))
+		}},
+		{"quantize.DominantColorsRGBA", func(in *testInputs) []interface{} { return outputs(quantize.DominantColorsRGBA(in.rgba, 3, 4)) }},
+		{"quantize.DominantColorsKMeansRGBA", func(in *testInputs) []interface{} {
+			return outputs(quantize.DominantColorsKMeansRGBA(in.rgba, 3, quantize.DefaultKMeansOptions()))
+		}},
+		{"quantize.MedianCutPalette", func(in *testInputs) []interface{} { return outputs(quantize.MedianCutPalette(in.rgba, 8)) }},
+		{"quantize.QuantizeRGBA", func(in *testInputs) []interface{} { return outputs(quantize.QuantizeRGBA(in.rgba, 8)) }},
+		{"quantize.QuantizeRGBADithered", func(in *testInputs) []interface{} {
+			return outputs(quantize.QuantizeRGBADithered(in.rgba, 8))
+		}},
+		// registration
+		{"registration.PhaseCorrelate", func(in *testInputs) []interface{} {
+			return outputs(registration.PhaseCorrelate(in.gray, in.gray2, true))
+		}},
+		// resize
+		{"resize.ResizeGray", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeGray(in.gray, 1.5, 0.5, resize.InterCatmullRom))
+		}},
+		{"resize.ResizeRGBA", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeRGBA(in.rgba, 0.7, 1.3, resize.InterLanczos))
+		}},
+		{"resize.ResizeRGBA with pool", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeRGBA(in.rgba, 0.7, 1.3, resize.InterLanczos, utils.WithPool(pool)))
+		}},
+		{"resize.ResizeGrayAntiAlias", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeGrayAntiAlias(in.gray, 2, 2, resize.InterLinear))
+		}},
+		{"resize.ResizeRGBAAntiAlias", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeRGBAAntiAlias(in.rgba, 0.25, 0.5, resize.InterNearest))
+		}},
+		{"resize.DownscaleMaxGray", func(in *testInputs) []interface{} { return outputs(resize.DownscaleMaxGray(in.gray, 3)) }},
+		{"resize.ResizeMaskGray", func(in *testInputs) []interface{} {
+			return outputs(resize.ResizeMaskGray(in.mask, 13, 11, 0.5))
+		}},
+		{"resize.MaskCoverageGray", func(in *testInputs) []interface{} {
+			return outputs(resize.MaskCoverageGray(in.mask, 13, 11))
+		}},
+		{"resize.ThumbnailRGBA", func(in *testInputs) []interface{} { return outputs(resize.ThumbnailRGBA(in.rgba, 16, true)) }},
+		// rle
+		{"rle.EncodeRLE", func(in *testInputs) []interface{} { return outputs(rle.EncodeRLE(in.mask)) }},
+		{"rle.DecodeRLE", func(in *testInputs) []interface{} {
+			data, err := rle.EncodeRLE(in.mask)
+			if err != nil {
+				return outputs(err)
+			}
+			return outputs(rle.DecodeRLE(data, in.mask.Rect.Dx(), in.mask.Rect.Dy()))
+		}},
+		// segmentation
+		{"segmentation.Watershed", func(in *testInputs) []interface{} { return outputs(segmentation.Watershed(in.gray, in.markers)) }},
+		{"segmentation.CleanMaskGray", func(in *testInputs) []interface{} {
+			return outputs(segmentation.CleanMaskGray(in.gray, 128, morphology.Square3x3(), morphology.Cross3x3()))
+		}},
+		// stitch
+		{"stitch.StitchRGBA", func(in *testInputs) []interface{} {
+			return outputs(stitch.StitchRGBA([]*image.RGBA{in.rgba, in.rgba2}, []image.Point{{0, 0}, {20, 3}}, true))
+		}},
+		// texture
+		{"texture.GLCMGray", func(in *testInputs) []interface{} { return outputs(texture.GLCMGray(in.gray, image.Point{X: 1, Y: 1})) }},
+		{"texture.EnergyGray", func(in *testInputs) []interface{} { return outputs(texture.EnergyGray(in.gray, image.Point{X: 1})) }},
+		{"texture.ContrastGray", func(in *testInputs) []interface{} { return outputs(texture.ContrastGray(in.gray, image.Point{Y: 2})) }},
+		{"texture.LBPGray", func(in *testInputs) []interface{} { return outputs(texture.LBPGray(in.gray, 2, 8, true)) }},
+		{"texture.LBPRotationInvariantGray", func(in *testInputs) []interface{} {
+			return outputs(texture.LBPRotationInvariantGray(in.gray, 1, 8))
+		}},
+		{"texture.HOGDescriptorGray", func(in *testInputs) []interface{} {
+			return outputs(texture.HOGDescriptorGray(in.gray, image.Point{X: 8, Y: 8}, image.Point{X: 2, Y: 2}, 9))
+		}},
+		{"texture.HOGGray", func(in *testInputs) []interface{} { return outputs(texture.HOGGray(in.gray, 6, 2, 9)) }},
+		{"texture.VisualizeHOG", func(in *testInputs) []interface{} {
+			return outputs(texture.VisualizeHOG(in.gray, image.Point{X: 8, Y: 8}, 9))
+		}},
+		// threshold
+		{"threshold.Threshold", func(in *testInputs) []interface{} {
+			return outputs(threshold.Threshold(in.gray, 128, threshold.ThreshBinary))
+		}},
+		{"threshold.ThresholdWithMax", func(in *testInputs) []interface{} {
+			return outputs(threshold.ThresholdWithMax(in.gray, 100, 200, threshold.ThreshBinaryInv))
+		}},
+		{"threshold.MultiOtsuGray", func(in *testInputs) []interface{} { return outputs(threshold.MultiOtsuGray(in.gray, 3)) }},
+		{"threshold.ThresholdByPercentileGray", func(in *testInputs) []interface{} {
+			return outputs(threshold.ThresholdByPercentileGray(in.gray, 90))
+		}},
+		{"threshold.SauvolaThresholdGray", func(in *testInputs) []interface{} {
+			return outputs(threshold.SauvolaThresholdGray(in.gray, 7, 0.2, 128))
+		}},
+		{"threshold.NiblackThresholdGray", func(in *testInputs) []interface{} {
+			return outputs(threshold.NiblackThresholdGray(in.gray, 7, -0.2))
+		}},
+		{"threshold.ThresholdRGBA", func(in *testInputs) []interface{} {
+			return outputs(threshold.ThresholdRGBA(in.rgba, 90, threshold.ThreshToZero))
+		}},
+		{"threshold.PalettedThreshold", func(in *testInputs) []interface{} {
+			return outputs(threshold.PalettedThreshold(in.paletted, 100, threshold.ThreshBinary))
+		}},
+		{"threshold.ThresholdROI", func(in *testInputs) []interface{} {
+			return outputs(threshold.ThresholdROI(in.gray, image.Rect(3, 3, 20, 20), 90, threshold.ThreshBinary))
+		}},
+		{"threshold.Threshold16", func(in *testInputs) []interface{} {
+			return outputs(threshold.Threshold16(in.gray16, 30000, threshold.ThreshBinary))
+		}},
+		{"threshold.OtsuThreshold", func(in *testInputs) []interface{} {
+			return outputs(threshold.OtsuThreshold(in.gray, threshold.ThreshBinary))
+		}},
+		{"threshold.OtsuThresholdGrayMasked", func(in *testInputs) []interface{} {
+			return outputs(threshold.OtsuThresholdGrayMasked(in.gray, in.mask, threshold.ThreshBinary, true))
+		}},
+		{"threshold.ScanlineRunsAdaptive", func(in *testInputs) []interface{} {
+			return outputs(threshold.ScanlineRunsAdaptive(in.gray, 10, 5))
+		}},
+		// tiling
+		{"tiling.ProcessTiledGray", func(in *testInputs) []interface{} {
+			// the callback writes into its tiles, which must not be views of the input
+			return outputs(tiling.ProcessTiledGray(in.gray, image.Point{X: 16, Y: 16}, 2, func(tile *image.Gray) (*image.Gray, error) {
+				for i := range tile.Pix {
+					tile.Pix[i] = 255 - tile.Pix[i]
+				}
+				return tile, nil
+			}))
+		}},
+		{"tiling.MontageRGBA", func(in *testInputs) []interface{} {
+			return outputs(tiling.MontageRGBA([]*image.RGBA{in.rgba, in.small, in.rgba2}, 2, in.rgba.Rect.Size(), color.RGBA{}, resize.InterLinear))
+		}},
+		{"tiling.SplitGrid", func(in *testInputs) []interface{} { return outputs(tiling.SplitGrid(in.rgba, 2, 3)) }},
+		{"tiling.SlidingWindowsGray", func(in *testInputs) []interface{} {
+			return outputs(tiling.SlidingWindowsGray(in.gray, image.Point{X: 8, Y: 6}, image.Point{X: 5, Y: 4}))
+		}},
+		// tracking
+		{"tracking.CornerSubPix", func(in *testInputs) []interface{} {
+			return outputs(tracking.CornerSubPix(in.gray, in.points, image.Point{X: 3, Y: 3}, tracking.TermCriteria{MaxIterations: 5}))
+		}},
+		{"tracking.LucasKanadeFlow", func(in *testInputs) []interface{} {
+			return outputs(tracking.LucasKanadeFlow(in.gray, in.gray2, in.points[:5], 5, 1))
+		}},
+		{"tracking.MeanShift", func(in *testInputs) []interface{} {
+			return outputs(tracking.MeanShift(in.gray, image.Rect(5, 5, 15, 15), tracking.TermCriteria{MaxIterations: 10, Epsilon: 1}))
+		}},
+		{"tracking.CamShift", func(in *testInputs) []interface{} {
+			return outputs(tracking.CamShift(in.gray, image.Rect(5, 5, 15, 15), tracking.TermCriteria{MaxIterations: 10, Epsilon: 1}))
+		}},
+		{"tracking.FrameDifferenceGray", func(in *testInputs) []interface{} {
+			return outputs(tracking.FrameDifferenceGray(in.gray, in.gray2, 30))
+		}},
+		{"tracking.BackgroundSubtractor", func(in *testInputs) []interface{} {
+			subtractor, err := tracking.NewRunningAverageSubtractor(0.5)
+			if err != nil {
+				return outputs(err)
+			}
+			first, err := subtractor.Apply(in.gray)
+			if err != nil {
+				return outputs(err)
+			}
+			return append(outputs(subtractor.Apply(in.gray2)), first)
+		}},
+		// transform
+		{"transform.RotateGray", func(in *testInputs) []interface{} {
+			return outputs(transform.RotateGray(in.gray, 30, image.Point{X: 10, Y: 10}, true))
+		}},
+		{"transform.RotateGrayInterp", func(in *testInputs) []interface{} {
+			return outputs(transform.RotateGrayInterp(in.gray, 0, image.Point{}, false, resize.InterLinear))
+		}},
+		{"transform.TileGray", func(in *testInputs) []interface{} { return outputs(transform.TileGray(in.gray, 50, 40)) }},
+		{"transform.RotateGrayWithMatrix", func(in *testInputs) []interface{} {
+			return outputs(transform.RotateGrayWithMatrix(in.gray, 30, resize.InterLinear))
+		}},
+		{"transform.RotateRGBA", func(in *testInputs) []interface{} {
+			return outputs(transform.RotateRGBA(in.rgba, -45, image.Point{X: 5, Y: 5}, false))
+		}},
+		{"transform.RotateRGBAInterp", func(in *testInputs) []interface{} {
+			return outputs(transform.RotateRGBAInterp(in.rgba, 10, image.Point{X: 5, Y: 5}, true, resize.InterCatmullRom))
+		}},
+		{"transform.TranslateGray", func(in *testInputs) []interface{} {
+			return outputs(transform.TranslateGray(in.gray, 0, 0, padding.BorderReflect, resize.InterNearest))
+		}},
+		{"transform.RemapGray", func(in *testInputs) []interface{} {
+			return outputs(transform.RemapGray(in.gray, in.floats, in.floats, resize.InterCatmullRom, padding.BorderReflect))
+		}},
+		{"transform.RemapRGBA", func(in *testInputs) []interface{} {
+			return outputs(transform.RemapRGBA(in.rgba, in.floats, in.floats, resize.InterLinear, padding.BorderReplicate))
+		}},
+		{"transform.ElasticDeformGray", func(in *testInputs) []interface{} {
+			return outputs(transform.ElasticDeformGray(in.gray, 20, 4, 1, resize.InterLinear))
+		}},
+		{"transform.ElasticDeformRGBA", func(in *testInputs) []interface{} {
+			return outputs(transform.ElasticDeformRGBA(in.rgba, 20, 4, 1, resize.InterNearest))
+		}},
+		{"transform.UndistortGray", func(in *testInputs) []interface{} {
+			return outputs(transform.UndistortGray(in.gray, 0.1, 0.01, image.Point{X: 18, Y: 14}))
+		}},
+		{"transform.UndistortGrayInterp", func(in *testInputs) []interface{} {
+			return outputs(transform.UndistortGrayInterp(in.gray, 0.1, 0.01, image.Point{X: 18, Y: 14}, resize.InterCatmullRom))
+		}},
+		{"transform.UndistortRGBA", func(in *testInputs) []interface{} {
+			return outputs(transform.UndistortRGBA(in.rgba, 0.1, 0.01, image.Point{X: 18, Y: 14}))
+		}},
+		{"transform.DistortGray", func(in *testInputs) []interface{} {
+			return outputs(transform.DistortGray(in.gray, 0, 0, image.Point{X: 18, Y: 14}))
+		}},
+		{"transform.DistortRGBA", func(in *testInputs) []interface{} {
+			return outputs(transform.DistortRGBA(in.rgba, 0.2, 0, image.Point{X: 18, Y: 14}))
+		}},
+		{"transform.RadonTransformGray", func(in *testInputs) []interface{} {
+			return outputs(transform.RadonTransformGray(in.gray, []float64{0, 45, 90}))
+		}},
+		{"transform.HorizontalProjectionGray", func(in *testInputs) []interface{} {
+			return outputs(transform.HorizontalProjectionGray(in.gray))
+		}},
+		{"transform.VerticalProjectionGray", func(in *testInputs) []interface{} { return outputs(transform.VerticalProjectionGray(in.gray)) }},
+		{"transform.TransposeGray", func(in *testInputs) []interface{} { return outputs(transform.TransposeGray(in.gray)) }},
+		{"transform.TransposeRGBA", func(in *testInputs) []interface{} { return outputs(transform.TransposeRGBA(in.rgba)) }},
+		{"transform.AutoCropGray", func(in *testInputs) []interface{} { return outputs(transform.AutoCropGray(in.gray, 128, 60)) }},
+		{"transform.Rotate90Gray", func(in *testInputs) []interface{} { return outputs(transform.Rotate90Gray(in.gray)) }},
+		{"transform.Rotate180Gray", func(in *testInputs) []interface{} { return outputs(transform.Rotate180Gray(in.gray)) }},
+		{"transform.Rotate270Gray", func(in *testInputs) []interface{} { return outputs(transform.Rotate270Gray(in.gray)) }},
+		{"transform.Rotate90RGBA", func(in *testInputs) []interface{} { return outputs(transform.Rotate90RGBA(in.rgba)) }},
+		{"transform.Rotate180RGBA", func(in *testInputs) []interface{} { return outputs(transform.Rotate180RGBA(in.rgba)) }},
+		{"transform.Rotate270RGBA", func(in *testInputs) []interface{} { return outputs(transform.Rotate270RGBA(in.rgba)) }},
+		{"transform.DetectSkewGray", func(in *testInputs) []interface{} { return outputs(transform.DetectSkewGray(in.mask, 10)) }},
+		{"transform.DeskewGray", func(in *testInputs) []interface{} { return outputs(transform.DeskewGray(in.mask, 10)) }},
+		{"transform.AutoDeskewGray", func(in *testInputs) []interface{} { return outputs(transform.AutoDeskewGray(in.mask)) }},
+		{"transform.DeskewRGBA", func(in *testInputs) []interface{} { return outputs(transform.DeskewRGBA(in.rgba, 10)) }},
+		// utils
+		{"utils.SplitRGBA", func(in *testInputs) []interface{} { return outputs(utils.SplitRGBA(in.rgba)) }},
+		{"utils.MergeRGBA", func(in *testInputs) []interface{} {
+			return outputs(utils.MergeRGBA(in.gray, in.gray2, in.mask, in.gray))
+		}},
+		{"utils.BilinearSampleGray", func(in *testInputs) []interface{} { return outputs(utils.BilinearSampleGray(in.gray, 3.3, 4.7)) }},
+		{"utils.BilinearSampleGrayF64", func(in *testInputs) []interface{} { return outputs(utils.BilinearSampleGrayF64(in.gray, 3.3, 4.7)) }},
+		{"utils.BilinearSampleRGBA", func(in *testInputs) []interface{} { return outputs(utils.BilinearSampleRGBA(in.rgba, 3.3, 4.7)) }},
+		{"utils.BicubicSampleGray", func(in *testInputs) []interface{} { return outputs(utils.BicubicSampleGray(in.gray, 3.3, 4.7)) }},
+		{"utils.DiffGray", func(in *testInputs) []interface{} { return outputs(utils.DiffGray(in.gray, in.gray2)) }},
+		{"utils.Unpremultiply", func(in *testInputs) []interface{} { return outputs(utils.Unpremultiply(in.rgba)) }},
+		{"utils.Premultiply", func(in *testInputs) []interface{} { return outputs(utils.Premultiply(in.nrgba)) }},
+		{"utils.CloneGray", func(in *testInputs) []interface{} { return outputs(utils.CloneGray(in.gray)) }},
+		{"utils.FromBytesGray", func(in *testInputs) []interface{} { return outputs(utils.FromBytesGray(in.gray.Pix, 37, 29)) }},
+		{"utils.ToBytesGray", func(in *testInputs) []interface{} { return outputs(utils.ToBytesGray(in.gray)) }},
+		{"utils.CloneRGBA", func(in *testInputs) []interface{} { return outputs(utils.CloneRGBA(in.rgba)) }},
+		{"utils.CloneNRGBA", func(in *testInputs) []interface{} { return outputs(utils.CloneNRGBA(in.nrgba)) }},
+		{"utils.MapGray", func(in *testInputs) []interface{} {
+			return outputs(utils.MapGray(in.gray, func(x, y int, v uint8) uint8 { return v / 2 }))
+		}},
+		{"utils.MapGrayParallel", func(in *testInputs) []interface{} {
+			return outputs(utils.MapGrayParallel(in.gray, func(x, y int, v uint8) uint8 { return v }, 3))
+		}},
+		{"utils.MapRGBA", func(in *testInputs) []interface{} {
+			return outputs(utils.MapRGBA(in.rgba, func(x, y int, c color.RGBA) color.RGBA { return c }))
+		}},
+		{"utils.ApplyLUTGray", func(in *testInputs) []interface{} { return outputs(utils.ApplyLUTGray(in.gray, [256]uint8{})) }},
+		{"utils.ApplyLUTRGBA", func(in *testInputs) []interface{} {
+			return outputs(utils.ApplyLUTRGBA(in.rgba, [256]uint8{}, [256]uint8{}, [256]uint8{}))
+		}},
+		{"utils.PalettedApplyLUT", func(in *testInputs) []interface{} {
+			return outputs(utils.PalettedApplyLUT(in.paletted, func(c color.Color) color.Color { return color.GrayModel.Convert(c) }))
+		}},
+		{"utils.ReduceGray", func(in *testInputs) []interface{} {
+			return outputs(utils.ReduceGray(in.gray, utils.AxisColumns, utils.ReduceMean))
+		}},
+		{"utils.ReduceRGBA", func(in *testInputs) []interface{} {
+			return outputs(utils.ReduceRGBA(in.rgba, utils.AxisRows, utils.ReduceMax))
+		}},
+		{"utils.AnalyzeRGBA", func(in *testInputs) []interface{} { return outputs(utils.AnalyzeRGBA(in.rgba)) }},
+		{"utils.CountNonZeroGray", func(in *testInputs) []interface{} { return outputs(utils.CountNonZeroGray(in.mask)) }},
+		{"utils.MeanStdDevGrayMasked", func(in *testInputs) []interface{} {
+			return outputs(utils.MeanStdDevGrayMasked(in.gray, in.mask))
+		}},
+		{"analysis.SharpnessGray", func(in *testInputs) []interface{} { return outputs(analysis.SharpnessGray(in.gray)) }},
+		{"utils.LaplacianVarianceGray", func(in *testInputs) []interface{} { return outputs(utils.LaplacianVarianceGray(in.gray)) }},
+		{"utils.EstimateNoiseGray", func(in *testInputs) []interface{} { return outputs(utils.EstimateNoiseGray(in.gray)) }},
+		{"utils.ClippedFractionsGray", func(in *testInputs) []interface{} { return outputs(utils.ClippedFractionsGray(in.gray)) }},
+		// visualize
+		{"visualize.OverlayMaskRGBA", func(in *testInputs) []interface{} {
+			return outputs(visualize.OverlayMaskRGBA(in.rgba, in.mask, color.RGBA{R: 255, A: 255}, 0.4))
+		}},
+	}
+	for i, c := range cases {
+		in := newTestInputs(int64(i))
+		before := in.clone()
+		results := c.run(in)
+		if !reflect.DeepEqual(before, in) {
+			t.Errorf("%s modified its input", c.name)
+			continue
+		}
+		for _, result := range results {
+			overwrite(result)
+		}
+		if !reflect.DeepEqual(before, in) {
+			t.Errorf("%s returned a result which shares memory with its input", c.name)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// testInputs holds every kind of input used by the functions under test.
+type testInputs struct {
+	gray, gray2, mask *image.Gray
+	rgba, rgba2       *image.RGBA
+	small             *image.RGBA
+	nrgba             *image.NRGBA
+	paletted          *image.Paletted
+	gray16            *image.Gray16
+	kernel            *convolution.Kernel
+	plane             *pyramid.Plane
+	floats            [][]float64
+	markers           [][]int
+	hist              []float64
+	signal            []complex128
+	points            []image.Point
+}
+
+// newTestInputs creates random 37x29 images and matching buffers.
+func newTestInputs(seed int64) *testInputs {
+	rnd := rand.New(rand.NewSource(seed))
+	const width, height = 37, 29
+	in := &testInputs{
+		gray:   image.NewGray(image.Rect(0, 0, width, height)),
+		gray2:  image.NewGray(image.Rect(0, 0, width, height)),
+		mask:   image.NewGray(image.Rect(0, 0, width, height)),
+		rgba:   image.NewRGBA(image.Rect(0, 0, width, height)),
+		rgba2:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		small:  image.NewRGBA(image.Rect(0, 0, 7, 5)),
+		nrgba:  image.NewNRGBA(image.Rect(0, 0, width, height)),
+		gray16: image.NewGray16(image.Rect(0, 0, width, height)),
+		plane:  pyramid.NewPlane(width, height),
+		hist:   make([]float64, 12),
+		signal: make([]complex128, 30),
+	}
+	for _, pix := range [][]uint8{in.gray.Pix, in.gray2.Pix, in.rgba.Pix, in.rgba2.Pix, in.small.Pix, in.nrgba.Pix, in.gray16.Pix} {
+		rnd.Read(pix)
+	}
+	palette := make(color.Palette, 16)
+	for i := range palette {
+		palette[i] = color.RGBA{R: uint8(rnd.Intn(256)), G: uint8(rnd.Intn(256)), B: uint8(rnd.Intn(256)), A: 255}
+	}
+	in.paletted = image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for i := range in.paletted.Pix {
+		in.paletted.Pix[i] = uint8(rnd.Intn(len(palette)))
+	}
+	// the mask has a rectangle with a hole and a few random pixels
+	utils.ForEachPixel(in.mask.Bounds().Size(), func(x, y int) {
+		inRect := x >= 8 && x < 28 && y >= 6 && y < 22 && !(x >= 15 && x < 20 && y >= 12 && y < 16)
+		if inRect || rnd.Intn(40) == 0 {
+			in.mask.SetGray(x, y, color.Gray{Y: 255})
+		}
+	})
+	in.kernel = convolution.NewSharpenKernel()
+	in.floats = make([][]float64, width)
+	in.markers = make([][]int, width)
+	for x := 0; x < width; x++ {
+		in.floats[x] = make([]float64, height)
+		in.markers[x] = make([]int, height)
+		for y := 0; y < height; y++ {
+			in.floats[x][y] = rnd.Float64() * 255
+			in.plane.Set(x, y, rnd.Float64()*255)
+		}
+	}
+	in.markers[5][5], in.markers[30][20] = 1, 2
+	for i := range in.hist {
+		in.hist[i] = rnd.Float64()
+	}
+	for i := range in.signal {
+		in.signal[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+	}
+	for i := 0; i < 30; i++ {
+		in.points = append(in.points, image.Point{X: rnd.Intn(width), Y: rnd.Intn(height)})
+	}
+	return in
+}
+
+// clone returns a deep copy of the inputs.
+func (in *testInputs) clone() *testInputs {
+	res := &testInputs{
+		gray:   utils.CloneGray(in.gray),
+		gray2:  utils.CloneGray(in.gray2),
+		mask:   utils.CloneGray(in.mask),
+		rgba:   utils.CloneRGBA(in.rgba),
+		rgba2:  utils.CloneRGBA(in.rgba2),
+		small:  utils.CloneRGBA(in.small),
+		nrgba:  utils.CloneNRGBA(in.nrgba),
+		gray16: image.NewGray16(in.gray16.Rect),
+		kernel: &convolution.Kernel{Content: cloneFloats(in.kernel.Content), Width: in.kernel.Width, Height: in.kernel.Height},
+		plane:  &pyramid.Plane{Pix: append([]float64{}, in.plane.Pix...), Width: in.plane.Width, Height: in.plane.Height},
+		floats: cloneFloats(in.floats),
+		hist:   append([]float64{}, in.hist...),
+		signal: append([]complex128{}, in.signal...),
+		points: append([]image.Point{}, in.points...),
+	}
+	copy(res.gray16.Pix, in.gray16.Pix)
+	res.paletted = image.NewPaletted(in.paletted.Rect, append(color.Palette{}, in.paletted.Palette...))
+	copy(res.paletted.Pix, in.paletted.Pix)
+	for _, column := range in.markers {
+		res.markers = append(res.markers, append([]int{}, column...))
+	}
+	return res
+}
+
+func cloneFloats(values [][]float64) [][]float64 {
+	res := make([][]float64, len(values))
+	for i := range values {
+		res[i] = append([]float64{}, values[i]...)
+	}
+	return res
+}
+
+// outputs collects the results of a call.
+func outputs(results ...interface{}) []interface{} {
+	return results
+}
+
+// overwrite fills the buffers of a result with a constant, so results sharing memory with an input change the input.
+func overwrite(result interface{}) {
+	switch r := result.(type) {
+	case *image.Gray:
+		fillBytes(r.Pix)
+	case *image.Gray16:
+		fillBytes(r.Pix)
+	case *image.RGBA:
+		fillBytes(r.Pix)
+	case *image.NRGBA:
+		fillBytes(r.Pix)
+	case *image.Paletted:
+		fillBytes(r.Pix)
+		for i := range r.Palette {
+			r.Palette[i] = color.RGBA{R: 0xA5, G: 0xA5, B: 0xA5, A: 0xA5}
+		}
+	case *pyramid.Plane:
+		for i := range r.Pix {
+			r.Pix[i] = -1
+		}
+	case []*image.Gray:
+		for _, img := range r {
+			overwrite(img)
+		}
+	case []*image.RGBA:
+		for _, img := range r {
+			overwrite(img)
+		}
+	case []*pyramid.Plane:
+		for _, p := range r {
+			overwrite(p)
+		}
+	case [][]float64:
+		for _, column := range r {
+			for i := range column {
+				column[i] = -1
+			}
+		}
+	case [][]int:
+		for _, column := range r {
+			for i := range column {
+				column[i] = -1
+			}
+		}
+	case [4][]float64:
+		for _, v := range r {
+			overwrite(v)
+		}
+	case []float64:
+		for i := range r {
+			r[i] = -1
+		}
+	case []complex128:
+		for i := range r {
+			r[i] = -1
+		}
+	case []image.Point:
+		for i := range r {
+			r[i] = image.Point{X: -1, Y: -1}
+		}
+	case *floatimage.FloatImage:
+		for _, plane := range r.Planes {
+			overwrite(plane)
+		}
+	case [][]image.Point:
+		for _, points := range r {
+			overwrite(points)
+		}
+	case []byte:
+		fillBytes(r)
+	}
+}
+
+func fillBytes(pix []uint8) {
+	for i := range pix {
+		pix[i] = 0xA5
+	}
+}
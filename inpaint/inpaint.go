@@ -0,0 +1,299 @@
+package inpaint
+
+import (
+	"container/heap"
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// InpaintMethod is the algorithm used to fill the masked regions of an image.
+type InpaintMethod int
+
+const (
+	// InpaintTelea fills the masked pixels with the fast marching method of A. Telea ("An Image Inpainting Technique
+	// Based on the Fast Marching Method", 2004): the pixels are filled from the boundary of the masked region inward
+	// and every pixel is estimated from the already known pixels in its neighbourhood, weighted by direction, distance
+	// and level set distance.
+	InpaintTelea InpaintMethod = iota
+)
+
+// InpaintGray fills the pixels of a grayscale image where the mask is non-zero using the known pixels within the given
+// radius, e.g. to remove dust or timestamps. Returns an error if the sizes of the image and the mask do not match, the
+// radius is smaller then 1, the method is unknown or the mask covers the whole image.
+// Example of usage:
+//
+//	res, err := inpaint.InpaintGray(img, mask, 3, inpaint.InpaintTelea)
+func InpaintGray(img, mask *image.Gray, radius int, method InpaintMethod) (*image.Gray, error) {
+	size := img.Bounds().Size()
+	masked, err := validate(size, mask, radius, method)
+	if err != nil {
+		return nil, err
+	}
+	plane := make([]float64, size.X*size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			plane[y*size.X+x] = float64(img.Pix[y*img.Stride+x])
+		}
+	}
+	telea([][]float64{plane}, size, masked, radius)
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for i, v := range plane {
+		res.Pix[i] = toUint8(v)
+	}
+	return res, nil
+}
+
+// InpaintRGBA fills the pixels of an RGBA image where the mask is non-zero, every channel (including alpha) is filled
+// the same way as by InpaintGray.
+// Example of usage:
+//
+//	res, err := inpaint.InpaintRGBA(img, mask, 3, inpaint.InpaintTelea)
+func InpaintRGBA(img *image.RGBA, mask *image.Gray, radius int, method InpaintMethod) (*image.RGBA, error) {
+	size := img.Bounds().Size()
+	masked, err := validate(size, mask, radius, method)
+	if err != nil {
+		return nil, err
+	}
+	planes := make([][]float64, 4)
+	for c := range planes {
+		planes[c] = make([]float64, size.X*size.Y)
+	}
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			for c := range planes {
+				planes[c][y*size.X+x] = float64(img.Pix[y*img.Stride+4*x+c])
+			}
+		}
+	}
+	telea(planes, size, masked, radius)
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for i := 0; i < size.X*size.Y; i++ {
+		for c := range planes {
+			res.Pix[4*i+c] = toUint8(planes[c][i])
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+const (
+	known = iota
+	band
+	inside
+)
+
+// unreached is the distance of the pixels which were not reached by the fast marching yet.
+const unreached = 1e6
+
+func validate(size image.Point, mask *image.Gray, radius int, method InpaintMethod) ([]bool, error) {
+	if mask.Bounds().Size() != size {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	if radius < 1 {
+		return nil, errors.New("radius must be bigger then 0")
+	}
+	if method != InpaintTelea {
+		return nil, errors.New("unknown inpaint method")
+	}
+	masked := make([]bool, size.X*size.Y)
+	count := 0
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if mask.Pix[y*mask.Stride+x] != 0 {
+				masked[y*size.X+x] = true
+				count++
+			}
+		}
+	}
+	if count == len(masked) {
+		return nil, errors.New("the mask covers the whole image")
+	}
+	return masked, nil
+}
+
+// telea fills the masked pixels of the row-major planes in place.
+func telea(planes [][]float64, size image.Point, masked []bool, radius int) {
+	flags := make([]int, len(masked))
+	dist := make([]float64, len(masked))
+	queue := &bandQueue{}
+	for i, m := range masked {
+		if m {
+			flags[i], dist[i] = inside, unreached
+		}
+	}
+	// the known pixels next to the masked region form the initial band
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			i := y*size.X + x
+			if flags[i] != known {
+				continue
+			}
+			for _, n := range neighbours(x, y, size) {
+				if flags[n.Y*size.X+n.X] == inside {
+					flags[i] = band
+					queue.push(image.Point{X: x, Y: y}, 0)
+					break
+				}
+			}
+		}
+	}
+
+	// the pixels outside of the image are never usable
+	usable := func(x, y int) bool {
+		return inBounds(x, y, size) && flags[y*size.X+x] != inside
+	}
+	solve := func(x1, y1, x2, y2 int) float64 {
+		f1, f2 := usable(x1, y1), usable(x2, y2)
+		var d1, d2 float64
+		if f1 {
+			d1 = dist[y1*size.X+x1]
+		}
+		if f2 {
+			d2 = dist[y2*size.X+x2]
+		}
+		switch {
+		case f1 && f2:
+			if math.Abs(d1-d2) >= 1 {
+				return 1 + math.Min(d1, d2)
+			}
+			return (d1 + d2 + math.Sqrt(2-(d1-d2)*(d1-d2))) / 2
+		case f1:
+			return 1 + d1
+		case f2:
+			return 1 + d2
+		}
+		return unreached
+	}
+
+	for queue.Len() > 0 {
+		p := heap.Pop(queue).(bandPixel).point
+		flags[p.Y*size.X+p.X] = known
+		for _, n := range neighbours(p.X, p.Y, size) {
+			i := n.Y*size.X + n.X
+			if flags[i] != inside {
+				continue
+			}
+			dist[i] = math.Min(math.Min(solve(n.X-1, n.Y, n.X, n.Y-1), solve(n.X+1, n.Y, n.X, n.Y-1)),
+				math.Min(solve(n.X-1, n.Y, n.X, n.Y+1), solve(n.X+1, n.Y, n.X, n.Y+1)))
+			fillPixel(planes, size, flags, dist, n, radius)
+			flags[i] = band
+			queue.push(n, dist[i])
+		}
+	}
+}
+
+// fillPixel estimates the pixel p of every plane from the known pixels within the radius.
+func fillPixel(planes [][]float64, size image.Point, flags []int, dist []float64, p image.Point, radius int) {
+	i := p.Y*size.X + p.X
+	gradDistX := centralDifference(dist, flags, size, p, 1, 0)
+	gradDistY := centralDifference(dist, flags, size, p, 0, 1)
+	sums := make([]float64, len(planes))
+	var weights float64
+	for ky := p.Y - radius; ky <= p.Y+radius; ky++ {
+		for kx := p.X - radius; kx <= p.X+radius; kx++ {
+			if !inBounds(kx, ky, size) || flags[ky*size.X+kx] == inside {
+				continue
+			}
+			rx, ry := float64(p.X-kx), float64(p.Y-ky)
+			lenSq := rx*rx + ry*ry
+			if lenSq > float64(radius*radius) {
+				continue
+			}
+			k := ky*size.X + kx
+			direction := math.Abs(rx*gradDistX+ry*gradDistY) / math.Sqrt(lenSq)
+			if direction == 0 {
+				direction = 1e-6
+			}
+			level := 1 / (1 + math.Abs(dist[k]-dist[i]))
+			w := direction * level / lenSq
+			for c, plane := range planes {
+				gx := centralDifference(plane, flags, size, image.Point{X: kx, Y: ky}, 1, 0)
+				gy := centralDifference(plane, flags, size, image.Point{X: kx, Y: ky}, 0, 1)
+				sums[c] += w * (plane[k] + gx*rx + gy*ry)
+			}
+			weights += w
+		}
+	}
+	if weights == 0 {
+		return
+	}
+	for c, plane := range planes {
+		plane[i] = sums[c] / weights
+	}
+}
+
+// centralDifference returns the derivative of the values along the {dx, dy} direction at p, using only the pixels
+// which are not inside of the masked region. One sided differences are used when only one neighbour is available.
+func centralDifference(values []float64, flags []int, size image.Point, p image.Point, dx, dy int) float64 {
+	usable := func(x, y int) bool {
+		return inBounds(x, y, size) && flags[y*size.X+x] != inside
+	}
+	nx, ny, px, py := p.X+dx, p.Y+dy, p.X-dx, p.Y-dy
+	switch {
+	case usable(nx, ny) && usable(px, py):
+		return (values[ny*size.X+nx] - values[py*size.X+px]) / 2
+	case usable(nx, ny) && usable(p.X, p.Y):
+		return values[ny*size.X+nx] - values[p.Y*size.X+p.X]
+	case usable(px, py) && usable(p.X, p.Y):
+		return values[p.Y*size.X+p.X] - values[py*size.X+px]
+	}
+	return 0
+}
+
+func neighbours(x, y int, size image.Point) []image.Point {
+	var res []image.Point
+	for _, d := range []image.Point{{X: -1}, {X: 1}, {Y: -1}, {Y: 1}} {
+		if inBounds(x+d.X, y+d.Y, size) {
+			res = append(res, image.Point{X: x + d.X, Y: y + d.Y})
+		}
+	}
+	return res
+}
+
+func inBounds(x, y int, size image.Point) bool {
+	return x >= 0 && y >= 0 && x < size.X && y < size.Y
+}
+
+func toUint8(v float64) uint8 {
+	return uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+}
+
+type bandPixel struct {
+	point image.Point
+	dist  float64
+	order int
+}
+
+// bandQueue is a priority queue of the narrow band ordered by the distance from the initial boundary. Pixels with the
+// same distance are processed in the order they were added.
+type bandQueue struct {
+	items   []bandPixel
+	counter int
+}
+
+func (q *bandQueue) push(p image.Point, dist float64) {
+	heap.Push(q, bandPixel{point: p, dist: dist, order: q.counter})
+	q.counter++
+}
+
+func (q *bandQueue) Len() int { return len(q.items) }
+
+func (q *bandQueue) Less(i, j int) bool {
+	if q.items[i].dist != q.items[j].dist {
+		return q.items[i].dist < q.items[j].dist
+	}
+	return q.items[i].order < q.items[j].order
+}
+
+func (q *bandQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *bandQueue) Push(x interface{}) { q.items = append(q.items, x.(bandPixel)) }
+
+func (q *bandQueue) Pop() interface{} {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items = q.items[:n-1]
+	return item
+}
@@ -0,0 +1,103 @@
+package inpaint
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_InpaintGray_Scratch(t *testing.T) {
+	img := gradientGray(60, 40)
+	mask := image.NewGray(img.Bounds())
+	damaged := image.NewGray(img.Bounds())
+	copy(damaged.Pix, img.Pix)
+	// a thin diagonal scratch across the image
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if d := 2*x/3 - y; d >= 0 && d <= 1 {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			damaged.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	res, err := InpaintGray(damaged, mask, 3, InpaintTelea)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImagesWithOffset(t, img, res, 3)
+}
+
+func Test_InpaintGray_Border(t *testing.T) {
+	img := gradientGray(20, 20)
+	mask := image.NewGray(img.Bounds())
+	damaged := image.NewGray(img.Bounds())
+	copy(damaged.Pix, img.Pix)
+	// a block in the top left corner and a strip along the right border
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if (x < 3 && y < 3) || x == 19 {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			damaged.SetGray(x, y, color.Gray{})
+		}
+	})
+	res, err := InpaintGray(damaged, mask, 4, InpaintTelea)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImagesWithOffset(t, img, res, 6)
+}
+
+func Test_InpaintRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 30, 30))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, color.RGBA{R: uint8(10 + 4*x), G: uint8(10 + 4*y), B: 0x80, A: 0xFF})
+	})
+	mask := image.NewGray(img.Bounds())
+	damaged := image.NewRGBA(img.Bounds())
+	copy(damaged.Pix, img.Pix)
+	for x := 10; x < 13; x++ {
+		for y := 12; y < 16; y++ {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			damaged.SetRGBA(x, y, color.RGBA{})
+		}
+	}
+	res, err := InpaintRGBA(damaged, mask, 3, InpaintTelea)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImagesWithOffset(t, img, res, 3)
+}
+
+func Test_InpaintGray_Invalid(t *testing.T) {
+	img := gradientGray(10, 10)
+	if _, err := InpaintGray(img, image.NewGray(image.Rect(0, 0, 9, 10)), 3, InpaintTelea); err == nil {
+		t.Error("Expected error for mismatching sizes")
+	}
+	full := image.NewGray(img.Bounds())
+	for i := range full.Pix {
+		full.Pix[i] = 0xFF
+	}
+	if _, err := InpaintGray(img, full, 3, InpaintTelea); err == nil {
+		t.Error("Expected error for a mask covering the whole image")
+	}
+	if _, err := InpaintGray(img, image.NewGray(img.Bounds()), 0, InpaintTelea); err == nil {
+		t.Error("Expected error for radius 0")
+	}
+	if _, err := InpaintGray(img, image.NewGray(img.Bounds()), 3, InpaintMethod(7)); err == nil {
+		t.Error("Expected error for an unknown method")
+	}
+	// an empty mask leaves the image unchanged
+	res, err := InpaintGray(img, image.NewGray(img.Bounds()), 3, InpaintTelea)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, img, res)
+}
+
+// -------------------------------------------------------------------------------
+func gradientGray(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: uint8(20 + 2*x + 2*y)})
+	})
+	return img
+}
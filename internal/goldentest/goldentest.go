@@ -0,0 +1,190 @@
+// Package goldentest runs image operations against checked-in input images and compares their outputs with golden
+// images, so that an unintended change of a filter output is caught by the tests. On a mismatch a visual diff image
+// is written next to the golden image. The golden images are regenerated deliberately with the -update flag:
+//
+//	go test ./blur -run Test_Golden -update
+package goldentest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// DiffAmplification is the factor the absolute per-pixel differences are multiplied with in the diff images, so
+// that small drifts are visible.
+const DiffAmplification = 16
+
+var update = flag.Bool("update", false, "regenerate the golden images instead of comparing against them")
+
+// OperationGray is an image operation under test.
+type OperationGray func(img *image.Gray) (*image.Gray, error)
+
+// Case is an operation registered for golden testing.
+type Case struct {
+	// Name of the golden image file without the extension
+	Name string
+	// Path of the input image
+	Input string
+	// Maximum absolute per-pixel difference which is still accepted
+	Tolerance uint8
+	// Operation under test
+	Operation OperationGray
+}
+
+// Registry holds the golden cases of a package together with the directory of their golden images.
+type Registry struct {
+	dir   string
+	cases []Case
+}
+
+// NewRegistry creates an empty registry which keeps the golden images in the given directory.
+// Example of usage:
+//
+//	registry := goldentest.NewRegistry("../res/golden/blur")
+func NewRegistry(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+// Register adds an operation which is run on the input image and compared against the golden image called name.
+// Example of usage:
+//
+//	registry.Register("box5x5", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {...})
+func (r *Registry) Register(name string, input string, tolerance uint8, operation OperationGray) {
+	r.cases = append(r.cases, Case{Name: name, Input: input, Tolerance: tolerance, Operation: operation})
+}
+
+// Cases returns the registered cases in the order of registration.
+func (r *Registry) Cases() []Case {
+	return r.cases
+}
+
+// GoldenPath returns the path of the golden image of a case.
+func (r *Registry) GoldenPath(name string) string {
+	return filepath.Join(r.dir, name+".png")
+}
+
+// DiffPath returns the path where the diff image of a failing case is written.
+func (r *Registry) DiffPath(name string) string {
+	return filepath.Join(r.dir, name+"_diff.png")
+}
+
+// Run runs every registered case as a subtest. With the -update flag the golden images are rewritten from the
+// current outputs, otherwise every output is compared against its golden image and on a mismatch the amplified
+// diff image is written to DiffPath.
+// Example of usage:
+//
+//	registry.Run(t)
+func (r *Registry) Run(t *testing.T) {
+	for _, c := range r.cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if err := r.runCase(c, *update); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// CompareGray compares two grayscale images and returns the number of pixels which differ by more then the
+// tolerance together with the largest absolute difference. Returns an error if the sizes do not match.
+// Example of usage:
+//
+//	mismatches, maxDiff, err := goldentest.CompareGray(expected, actual, 1)
+func CompareGray(expected, actual *image.Gray, tolerance uint8) (int, uint8, error) {
+	diff, _, err := diffGray(expected, actual)
+	if err != nil {
+		return 0, 0, err
+	}
+	mismatches := 0
+	var maxDiff uint8
+	for _, d := range diff.Pix {
+		if d > tolerance {
+			mismatches++
+		}
+		if d > maxDiff {
+			maxDiff = d
+		}
+	}
+	return mismatches, maxDiff, nil
+}
+
+// DiffImageGray renders the absolute per-pixel difference of two grayscale images multiplied by DiffAmplification,
+// differences which do not fit into a byte are saturated. Returns an error if the sizes do not match.
+// Example of usage:
+//
+//	diff, err := goldentest.DiffImageGray(expected, actual)
+func DiffImageGray(expected, actual *image.Gray) (*image.Gray, error) {
+	diff, _, err := diffGray(expected, actual)
+	if err != nil {
+		return nil, err
+	}
+	for i, d := range diff.Pix {
+		diff.Pix[i] = uint8(utils.ClampInt(int(d)*DiffAmplification, 0, int(utils.MaxUint8)))
+	}
+	return diff, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+func (r *Registry) runCase(c Case, regenerate bool) error {
+	input, err := imgio.ImreadGray(c.Input)
+	if err != nil {
+		return fmt.Errorf("could not read input image %s: %s", c.Input, err)
+	}
+	actual, err := c.Operation(input)
+	if err != nil {
+		return fmt.Errorf("operation failed: %s", err)
+	}
+	goldenPath := r.GoldenPath(c.Name)
+	if regenerate {
+		if err := os.MkdirAll(r.dir, 0755); err != nil {
+			return err
+		}
+		return imgio.Imwrite(actual, goldenPath)
+	}
+	expected, err := imgio.ImreadGray(goldenPath)
+	if err != nil {
+		return fmt.Errorf("could not read golden image %s, run the test with -update to create it: %s", goldenPath, err)
+	}
+	mismatches, maxDiff, err := CompareGray(expected, actual, c.Tolerance)
+	if err != nil {
+		return fmt.Errorf("golden image %s: %s", goldenPath, err)
+	}
+	diffPath := r.DiffPath(c.Name)
+	if mismatches == 0 {
+		// a diff image of an earlier failure is stale now
+		os.Remove(diffPath)
+		return nil
+	}
+	diff, err := DiffImageGray(expected, actual)
+	if err != nil {
+		return err
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "output differs from golden image %s: %d of %d pixels differ by more then %d (max difference: %d)",
+		goldenPath, mismatches, len(diff.Pix), c.Tolerance, maxDiff)
+	if err := imgio.Imwrite(diff, diffPath); err != nil {
+		fmt.Fprintf(&msg, ", could not write diff image: %s", err)
+	} else {
+		fmt.Fprintf(&msg, ", diff image: %s", diffPath)
+	}
+	return errors.New(msg.String())
+}
+
+func diffGray(expected, actual *image.Gray) (*image.Gray, int, error) {
+	expectedSize := expected.Bounds().Size()
+	actualSize := actual.Bounds().Size()
+	if !expectedSize.Eq(actualSize) {
+		return nil, 0, fmt.Errorf("expected size %d %d - actual size %d %d", expectedSize.X, expectedSize.Y, actualSize.X, actualSize.Y)
+	}
+	// DiffGray requires equal bounds, the images are compared from their top-left corner
+	return utils.DiffGray(utils.CloneGray(expected), utils.CloneGray(actual))
+}
@@ -0,0 +1,95 @@
+package goldentest
+
+import (
+	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_CompareGray(t *testing.T) {
+	expected := &image.Gray{Rect: image.Rect(0, 0, 3, 1), Stride: 3, Pix: []uint8{10, 20, 30}}
+	actual := &image.Gray{Rect: image.Rect(0, 0, 3, 1), Stride: 3, Pix: []uint8{11, 20, 25}}
+	mismatches, maxDiff, err := CompareGray(expected, actual, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if mismatches != 1 || maxDiff != 5 {
+		t.Errorf("Expected 1 mismatch with max difference 5 - actual: %d mismatches, max difference %d", mismatches, maxDiff)
+	}
+	if _, _, err := CompareGray(expected, image.NewGray(image.Rect(0, 0, 1, 3)), 0); err == nil {
+		t.Error("Expected error for images of different sizes")
+	}
+}
+
+func Test_DiffImageGray(t *testing.T) {
+	expected := &image.Gray{Rect: image.Rect(0, 0, 4, 1), Stride: 4, Pix: []uint8{0, 100, 200, 50}}
+	actual := &image.Gray{Rect: image.Rect(0, 0, 4, 1), Stride: 4, Pix: []uint8{0, 102, 100, 49}}
+	diff, err := DiffImageGray(expected, actual)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, &image.Gray{Rect: image.Rect(0, 0, 4, 1), Stride: 4, Pix: []uint8{0, 32, 255, 16}}, diff)
+}
+
+func Test_Registry_runCase(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.png")
+	img := image.NewGray(image.Rect(0, 0, 8, 6))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 5)
+	}
+	if err := imgio.Imwrite(img, input); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	shift := uint8(0)
+	registry := NewRegistry(filepath.Join(dir, "golden"))
+	registry.Register("shift", input, 1, func(img *image.Gray) (*image.Gray, error) {
+		res := utils.CloneGray(img)
+		for i := range res.Pix {
+			res.Pix[i] += shift
+		}
+		return res, nil
+	})
+	c := registry.Cases()[0]
+	if err := registry.runCase(c, false); err == nil {
+		t.Error("Expected error for a missing golden image")
+	}
+	if err := registry.runCase(c, true); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if err := registry.runCase(c, false); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+	// a drift within the tolerance passes
+	shift = 1
+	if err := registry.runCase(c, false); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+	shift = 3
+	if err := registry.runCase(c, false); err == nil {
+		t.Error("Expected error for an output which drifted from the golden image")
+	}
+	diff, err := imgio.ImreadGray(registry.DiffPath("shift"))
+	if err != nil {
+		t.Fatalf("Expected the diff image to be written. Error value: %s", err)
+	}
+	for _, d := range diff.Pix {
+		if d != 3*DiffAmplification {
+			t.Fatalf("Expected amplified difference: %d - actual: %d", 3*DiffAmplification, d)
+		}
+	}
+	// the stale diff image is removed once the output matches again
+	shift = 0
+	if err := registry.runCase(c, false); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+	if _, err := os.Stat(registry.DiffPath("shift")); !os.IsNotExist(err) {
+		t.Error("Expected the diff image to be removed")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,68 @@
+package matching
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/resize"
+	"image"
+)
+
+// MatchTemplateMultiScaleGray finds a template in a grayscale image when the object can appear at a different size.
+// The template is resized by every given scale (with resize.ResizeGrayAntiAlias, so a downscaled template does not
+// alias) and matched with MatchTemplateGray, the best score over all the positions and scales is returned together with
+// its location, the top left corner of the scaled template relative to the top left corner of the image, and its
+// scale. The scores of MatchSqDiff grow with the area of the template, so for this method the returned score is the
+// mean squared difference, the sum divided by the area of the scaled template. The other methods are normalized and
+// their scores are returned unchanged. The scales where the template becomes empty or larger then the image are
+// skipped, if several scales give the same score the first one wins. Returns an error if a scale is not positive, no
+// scale fits into the image or the method is invalid.
+// Example of usage:
+//
+//	loc, scale, score, err := matching.MatchTemplateMultiScaleGray(img, template, []float64{0.5, 0.75, 1, 1.5, 2}, matching.MatchCCoeffNormed)
+func MatchTemplateMultiScaleGray(img, template *image.Gray, scales []float64, method MatchMethod) (bestLoc image.Point, bestScale float64, bestScore float64, err error) {
+	if method < MatchSqDiff || method > MatchCCoeffNormed {
+		return image.Point{}, 0, 0, errors.New("invalid match method")
+	}
+	found := false
+	size := img.Bounds().Size()
+	for _, scale := range scales {
+		if scale <= 0 {
+			return image.Point{}, 0, 0, errors.New("scale value should be greater then 0")
+		}
+		scaled, err := resize.ResizeGrayAntiAlias(template, scale, scale, resize.InterLinear)
+		if err != nil {
+			return image.Point{}, 0, 0, err
+		}
+		scaledSize := scaled.Bounds().Size()
+		if scaledSize.X <= 0 || scaledSize.Y <= 0 || scaledSize.X > size.X || scaledSize.Y > size.Y {
+			continue
+		}
+		scores, err := MatchTemplateGray(img, scaled, method)
+		if err != nil {
+			return image.Point{}, 0, 0, err
+		}
+		if method == MatchSqDiff {
+			area := float64(scaledSize.X * scaledSize.Y)
+			for _, column := range scores {
+				for y := range column {
+					column[y] /= area
+				}
+			}
+		}
+		for x, column := range scores {
+			for y, score := range column {
+				better := score > bestScore
+				if lowerIsBetter(method) {
+					better = score < bestScore
+				}
+				if !found || better {
+					found = true
+					bestLoc, bestScale, bestScore = image.Point{X: x, Y: y}, scale, score
+				}
+			}
+		}
+	}
+	if !found {
+		return image.Point{}, 0, 0, errors.New("the template does not fit into the image at any scale")
+	}
+	return bestLoc, bestScale, bestScore, nil
+}
@@ -0,0 +1,67 @@
+package matching
+
+import (
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"image"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_MatchTemplateMultiScaleGray(t *testing.T) {
+	// a smooth random pattern, so the template can be resized without losing its content
+	pattern, _, err := blur.BoxGray(setupTestCaseNoise(16, 12, 4), image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	enlarged, err := resize.ResizeGray(pattern, 1.5, 1.5, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	img := setupTestCaseNoise(90, 70, 5)
+	location := image.Point{X: 41, Y: 23}
+	for y := 0; y < 18; y++ {
+		copy(img.Pix[(location.Y+y)*img.Stride+location.X:], enlarged.Pix[y*enlarged.Stride:y*enlarged.Stride+24])
+	}
+	for _, method := range []MatchMethod{MatchSqDiff, MatchCCoeffNormed} {
+		loc, scale, score, err := MatchTemplateMultiScaleGray(img, pattern, []float64{0.5, 0.75, 1, 1.25, 1.5, 2}, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if math.Abs(scale-1.5) > 1e-9 {
+			t.Errorf("Expected scale: 1.5 - actual scale: %f with method: %d", scale, method)
+		}
+		if loc != location {
+			t.Errorf("Expected location: %v - actual location: %v with method: %d", location, loc, method)
+		}
+		if (method == MatchSqDiff && score > 1) || (method == MatchCCoeffNormed && score < 0.99) {
+			t.Errorf("Expected a perfect match - actual score: %f with method: %d", score, method)
+		}
+	}
+}
+
+func Test_MatchTemplateMultiScaleGray_Invalid(t *testing.T) {
+	img := setupTestCaseNoise(20, 20, 6)
+	template := setupTestCaseNoise(8, 8, 7)
+	if _, _, _, err := MatchTemplateMultiScaleGray(img, template, []float64{1, 0}, MatchCCoeffNormed); err == nil {
+		t.Error("Expected error for a scale of 0")
+	}
+	if _, _, _, err := MatchTemplateMultiScaleGray(img, template, []float64{3, 4}, MatchCCoeffNormed); err == nil {
+		t.Error("Expected error when the template does not fit into the image at any scale")
+	}
+	if _, _, _, err := MatchTemplateMultiScaleGray(img, template, []float64{1}, MatchMethod(-1)); err == nil {
+		t.Error("Expected error for an invalid method")
+	}
+	// the scales which do not fit are skipped
+	loc, scale, _, err := MatchTemplateMultiScaleGray(img, template, []float64{4, 1}, MatchSqDiffNormed)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if scale != 1 || loc.X < 0 || loc.X > 12 || loc.Y < 0 || loc.Y > 12 {
+		t.Errorf("Expected a location with scale 1 - actual location: %v, scale: %f", loc, scale)
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,105 @@
+// Package matching finds the location of a small template image inside of a larger image.
+package matching
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// MatchMethod is an enum type for the comparison methods of template matching
+type MatchMethod int
+
+const (
+	// MatchSqDiff is the sum of the squared differences of the template and the image, the best match has the
+	// lowest score
+	MatchSqDiff MatchMethod = iota
+	// MatchSqDiffNormed is the sum of the squared differences divided by sqrt(sum(T^2) * sum(I^2)), the best match
+	// has the lowest score
+	MatchSqDiffNormed
+	// MatchCCorrNormed is the cross correlation of the template and the image divided by sqrt(sum(T^2) * sum(I^2)),
+	// the best match has the highest score
+	MatchCCorrNormed
+	// MatchCCoeffNormed is the correlation coefficient of the template and the image, the cross correlation of the
+	// values with their means subtracted, in the [-1, 1] interval. It does not depend on the brightness and the
+	// contrast of the image. The best match has the highest score
+	MatchCCoeffNormed
+)
+
+// MatchTemplateGray slides the template over a grayscale image and compares it with the covered part of the image at
+// every position using the given method. The result is indexed as res[x][y], where x and y are the coordinates of
+// the top left corner of the template relative to the top left corner of the image, so its size is
+// (W - w + 1) x (H - h + 1). A flat template or a flat part of the image gives a score of 0 with MatchCCoeffNormed.
+// Returns an error if the template is empty or larger then the image, or the method is invalid.
+// Example of usage:
+//
+//	scores, err := matching.MatchTemplateGray(img, template, matching.MatchCCoeffNormed)
+func MatchTemplateGray(img, template *image.Gray, method MatchMethod) ([][]float64, error) {
+	if method < MatchSqDiff || method > MatchCCoeffNormed {
+		return nil, errors.New("invalid match method")
+	}
+	size := img.Bounds().Size()
+	templateSize := template.Bounds().Size()
+	if templateSize.X <= 0 || templateSize.Y <= 0 {
+		return nil, errors.New("empty template")
+	}
+	if templateSize.X > size.X || templateSize.Y > size.Y {
+		return nil, errors.New("the template is larger then the image")
+	}
+	area := float64(templateSize.X * templateSize.Y)
+	t := make([]float64, templateSize.X*templateSize.Y)
+	var templateSum, templateSqSum float64
+	utils.ForEachPixel(templateSize, func(x, y int) {
+		v := float64(template.Pix[template.PixOffset(template.Rect.Min.X+x, template.Rect.Min.Y+y)])
+		t[y*templateSize.X+x] = v
+		templateSum += v
+		templateSqSum += v * v
+	})
+	templateMean := templateSum / area
+	// the sum of the squared deviations of the template
+	templateVar := templateSqSum - templateSum*templateMean
+	resSize := size.Sub(templateSize).Add(image.Point{X: 1, Y: 1})
+	res := make([][]float64, resSize.X)
+	for x := range res {
+		res[x] = make([]float64, resSize.Y)
+	}
+	utils.ParallelForEachPixel(resSize, func(x, y int) {
+		var cross, sum, sqSum float64
+		for ty := 0; ty < templateSize.Y; ty++ {
+			row := img.Pix[img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y+ty):]
+			for tx := 0; tx < templateSize.X; tx++ {
+				v := float64(row[tx])
+				cross += v * t[ty*templateSize.X+tx]
+				sum += v
+				sqSum += v * v
+			}
+		}
+		var score float64
+		switch method {
+		case MatchSqDiff:
+			score = sqSum - 2*cross + templateSqSum
+		case MatchSqDiffNormed:
+			if norm := math.Sqrt(sqSum * templateSqSum); norm > 0 {
+				score = (sqSum - 2*cross + templateSqSum) / norm
+			}
+		case MatchCCorrNormed:
+			if norm := math.Sqrt(sqSum * templateSqSum); norm > 0 {
+				score = cross / norm
+			}
+		case MatchCCoeffNormed:
+			variance := sqSum - sum*sum/area
+			if norm := math.Sqrt(variance * templateVar); norm > 1e-9 {
+				score = utils.ClampF64((cross-sum*templateMean)/norm, -1, 1)
+			}
+		}
+		res[x][y] = score
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// lowerIsBetter tells whether the best match of a method has the lowest score.
+func lowerIsBetter(method MatchMethod) bool {
+	return method == MatchSqDiff || method == MatchSqDiffNormed
+}
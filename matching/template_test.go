@@ -0,0 +1,92 @@
+package matching
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_MatchTemplateGray(t *testing.T) {
+	img := setupTestCaseNoise(50, 40, 1)
+	template := utils.CloneGray(img.SubImage(image.Rect(17, 9, 29, 17)).(*image.Gray))
+	cases := []struct {
+		method   MatchMethod
+		expected float64
+	}{
+		{MatchSqDiff, 0},
+		{MatchSqDiffNormed, 0},
+		{MatchCCorrNormed, 1},
+		{MatchCCoeffNormed, 1},
+	}
+	for _, c := range cases {
+		scores, err := MatchTemplateGray(img, template, c.method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if len(scores) != 39 || len(scores[0]) != 33 {
+			t.Fatalf("Expected size: 39x33 - actual size: %dx%d", len(scores), len(scores[0]))
+		}
+		best := bestLocation(scores, c.method)
+		if best != (image.Point{X: 17, Y: 9}) {
+			t.Errorf("Expected location: (17,9) - actual location: %v with method: %d", best, c.method)
+		}
+		if !utils.IsEqualFloat64(c.expected, scores[17][9]) {
+			t.Errorf("Expected score: %f - actual score: %f with method: %d", c.expected, scores[17][9], c.method)
+		}
+	}
+}
+
+func Test_MatchTemplateGray_CCoeffNormedContrast(t *testing.T) {
+	img := setupTestCaseNoise(30, 30, 2)
+	template := utils.CloneGray(img.SubImage(image.Rect(5, 11, 15, 19)).(*image.Gray))
+	// the correlation coefficient does not depend on the brightness and the contrast of the template
+	for i, v := range template.Pix {
+		template.Pix[i] = v/2 + 40
+	}
+	scores, err := MatchTemplateGray(img, template, MatchCCoeffNormed)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if best := bestLocation(scores, MatchCCoeffNormed); best != (image.Point{X: 5, Y: 11}) {
+		t.Errorf("Expected location: (5,11) - actual location: %v", best)
+	}
+	if scores[5][11] < 0.99 {
+		t.Errorf("Expected a score close to 1 - actual score: %f", scores[5][11])
+	}
+}
+
+func Test_MatchTemplateGray_Invalid(t *testing.T) {
+	img := setupTestCaseNoise(10, 10, 3)
+	if _, err := MatchTemplateGray(img, setupTestCaseNoise(11, 5, 3), MatchSqDiff); err == nil {
+		t.Error("Expected error for a template larger then the image")
+	}
+	if _, err := MatchTemplateGray(img, image.NewGray(image.Rect(0, 0, 0, 3)), MatchSqDiff); err == nil {
+		t.Error("Expected error for an empty template")
+	}
+	if _, err := MatchTemplateGray(img, img, MatchMethod(42)); err == nil {
+		t.Error("Expected error for an invalid method")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseNoise(width, height int, seed int64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(seed)).Read(img.Pix)
+	return img
+}
+
+func bestLocation(scores [][]float64, method MatchMethod) image.Point {
+	var best image.Point
+	for x, column := range scores {
+		for y, score := range column {
+			current := scores[best.X][best.Y]
+			if (lowerIsBetter(method) && score < current) || (!lowerIsBetter(method) && score > current) {
+				best = image.Point{X: x, Y: y}
+			}
+		}
+	}
+	return best
+}
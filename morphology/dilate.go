@@ -0,0 +1,87 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// DilateGray dilates a grayscale image with a structuring element centered on the output pixel: every pixel gets the
+// maximum of the pixels covered by the nonzero entries of the element. The structuring element is indexed as
+// kernel[x][y] and its size has to be odd. The pixels outside of the image are ignored.
+// Example of usage:
+//
+//	res, err := morphology.DilateGray(img, morphology.Square3x3())
+func DilateGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	if _, err := kernelOffsets(kernel); err != nil {
+		return nil, err
+	}
+	return DilateGrayAnchored(img, kernel, image.Point{X: -1, Y: -1})
+}
+
+// ErodeGray erodes a grayscale image with a structuring element centered on the output pixel: every pixel gets the
+// minimum of the pixels covered by the nonzero entries of the element. The structuring element is indexed as
+// kernel[x][y] and its size has to be odd. The pixels outside of the image are ignored.
+// Example of usage:
+//
+//	res, err := morphology.ErodeGray(img, morphology.Square3x3())
+func ErodeGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	if _, err := kernelOffsets(kernel); err != nil {
+		return nil, err
+	}
+	return ErodeGrayAnchored(img, kernel, image.Point{X: -1, Y: -1})
+}
+
+// DilateGrayAnchored dilates a grayscale image with a structuring element whose origin is the given anchor cell, the
+// anchor {-1, -1} means the center of the element. The dilation is the union of the image shifted by every nonzero
+// entry of the element relative to the anchor, so a single pixel grows into a copy of the element placed with its
+// anchor on the pixel. An element anchored in its top left corner grows the shapes toward the bottom right. The
+// structuring element is indexed as kernel[x][y], its size can be even. The pixels outside of the image are ignored.
+// Example of usage:
+//
+//	res, err := morphology.DilateGrayAnchored(img, morphology.Square3x3(), image.Point{X: 0, Y: 0})
+func DilateGrayAnchored(img *image.Gray, kernel [][]uint8, anchor image.Point) (*image.Gray, error) {
+	offsets, err := anchoredOffsets(kernel, anchor)
+	if err != nil {
+		return nil, err
+	}
+	return morph(img, offsets, -1, false), nil
+}
+
+// ErodeGrayAnchored erodes a grayscale image with a structuring element whose origin is the given anchor cell, the
+// anchor {-1, -1} means the center of the element. A pixel stays foreground only if the element placed with its anchor
+// on the pixel fits into the foreground, it is the dual of DilateGrayAnchored. The structuring element is indexed as
+// kernel[x][y], its size can be even. The pixels outside of the image are ignored.
+// Example of usage:
+//
+//	res, err := morphology.ErodeGrayAnchored(img, morphology.Square3x3(), image.Point{X: 0, Y: 0})
+func ErodeGrayAnchored(img *image.Gray, kernel [][]uint8, anchor image.Point) (*image.Gray, error) {
+	offsets, err := anchoredOffsets(kernel, anchor)
+	if err != nil {
+		return nil, err
+	}
+	return morph(img, offsets, 1, true), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// morph computes the maximum (or the minimum if erode is set) of the pixels at p + sign * offset for every pixel p.
+func morph(img *image.Gray, offsets []image.Point, sign int, erode bool) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var v uint8
+		if erode {
+			v = utils.MaxUint8
+		}
+		for _, o := range offsets {
+			nx, ny := x+sign*o.X, y+sign*o.Y
+			if nx < 0 || ny < 0 || nx >= size.X || ny >= size.Y {
+				continue
+			}
+			if p := img.Pix[ny*img.Stride+nx]; (erode && p < v) || (!erode && p > v) {
+				v = p
+			}
+		}
+		res.Pix[y*res.Stride+x] = v
+	})
+	return res
+}
@@ -0,0 +1,84 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_DilateGrayAnchored_TopLeft(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 7, 7))
+	img.SetGray(2, 3, color.Gray{Y: 0xFF})
+	res, err := DilateGrayAnchored(img, Square3x3(), image.Point{X: 0, Y: 0})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := image.NewGray(img.Bounds())
+	for x := 2; x <= 4; x++ {
+		for y := 3; y <= 5; y++ {
+			expected.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	}
+	utils.CompareGrayImages(t, expected, res)
+}
+
+func Test_ErodeGrayAnchored_TopLeft(t *testing.T) {
+	// eroding the dilated pixel with the same element gives back the pixel
+	img := image.NewGray(image.Rect(0, 0, 7, 7))
+	expected := image.NewGray(img.Bounds())
+	expected.SetGray(2, 3, color.Gray{Y: 0xFF})
+	for x := 2; x <= 4; x++ {
+		for y := 3; y <= 5; y++ {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	}
+	res, err := ErodeGrayAnchored(img, Square3x3(), image.Point{X: 0, Y: 0})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, res)
+}
+
+func Test_DilateGray_Centered(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	img.SetGray(2, 2, color.Gray{Y: 0x80})
+	res, err := DilateGray(img, Cross3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	anchored, err := DilateGrayAnchored(img, Cross3x3(), image.Point{X: -1, Y: -1})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := image.NewGray(img.Bounds())
+	for _, p := range []image.Point{{X: 2, Y: 2}, {X: 1, Y: 2}, {X: 3, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 3}} {
+		expected.SetGray(p.X, p.Y, color.Gray{Y: 0x80})
+	}
+	utils.CompareGrayImages(t, expected, res)
+	utils.CompareGrayImages(t, expected, anchored)
+	eroded, err := ErodeGray(res, Cross3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// only the center of the cross survives the erosion
+	if eroded.GrayAt(2, 2).Y != 0x80 || eroded.GrayAt(1, 2).Y != 0 {
+		t.Errorf("Expected only the center to survive - actual: %d %d", eroded.GrayAt(2, 2).Y, eroded.GrayAt(1, 2).Y)
+	}
+}
+
+func Test_DilateGrayAnchored_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	if _, err := DilateGrayAnchored(img, Square3x3(), image.Point{X: 3, Y: 0}); err == nil {
+		t.Error("Expected error for an anchor outside of the element")
+	}
+	if _, err := ErodeGray(img, [][]uint8{{1, 1}, {1, 1}}); err == nil {
+		t.Error("Expected error for an even centered element")
+	}
+	if _, err := ErodeGrayAnchored(img, [][]uint8{{1, 1}, {1, 1}}, image.Point{X: 1, Y: 1}); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,53 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+)
+
+// FillHolesGray fills the holes of the objects in a binary image. Every non-zero pixel is treated as foreground. The
+// background is flood filled (4-connected) starting from the background pixels on the border of the image, every
+// background pixel which is not reached is part of a hole and becomes foreground. Foreground pixels are marked with
+// 255 in the returned image.
+// Example of usage:
+//
+//	res := morphology.FillHolesGray(img)
+func FillHolesGray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	outside := make([][]bool, size.X)
+	for x := range outside {
+		outside[x] = make([]bool, size.Y)
+	}
+	var stack []image.Point
+	push := func(x, y int) {
+		if x < 0 || y < 0 || x >= size.X || y >= size.Y || outside[x][y] || img.GrayAt(x, y).Y != 0 {
+			return
+		}
+		outside[x][y] = true
+		stack = append(stack, image.Point{X: x, Y: y})
+	}
+	for x := 0; x < size.X; x++ {
+		push(x, 0)
+		push(x, size.Y-1)
+	}
+	for y := 0; y < size.Y; y++ {
+		push(0, y)
+		push(size.X-1, y)
+	}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		push(p.X+1, p.Y)
+		push(p.X-1, p.Y)
+		push(p.X, p.Y+1)
+		push(p.X, p.Y-1)
+	}
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ForEachPixel(size, func(x, y int) {
+		if !outside[x][y] {
+			res.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+		}
+	})
+	return res
+}
@@ -0,0 +1,50 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_FillHolesGray_Ring(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	expected := image.NewGray(img.Bounds())
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		d := (x-15)*(x-15) + (y-15)*(y-15)
+		if d <= 100 {
+			expected.SetGray(x, y, color.Gray{Y: 0xFF})
+			if d >= 36 {
+				img.SetGray(x, y, color.Gray{Y: 0x80})
+			}
+		}
+		// a separate object on the right side
+		if x >= 30 && x < 35 && y >= 10 && y < 20 {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+			expected.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	res := FillHolesGray(img)
+	utils.CompareGrayImages(t, expected, res)
+	// the background between the objects is connected to the border and stays background
+	if res.GrayAt(27, 15).Y != 0 || res.GrayAt(0, 0).Y != 0 {
+		t.Errorf("Expected the outer background to stay background")
+	}
+}
+
+func Test_FillHolesGray_TouchingBorder(t *testing.T) {
+	// a U shape open towards the top border has no hole
+	img := image.NewGray(image.Rect(0, 0, 7, 7))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if x == 1 || x == 5 || y == 5 {
+			if x >= 1 && x <= 5 && y <= 5 {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	})
+	res := FillHolesGray(img)
+	utils.CompareGrayImages(t, img, res)
+}
+
+// -------------------------------------------------------------------------------
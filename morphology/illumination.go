@@ -0,0 +1,53 @@
+package morphology
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// CorrectIlluminationGray removes a slowly varying illumination from a grayscale image with bright objects on a darker
+// background, e.g. a fluorescence microscopy image or a scan with a shadow. The background is estimated by an opening
+// with a kernelSize x kernelSize square (see OpenGray), which removes every object smaller then the square, and it is
+// subtracted from the image (the white top-hat). The difference is stretched to the [0, 255] interval, so the
+// background becomes uniformly dark and the objects keep their contrast. The kernel size has to be an odd number
+// larger then the objects. Returns an error if the kernel size is not a positive odd number.
+// Example of usage:
+//
+//	res, err := morphology.CorrectIlluminationGray(img, 51)
+func CorrectIlluminationGray(img *image.Gray, kernelSize int) (*image.Gray, error) {
+	if kernelSize < 1 || kernelSize%2 == 0 {
+		return nil, errors.New("kernel size must be a positive odd number")
+	}
+	// the square is the composition of a horizontal and a vertical line, so both the erosion and the dilation are
+	// computed with two 1D passes instead of kernelSize^2 comparisons per pixel
+	line := make([]image.Point, kernelSize)
+	for i := range line {
+		line[i] = image.Point{X: i - kernelSize/2}
+	}
+	column := make([]image.Point, kernelSize)
+	for i := range column {
+		column[i] = image.Point{Y: i - kernelSize/2}
+	}
+	background := morph(morph(utils.CloneGray(img), line, 1, true), column, 1, true)
+	background = morph(morph(background, line, -1, false), column, -1, false)
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	var max uint8
+	utils.ForEachPixel(size, func(x, y int) {
+		// the opening is never brighter then the image
+		v := img.Pix[img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y)] - background.Pix[y*background.Stride+x]
+		res.Pix[y*res.Stride+x] = v
+		if v > max {
+			max = v
+		}
+	})
+	if max == 0 {
+		return res, nil
+	}
+	var lut [256]uint8
+	for i := range lut {
+		lut[i] = uint8(utils.ClampInt((i*int(utils.MaxUint8)+int(max)/2)/int(max), utils.MinUint8, int(utils.MaxUint8)))
+	}
+	return utils.ApplyLUTGray(res, lut), nil
+}
@@ -0,0 +1,94 @@
+package morphology
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_CorrectIlluminationGray_Gradient(t *testing.T) {
+	// a bright square on a background which gets brighter from left to right
+	img := image.NewGray(image.Rect(0, 0, 60, 40))
+	object := image.Rect(25, 15, 35, 25)
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 60; x++ {
+			v := 40 + 3*x
+			if (image.Point{X: x, Y: y}).In(object) {
+				v += 60
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(v)})
+		}
+	}
+	res, err := CorrectIlluminationGray(img, 15)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the opening follows the ramp everywhere except for the half of the kernel at the right border, where the
+	// erosion has no darker pixels to the right of it
+	background := func(img *image.Gray) (min, max uint8) {
+		min = 255
+		for y := 0; y < 40; y++ {
+			for x := 0; x < 60-7; x++ {
+				if (image.Point{X: x, Y: y}).In(object.Inset(-2)) {
+					continue
+				}
+				v := img.GrayAt(x, y).Y
+				if v < min {
+					min = v
+				}
+				if v > max {
+					max = v
+				}
+			}
+		}
+		return min, max
+	}
+	objectMin := func(img *image.Gray) uint8 {
+		min := uint8(255)
+		for y := object.Min.Y; y < object.Max.Y; y++ {
+			for x := object.Min.X; x < object.Max.X; x++ {
+				if v := img.GrayAt(x, y).Y; v < min {
+					min = v
+				}
+			}
+		}
+		return min
+	}
+	// in the original image the right side of the background is as bright as the object
+	if low, high := background(img); high < objectMin(img) {
+		t.Fatalf("Expected the illumination to hide the object - background: [%d, %d], object: %d", low, high, objectMin(img))
+	}
+	low, high := background(res)
+	if float64(high-low) > 0.15*float64(objectMin(res)-high) {
+		t.Errorf("Expected a uniform background - background: [%d, %d], object: %d", low, high, objectMin(res))
+	}
+	for x := 0; x < 60-7; x++ {
+		if v := res.GrayAt(x, 5).Y; v != 0 {
+			t.Errorf("Expected value: 0 - actual value: %d at: %d 5", v, x)
+		}
+	}
+	if objectMin(res) < 200 {
+		t.Errorf("Expected the object to be visible - actual value: %d", objectMin(res))
+	}
+}
+
+func Test_CorrectIlluminationGray_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for _, kernelSize := range []int{0, -3, 4} {
+		if _, err := CorrectIlluminationGray(img, kernelSize); err == nil {
+			t.Errorf("Expected error for kernel size: %d", kernelSize)
+		}
+	}
+	res, err := CorrectIlluminationGray(img, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for _, v := range res.Pix {
+		if v != 0 {
+			t.Fatalf("Expected a black image for a flat input - actual value: %d", v)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
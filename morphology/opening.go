@@ -0,0 +1,90 @@
+package morphology
+
+import (
+	"image"
+)
+
+// OpenGray computes the morphological opening of a grayscale image: an erosion followed by a dilation with the same
+// structuring element (see ErodeGray and DilateGray). It removes the bright details smaller then the element, but it
+// also rounds the shapes which are kept to the shape of the element.
+// Example of usage:
+//
+//	res, err := morphology.OpenGray(img, morphology.Square3x3())
+func OpenGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	eroded, err := ErodeGray(img, kernel)
+	if err != nil {
+		return nil, err
+	}
+	return DilateGray(eroded, kernel)
+}
+
+// CloseGray computes the morphological closing of a grayscale image: a dilation followed by an erosion with the same
+// structuring element (see DilateGray and ErodeGray). It fills the dark details smaller then the element.
+// Example of usage:
+//
+//	res, err := morphology.CloseGray(img, morphology.Square3x3())
+func CloseGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	dilated, err := DilateGray(img, kernel)
+	if err != nil {
+		return nil, err
+	}
+	return ErodeGray(dilated, kernel)
+}
+
+// OpeningByReconstructionGray erodes a grayscale image with the structuring element and reconstructs the result by
+// dilation under the original image with 8-connectivity (see ReconstructByDilationGray). Unlike OpenGray it keeps the
+// exact shape of the objects which survive the erosion, while the objects where the element does not fit anywhere are
+// removed completely. Details which are connected to a surviving object are kept as well. Returns an error if the
+// structuring element is invalid.
+// Example of usage:
+//
+//	res, err := morphology.OpeningByReconstructionGray(img, kernel)
+func OpeningByReconstructionGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	eroded, err := ErodeGray(img, kernel)
+	if err != nil {
+		return nil, err
+	}
+	return ReconstructByDilationGray(eroded, img, Square3x3())
+}
+
+// ClosingByReconstructionGray is the dual of OpeningByReconstructionGray: it dilates a grayscale image with the
+// structuring element and reconstructs the result by erosion above the original image with 8-connectivity (see
+// ReconstructByErosionGray). It fills the dark objects where the element does not fit anywhere and keeps the exact
+// shape of the other dark objects. Returns an error if the structuring element is invalid.
+// Example of usage:
+//
+//	res, err := morphology.ClosingByReconstructionGray(img, kernel)
+func ClosingByReconstructionGray(img *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	dilated, err := DilateGray(img, kernel)
+	if err != nil {
+		return nil, err
+	}
+	return ReconstructByErosionGray(dilated, img, Square3x3())
+}
+
+// ReconstructByErosionGray computes the morphological reconstruction by erosion of the marker above the mask, the dual
+// of ReconstructByDilationGray: the marker is repeatedly eroded with the structuring element and limited by the mask
+// (pointwise maximum) until it does not change anymore. Returns an error if the sizes of the images do not match or
+// the structuring element is invalid.
+// Example of usage:
+//
+//	res, err := morphology.ReconstructByErosionGray(marker, mask, morphology.Cross3x3())
+func ReconstructByErosionGray(marker, mask *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	res, err := ReconstructByDilationGray(invertGray(marker), invertGray(mask), kernel)
+	if err != nil {
+		return nil, err
+	}
+	return invertGray(res), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func invertGray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			res.Pix[y*res.Stride+x] = 255 - img.Pix[y*img.Stride+x]
+		}
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_OpeningByReconstructionGray(t *testing.T) {
+	// a disk and a separate thin line, the line is removed by both openings, but only the opening by reconstruction
+	// keeps the exact shape of the disk
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	body := image.NewGray(img.Bounds())
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		dx, dy := x-14, y-14
+		if dx*dx+dy*dy <= 100 {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+			body.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+		if x >= 28 && x < 30 && y >= 3 && y < 27 {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	kernel := squareKernel(5)
+	opened, err := OpenGray(img, kernel)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if opened.GrayAt(28, 10).Y != 0 {
+		t.Errorf("Expected the opening to remove the thin line")
+	}
+	lost := 0
+	utils.ForEachPixel(body.Bounds().Size(), func(x, y int) {
+		if body.GrayAt(x, y).Y != 0 && opened.GrayAt(x, y).Y == 0 {
+			lost++
+		}
+	})
+	if lost == 0 {
+		t.Errorf("Expected the opening to remove a part of the disk")
+	}
+	res, err := OpeningByReconstructionGray(img, kernel)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, body, res)
+}
+
+func Test_ClosingByReconstructionGray(t *testing.T) {
+	// the dual of the opening test: a dark disk and a dark thin line on a bright background
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	body := image.NewGray(img.Bounds())
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: 0xC0})
+		body.SetGray(x, y, color.Gray{Y: 0xC0})
+		dx, dy := x-14, y-14
+		if dx*dx+dy*dy <= 100 {
+			img.SetGray(x, y, color.Gray{Y: 0x20})
+			body.SetGray(x, y, color.Gray{Y: 0x20})
+		}
+		if x >= 28 && x < 30 && y >= 3 && y < 27 {
+			img.SetGray(x, y, color.Gray{Y: 0x20})
+		}
+	})
+	kernel := squareKernel(5)
+	closed, err := CloseGray(img, kernel)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if closed.GrayAt(28, 10).Y != 0xC0 || closed.GrayAt(14, 4).Y != 0xC0 {
+		t.Errorf("Expected the closing to fill the thin line and a part of the disk")
+	}
+	res, err := ClosingByReconstructionGray(img, kernel)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, body, res)
+}
+
+func Test_OpeningByReconstructionGray_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	if _, err := OpeningByReconstructionGray(img, [][]uint8{{1, 1}, {1, 1}}); err == nil {
+		t.Error("Expected error for an even structuring element")
+	}
+	if _, err := ClosingByReconstructionGray(img, [][]uint8{}); err == nil {
+		t.Error("Expected error for an empty structuring element")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func squareKernel(size int) [][]uint8 {
+	kernel := make([][]uint8, size)
+	for x := range kernel {
+		kernel[x] = make([]uint8, size)
+		for y := range kernel[x] {
+			kernel[x][y] = 1
+		}
+	}
+	return kernel
+}
@@ -0,0 +1,111 @@
+package morphology
+
+import (
+	"errors"
+	"image"
+)
+
+// ReconstructByDilationGray computes the morphological reconstruction by dilation of the marker under the mask: the
+// marker is repeatedly dilated with the structuring element and limited by the mask (pointwise minimum) until it does
+// not change anymore. On binary images the result contains exactly the connected regions of the mask which are hit by
+// the marker. The structuring element is indexed as kernel[x][y], its nonzero entries are the neighbours, its size has
+// to be odd and its center is the origin. The marker is limited by the mask before the first iteration. Returns an
+// error if the sizes of the images do not match or the structuring element is invalid.
+// Example of usage:
+//
+//	res, err := morphology.ReconstructByDilationGray(marker, mask, morphology.Cross3x3())
+func ReconstructByDilationGray(marker, mask *image.Gray, kernel [][]uint8) (*image.Gray, error) {
+	size := mask.Bounds().Size()
+	if marker.Bounds().Size() != size {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	offsets, err := kernelOffsets(kernel)
+	if err != nil {
+		return nil, err
+	}
+	current := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			current.Pix[y*current.Stride+x] = minUint8(marker.Pix[y*marker.Stride+x], mask.Pix[y*mask.Stride+x])
+		}
+	}
+	next := image.NewGray(current.Rect)
+	for changed := true; changed; {
+		changed = false
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				var dilated uint8
+				for _, o := range offsets {
+					nx, ny := x+o.X, y+o.Y
+					if nx < 0 || ny < 0 || nx >= size.X || ny >= size.Y {
+						continue
+					}
+					if v := current.Pix[ny*current.Stride+nx]; v > dilated {
+						dilated = v
+					}
+				}
+				v := minUint8(dilated, mask.Pix[y*mask.Stride+x])
+				if v != current.Pix[y*current.Stride+x] {
+					changed = true
+				}
+				next.Pix[y*next.Stride+x] = v
+			}
+		}
+		current, next = next, current
+	}
+	return current, nil
+}
+
+// Cross3x3 returns the 3x3 cross shaped structuring element, which connects the 4-neighbours of a pixel.
+func Cross3x3() [][]uint8 {
+	return [][]uint8{{0, 1, 0}, {1, 1, 1}, {0, 1, 0}}
+}
+
+// Square3x3 returns the 3x3 square structuring element, which connects the 8-neighbours of a pixel.
+func Square3x3() [][]uint8 {
+	return [][]uint8{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}}
+}
+
+// -------------------------------------------------------------------------------------------------------
+// kernelOffsets returns the offsets of the nonzero entries of a structuring element relative to its center.
+func kernelOffsets(kernel [][]uint8) ([]image.Point, error) {
+	if len(kernel) == 0 || len(kernel)%2 == 0 || len(kernel[0])%2 == 0 {
+		return nil, errors.New("the size of the structuring element has to be odd")
+	}
+	return anchoredOffsets(kernel, image.Point{X: -1, Y: -1})
+}
+
+// anchoredOffsets returns the offsets of the nonzero entries of a structuring element relative to the anchor, the
+// anchor {-1, -1} means the center of the element.
+func anchoredOffsets(kernel [][]uint8, anchor image.Point) ([]image.Point, error) {
+	width := len(kernel)
+	if width == 0 || len(kernel[0]) == 0 {
+		return nil, errors.New("empty structuring element")
+	}
+	height := len(kernel[0])
+	if anchor.X == -1 && anchor.Y == -1 {
+		anchor = image.Point{X: width / 2, Y: height / 2}
+	}
+	if anchor.X < 0 || anchor.Y < 0 || anchor.X >= width || anchor.Y >= height {
+		return nil, errors.New("anchor value outside of the structuring element")
+	}
+	var offsets []image.Point
+	for x, column := range kernel {
+		if len(column) != height {
+			return nil, errors.New("the columns of the structuring element have different lengths")
+		}
+		for y, v := range column {
+			if v != 0 {
+				offsets = append(offsets, image.Point{X: x - anchor.X, Y: y - anchor.Y})
+			}
+		}
+	}
+	return offsets, nil
+}
+
+func minUint8(a, b uint8) uint8 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package morphology
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ReconstructByDilationGray(t *testing.T) {
+	mask := image.NewGray(image.Rect(0, 0, 30, 20))
+	expected := image.NewGray(mask.Bounds())
+	utils.ForEachPixel(mask.Bounds().Size(), func(x, y int) {
+		// a U shaped region which contains the seed
+		inU := (x >= 2 && x < 6 && y >= 2 && y < 18) || (x >= 2 && x < 14 && y >= 14 && y < 18) || (x >= 10 && x < 14 && y >= 2 && y < 18)
+		// a separate block and a block touching the U only diagonally
+		other := (x >= 20 && x < 27 && y >= 3 && y < 10) || (x >= 14 && x < 17 && y >= 18 && y < 20)
+		if inU {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			expected.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+		if other {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	marker := image.NewGray(mask.Bounds())
+	marker.SetGray(3, 3, color.Gray{Y: 0xFF})
+	res, err := ReconstructByDilationGray(marker, mask, Cross3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, res)
+
+	// with 8-connectivity the diagonally touching block is reached as well
+	res, err = ReconstructByDilationGray(marker, mask, Square3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.GrayAt(15, 19).Y != 0xFF || res.GrayAt(22, 5).Y != 0 {
+		t.Errorf("Expected only the 8-connected region to be reconstructed")
+	}
+}
+
+func Test_ReconstructByDilationGray_Grayscale(t *testing.T) {
+	mask := &image.Gray{Rect: image.Rect(0, 0, 5, 1), Stride: 5, Pix: []uint8{10, 50, 30, 80, 20}}
+	marker := &image.Gray{Rect: image.Rect(0, 0, 5, 1), Stride: 5, Pix: []uint8{0, 40, 0, 0, 0}}
+	expected := &image.Gray{Rect: image.Rect(0, 0, 5, 1), Stride: 5, Pix: []uint8{10, 40, 30, 30, 20}}
+	res, err := ReconstructByDilationGray(marker, mask, Cross3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, res)
+}
+
+func Test_ReconstructByDilationGray_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	if _, err := ReconstructByDilationGray(img, image.NewGray(image.Rect(0, 0, 3, 3)), Cross3x3()); err == nil {
+		t.Error("Expected error for different sizes")
+	}
+	if _, err := ReconstructByDilationGray(img, img, [][]uint8{{1, 1}, {1, 1}}); err == nil {
+		t.Error("Expected error for even sized structuring element")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,144 @@
+package morphology
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+)
+
+// ThinningMethod is the algorithm used by ThinningGray.
+type ThinningMethod int
+
+const (
+	// ThinningZhangSuen is the thinning algorithm of T. Y. Zhang and C. Y. Suen ("A fast parallel algorithm for thinning
+	// digital patterns", 1984).
+	ThinningZhangSuen ThinningMethod = iota
+	// ThinningGuoHall is the thinning algorithm of Z. Guo and R. W. Hall ("Parallel thinning with two-subiteration
+	// algorithms", 1989), it gives slightly thinner skeletons on diagonal strokes.
+	ThinningGuoHall
+)
+
+// ZhangSuenThinGray reduces the shapes of a binary image to one pixel wide skeletons using the Zhang-Suen thinning
+// algorithm. Every non-zero pixel is treated as foreground. The boundary pixels which satisfy the Zhang-Suen
+// conditions are removed in two alternating sub-iterations until no more pixels can be removed, which keeps the
+// skeleton 8-connected. Skeleton pixels are marked with 255 in the returned image.
+// Example of usage:
+//
+//	res := morphology.ZhangSuenThinGray(img)
+func ZhangSuenThinGray(img *image.Gray) *image.Gray {
+	// every iteration removes a layer of pixels, so the iteration cap of ThinningGray is never reached
+	res, _ := ThinningGray(img, ThinningZhangSuen)
+	return res
+}
+
+// ThinningGray reduces the shapes of a binary image to one pixel wide, 8-connected skeletons using the given thinning
+// method, e.g. to analyze handwritten strokes. The input is thresholded: every non-zero pixel is treated as foreground.
+// The boundary pixels which satisfy the conditions of the method are removed in two alternating sub-iterations until no
+// more pixels change. Skeleton pixels are marked with 255 in the returned image. Returns an error if the method is
+// unknown or the thinning does not converge within width + height iterations, which can not happen for valid inputs as
+// every iteration removes a layer of the shapes.
+// Example of usage:
+//
+//	res, err := morphology.ThinningGray(img, morphology.ThinningGuoHall)
+func ThinningGray(img *image.Gray, method ThinningMethod) (*image.Gray, error) {
+	var removable func(p [8]int, step int) bool
+	switch method {
+	case ThinningZhangSuen:
+		removable = zhangSuenRemovable
+	case ThinningGuoHall:
+		removable = guoHallRemovable
+	default:
+		return nil, errors.New("unknown thinning method")
+	}
+	size := img.Bounds().Size()
+	fg := make([][]bool, size.X)
+	for x := range fg {
+		fg[x] = make([]bool, size.Y)
+		for y := range fg[x] {
+			fg[x][y] = img.GrayAt(x, y).Y != 0
+		}
+	}
+	at := func(x, y int) int {
+		if x < 0 || y < 0 || x >= size.X || y >= size.Y || !fg[x][y] {
+			return 0
+		}
+		return 1
+	}
+
+	converged := false
+	for iteration := 0; iteration <= size.X+size.Y && !converged; iteration++ {
+		converged = true
+		for step := 0; step < 2; step++ {
+			var remove []image.Point
+			utils.ForEachPixel(size, func(x, y int) {
+				if !fg[x][y] {
+					return
+				}
+				// neighbours p2..p9 clockwise starting from the top
+				p := [8]int{at(x, y-1), at(x+1, y-1), at(x+1, y), at(x+1, y+1), at(x, y+1), at(x-1, y+1), at(x-1, y), at(x-1, y-1)}
+				if removable(p, step) {
+					remove = append(remove, image.Point{X: x, Y: y})
+				}
+			})
+			for _, r := range remove {
+				fg[r.X][r.Y] = false
+			}
+			if len(remove) > 0 {
+				converged = false
+			}
+		}
+	}
+	if !converged {
+		return nil, errors.New("the thinning did not converge")
+	}
+
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ForEachPixel(size, func(x, y int) {
+		if fg[x][y] {
+			res.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+		}
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// zhangSuenRemovable checks the Zhang-Suen conditions for the neighbours p2..p9 (clockwise starting from the top) of a
+// foreground pixel in the given sub-iteration.
+func zhangSuenRemovable(p [8]int, step int) bool {
+	b := 0
+	a := 0
+	for i := 0; i < 8; i++ {
+		b += p[i]
+		if p[i] == 0 && p[(i+1)%8] == 1 {
+			a++
+		}
+	}
+	if b < 2 || b > 6 || a != 1 {
+		return false
+	}
+	if step == 0 {
+		return p[0]*p[2]*p[4] == 0 && p[2]*p[4]*p[6] == 0
+	}
+	return p[0]*p[2]*p[6] == 0 && p[0]*p[4]*p[6] == 0
+}
+
+// guoHallRemovable checks the Guo-Hall conditions for the neighbours p2..p9 (clockwise starting from the top) of a
+// foreground pixel in the given sub-iteration.
+func guoHallRemovable(p [8]int, step int) bool {
+	p2, p3, p4, p5, p6, p7, p8, p9 := p[0], p[1], p[2], p[3], p[4], p[5], p[6], p[7]
+	c := (1-p2)&(p3|p4) + (1-p4)&(p5|p6) + (1-p6)&(p7|p8) + (1-p8)&(p9|p2)
+	n1 := (p9 | p2) + (p3 | p4) + (p5 | p6) + (p7 | p8)
+	n2 := (p2 | p3) + (p4 | p5) + (p6 | p7) + (p8 | p9)
+	n := n1
+	if n2 < n {
+		n = n2
+	}
+	var m int
+	if step == 0 {
+		m = (p6 | p7 | (1 - p9)) & p8
+	} else {
+		m = (p2 | p3 | (1 - p5)) & p4
+	}
+	return c == 1 && n >= 2 && n <= 3 && m == 0
+}
@@ -0,0 +1,195 @@
+package morphology
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseLetter draws a thick "T" shape, its bar and stem are 7 pixels wide.
+func setupTestCaseLetter() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			if (y >= 5 && y < 12 && x >= 5 && x < 35) || (x >= 17 && x < 24 && y >= 5 && y < 35) {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	return img
+}
+
+func countComponents(img *image.Gray) int {
+	size := img.Bounds().Size()
+	visited := make(map[image.Point]bool)
+	components := 0
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			start := image.Point{X: x, Y: y}
+			if img.GrayAt(x, y).Y == 0 || visited[start] {
+				continue
+			}
+			components++
+			stack := []image.Point{start}
+			visited[start] = true
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for dx := -1; dx <= 1; dx++ {
+					for dy := -1; dy <= 1; dy++ {
+						n := image.Point{X: p.X + dx, Y: p.Y + dy}
+						if n.In(img.Bounds()) && img.GrayAt(n.X, n.Y).Y != 0 && !visited[n] {
+							visited[n] = true
+							stack = append(stack, n)
+						}
+					}
+				}
+			}
+		}
+	}
+	return components
+}
+
+func Test_ZhangSuenThinGray(t *testing.T) {
+	img := setupTestCaseLetter()
+	res := ZhangSuenThinGray(img)
+	size := res.Bounds().Size()
+	count := 0
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			v := res.GrayAt(x, y).Y
+			if v != 0 && v != 0xFF {
+				t.Fatalf("Expected a binary image - actual gray: %d at: %d %d", v, x, y)
+			}
+			if v != 0 {
+				count++
+				if img.GrayAt(x, y).Y == 0 {
+					t.Errorf("Skeleton pixel outside of the shape at: %d %d", x, y)
+				}
+			}
+			// a one pixel wide skeleton contains no 2x2 foreground block
+			if x < size.X-1 && y < size.Y-1 && v != 0 && res.GrayAt(x+1, y).Y != 0 &&
+				res.GrayAt(x, y+1).Y != 0 && res.GrayAt(x+1, y+1).Y != 0 {
+				t.Errorf("Skeleton is thicker then one pixel at: %d %d", x, y)
+			}
+		}
+	}
+	// the skeleton should still span most of the bar and the stem
+	if count < 40 {
+		t.Errorf("Expected a skeleton with at least 40 pixels - actual: %d", count)
+	}
+	if components := countComponents(res); components != 1 {
+		t.Errorf("Expected a connected skeleton - actual components: %d", components)
+	}
+}
+
+func Test_ZhangSuenThinGray_Line(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 3))
+	for x := 1; x < 9; x++ {
+		img.SetGray(x, 1, color.Gray{Y: 0x80})
+	}
+	res := ZhangSuenThinGray(img)
+	for x := 2; x < 8; x++ {
+		if res.GrayAt(x, 1).Y != 0xFF {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", 0xFF, res.GrayAt(x, 1).Y, x, 1)
+		}
+	}
+}
+
+func Test_ThinningGray_Bar(t *testing.T) {
+	// a 5 pixels wide and 30 pixels long horizontal bar
+	img := image.NewGray(image.Rect(0, 0, 40, 11))
+	for x := 5; x < 35; x++ {
+		for y := 3; y < 8; y++ {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	}
+	for _, method := range []ThinningMethod{ThinningZhangSuen, ThinningGuoHall} {
+		res, err := ThinningGray(img, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		minX, maxX, count := 40, -1, 0
+		for x := 0; x < 40; x++ {
+			column := 0
+			for y := 0; y < 11; y++ {
+				if res.GrayAt(x, y).Y != 0 {
+					column++
+					count++
+					if x < minX {
+						minX = x
+					}
+					if x > maxX {
+						maxX = x
+					}
+				}
+			}
+			if column > 1 {
+				t.Errorf("Expected a one pixel wide line - actual: %d pixels in column %d with method %d", column, x, method)
+			}
+		}
+		// the skeleton ends half the width of the bar before the ends of the bar, like the medial axis
+		if length := maxX - minX + 1; length < 25 || length > 27 || count != length {
+			t.Errorf("Expected a continuous line of 26 +- 1 pixels - actual: %d pixels from %d to %d with method %d", count, minX, maxX, method)
+		}
+	}
+}
+
+func Test_ThinningGray_Plus(t *testing.T) {
+	// a plus sign with 5 pixels wide arms
+	img := image.NewGray(image.Rect(0, 0, 41, 41))
+	for x := 0; x < 41; x++ {
+		for y := 0; y < 41; y++ {
+			if (x >= 18 && x < 23 && y >= 4 && y < 37) || (y >= 18 && y < 23 && x >= 4 && x < 37) {
+				img.SetGray(x, y, color.Gray{Y: 0x80})
+			}
+		}
+	}
+	for _, method := range []ThinningMethod{ThinningZhangSuen, ThinningGuoHall} {
+		res, err := ThinningGray(img, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if components := countComponents(res); components != 1 {
+			t.Errorf("Expected a connected skeleton - actual components: %d with method %d", components, method)
+		}
+		if endpoints := countEndpoints(res); endpoints != 4 {
+			t.Errorf("Expected 4 endpoints - actual: %d with method %d", endpoints, method)
+		}
+	}
+}
+
+func Test_ThinningGray_UnknownMethod(t *testing.T) {
+	if _, err := ThinningGray(image.NewGray(image.Rect(0, 0, 3, 3)), ThinningMethod(5)); err == nil {
+		t.Errorf("Expected error for an unknown method")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// countEndpoints counts the foreground pixels with exactly one foreground 8-neighbour.
+func countEndpoints(img *image.Gray) int {
+	size := img.Bounds().Size()
+	endpoints := 0
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			if img.GrayAt(x, y).Y == 0 {
+				continue
+			}
+			neighbours := 0
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					n := image.Point{X: x + dx, Y: y + dy}
+					if (dx != 0 || dy != 0) && n.In(img.Bounds()) && img.GrayAt(n.X, n.Y).Y != 0 {
+						neighbours++
+					}
+				}
+			}
+			if neighbours == 1 {
+				endpoints++
+			}
+		}
+	}
+	return endpoints
+}
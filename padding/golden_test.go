@@ -0,0 +1,26 @@
+package padding
+
+import (
+	"github.com/yafeiliu/imger/internal/goldentest"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Golden tests------------------------------------
+func Test_Golden(t *testing.T) {
+	registry := goldentest.NewRegistry("../res/golden/padding")
+	registry.Register("reflect_7x5", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		return PaddingGray(img, image.Point{X: 7, Y: 5}, image.Point{X: 3, Y: 2}, BorderReflect)
+	})
+	registry.Register("replicate_to_80x60", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		res, _, err := PadToSizeGray(img, 80, 60, BorderReplicate)
+		return res, err
+	})
+	registry.Register("white_border", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		return AddBorderGray(img, 4, 2, 6, 1, BorderConstant, color.Gray{Y: 255})
+	})
+	registry.Run(t)
+}
+
+// -------------------------------------------------------------------------------
@@ -2,6 +2,7 @@ package padding
 
 import (
 	"errors"
+	"github.com/yafeiliu/imger/utils"
 	"image"
 	"image/color"
 )
@@ -31,9 +32,10 @@ type Paddings struct {
 }
 
 func topPaddingReplicate(img image.Image, p Paddings, setPixel func(int, int, color.Color)) {
-	originalSize := img.Bounds().Size()
+	bounds := img.Bounds()
+	originalSize := bounds.Size()
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
-		firstPixel := img.At(x-p.PaddingLeft, p.PaddingTop)
+		firstPixel := img.At(bounds.Min.X+x-p.PaddingLeft, bounds.Min.Y)
 		for y := 0; y < p.PaddingTop; y++ {
 			setPixel(x, y, firstPixel)
 		}
@@ -41,9 +43,10 @@ func topPaddingReplicate(img image.Image, p Paddings, setPixel func(int, int, co
 }
 
 func bottomPaddingReplicate(img image.Image, p Paddings, setPixel func(int, int, color.Color)) {
-	originalSize := img.Bounds().Size()
+	bounds := img.Bounds()
+	originalSize := bounds.Size()
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
-		lastPixel := img.At(x-p.PaddingLeft, originalSize.Y-1)
+		lastPixel := img.At(bounds.Min.X+x-p.PaddingLeft, bounds.Max.Y-1)
 		for y := p.PaddingTop + originalSize.Y; y < originalSize.Y+p.PaddingTop+p.PaddingBottom; y++ {
 			setPixel(x, y, lastPixel)
 		}
@@ -71,20 +74,22 @@ func rightPaddingReplicate(img image.Image, padded image.Image, p Paddings, setP
 }
 
 func topPaddingReflect(img image.Image, p Paddings, setPixel func(int, int, color.Color)) {
-	originalSize := img.Bounds().Size()
+	bounds := img.Bounds()
+	originalSize := bounds.Size()
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
 		for y := 0; y < p.PaddingTop; y++ {
-			pixel := img.At(x-p.PaddingLeft, p.PaddingTop-y)
+			pixel := img.At(bounds.Min.X+x-p.PaddingLeft, bounds.Min.Y+p.PaddingTop-y)
 			setPixel(x, y, pixel)
 		}
 	}
 }
 
 func bottomPaddingReflect(img image.Image, p Paddings, setPixel func(int, int, color.Color)) {
-	originalSize := img.Bounds().Size()
+	bounds := img.Bounds()
+	originalSize := bounds.Size()
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
 		for y := p.PaddingTop + originalSize.Y; y < originalSize.Y+p.PaddingTop+p.PaddingBottom; y++ {
-			pixel := img.At(x-p.PaddingLeft, originalSize.Y-(y-p.PaddingTop-originalSize.Y)-2)
+			pixel := img.At(bounds.Min.X+x-p.PaddingLeft, bounds.Min.Y+originalSize.Y-(y-p.PaddingTop-originalSize.Y)-2)
 			setPixel(x, y, pixel)
 		}
 	}
@@ -117,19 +122,31 @@ func rightPaddingReflect(img image.Image, padded image.Image, p Paddings, setPix
 //	res, err := padding.PaddingGray(img, {5, 5}, {1, 1}, BorderReflect)
 //
 // Note: this will add a 1px padding for the top and left borders of the image and a 3px padding fot the bottom and
-// right borders of the image.
-func PaddingGray(img *image.Gray, kernelSize image.Point, anchor image.Point, border Border) (*image.Gray, error) {
+// right borders of the image. With utils.WithPool the padded image is taken from the pool, the caller can give it back
+// with PutGray once it is not needed anymore.
+func PaddingGray(img *image.Gray, kernelSize image.Point, anchor image.Point, border Border, opts ...utils.Option) (*image.Gray, error) {
+	padded, _, err := PaddingGrayWithInfo(img, kernelSize, anchor, border, opts...)
+	return padded, err
+}
+
+// PaddingGrayWithInfo works like PaddingGray, but it also returns the size of the padding applied on every side of
+// the image, so the original area can be cropped back later.
+// Example of usage:
+//
+//	res, p, err := padding.PaddingGrayWithInfo(img, image.Point{X: 5, Y: 5}, image.Point{X: 1, Y: 1}, padding.BorderReflect)
+func PaddingGrayWithInfo(img *image.Gray, kernelSize image.Point, anchor image.Point, border Border, opts ...utils.Option) (*image.Gray, Paddings, error) {
+	origin := img.Bounds().Min
 	originalSize := img.Bounds().Size()
 	p, error := calculatePaddings(kernelSize, anchor)
 	if error != nil {
-		return nil, error
+		return nil, Paddings{}, error
 	}
 	rect := getRectangleFromPaddings(p, originalSize)
-	padded := image.NewGray(rect)
+	padded := utils.ApplyOptions(opts).Pool.NewGray(rect)
 
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
 		for y := p.PaddingTop; y < originalSize.Y+p.PaddingTop; y++ {
-			padded.Set(x, y, img.GrayAt(x-p.PaddingLeft, y-p.PaddingTop))
+			padded.Set(x, y, img.GrayAt(origin.X+x-p.PaddingLeft, origin.Y+y-p.PaddingTop))
 		}
 	}
 
@@ -163,8 +180,50 @@ func PaddingGray(img *image.Gray, kernelSize image.Point, anchor image.Point, bo
 			padded.Set(x, y, pixel)
 		})
 	default:
-		return nil, errors.New("unknown border type")
+		return nil, Paddings{}, errors.New("unknown border type")
+	}
+	return padded, p, nil
+}
+
+// PaddingGrayPerAxis appends padding to a grayscale image like PaddingGray, but with a different border type for each
+// axis: the horizontal border type fills the left and the right padding, the vertical one the top and the bottom
+// padding. The corners are filled by applying both rules, the pixel at (x, y) is taken from the column given by the
+// horizontal rule and the row given by the vertical rule, so the corners continue both adjacent edges. A corner is
+// black if one of the types is BorderConstant. Returns an error if a border type is unknown or the anchor is outside of
+// the kernel.
+// Example of usage:
+//
+//	res, err := padding.PaddingGrayPerAxis(img, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, padding.BorderReflect, padding.BorderReplicate)
+func PaddingGrayPerAxis(img *image.Gray, kernelSize, anchor image.Point, horizontal, vertical Border) (*image.Gray, error) {
+	for _, border := range []Border{horizontal, vertical} {
+		if border != BorderConstant && border != BorderReplicate && border != BorderReflect {
+			return nil, errors.New("unknown border type")
+		}
+	}
+	p, err := calculatePaddings(kernelSize, anchor)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	padded := image.NewGray(getRectangleFromPaddings(p, size))
+	paddedSize := padded.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return padded, nil
 	}
+	utils.ParallelForEachRow(paddedSize, func(y int) {
+		sy, ok := BorderIndex(y-p.PaddingTop, size.Y, vertical)
+		if !ok {
+			return
+		}
+		src := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+sy):]
+		row := padded.Pix[y*padded.Stride : y*padded.Stride+paddedSize.X]
+		for x := range row {
+			if sx, ok := BorderIndex(x-p.PaddingLeft, size.X, horizontal); ok {
+				row[x] = src[sx]
+			}
+		}
+	})
 	return padded, nil
 }
 
@@ -175,19 +234,21 @@ func PaddingGray(img *image.Gray, kernelSize image.Point, anchor image.Point, bo
 //	res, err := padding.PaddingRGBA(img, {5, 5}, {1, 1}, BorderReflect)
 //
 // Note: this will add a 1px padding for the top and left borders of the image and a 3px padding fot the bottom and
-// right borders of the image.
-func PaddingRGBA(img *image.RGBA, kernelSize image.Point, anchor image.Point, border Border) (*image.RGBA, error) {
+// right borders of the image. With utils.WithPool the padded image is taken from the pool, the caller can give it back
+// with PutRGBA once it is not needed anymore.
+func PaddingRGBA(img *image.RGBA, kernelSize image.Point, anchor image.Point, border Border, opts ...utils.Option) (*image.RGBA, error) {
+	origin := img.Bounds().Min
 	originalSize := img.Bounds().Size()
 	p, error := calculatePaddings(kernelSize, anchor)
 	if error != nil {
 		return nil, error
 	}
 	rect := getRectangleFromPaddings(p, originalSize)
-	padded := image.NewRGBA(rect)
+	padded := utils.ApplyOptions(opts).Pool.NewRGBA(rect)
 
 	for x := p.PaddingLeft; x < originalSize.X+p.PaddingLeft; x++ {
 		for y := p.PaddingTop; y < originalSize.Y+p.PaddingTop; y++ {
-			padded.Set(x, y, img.RGBAAt(x-p.PaddingLeft, y-p.PaddingTop))
+			padded.Set(x, y, img.RGBAAt(origin.X+x-p.PaddingLeft, origin.Y+y-p.PaddingTop))
 		}
 	}
 
@@ -226,6 +287,95 @@ func PaddingRGBA(img *image.RGBA, kernelSize image.Point, anchor image.Point, bo
 	return padded, nil
 }
 
+// PadToSizeGray pads a grayscale image up to the given target size and centers the content, e.g. to batch images of
+// different sizes into a fixed size tensor. When the padding can not be split evenly the extra pixel goes to the right
+// and to the bottom. Returns the padded image and the applied paddings, which can be used to crop the original area
+// later. The border is built with BorderIndex, so BorderReflect also works for paddings which are wider then the image,
+// the content is then reflected repeatedly. Returns an error if the target size is smaller then the size of the image
+// or the border type is unknown.
+// Example of usage:
+//
+//	res, p, err := padding.PadToSizeGray(img, 224, 224, padding.BorderConstant)
+func PadToSizeGray(img *image.Gray, targetW, targetH int, border Border) (*image.Gray, Paddings, error) {
+	size := img.Bounds().Size()
+	if targetW < size.X || targetH < size.Y {
+		return nil, Paddings{}, errors.New("the target size is smaller then the size of the image")
+	}
+	p := Paddings{PaddingLeft: (targetW - size.X) / 2, PaddingTop: (targetH - size.Y) / 2}
+	p.PaddingRight = targetW - size.X - p.PaddingLeft
+	p.PaddingBottom = targetH - size.Y - p.PaddingTop
+	kernelSize := image.Point{X: p.PaddingLeft + p.PaddingRight + 1, Y: p.PaddingTop + p.PaddingBottom + 1}
+	padded, err := PaddingGrayPerAxis(img, kernelSize, image.Point{X: p.PaddingLeft, Y: p.PaddingTop}, border, border)
+	if err != nil {
+		return nil, Paddings{}, err
+	}
+	return padded, p, nil
+}
+
+// AddBorderGray adds a border of the given width on every side of a grayscale image, e.g. to frame the image for
+// display. The border is filled according to the border type, BorderConstant fills it with the color c (the color is
+// ignored by the other types). Returns an error if a border width is negative, or if BorderReflect is used with a
+// border which is not narrower then the image.
+// Example of usage:
+//
+//	res, err := padding.AddBorderGray(img, 5, 5, 10, 10, padding.BorderConstant, color.Gray{Y: 255})
+func AddBorderGray(img *image.Gray, top, bottom, left, right int, border Border, c color.Gray) (*image.Gray, error) {
+	if top < 0 || bottom < 0 || left < 0 || right < 0 {
+		return nil, errors.New("negative border size")
+	}
+	size := img.Bounds().Size()
+	if border == BorderReflect && (top >= size.Y || bottom >= size.Y || left >= size.X || right >= size.X) {
+		return nil, errors.New("the border is too wide to be reflected")
+	}
+	kernelSize := image.Point{X: left + right + 1, Y: top + bottom + 1}
+	padded, err := PaddingGray(img, kernelSize, image.Point{X: left, Y: top}, border)
+	if err != nil {
+		return nil, err
+	}
+	if border == BorderConstant && c.Y != 0 {
+		inner := image.Rect(left, top, left+size.X, top+size.Y)
+		paddedSize := padded.Bounds().Size()
+		for y := 0; y < paddedSize.Y; y++ {
+			for x := 0; x < paddedSize.X; x++ {
+				if !(image.Point{X: x, Y: y}).In(inner) {
+					padded.SetGray(x, y, c)
+				}
+			}
+		}
+	}
+	return padded, nil
+}
+
+// BorderIndex maps an index which can be outside of [0, n) to an index of an axis of length n according to the border
+// type, e.g. -2 is mapped to 2 with BorderReflect. Returns false if the position is a constant (black) pixel of
+// BorderConstant.
+// Example of usage:
+//
+//	sx, ok := padding.BorderIndex(x-2, size.X, padding.BorderReflect)
+func BorderIndex(i int, n int, border Border) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+	switch border {
+	case BorderReplicate:
+		return utils.ClampInt(i, 0, n-1), true
+	case BorderReflect:
+		if n == 1 {
+			return 0, true
+		}
+		for i < 0 || i >= n {
+			if i < 0 {
+				i = -i
+			}
+			if i >= n {
+				i = 2*n - 2 - i
+			}
+		}
+		return i, true
+	}
+	return 0, false
+}
+
 // -------------------------------------------------------------------------------------------------------
 func calculatePaddings(kernelSize image.Point, anchor image.Point) (Paddings, error) {
 	var p Paddings
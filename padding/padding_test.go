@@ -4,10 +4,36 @@ import (
 	"github.com/yafeiliu/imger/imgio"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"testing"
 )
 
 // ---------------------------------Unit tests--------------------------------------
+func Test_BorderIndex(t *testing.T) {
+	cases := []struct {
+		i        int
+		border   Border
+		expected int
+		ok       bool
+	}{
+		{2, BorderConstant, 2, true},
+		{-1, BorderConstant, 0, false},
+		{5, BorderConstant, 0, false},
+		{-3, BorderReplicate, 0, true},
+		{7, BorderReplicate, 4, true},
+		{-1, BorderReflect, 1, true},
+		{-2, BorderReflect, 2, true},
+		{5, BorderReflect, 3, true},
+		{11, BorderReflect, 3, true},
+	}
+	for _, c := range cases {
+		actual, ok := BorderIndex(c.i, 5, c.border)
+		if actual != c.expected || ok != c.ok {
+			t.Errorf("Expected: %d, %t - actual: %d, %t for index %d and border %d", c.expected, c.ok, actual, ok, c.i, c.border)
+		}
+	}
+}
+
 func Test_GrayPaddingBorderConstant_1pxPadding(t *testing.T) {
 	gray := image.Gray{
 		Rect:   image.Rect(0, 0, 5, 3),
@@ -182,6 +208,68 @@ func Test_GrayPaddingBorderReplicate_1_3pxPadding(t *testing.T) {
 	utils.CompareGrayImages(t, &expected, actual)
 }
 
+func Test_GrayPaddingBorderReplicate_DistinctRows(t *testing.T) {
+	gray := image.Gray{
+		Rect:   image.Rect(0, 0, 3, 3),
+		Stride: 3,
+		Pix: []uint8{
+			0x11, 0x22, 0x33,
+			0x44, 0x55, 0x66,
+			0x77, 0x88, 0x99,
+		},
+	}
+	expected := image.Gray{
+		Rect:   image.Rect(0, 0, 5, 7),
+		Stride: 5,
+		Pix: []uint8{
+			0x11, 0x11, 0x22, 0x33, 0x33,
+			0x11, 0x11, 0x22, 0x33, 0x33,
+			0x11, 0x11, 0x22, 0x33, 0x33,
+			0x44, 0x44, 0x55, 0x66, 0x66,
+			0x77, 0x77, 0x88, 0x99, 0x99,
+			0x77, 0x77, 0x88, 0x99, 0x99,
+			0x77, 0x77, 0x88, 0x99, 0x99,
+		},
+	}
+	actual, err := PaddingGray(&gray, image.Point{X: 3, Y: 5}, image.Point{X: 1, Y: 2}, BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, &expected, actual)
+}
+
+func Test_PaddingSubImage(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	rgba := image.NewRGBA(image.Rect(0, 0, 9, 8))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i*7 + 3)
+	}
+	for i := range rgba.Pix {
+		rgba.Pix[i] = uint8(i*5 + 1)
+	}
+	rect := image.Rect(2, 3, 7, 7)
+	for _, border := range []Border{BorderConstant, BorderReplicate, BorderReflect} {
+		expectedGray, err := PaddingGray(utils.CloneGray(gray.SubImage(rect).(*image.Gray)), image.Point{X: 5, Y: 3}, image.Point{X: 1, Y: 2}, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actualGray, err := PaddingGray(gray.SubImage(rect).(*image.Gray), image.Point{X: 5, Y: 3}, image.Point{X: 1, Y: 2}, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expectedGray, actualGray)
+		expectedRGBA, err := PaddingRGBA(utils.CloneRGBA(rgba.SubImage(rect).(*image.RGBA)), image.Point{X: 5, Y: 3}, image.Point{X: 1, Y: 2}, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actualRGBA, err := PaddingRGBA(rgba.SubImage(rect).(*image.RGBA), image.Point{X: 5, Y: 3}, image.Point{X: 1, Y: 2}, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareRGBAImages(t, expectedRGBA, actualRGBA)
+	}
+}
+
 func Test_GrayPaddingBorderReflect_1_3pxPadding(t *testing.T) {
 	gray := image.Gray{
 		Rect:   image.Rect(0, 0, 5, 3),
@@ -243,8 +331,232 @@ func Test_GrayPaddingBorderReflect_2pxPadding(t *testing.T) {
 	utils.CompareGrayImages(t, &expected, actual)
 }
 
+func Test_PaddingGrayWithInfo(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 4, 3))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i + 1)
+	}
+	kernelSize := image.Point{X: 5, Y: 4}
+	anchor := image.Point{X: 1, Y: 3}
+	actual, p, err := PaddingGrayWithInfo(gray, kernelSize, anchor, BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := (Paddings{PaddingLeft: 1, PaddingRight: 3, PaddingTop: 3, PaddingBottom: 0}); p != expected {
+		t.Fatalf("Expected paddings: %+v - actual paddings: %+v", expected, p)
+	}
+	if actual.Bounds() != image.Rect(0, 0, 8, 6) {
+		t.Fatalf("Expected bounds: [0 0 8 6] - actual bounds: %v", actual.Bounds())
+	}
+	// cropping with the paddings gives back the original image
+	cropped := actual.SubImage(image.Rect(p.PaddingLeft, p.PaddingTop, 8-p.PaddingRight, 6-p.PaddingBottom)).(*image.Gray)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			if cropped.GrayAt(x+p.PaddingLeft, y+p.PaddingTop) != gray.GrayAt(x, y) {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", gray.GrayAt(x, y).Y, cropped.GrayAt(x+p.PaddingLeft, y+p.PaddingTop).Y, x, y)
+			}
+		}
+	}
+	plain, err := PaddingGray(gray, kernelSize, anchor, BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, plain, actual)
+	if _, _, err := PaddingGrayWithInfo(gray, kernelSize, image.Point{X: -1, Y: 0}, BorderConstant); err == nil {
+		t.Error("Expected error for a negative anchor")
+	}
+}
+
+func Test_PadToSizeGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i + 1)
+	}
+	actual, p, err := PadToSizeGray(img, 20, 16, BorderConstant)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := (Paddings{PaddingLeft: 5, PaddingRight: 5, PaddingTop: 3, PaddingBottom: 3}); p != expected {
+		t.Fatalf("Expected paddings: %+v - actual paddings: %+v", expected, p)
+	}
+	if actual.Bounds() != image.Rect(0, 0, 20, 16) {
+		t.Fatalf("Expected bounds: [0 0 20 16] - actual bounds: %v", actual.Bounds())
+	}
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 16; y++ {
+			var expected uint8
+			if x >= 5 && x < 15 && y >= 3 && y < 13 {
+				expected = img.GrayAt(x-5, y-3).Y
+			}
+			if actual.GrayAt(x, y).Y != expected {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual.GrayAt(x, y).Y, x, y)
+			}
+		}
+	}
+}
+
+func Test_PadToSizeGray_Odd(t *testing.T) {
+	gray := image.Gray{
+		Rect:   image.Rect(0, 0, 2, 2),
+		Stride: 2,
+		Pix: []uint8{
+			0xAA, 0xBB,
+			0xCC, 0xDD,
+		},
+	}
+	expected := image.Gray{
+		Rect:   image.Rect(0, 0, 5, 4),
+		Stride: 5,
+		Pix: []uint8{
+			0xAA, 0xAA, 0xBB, 0xBB, 0xBB,
+			0xAA, 0xAA, 0xBB, 0xBB, 0xBB,
+			0xCC, 0xCC, 0xDD, 0xDD, 0xDD,
+			0xCC, 0xCC, 0xDD, 0xDD, 0xDD,
+		},
+	}
+	actual, p, err := PadToSizeGray(&gray, 5, 4, BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if p != (Paddings{PaddingLeft: 1, PaddingRight: 2, PaddingTop: 1, PaddingBottom: 1}) {
+		t.Errorf("Unexpected paddings: %+v", p)
+	}
+	utils.CompareGrayImages(t, &expected, actual)
+	if _, _, err := PadToSizeGray(&gray, 1, 4, BorderConstant); err == nil {
+		t.Error("Expected error for a target smaller then the image")
+	}
+}
+
+func Test_PadToSizeGray_WideReflect(t *testing.T) {
+	gray := image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x11, 0x22, 0x33,
+			0x44, 0x55, 0x66,
+		},
+	}
+	expected := image.Gray{
+		Rect:   image.Rect(0, 0, 11, 8),
+		Stride: 11,
+		Pix: []uint8{
+			0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66,
+			0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33,
+			0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66,
+			0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33,
+			0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66,
+			0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33,
+			0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66, 0x55, 0x44, 0x55, 0x66,
+			0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33, 0x22, 0x11, 0x22, 0x33,
+		},
+	}
+	actual, p, err := PadToSizeGray(&gray, 11, 8, BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if p != (Paddings{PaddingLeft: 4, PaddingRight: 4, PaddingTop: 3, PaddingBottom: 3}) {
+		t.Errorf("Unexpected paddings: %+v", p)
+	}
+	utils.CompareGrayImages(t, &expected, actual)
+	if _, _, err := PadToSizeGray(&gray, 11, 8, Border(42)); err == nil {
+		t.Error("Expected error for an unknown border type")
+	}
+}
+
+func Test_AddBorderGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 6))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 3)
+	}
+	actual, err := AddBorderGray(img, 5, 5, 5, 5, BorderConstant, color.Gray{Y: 255})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if actual.Bounds() != image.Rect(0, 0, 18, 16) {
+		t.Fatalf("Expected bounds: [0 0 18 16] - actual bounds: %v", actual.Bounds())
+	}
+	for x := 0; x < 18; x++ {
+		for y := 0; y < 16; y++ {
+			expected := uint8(255)
+			if x >= 5 && x < 13 && y >= 5 && y < 11 {
+				expected = img.GrayAt(x-5, y-5).Y
+			}
+			if actual.GrayAt(x, y).Y != expected {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual.GrayAt(x, y).Y, x, y)
+			}
+		}
+	}
+	replicated, err := AddBorderGray(img, 0, 2, 1, 0, BorderReplicate, color.Gray{Y: 255})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if replicated.Bounds() != image.Rect(0, 0, 9, 8) {
+		t.Fatalf("Expected bounds: [0 0 9 8] - actual bounds: %v", replicated.Bounds())
+	}
+	if replicated.GrayAt(0, 7).Y != img.GrayAt(0, 5).Y {
+		t.Errorf("Expected the corner to replicate the image - actual gray: %d", replicated.GrayAt(0, 7).Y)
+	}
+	if _, err := AddBorderGray(img, -1, 0, 0, 0, BorderConstant, color.Gray{}); err == nil {
+		t.Error("Expected error for a negative border")
+	}
+	if _, err := AddBorderGray(img, 6, 0, 0, 0, BorderReflect, color.Gray{}); err == nil {
+		t.Error("Expected error for a border wider then the image with BorderReflect")
+	}
+}
+
 // ---------------------------------------------------------------------------------
 
+func Test_PaddingGrayPerAxis(t *testing.T) {
+	// a gradient with a distinct value for every pixel: 10 * x + y
+	img := image.NewGray(image.Rect(0, 0, 5, 4))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: uint8(10*x + y)})
+	})
+	res, err := PaddingGrayPerAxis(img, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, BorderReflect, BorderReplicate)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 9, 8); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	// the columns of the padded image reflect: 2 1 | 0 1 2 3 4 | 3 2, the rows replicate: 0 0 | 0 1 2 3 | 3 3
+	columns := []int{2, 1, 0, 1, 2, 3, 4, 3, 2}
+	rows := []int{0, 0, 0, 1, 2, 3, 3, 3}
+	for y, sy := range rows {
+		for x, sx := range columns {
+			if expected, actual := uint8(10*sx+sy), res.GrayAt(x, y).Y; expected != actual {
+				t.Errorf("Expected value: %d - actual value: %d at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_PaddingGrayPerAxis_SameBorders(t *testing.T) {
+	img := setupTestCaseGray(t)
+	kernelSize, anchor := image.Point{X: 7, Y: 4}, image.Point{X: 2, Y: 3}
+	for _, border := range []Border{BorderConstant, BorderReplicate, BorderReflect} {
+		expected, err := PaddingGray(img, kernelSize, anchor, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual, err := PaddingGrayPerAxis(img, kernelSize, anchor, border, border)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+	}
+}
+
+func Test_PaddingGrayPerAxis_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	if _, err := PaddingGrayPerAxis(img, image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, BorderReflect, Border(7)); err == nil {
+		t.Error("Expected error for an unknown vertical border type")
+	}
+	if _, err := PaddingGrayPerAxis(img, image.Point{X: 3, Y: 3}, image.Point{X: 5, Y: 1}, BorderReflect, BorderReplicate); err == nil {
+		t.Error("Expected error for an anchor outside of the kernel")
+	}
+}
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/girl.jpg"
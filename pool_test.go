@@ -0,0 +1,113 @@
+package Imger
+
+import (
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_BufferPool_Chain(t *testing.T) {
+	img := setupTestCasePoolGray(97, 61, 3)
+	expected, err := padBlurResize(img, nil)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	pool := utils.NewBufferPool()
+	for i := 0; i < 3; i++ {
+		actual, err := padBlurResize(img, pool)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+	}
+}
+
+// Test_BufferPool_Concurrent shares one pool between many goroutines, run it with -race to check that no buffer is
+// used by two operations at the same time.
+func Test_BufferPool_Concurrent(t *testing.T) {
+	pool := utils.NewBufferPool()
+	inputs := make([]*image.Gray, 4)
+	expected := make([]*image.Gray, len(inputs))
+	for i := range inputs {
+		inputs[i] = setupTestCasePoolGray(40+7*i, 30+5*i, int64(i))
+		res, err := padBlurResize(inputs[i], nil)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		expected[i] = res
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < 16; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				index := (w + i) % len(inputs)
+				res, err := padBlurResize(inputs[index], pool)
+				if err != nil {
+					t.Errorf("Error should not be returned. Error value: %s", err)
+					return
+				}
+				for j := range res.Pix {
+					if res.Pix[j] != expected[index].Pix[j] {
+						t.Errorf("Expected value: %d - actual value: %d at: %d", expected[index].Pix[j], res.Pix[j], j)
+						return
+					}
+				}
+				// a caller writing into its result must not disturb the other goroutines
+				for j := range res.Pix {
+					res.Pix[j] = 0
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func Benchmark_PadBlurResize(b *testing.B) {
+	benchmarkPadBlurResize(b, nil)
+}
+
+func Benchmark_PadBlurResize_Pool(b *testing.B) {
+	benchmarkPadBlurResize(b, utils.NewBufferPool())
+}
+
+// -------------------------------------------------------------------------------
+
+// padBlurResize is a typical service pipeline: the image is framed, blurred and downscaled. The framed image is an
+// intermediate result, so it is given back to the pool.
+func padBlurResize(img *image.Gray, pool *utils.BufferPool) (*image.Gray, error) {
+	padded, err := padding.PaddingGray(img, image.Point{X: 9, Y: 9}, image.Point{X: 4, Y: 4}, padding.BorderReflect, utils.WithPool(pool))
+	if err != nil {
+		return nil, err
+	}
+	defer pool.PutGray(padded)
+	blurred, _, err := blur.GaussianBlurGray(padded, 2, 1.5, padding.BorderReflect, utils.WithPool(pool))
+	if err != nil {
+		return nil, err
+	}
+	return resize.ResizeGray(blurred, 0.5, 0.5, resize.InterLinear, utils.WithPool(pool))
+}
+
+func benchmarkPadBlurResize(b *testing.B, pool *utils.BufferPool) {
+	img := setupTestCasePoolGray(512, 512, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := padBlurResize(img, pool); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func setupTestCasePoolGray(width, height int, seed int64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(seed)).Read(img.Pix)
+	return img
+}
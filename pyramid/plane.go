@@ -0,0 +1,61 @@
+package pyramid
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Plane is a single channel image with floating point pixel values stored in row-major order. It is used to build
+// pyramids without losing precision between the levels.
+type Plane struct {
+	Pix    []float64
+	Width  int
+	Height int
+}
+
+// NewPlane creates a new Plane with the given width and height where every value is 0.
+func NewPlane(width int, height int) *Plane {
+	return &Plane{Pix: make([]float64, width*height), Width: width, Height: height}
+}
+
+// At returns the value at the {x, y} position of the plane.
+func (p *Plane) At(x, y int) float64 {
+	return p.Pix[y*p.Width+x]
+}
+
+// Set sets the value at the {x, y} position of the plane.
+func (p *Plane) Set(x int, y int, value float64) {
+	p.Pix[y*p.Width+x] = value
+}
+
+// Size returns the size of the plane.
+func (p *Plane) Size() image.Point {
+	return image.Point{X: p.Width, Y: p.Height}
+}
+
+// PlaneFromGray creates a Plane from a grayscale image, the values are kept in the [0, 255] interval.
+func PlaneFromGray(img *image.Gray) *Plane {
+	size := img.Bounds().Size()
+	p := NewPlane(size.X, size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		p.Set(x, y, float64(img.GrayAt(x, y).Y))
+	})
+	return p
+}
+
+// ToGray converts the plane to a grayscale image, the values are rounded and clamped to the [0, 255] interval.
+func (p *Plane) ToGray() *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, p.Width, p.Height))
+	utils.ParallelForEachPixel(p.Size(), func(x, y int) {
+		v := utils.ClampF64(math.Round(p.At(x, y)), utils.MinUint8, float64(utils.MaxUint8))
+		res.SetGray(x, y, color.Gray{Y: uint8(v)})
+	})
+	return res
+}
+
+// Clone returns a deep copy of the plane.
+func (p *Plane) Clone() *Plane {
+	return &Plane{Pix: append([]float64(nil), p.Pix...), Width: p.Width, Height: p.Height}
+}
@@ -0,0 +1,164 @@
+package pyramid
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"image"
+)
+
+// kernel is the 5-tap binomial approximation of a Gaussian used for building the pyramids.
+var kernel = [5]float64{1.0 / 16, 4.0 / 16, 6.0 / 16, 4.0 / 16, 1.0 / 16}
+
+// PyrDown blurs a plane with a 5x5 Gaussian kernel and drops every second row and column. The size of the result is
+// ((width + 1) / 2, (height + 1) / 2). The border is handled as in BorderReflect.
+func PyrDown(p *Plane) *Plane {
+	tmp := NewPlane((p.Width+1)/2, p.Height)
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < tmp.Width; x++ {
+			var sum float64
+			for k, w := range kernel {
+				sum += p.At(reflectIndex(2*x+k-2, p.Width), y) * w
+			}
+			tmp.Set(x, y, sum)
+		}
+	}
+	res := NewPlane(tmp.Width, (p.Height+1)/2)
+	for y := 0; y < res.Height; y++ {
+		for x := 0; x < res.Width; x++ {
+			var sum float64
+			for k, w := range kernel {
+				sum += tmp.At(x, reflectIndex(2*y+k-2, p.Height)) * w
+			}
+			res.Set(x, y, sum)
+		}
+	}
+	return res
+}
+
+// PyrUp upsamples a plane to the given size (which should be at most twice the size of the plane) by inserting zero
+// rows and columns and blurring the result with a 5x5 Gaussian kernel multiplied by 4.
+func PyrUp(p *Plane, size image.Point) *Plane {
+	// upsampled(i) is p(i / 2) for even i inside the plane and 0 otherwise
+	sample := func(i int, n int, upN int) (int, bool) {
+		i = reflectIndex(i, upN)
+		if i%2 != 0 || i/2 >= n {
+			return 0, false
+		}
+		return i / 2, true
+	}
+	tmp := NewPlane(size.X, p.Height)
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < size.X; x++ {
+			var sum float64
+			for k, w := range kernel {
+				if sx, ok := sample(x+k-2, p.Width, size.X); ok {
+					sum += 2 * w * p.At(sx, y)
+				}
+			}
+			tmp.Set(x, y, sum)
+		}
+	}
+	res := NewPlane(size.X, size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			var sum float64
+			for k, w := range kernel {
+				if sy, ok := sample(y+k-2, p.Height, size.Y); ok {
+					sum += 2 * w * tmp.At(x, sy)
+				}
+			}
+			res.Set(x, y, sum)
+		}
+	}
+	return res
+}
+
+// GaussianPyramid builds a Gaussian pyramid with the given number of levels. The first level is a copy of the original
+// plane, every following level is computed with PyrDown from the previous one. The number of levels is limited so the
+// smallest level is at least 1x1.
+func GaussianPyramid(p *Plane, levels int) ([]*Plane, error) {
+	if levels < 1 {
+		return nil, errors.New("the number of levels should be at least 1")
+	}
+	res := []*Plane{p.Clone()}
+	for i := 1; i < levels && (res[i-1].Width > 1 || res[i-1].Height > 1); i++ {
+		res = append(res, PyrDown(res[i-1]))
+	}
+	return res, nil
+}
+
+// LaplacianPyramid builds a Laplacian pyramid with the given number of levels. Every level contains the difference
+// between the corresponding level of the Gaussian pyramid and the upsampled next level, the last level is the last
+// level of the Gaussian pyramid. The original plane can be restored using CollapseLaplacianPyramid.
+func LaplacianPyramid(p *Plane, levels int) ([]*Plane, error) {
+	gaussian, err := GaussianPyramid(p, levels)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Plane, len(gaussian))
+	for i := 0; i < len(gaussian)-1; i++ {
+		up := PyrUp(gaussian[i+1], gaussian[i].Size())
+		diff := NewPlane(gaussian[i].Width, gaussian[i].Height)
+		for j := range diff.Pix {
+			diff.Pix[j] = gaussian[i].Pix[j] - up.Pix[j]
+		}
+		res[i] = diff
+	}
+	res[len(res)-1] = gaussian[len(gaussian)-1]
+	return res, nil
+}
+
+// CollapseLaplacianPyramid reconstructs a plane from its Laplacian pyramid. The levels are not modified.
+func CollapseLaplacianPyramid(levels []*Plane) (*Plane, error) {
+	if len(levels) == 0 {
+		return nil, errors.New("empty pyramid")
+	}
+	res := levels[len(levels)-1].Clone()
+	for i := len(levels) - 2; i >= 0; i-- {
+		up := PyrUp(res, levels[i].Size())
+		for j := range up.Pix {
+			up.Pix[j] += levels[i].Pix[j]
+		}
+		res = up
+	}
+	return res, nil
+}
+
+// PyrDownGray blurs a grayscale image with a 5x5 Gaussian kernel and halves its size.
+// Example of usage:
+//
+//	res := pyramid.PyrDownGray(img)
+func PyrDownGray(img *image.Gray) *image.Gray {
+	return PyrDown(PlaneFromGray(img)).ToGray()
+}
+
+// PyrUpGray upsamples a grayscale image to the given size (which should be at most twice the size of the image).
+// Example of usage:
+//
+//	res := pyramid.PyrUpGray(img, image.Point{X: 512, Y: 512})
+func PyrUpGray(img *image.Gray, size image.Point) *image.Gray {
+	return PyrUp(PlaneFromGray(img), size).ToGray()
+}
+
+// GaussianPyramidGray builds a Gaussian pyramid of grayscale images with the given number of levels. The first level
+// is a copy of the original image.
+// Example of usage:
+//
+//	levels, err := pyramid.GaussianPyramidGray(img, 4)
+func GaussianPyramidGray(img *image.Gray, levels int) ([]*image.Gray, error) {
+	planes, err := GaussianPyramid(PlaneFromGray(img), levels)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*image.Gray, len(planes))
+	for i := range planes {
+		res[i] = planes[i].ToGray()
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func reflectIndex(i int, n int) int {
+	i, _ = padding.BorderIndex(i, n, padding.BorderReflect)
+	return i
+}
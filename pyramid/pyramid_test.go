@@ -0,0 +1,92 @@
+package pyramid
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func setupTestCasePlane(width, height int) *Plane {
+	p := NewPlane(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p.Set(x, y, float64((x*31+y*17+x*y)%256))
+		}
+	}
+	return p
+}
+
+func Test_PyrDown_Size(t *testing.T) {
+	res := PyrDown(NewPlane(9, 4))
+	if res.Width != 5 || res.Height != 2 {
+		t.Errorf("Expected size: 5x2 - actual size: %dx%d", res.Width, res.Height)
+	}
+}
+
+func Test_PyrDown_Constant(t *testing.T) {
+	p := NewPlane(8, 8)
+	for i := range p.Pix {
+		p.Pix[i] = 100
+	}
+	down := PyrDown(p)
+	up := PyrUp(down, p.Size())
+	for i := range down.Pix {
+		if !utils.IsEqualFloat64(down.Pix[i], 100) {
+			t.Fatalf("Expected: 100 - actual: %f", down.Pix[i])
+		}
+	}
+	for i := range up.Pix {
+		if !utils.IsEqualFloat64(up.Pix[i], 100) {
+			t.Fatalf("Expected: 100 - actual: %f", up.Pix[i])
+		}
+	}
+}
+
+func Test_LaplacianPyramid_Collapse(t *testing.T) {
+	for _, size := range []image.Point{{X: 32, Y: 32}, {X: 37, Y: 21}} {
+		p := setupTestCasePlane(size.X, size.Y)
+		levels, err := LaplacianPyramid(p, 4)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if len(levels) != 4 {
+			t.Fatalf("Expected 4 levels - actual: %d", len(levels))
+		}
+		res, err := CollapseLaplacianPyramid(levels)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for i := range p.Pix {
+			if math.Abs(p.Pix[i]-res.Pix[i]) > 1e-9 {
+				t.Fatalf("Expected: %f - actual: %f at: %d", p.Pix[i], res.Pix[i], i)
+			}
+		}
+	}
+}
+
+func Test_GaussianPyramidGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 16, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.SetGray(0, 0, color.Gray{Y: 0x80})
+	levels, err := GaussianPyramidGray(img, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(levels) != 5 {
+		t.Fatalf("Expected the pyramid to stop at 1x1 (5 levels) - actual: %d", len(levels))
+	}
+	last := levels[len(levels)-1]
+	if last.Bounds().Size() != (image.Point{X: 1, Y: 1}) || last.GrayAt(0, 0).Y != 0x80 {
+		t.Errorf("Invalid last level: %v %d", last.Bounds(), last.GrayAt(0, 0).Y)
+	}
+	if _, err := GaussianPyramidGray(img, 0); err == nil {
+		t.Error("Expected error for zero levels")
+	}
+}
+
+// -------------------------------------------------------------------------------
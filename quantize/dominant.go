@@ -0,0 +1,70 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// DominantColorsRGBA returns the topN most frequent colors of an RGBA image, sorted by decreasing frequency. Every
+// channel is quantized to the given number of bits (1 - 8, values out of this range are clamped), which bins the colors
+// into a reduced color cube, and the centers of the most populated bins are returned. Bins with the same number of
+// pixels are ordered by their position in the cube. Fewer then topN colors are returned if the image occupies fewer
+// bins. The alpha channel is ignored and the returned colors are opaque.
+// Example of usage:
+//
+//	palette := quantize.DominantColorsRGBA(img, 4, 5)
+func DominantColorsRGBA(img *image.RGBA, bits uint, topN int) []color.RGBA {
+	if bits < 1 {
+		bits = 1
+	}
+	if bits > 8 {
+		bits = 8
+	}
+	if topN <= 0 {
+		return nil
+	}
+	shift := 8 - bits
+	counts := make([]int, 1<<(3*bits))
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+4*size.X]
+		for i := 0; i < len(row); i += 4 {
+			counts[binIndex(row[i]>>shift, row[i+1]>>shift, row[i+2]>>shift, bits)]++
+		}
+	}
+
+	var bins []int
+	for bin, count := range counts {
+		if count > 0 {
+			bins = append(bins, bin)
+		}
+	}
+	sort.SliceStable(bins, func(i, j int) bool {
+		return counts[bins[i]] > counts[bins[j]]
+	})
+	if len(bins) > topN {
+		bins = bins[:topN]
+	}
+
+	mask := 1<<bits - 1
+	half := (1 << shift) / 2
+	res := make([]color.RGBA, len(bins))
+	for i, bin := range bins {
+		r := bin >> (2 * bits) & mask
+		g := bin >> bits & mask
+		b := bin & mask
+		res[i] = color.RGBA{
+			R: uint8(r<<shift + half),
+			G: uint8(g<<shift + half),
+			B: uint8(b<<shift + half),
+			A: 0xFF,
+		}
+	}
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------------
+func binIndex(r, g, b uint8, bits uint) int {
+	return int(r)<<(2*bits) | int(g)<<bits | int(b)
+}
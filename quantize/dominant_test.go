@@ -0,0 +1,61 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_DominantColorsRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	red := color.RGBA{R: 0xF0, G: 0x10, B: 0x10, A: 0xFF}
+	green := color.RGBA{R: 0x10, G: 0xF0, B: 0x10, A: 0xFF}
+	blue := color.RGBA{R: 0x10, G: 0x10, B: 0xF0, A: 0xFF}
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			switch {
+			case y < 10:
+				img.SetRGBA(x, y, red)
+			case y < 16:
+				img.SetRGBA(x, y, green)
+			case y < 19:
+				img.SetRGBA(x, y, blue)
+			default:
+				// a few sparse noise colors
+				img.SetRGBA(x, y, color.RGBA{R: uint8(x * 12), G: 0x80, B: uint8(x * 5), A: 0xFF})
+			}
+		}
+	}
+	colors := DominantColorsRGBA(img, 4, 3)
+	expected := []color.RGBA{
+		{R: 0xF8, G: 0x18, B: 0x18, A: 0xFF},
+		{R: 0x18, G: 0xF8, B: 0x18, A: 0xFF},
+		{R: 0x18, G: 0x18, B: 0xF8, A: 0xFF},
+	}
+	if len(colors) != len(expected) {
+		t.Fatalf("Expected %d colors - actual: %d", len(expected), len(colors))
+	}
+	for i := range expected {
+		if colors[i] != expected[i] {
+			t.Errorf("Expected color: %v - actual color: %v at: %d", expected[i], colors[i], i)
+		}
+	}
+}
+
+func Test_DominantColorsRGBA_FullPrecision(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	c := color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xFF}
+	img.SetRGBA(0, 0, c)
+	img.SetRGBA(1, 0, c)
+	img.SetRGBA(2, 0, color.RGBA{A: 0xFF})
+	colors := DominantColorsRGBA(img, 8, 10)
+	if len(colors) != 2 || colors[0] != c || colors[1] != (color.RGBA{A: 0xFF}) {
+		t.Errorf("Expected [%v %v] - actual: %v", c, color.RGBA{A: 0xFF}, colors)
+	}
+	if colors := DominantColorsRGBA(img, 8, 0); len(colors) != 0 {
+		t.Errorf("Expected no colors - actual: %v", colors)
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,195 @@
+package quantize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// KMeansOptions configures DominantColorsKMeansRGBA.
+type KMeansOptions struct {
+	// Lab clusters the colors in the CIE L*a*b* color space instead of RGB, which matches the perceived color
+	// differences better.
+	Lab bool
+	// Seed initializes the random generator used for the subsampling and the initial centers, the same seed always
+	// gives the same result.
+	Seed int64
+	// MaxSamples is the maximum number of pixels used for the clustering, 0 means every pixel.
+	MaxSamples int
+	// MaxIterations is the maximum number of k-means iterations, 0 means DefaultKMeansIterations.
+	MaxIterations int
+	// MinAlpha excludes the pixels with an alpha value smaller then MinAlpha.
+	MinAlpha uint8
+}
+
+// DefaultKMeansIterations is the number of k-means iterations used when KMeansOptions.MaxIterations is 0.
+const DefaultKMeansIterations = 20
+
+// DefaultKMeansOptions returns the options used for the dominant colors of typical photos: RGB clustering over at
+// most 10000 pixels and half transparent or more transparent pixels are excluded.
+func DefaultKMeansOptions() KMeansOptions {
+	return KMeansOptions{Seed: 1, MaxSamples: 10000, MaxIterations: DefaultKMeansIterations, MinAlpha: 128}
+}
+
+// DominantColorsKMeansRGBA computes the k dominant colors of an RGBA image by k-means clustering (with k-means++
+// initialization) of a random subsample of its pixels. Returns the cluster centers sorted by decreasing weight and the
+// fraction of the clustered pixels which belongs to every center. Fewer then k colors are returned if the image has
+// fewer distinct colors. The returned colors are opaque. Returns an error if k is smaller then 1 or every pixel is
+// excluded by its alpha value.
+// Example of usage:
+//
+//	colors, weights, err := quantize.DominantColorsKMeansRGBA(img, 5, quantize.DefaultKMeansOptions())
+func DominantColorsKMeansRGBA(img *image.RGBA, k int, options KMeansOptions) ([]color.RGBA, []float64, error) {
+	if k < 1 {
+		return nil, nil, errors.New("k should be bigger then 0")
+	}
+	rng := rand.New(rand.NewSource(options.Seed))
+	points := samplePoints(img, options, rng)
+	if len(points) == 0 {
+		return nil, nil, errors.New("the image has no pixels to cluster")
+	}
+	iterations := options.MaxIterations
+	if iterations <= 0 {
+		iterations = DefaultKMeansIterations
+	}
+
+	centers := initCenters(points, k, rng)
+	labels := make([]int, len(points))
+	for it := 0; it < iterations; it++ {
+		changed := false
+		for i, p := range points {
+			if best := nearestCenter(p, centers); best != labels[i] {
+				labels[i] = best
+				changed = true
+			}
+		}
+		if it > 0 && !changed {
+			break
+		}
+		sums := make([][3]float64, len(centers))
+		counts := make([]int, len(centers))
+		for i, p := range points {
+			for c := 0; c < 3; c++ {
+				sums[labels[i]][c] += p[c]
+			}
+			counts[labels[i]]++
+		}
+		for i := range centers {
+			if counts[i] > 0 {
+				centers[i] = [3]float64{sums[i][0] / float64(counts[i]), sums[i][1] / float64(counts[i]), sums[i][2] / float64(counts[i])}
+			}
+		}
+	}
+
+	counts := make([]int, len(centers))
+	for _, l := range labels {
+		counts[l]++
+	}
+	order := make([]int, 0, len(centers))
+	for i := range centers {
+		if counts[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	colors := make([]color.RGBA, len(order))
+	weights := make([]float64, len(order))
+	for i, c := range order {
+		colors[i] = pointToRGBA(centers[c], options.Lab)
+		weights[i] = float64(counts[c]) / float64(len(points))
+	}
+	return colors, weights, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// samplePoints returns the colors of the pixels which are not excluded by their alpha value, converted to the
+// clustering color space. At most options.MaxSamples randomly chosen pixels are returned.
+func samplePoints(img *image.RGBA, options KMeansOptions, rng *rand.Rand) [][3]float64 {
+	size := img.Bounds().Size()
+	var offsets []int
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if o := y*img.Stride + 4*x; img.Pix[o+3] >= options.MinAlpha {
+				offsets = append(offsets, o)
+			}
+		}
+	}
+	if options.MaxSamples > 0 && len(offsets) > options.MaxSamples {
+		rng.Shuffle(len(offsets), func(i, j int) {
+			offsets[i], offsets[j] = offsets[j], offsets[i]
+		})
+		offsets = offsets[:options.MaxSamples]
+	}
+	points := make([][3]float64, len(offsets))
+	for i, o := range offsets {
+		r, g, b := img.Pix[o], img.Pix[o+1], img.Pix[o+2]
+		if options.Lab {
+			l, a, bb := utils.RGBToLab(r, g, b)
+			points[i] = [3]float64{l, a, bb}
+		} else {
+			points[i] = [3]float64{float64(r), float64(g), float64(b)}
+		}
+	}
+	return points
+}
+
+// initCenters chooses at most k initial centers with the k-means++ method, fewer centers are returned when every
+// point coincides with one of the chosen centers.
+func initCenters(points [][3]float64, k int, rng *rand.Rand) [][3]float64 {
+	centers := [][3]float64{points[rng.Intn(len(points))]}
+	distances := make([]float64, len(points))
+	for len(centers) < k {
+		total := 0.0
+		for i, p := range points {
+			distances[i] = math.Inf(1)
+			for _, c := range centers {
+				distances[i] = math.Min(distances[i], squaredDistance(p, c))
+			}
+			total += distances[i]
+		}
+		if total == 0 {
+			break
+		}
+		target := rng.Float64() * total
+		chosen := len(points) - 1
+		for i, d := range distances {
+			if target -= d; target < 0 {
+				chosen = i
+				break
+			}
+		}
+		centers = append(centers, points[chosen])
+	}
+	return centers
+}
+
+func nearestCenter(p [3]float64, centers [][3]float64) int {
+	best, bestDistance := 0, math.Inf(1)
+	for i, c := range centers {
+		if d := squaredDistance(p, c); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+func squaredDistance(a, b [3]float64) float64 {
+	return (a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2])
+}
+
+func pointToRGBA(p [3]float64, lab bool) color.RGBA {
+	if lab {
+		r, g, b := utils.LabToRGB(p[0], p[1], p[2])
+		return color.RGBA{R: r, G: g, B: b, A: utils.MaxUint8}
+	}
+	toUint8 := func(v float64) uint8 {
+		return uint8(utils.ClampF64(math.Round(v), utils.MinUint8, float64(utils.MaxUint8)))
+	}
+	return color.RGBA{R: toUint8(p[0]), G: toUint8(p[1]), B: toUint8(p[2]), A: utils.MaxUint8}
+}
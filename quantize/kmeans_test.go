@@ -0,0 +1,98 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_DominantColorsKMeansRGBA(t *testing.T) {
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	yellow := color.RGBA{R: 0xFF, G: 0xFF, A: 0xFF}
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 50; y++ {
+			if x < 70 {
+				img.SetRGBA(x, y, blue)
+			} else {
+				img.SetRGBA(x, y, yellow)
+			}
+		}
+	}
+	for _, lab := range []bool{false, true} {
+		options := DefaultKMeansOptions()
+		options.Lab = lab
+		options.MaxSamples = 2000
+		colors, weights, err := DominantColorsKMeansRGBA(img, 2, options)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if len(colors) != 2 || colors[0] != blue || colors[1] != yellow {
+			t.Fatalf("Expected colors: %v - actual colors: %v (lab: %t)", []color.RGBA{blue, yellow}, colors, lab)
+		}
+		if math.Abs(weights[0]-0.7) > 0.03 || math.Abs(weights[1]-0.3) > 0.03 {
+			t.Errorf("Expected weights: [0.7 0.3] - actual weights: %v (lab: %t)", weights, lab)
+		}
+	}
+}
+
+func Test_DominantColorsKMeansRGBA_Deterministic(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: uint8(x * y), A: 0xFF})
+		}
+	}
+	options := DefaultKMeansOptions()
+	options.Seed = 42
+	options.MaxSamples = 500
+	first, firstWeights, _ := DominantColorsKMeansRGBA(img, 5, options)
+	second, secondWeights, _ := DominantColorsKMeansRGBA(img, 5, options)
+	if len(first) != 5 || len(first) != len(second) {
+		t.Fatalf("Expected 5 colors - actual: %d and %d", len(first), len(second))
+	}
+	sum := 0.0
+	for i := range first {
+		if first[i] != second[i] || firstWeights[i] != secondWeights[i] {
+			t.Errorf("Expected the same result for the same seed - actual: %v %v", first, second)
+		}
+		if i > 0 && firstWeights[i] > firstWeights[i-1] {
+			t.Errorf("Expected the colors to be sorted by weight - actual weights: %v", firstWeights)
+		}
+		sum += firstWeights[i]
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected the weights to sum to 1 - actual: %f", sum)
+	}
+}
+
+func Test_DominantColorsKMeansRGBA_Transparent(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if x < 3 {
+				img.SetRGBA(x, y, red)
+			} else {
+				img.SetRGBA(x, y, color.RGBA{G: 0x20, A: 0x10})
+			}
+		}
+	}
+	colors, weights, err := DominantColorsKMeansRGBA(img, 3, DefaultKMeansOptions())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(colors) != 1 || colors[0] != red || weights[0] != 1 {
+		t.Errorf("Expected only the opaque red color - actual colors: %v, weights: %v", colors, weights)
+	}
+	if _, _, err := DominantColorsKMeansRGBA(image.NewRGBA(image.Rect(0, 0, 4, 4)), 2, DefaultKMeansOptions()); err == nil {
+		t.Error("Expected error for a fully transparent image")
+	}
+	if _, _, err := DominantColorsKMeansRGBA(img, 0, DefaultKMeansOptions()); err == nil {
+		t.Error("Expected error for k = 0")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,159 @@
+package quantize
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+)
+
+// MedianCutPalette computes a palette with at most n colors for an image. If the image contains at most n distinct
+// colors they are returned directly, otherwise the color space is recursively split at the pixel weighted median of
+// the longest axis of the box with the widest range and every box is represented by its average color. The alpha
+// channel is ignored and the returned colors are opaque.
+// Example of usage:
+//
+//	palette := quantize.MedianCutPalette(img, 16)
+func MedianCutPalette(img image.Image, n int) color.Palette {
+	bounds := img.Bounds()
+	counts := make(map[color.RGBA]int)
+	var colors []color.RGBA
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			c.A = 0xFF
+			if counts[c] == 0 {
+				colors = append(colors, c)
+			}
+			counts[c]++
+		}
+	}
+	if len(colors) <= n {
+		res := make(color.Palette, len(colors))
+		for i, c := range colors {
+			res[i] = c
+		}
+		return res
+	}
+
+	boxes := []colorBox{{colors: colors}}
+	for len(boxes) < n {
+		best, bestRange, bestAxis := -1, 0, 0
+		for i, b := range boxes {
+			if len(b.colors) < 2 {
+				continue
+			}
+			if axis, r := b.longestAxis(); r > bestRange {
+				best, bestRange, bestAxis = i, r, axis
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best].colors
+		sort.Slice(box, func(i, j int) bool {
+			return channel(box[i], bestAxis) < channel(box[j], bestAxis)
+		})
+		// split at the pixel weighted median
+		total := 0
+		for _, c := range box {
+			total += counts[c]
+		}
+		split, acc := 0, 0
+		for split < len(box)-1 && acc+counts[box[split]] <= total/2 {
+			acc += counts[box[split]]
+			split++
+		}
+		if split == 0 {
+			split = 1
+		}
+		boxes[best] = colorBox{colors: box[:split]}
+		boxes = append(boxes, colorBox{colors: box[split:]})
+	}
+
+	res := make(color.Palette, len(boxes))
+	for i, b := range boxes {
+		var r, g, bl, total int
+		for _, c := range b.colors {
+			w := counts[c]
+			r += int(c.R) * w
+			g += int(c.G) * w
+			bl += int(c.B) * w
+			total += w
+		}
+		res[i] = color.RGBA{R: uint8(r / total), G: uint8(g / total), B: uint8(bl / total), A: 0xFF}
+	}
+	return res
+}
+
+// QuantizeRGBA reduces the colors of an RGBA image to a palette of the given number of colors computed with the median
+// cut algorithm (see MedianCutPalette), every pixel is mapped to the nearest color of the palette. Opaque images which
+// contain at most the given number of distinct colors are reproduced exactly. The palette colors are always opaque, so
+// the alpha channel of transparent or translucent pixels is lost. The palette of the result can be used directly for
+// GIF or PNG-8 export or as the extracted palette of the image. The number of colors has to be in the [2, 256]
+// interval.
+// Example of usage:
+//
+//	res, err := quantize.QuantizeRGBA(img, 16)
+func QuantizeRGBA(img *image.RGBA, colors int) (*image.Paletted, error) {
+	return quantizeRGBA(img, colors, draw.Src)
+}
+
+// QuantizeRGBADithered works like QuantizeRGBA, but the pixels are mapped to the palette with Floyd-Steinberg error
+// diffusion dithering, which hides the banding of smooth gradients.
+// Example of usage:
+//
+//	res, err := quantize.QuantizeRGBADithered(img, 16)
+func QuantizeRGBADithered(img *image.RGBA, colors int) (*image.Paletted, error) {
+	return quantizeRGBA(img, colors, draw.FloydSteinberg)
+}
+
+// -------------------------------------------------------------------------------------------------------
+func quantizeRGBA(img *image.RGBA, colors int, drawer draw.Drawer) (*image.Paletted, error) {
+	if colors < 2 || colors > 256 {
+		return nil, errors.New("the number of colors should be in the [2, 256] interval")
+	}
+	bounds := img.Bounds()
+	res := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), MedianCutPalette(img, colors))
+	drawer.Draw(res, res.Rect, img, bounds.Min)
+	return res, nil
+}
+
+// colorBox is a set of colors which is split by the median cut algorithm.
+type colorBox struct {
+	colors []color.RGBA
+}
+
+// longestAxis returns the channel (0 - red, 1 - green, 2 - blue) with the widest range in the box and its range.
+func (b colorBox) longestAxis() (int, int) {
+	lo := [3]int{255, 255, 255}
+	hi := [3]int{0, 0, 0}
+	for _, c := range b.colors {
+		for i, v := range [3]int{int(c.R), int(c.G), int(c.B)} {
+			if v < lo[i] {
+				lo[i] = v
+			}
+			if v > hi[i] {
+				hi[i] = v
+			}
+		}
+	}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if hi[i]-lo[i] > hi[axis]-lo[axis] {
+			axis = i
+		}
+	}
+	return axis, hi[axis] - lo[axis]
+}
+
+func channel(c color.RGBA, axis int) uint8 {
+	switch axis {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	}
+	return c.B
+}
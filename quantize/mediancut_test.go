@@ -0,0 +1,135 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_QuantizeRGBA_ExactColors(t *testing.T) {
+	colors := []color.RGBA{
+		{R: 0xFF, A: 0xFF}, {G: 0xFF, A: 0xFF}, {B: 0xFF, A: 0xFF},
+		{R: 0x10, G: 0x20, B: 0x30, A: 0xFF}, {R: 0x80, G: 0x80, B: 0x80, A: 0xFF},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			img.SetRGBA(x, y, colors[(x+y)%len(colors)])
+		}
+	}
+	res, err := QuantizeRGBA(img, len(colors))
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(res.Palette) != len(colors) {
+		t.Fatalf("Expected palette size: %d - actual: %d", len(colors), len(res.Palette))
+	}
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if actual := color.RGBAModel.Convert(res.At(x, y)); actual != img.RGBAAt(x, y) {
+				t.Fatalf("Expected color: %v - actual color: %v at: %d %d", img.RGBAAt(x, y), actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_QuantizeRGBA_OpaquePalette(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 0x40, A: 0x80})
+	img.SetRGBA(1, 0, color.RGBA{G: 0xFF, A: 0xFF})
+	res, err := QuantizeRGBA(img, 2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, c := range res.Palette {
+		if _, _, _, a := c.RGBA(); a != 0xFFFF {
+			t.Errorf("Expected an opaque palette color - actual: %v at index %d", c, i)
+		}
+	}
+}
+
+func Test_QuantizeRGBA_ErrorDecreases(t *testing.T) {
+	img := gradientRGBA(64)
+	previous := -1.0
+	for _, n := range []int{2, 4, 16, 64} {
+		res, err := QuantizeRGBA(img, n)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if len(res.Palette) > n {
+			t.Fatalf("Expected at most %d colors - actual: %d", n, len(res.Palette))
+		}
+		e := meanError(img, res)
+		if previous >= 0 && e >= previous {
+			t.Errorf("Expected the mean error to decrease for %d colors - previous: %f, actual: %f", n, previous, e)
+		}
+		previous = e
+	}
+}
+
+func Test_QuantizeRGBADithered(t *testing.T) {
+	img := gradientRGBA(64)
+	res, err := QuantizeRGBADithered(img, 4)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != img.Bounds() || len(res.Palette) > 4 {
+		t.Fatalf("Expected a 64x64 image with at most 4 colors - actual: %v, %d colors", res.Bounds(), len(res.Palette))
+	}
+	// dithering keeps the average color of large areas close to the original one
+	var expected, actual [3]int
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			e, a := img.RGBAAt(x, y), color.RGBAModel.Convert(res.At(x, y)).(color.RGBA)
+			expected[0], expected[1], expected[2] = expected[0]+int(e.R), expected[1]+int(e.G), expected[2]+int(e.B)
+			actual[0], actual[1], actual[2] = actual[0]+int(a.R), actual[1]+int(a.G), actual[2]+int(a.B)
+		}
+	}
+	for i := range expected {
+		if d := (expected[i] - actual[i]) / (64 * 64); d > 4 || d < -4 {
+			t.Errorf("Expected the average of channel %d to be preserved - difference: %d", i, d)
+		}
+	}
+}
+
+func Test_QuantizeRGBA_InvalidColors(t *testing.T) {
+	img := gradientRGBA(8)
+	for _, n := range []int{-1, 0, 1, 257} {
+		if _, err := QuantizeRGBA(img, n); err == nil {
+			t.Errorf("Expected error for %d colors", n)
+		}
+		if _, err := QuantizeRGBADithered(img, n); err == nil {
+			t.Errorf("Expected error for %d colors", n)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+func gradientRGBA(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 255 / size), G: uint8(y * 255 / size), B: uint8((x + y) * 127 / size), A: 0xFF})
+		}
+	}
+	return img
+}
+
+func meanError(img *image.RGBA, res *image.Paletted) float64 {
+	abs := func(a, b uint8) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+	bounds := img.Bounds()
+	var sum int
+	for x := 0; x < bounds.Dx(); x++ {
+		for y := 0; y < bounds.Dy(); y++ {
+			e, a := img.RGBAAt(x, y), color.RGBAModel.Convert(res.At(x, y)).(color.RGBA)
+			sum += abs(e.R, a.R) + abs(e.G, a.G) + abs(e.B, a.B)
+		}
+	}
+	return float64(sum) / float64(bounds.Dx()*bounds.Dy()*3)
+}
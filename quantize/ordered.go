@@ -0,0 +1,68 @@
+package quantize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// OrderedDitherGray converts a grayscale image to black and white (0 and 255) with ordered dithering: every pixel is
+// compared against the threshold at its position in a tiled Bayer matrix. Unlike error diffusion, every pixel is
+// processed independently, so the result is fast to compute and stable between similar frames. The matrix size has to
+// be 2, 4 or 8.
+// Example of usage:
+//
+//	res, err := quantize.OrderedDitherGray(img, 4)
+func OrderedDitherGray(img *image.Gray, matrixSize int) (*image.Gray, error) {
+	return OrderedDitherGrayLevels(img, matrixSize, 2)
+}
+
+// OrderedDitherGrayLevels works like OrderedDitherGray, but the result has the given number of evenly spaced gray
+// levels (e.g. 4 levels: 0, 85, 170 and 255), the Bayer matrix dithers between the two levels around every pixel. The
+// matrix size has to be 2, 4 or 8 and the number of levels has to be in the [2, 256] interval.
+// Example of usage:
+//
+//	res, err := quantize.OrderedDitherGrayLevels(img, 8, 4)
+func OrderedDitherGrayLevels(img *image.Gray, matrixSize int, levels int) (*image.Gray, error) {
+	if matrixSize != 2 && matrixSize != 4 && matrixSize != 8 {
+		return nil, errors.New("the size of the Bayer matrix should be 2, 4 or 8")
+	}
+	if levels < 2 || levels > 256 {
+		return nil, errors.New("the number of levels should be in the [2, 256] interval")
+	}
+	matrix := bayerMatrix(matrixSize)
+	cells := float64(matrixSize * matrixSize)
+	step := float64(utils.MaxUint8) / float64(levels-1)
+	bounds := img.Bounds()
+	size := bounds.Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		value := float64(img.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y) / step
+		level := math.Floor(value)
+		if value-level > (float64(matrix[x%matrixSize][y%matrixSize])+0.5)/cells {
+			level++
+		}
+		res.Pix[y*res.Stride+x] = uint8(math.Round(level * step))
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// bayerMatrix returns the size x size Bayer index matrix indexed as [x][y], size has to be a power of two. The matrix
+// of size 2n is built from the matrix of size n: M2n(x, y) = 4 * Mn(x mod n, y mod n) + M2(x / n, y / n).
+func bayerMatrix(size int) [][]int {
+	base := [2][2]int{{0, 3}, {2, 1}}
+	matrix := [][]int{{0}}
+	for n := 1; n < size; n *= 2 {
+		next := make([][]int, 2*n)
+		for x := range next {
+			next[x] = make([]int, 2*n)
+			for y := range next[x] {
+				next[x][y] = 4*matrix[x%n][y%n] + base[x/n][y/n]
+			}
+		}
+		matrix = next
+	}
+	return matrix
+}
@@ -0,0 +1,102 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_bayerMatrix(t *testing.T) {
+	// the classic 4x4 Bayer matrix in rows
+	expected := [][]int{
+		{0, 8, 2, 10},
+		{12, 4, 14, 6},
+		{3, 11, 1, 9},
+		{15, 7, 13, 5},
+	}
+	matrix := bayerMatrix(4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if matrix[x][y] != expected[y][x] {
+				t.Errorf("Expected: %d - Actual: %d at %d, %d", expected[y][x], matrix[x][y], x, y)
+			}
+		}
+	}
+}
+
+func Test_OrderedDitherGray_Checkerboard(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	res, err := OrderedDitherGray(img, 4)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			expected := uint8(0)
+			if (x+y)%2 == 0 {
+				expected = 255
+			}
+			if actual := res.GrayAt(x, y).Y; actual != expected {
+				t.Fatalf("Expected: %d - Actual: %d at %d, %d", expected, actual, x, y)
+			}
+		}
+	}
+	for _, size := range []int{0, 1, 3, 16} {
+		if _, err := OrderedDitherGray(img, size); err == nil {
+			t.Errorf("Expected error for matrix size %d", size)
+		}
+	}
+	if _, err := OrderedDitherGrayLevels(img, 4, 1); err == nil {
+		t.Error("Expected error for a single level")
+	}
+}
+
+func Test_OrderedDitherGrayLevels_BlockMean(t *testing.T) {
+	// every 8x8 block has a uniform random gray value
+	rnd := rand.New(rand.NewSource(3))
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for bx := 0; bx < 8; bx++ {
+		for by := 0; by < 8; by++ {
+			value := uint8(rnd.Intn(256))
+			for x := 0; x < 8; x++ {
+				for y := 0; y < 8; y++ {
+					img.SetGray(bx*8+x, by*8+y, color.Gray{Y: value})
+				}
+			}
+		}
+	}
+	for _, levels := range []int{2, 4, 16} {
+		res, err := OrderedDitherGrayLevels(img, 8, levels)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		step := 255 / float64(levels-1)
+		// the 64 thresholds of the matrix resolve the mean of a block to 1/64 of a level
+		tolerance := step/64 + 0.5
+		for bx := 0; bx < 8; bx++ {
+			for by := 0; by < 8; by++ {
+				mean := 0.0
+				for x := 0; x < 8; x++ {
+					for y := 0; y < 8; y++ {
+						value := float64(res.GrayAt(bx*8+x, by*8+y).Y)
+						if math.Abs(value/step-math.Round(value/step)) > 1e-9 {
+							t.Fatalf("Expected one of %d levels - actual: %f", levels, value)
+						}
+						mean += value / 64
+					}
+				}
+				if expected := float64(img.GrayAt(bx*8, by*8).Y); math.Abs(mean-expected) > tolerance {
+					t.Errorf("Expected the mean of the block %d, %d to be %f with %d levels - actual: %f", bx, by, expected, levels, mean)
+				}
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
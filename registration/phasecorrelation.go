@@ -0,0 +1,140 @@
+package registration
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/fft"
+	"image"
+	"math"
+	"math/cmplx"
+)
+
+// PhaseCorrelate estimates the global translation between two grayscale images of the same size using phase
+// correlation. The normalized cross-power spectrum of the images is transformed back with the inverse FFT, the
+// position of its highest peak gives the integer shift which is then refined to sub-pixel precision by fitting a
+// parabola through the peak and its neighbours in the 3x3 neighbourhood. The correlation surface is smoothed with a
+// small Gaussian before the fit, which keeps the parabolic fit unbiased. The returned (dx, dy) is the shift of img2
+// relative to img1, so img2(x, y) ~ img1(x - dx, y - dy). The response is the height of the correlation peak, it is
+// close to 1 for images which differ only by a translation and close to 0 for unrelated images. If useHann is true a
+// Hann window is applied to both images to reduce the effect of the image borders.
+// Example of usage:
+//
+//	dx, dy, response, err := registration.PhaseCorrelate(img1, img2, true)
+func PhaseCorrelate(img1, img2 *image.Gray, useHann bool) (float64, float64, float64, error) {
+	size := img1.Bounds().Size()
+	if size != img2.Bounds().Size() {
+		return 0, 0, 0, errors.New("the size of the two image does not match")
+	}
+	if size.X == 0 || size.Y == 0 {
+		return 0, 0, 0, errors.New("empty image")
+	}
+	f1, err := fft.FFT2D(complexPlane(img1, useHann), size.X, size.Y)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	f2, err := fft.FFT2D(complexPlane(img2, useHann), size.X, size.Y)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	crossPower := make([]complex128, len(f1))
+	// norm is the height of the smoothed peak for two identical images
+	var norm float64
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			norm += peakSmoothing(x, size.X) * peakSmoothing(y, size.Y)
+		}
+	}
+	norm /= float64(size.X * size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			i := y*size.X + x
+			c := cmplx.Conj(f1[i]) * f2[i]
+			if magnitude := cmplx.Abs(c); magnitude > 1e-12 {
+				crossPower[i] = c / complex(magnitude, 0) * complex(peakSmoothing(x, size.X)*peakSmoothing(y, size.Y), 0)
+			}
+		}
+	}
+	correlation, err := fft.IFFT2D(crossPower, size.X, size.Y)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	at := func(x, y int) float64 {
+		x = ((x % size.X) + size.X) % size.X
+		y = ((y % size.Y) + size.Y) % size.Y
+		return real(correlation[y*size.X+x])
+	}
+	peakX, peakY := 0, 0
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if at(x, y) > at(peakX, peakY) {
+				peakX, peakY = x, y
+			}
+		}
+	}
+	peak := at(peakX, peakY)
+	dx := float64(peakX) + parabolicOffset(at(peakX-1, peakY), peak, at(peakX+1, peakY))
+	dy := float64(peakY) + parabolicOffset(at(peakX, peakY-1), peak, at(peakX, peakY+1))
+	response := peak / norm
+
+	// shifts bigger then half of the image wrap around to negative values
+	if dx > float64(size.X)/2 {
+		dx -= float64(size.X)
+	}
+	if dy > float64(size.Y)/2 {
+		dy -= float64(size.Y)
+	}
+	return dx, dy, response, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// peakSigma is the standard deviation in pixels of the Gaussian used to smooth the correlation surface. The peak of the
+// raw surface is too sharp for a parabolic fit, after smoothing it is close to a Gaussian and the fit is accurate.
+const peakSigma = 2.0
+
+// peakSmoothing returns the frequency response of the smoothing Gaussian for the i-th of n frequencies.
+func peakSmoothing(i int, n int) float64 {
+	f := float64(i) / float64(n)
+	if f > 0.5 {
+		f -= 1
+	}
+	return math.Exp(-2 * math.Pi * math.Pi * peakSigma * peakSigma * f * f)
+}
+
+// complexPlane converts the image into a row-major complex matrix with zero mean, optionally multiplied by a Hann
+// window. Removing the mean keeps the window itself from producing a correlation peak at zero shift.
+func complexPlane(img *image.Gray, useHann bool) []complex128 {
+	size := img.Bounds().Size()
+	var mean float64
+	for _, v := range img.Pix {
+		mean += float64(v)
+	}
+	mean /= float64(len(img.Pix))
+	res := make([]complex128, size.X*size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			v := float64(img.GrayAt(x, y).Y) - mean
+			if useHann {
+				v *= hann(x, size.X) * hann(y, size.Y)
+			}
+			res[y*size.X+x] = complex(v, 0)
+		}
+	}
+	return res
+}
+
+func hann(i int, n int) float64 {
+	if n == 1 {
+		return 1
+	}
+	return 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+}
+
+// parabolicOffset returns the position of the vertex of the parabola through (-1, left), (0, center), (1, right).
+func parabolicOffset(left, center, right float64) float64 {
+	denominator := left - 2*center + right
+	if denominator == 0 {
+		return 0
+	}
+	offset := (left - right) / (2 * denominator)
+	return math.Max(-0.5, math.Min(0.5, offset))
+}
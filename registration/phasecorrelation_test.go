@@ -0,0 +1,65 @@
+package registration
+
+import (
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/transform"
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseTexture creates a smoothed random texture which has energy in all the frequency bands.
+func setupTestCaseTexture(t *testing.T, seed int64) *image.Gray {
+	r := rand.New(rand.NewSource(seed))
+	img := image.NewGray(image.Rect(0, 0, 96, 80))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(r.Intn(256))
+	}
+	res, _, err := blur.GaussianBlurGray(img, 3, 1.2, padding.BorderReflect)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	return res
+}
+
+func Test_PhaseCorrelate(t *testing.T) {
+	img1 := setupTestCaseTexture(t, 1)
+	for _, shift := range [][2]float64{{3, -2}, {2.3, -1.6}, {-4.5, 0.25}, {0.7, 3.4}, {-1.35, -2.85}} {
+		img2, err := transform.TranslateGray(img1, shift[0], shift[1], padding.BorderReflect, resize.InterCatmullRom)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		dx, dy, response, err := PhaseCorrelate(img1, img2, true)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if math.Abs(dx-shift[0]) > 0.1 || math.Abs(dy-shift[1]) > 0.1 {
+			t.Errorf("Expected shift: %f %f - actual shift: %f %f", shift[0], shift[1], dx, dy)
+		}
+		if response < 0.5 {
+			t.Errorf("Expected a strong response for: %v - actual response: %f", shift, response)
+		}
+	}
+}
+
+func Test_PhaseCorrelate_Unrelated(t *testing.T) {
+	_, _, response, err := PhaseCorrelate(setupTestCaseTexture(t, 1), setupTestCaseTexture(t, 2), false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if response > 0.3 {
+		t.Errorf("Expected a weak response for unrelated images - actual response: %f", response)
+	}
+}
+
+func Test_PhaseCorrelate_Invalid(t *testing.T) {
+	if _, _, _, err := PhaseCorrelate(image.NewGray(image.Rect(0, 0, 4, 4)), image.NewGray(image.Rect(0, 0, 4, 5)), true); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,105 @@
+package resize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+)
+
+// ResizeGrayAntiAlias resizes a grayscale (Gray) image the same way as ResizeGray, but before downscaling the image
+// is smoothed with a separable Gaussian filter, where sigma is proportional to the downscale factor. This removes the
+// high frequencies which would otherwise cause aliasing. The pre-filtering is skipped for the axes which are upscaled.
+// Example of usage:
+//
+//	res, err := resize.ResizeGrayAntiAlias(img, 0.125, 0.125, resize.InterLinear)
+func ResizeGrayAntiAlias(img *image.Gray, fx float64, fy float64, interpolation Interpolation) (*image.Gray, error) {
+	if fx < 0 || fy < 0 {
+		return nil, errors.New("scale value should be greater then 0")
+	}
+	return ResizeGray(prefilterGray(img, antiAliasSigma(fx), antiAliasSigma(fy)), fx, fy, interpolation)
+}
+
+// ResizeRGBAAntiAlias resizes an RGBA image the same way as ResizeRGBA, but before downscaling the image is smoothed
+// with a separable Gaussian filter, where sigma is proportional to the downscale factor. This removes the high
+// frequencies which would otherwise cause aliasing. The pre-filtering is skipped for the axes which are upscaled.
+// Example of usage:
+//
+//	res, err := resize.ResizeRGBAAntiAlias(img, 0.125, 0.125, resize.InterLinear)
+func ResizeRGBAAntiAlias(img *image.RGBA, fx float64, fy float64, interpolation Interpolation) (*image.RGBA, error) {
+	if fx < 0 || fy < 0 {
+		return nil, errors.New("scale value should be greater then 0")
+	}
+	return ResizeRGBA(prefilterRGBA(img, antiAliasSigma(fx), antiAliasSigma(fy)), fx, fy, interpolation)
+}
+
+// -------------------------------------------------------------------------------------------------------
+// antiAliasSigma returns the sigma of the Gaussian pre-filter for a given scale factor, or 0 if no filtering is
+// needed.
+func antiAliasSigma(f float64) float64 {
+	if f <= 0 || f >= 1 {
+		return 0
+	}
+	return (1/f - 1) / 2
+}
+
+// gaussianWeights returns the kernel of the pre-filter which covers three sigmas, nil if no filtering is needed.
+func gaussianWeights(sigma float64) []float64 {
+	if sigma <= 0 {
+		return nil
+	}
+	return blur.GaussianKernel1D(3*sigma, sigma)
+}
+
+func prefilterGray(img *image.Gray, sigmaX float64, sigmaY float64) *image.Gray {
+	if sigmaX == 0 && sigmaY == 0 {
+		return img
+	}
+	origin := img.Bounds().Min
+	size := img.Bounds().Size()
+	plane := make([]float64, size.X*size.Y)
+	utils.ForEachPixel(size, func(x, y int) {
+		plane[y*size.X+x] = float64(img.GrayAt(origin.X+x, origin.Y+y).Y)
+	})
+	plane, _ = blur.SeparableBlurPlane(plane, size, gaussianWeights(sigmaX), gaussianWeights(sigmaY), padding.BorderReplicate)
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(plane[y*size.X+x]+0.5, 0, 255))})
+	})
+	return res
+}
+
+func prefilterRGBA(img *image.RGBA, sigmaX float64, sigmaY float64) *image.RGBA {
+	if sigmaX == 0 && sigmaY == 0 {
+		return img
+	}
+	origin := img.Bounds().Min
+	size := img.Bounds().Size()
+	kx := gaussianWeights(sigmaX)
+	ky := gaussianWeights(sigmaY)
+	var planes [4][]float64
+	for c := range planes {
+		planes[c] = make([]float64, size.X*size.Y)
+	}
+	utils.ForEachPixel(size, func(x, y int) {
+		pixel := img.RGBAAt(origin.X+x, origin.Y+y)
+		planes[0][y*size.X+x] = float64(pixel.R)
+		planes[1][y*size.X+x] = float64(pixel.G)
+		planes[2][y*size.X+x] = float64(pixel.B)
+		planes[3][y*size.X+x] = float64(pixel.A)
+	})
+	for c := range planes {
+		planes[c], _ = blur.SeparableBlurPlane(planes[c], size, kx, ky, padding.BorderReplicate)
+	}
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		i := y*size.X + x
+		res.SetRGBA(x, y, color.RGBA{R: uint8(utils.ClampF64(planes[0][i]+0.5, 0, 255)),
+			G: uint8(utils.ClampF64(planes[1][i]+0.5, 0, 255)),
+			B: uint8(utils.ClampF64(planes[2][i]+0.5, 0, 255)),
+			A: uint8(utils.ClampF64(planes[3][i]+0.5, 0, 255))})
+	})
+	return res
+}
@@ -0,0 +1,81 @@
+package resize
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func checkerboardGray(size int, cell int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if (x/cell+y/cell)%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	return img
+}
+
+func stdDevGray(img *image.Gray) float64 {
+	var sum, sumSqr float64
+	n := float64(len(img.Pix))
+	for _, p := range img.Pix {
+		sum += float64(p)
+		sumSqr += float64(p) * float64(p)
+	}
+	mean := sum / n
+	return math.Sqrt(sumSqr/n - mean*mean)
+}
+
+func Test_ResizeGrayAntiAlias_Checkerboard(t *testing.T) {
+	img := checkerboardGray(96, 3)
+	aliased, err := ResizeGray(img, 0.125, 0.125, InterLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smooth, err := ResizeGrayAntiAlias(img, 0.125, 0.125, InterLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smooth.Bounds().Size() != aliased.Bounds().Size() {
+		t.Fatalf("Expected size: %v - actual size: %v", aliased.Bounds().Size(), smooth.Bounds().Size())
+	}
+	if sd := stdDevGray(aliased); sd < 50 {
+		t.Errorf("Expected visible aliasing without anti-aliasing, standard deviation: %f", sd)
+	}
+	if sd := stdDevGray(smooth); sd > 10 {
+		t.Errorf("Expected near uniform gray with anti-aliasing, standard deviation: %f", sd)
+	}
+}
+
+func Test_ResizeRGBAAntiAlias_Upscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.SetRGBA(1, 1, color.RGBA{R: 0xFF, A: 0xFF})
+	expected, _ := ResizeRGBA(img, 2, 2, InterLinear)
+	actual, err := ResizeRGBAAntiAlias(img, 2, 2, InterLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range expected.Pix {
+		if expected.Pix[i] != actual.Pix[i] {
+			t.Fatalf("Expected upscaling to be unchanged by the anti-alias filter at: %d", i)
+		}
+	}
+}
+
+func benchmarkResizeGrayAntiAlias(b *testing.B, f float64) {
+	img := checkerboardGray(512, 3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ResizeGrayAntiAlias(img, f, f, InterLinear)
+	}
+}
+
+func Benchmark_ResizeGrayAntiAlias_2X(b *testing.B) { benchmarkResizeGrayAntiAlias(b, 0.5) }
+
+func Benchmark_ResizeGrayAntiAlias_4X(b *testing.B) { benchmarkResizeGrayAntiAlias(b, 0.25) }
+
+func Benchmark_ResizeGrayAntiAlias_8X(b *testing.B) { benchmarkResizeGrayAntiAlias(b, 0.125) }
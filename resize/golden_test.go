@@ -0,0 +1,27 @@
+package resize
+
+import (
+	"github.com/yafeiliu/imger/internal/goldentest"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Golden tests------------------------------------
+func Test_Golden(t *testing.T) {
+	registry := goldentest.NewRegistry("../res/golden/resize")
+	registry.Register("nearest_x2", "../res/golden/input.png", 0, func(img *image.Gray) (*image.Gray, error) {
+		return ResizeGray(img, 2, 2, InterNearest)
+	})
+	registry.Register("linear_x1.5", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		return ResizeGray(img, 1.5, 1.5, InterLinear)
+	})
+	registry.Register("catmullrom_x0.75", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		return ResizeGray(img, 0.75, 0.75, InterCatmullRom)
+	})
+	registry.Register("lanczos_antialias_x0.5", "../res/golden/input.png", 1, func(img *image.Gray) (*image.Gray, error) {
+		return ResizeGrayAntiAlias(img, 0.5, 0.5, InterLanczos)
+	})
+	registry.Run(t)
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,58 @@
+package resize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// ResizeIntoRGBA scales an RGBA image directly into the dstRect sub-rectangle of an existing destination image, which
+// avoids allocating and copying a temporary resized image, for example when many thumbnails are drawn into a contact
+// sheet. The scaling factors are dstRect.Dx() / srcWidth and dstRect.Dy() / srcHeight. Only the part of dstRect which
+// lies inside of the bounds of dst is written, all the other pixels of dst are left untouched.
+// Returns an error if dstRect, src or the clipped rectangle is empty or the interpolation method is invalid.
+// Example of usage:
+//
+//	err := resize.ResizeIntoRGBA(sheet, image.Rect(100, 0, 200, 75), thumbnail, resize.InterLinear)
+func ResizeIntoRGBA(dst *image.RGBA, dstRect image.Rectangle, src *image.RGBA, interpolation Interpolation) error {
+	if dstRect.Empty() || src.Bounds().Empty() {
+		return errors.New("empty rectangle")
+	}
+	clip := dstRect.Intersect(dst.Bounds())
+	if clip.Empty() {
+		return errors.New("destination rectangle is outside of the destination image")
+	}
+	srcSize := src.Bounds().Size()
+	fx := float64(dstRect.Dx()) / float64(srcSize.X)
+	fy := float64(dstRect.Dy()) / float64(srcSize.Y)
+	var filter Filter
+	switch interpolation {
+	case InterNearest:
+		resizeNearestRGBAInto(dst, dstRect, clip, src, fx, fy)
+		return nil
+	case InterLinear:
+		filter = NewLinear()
+	case InterCatmullRom:
+		filter = NewCatmullRom()
+	case InterLanczos:
+		filter = NewLanczos()
+	default:
+		return errors.New("invalid interpolation method")
+	}
+	horizontal := resizeHorizontalRGBAToWidth(src, dstRect.Dx(), fx, filter, nil)
+	resizeVerticalRGBAInto(dst, dstRect, clip, horizontal, fy, filter)
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func resizeNearestRGBAInto(dst *image.RGBA, dstRect image.Rectangle, clip image.Rectangle, img *image.RGBA, fx float64, fy float64) {
+	origin := img.Bounds().Min
+	oldSize := img.Bounds().Size()
+	for y := clip.Min.Y; y < clip.Max.Y; y++ {
+		oldY := utils.ClampInt(int(float64(y-dstRect.Min.Y)/fy+0.5), 0, oldSize.Y-1)
+		for x := clip.Min.X; x < clip.Max.X; x++ {
+			oldX := utils.ClampInt(int(float64(x-dstRect.Min.X)/fx+0.5), 0, oldSize.X-1)
+			dst.SetRGBA(x, y, img.RGBAAt(origin.X+oldX, origin.Y+oldY))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package resize
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func gradientRGBA(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 12), G: uint8(y * 25), B: uint8((x * y) % 256), A: 0xFF})
+		}
+	}
+	return img
+}
+
+func Test_ResizeIntoRGBA(t *testing.T) {
+	src := gradientRGBA(20, 10)
+	marker := color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x44}
+	dstRect := image.Rect(5, 7, 45, 37)
+	for _, interpolation := range []Interpolation{InterLinear, InterCatmullRom, InterLanczos} {
+		sheet := image.NewRGBA(image.Rect(0, 0, 60, 50))
+		draw.Draw(sheet, sheet.Bounds(), &image.Uniform{C: marker}, image.Point{}, draw.Src)
+		if err := ResizeIntoRGBA(sheet, dstRect, src, interpolation); err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		resized, err := ResizeRGBA(src, 2, 3, interpolation)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		expected := image.NewRGBA(sheet.Bounds())
+		draw.Draw(expected, expected.Bounds(), &image.Uniform{C: marker}, image.Point{}, draw.Src)
+		draw.Draw(expected, dstRect, resized, image.Point{}, draw.Src)
+		for x := 0; x < 60; x++ {
+			for y := 0; y < 50; y++ {
+				e, a := expected.RGBAAt(x, y), sheet.RGBAAt(x, y)
+				if !(image.Point{X: x, Y: y}).In(dstRect) && a != marker {
+					t.Fatalf("Expected pixel outside of the rectangle to be untouched - actual: %v at: %d %d", a, x, y)
+				}
+				if absDiff(e.R, a.R) > 1 || absDiff(e.G, a.G) > 1 || absDiff(e.B, a.B) > 1 || absDiff(e.A, a.A) > 1 {
+					t.Fatalf("Expected color: %v - actual color: %v at: %d %d", e, a, x, y)
+				}
+			}
+		}
+	}
+}
+
+func Test_ResizeIntoRGBA_Clipping(t *testing.T) {
+	src := gradientRGBA(10, 10)
+	sheet := image.NewRGBA(image.Rect(0, 0, 15, 15))
+	if err := ResizeIntoRGBA(sheet, image.Rect(10, 10, 30, 30), src, InterNearest); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if actual := sheet.RGBAAt(14, 14); actual != src.RGBAAt(2, 2) {
+		t.Errorf("Expected color: %v - actual color: %v at: 14 14", src.RGBAAt(2, 2), actual)
+	}
+	if actual := sheet.RGBAAt(9, 9); actual != (color.RGBA{}) {
+		t.Errorf("Expected pixel outside of the rectangle to be untouched - actual: %v", actual)
+	}
+}
+
+func Test_ResizeIntoRGBA_Invalid(t *testing.T) {
+	src := gradientRGBA(10, 10)
+	sheet := image.NewRGBA(image.Rect(0, 0, 15, 15))
+	if err := ResizeIntoRGBA(sheet, image.Rect(3, 3, 3, 8), src, InterLinear); err == nil {
+		t.Error("Expected error for empty rectangle")
+	}
+	if err := ResizeIntoRGBA(sheet, image.Rect(20, 20, 30, 30), src, InterLinear); err == nil {
+		t.Error("Expected error for rectangle outside of the destination")
+	}
+	if err := ResizeIntoRGBA(sheet, image.Rect(0, 0, 5, 5), image.NewRGBA(image.Rectangle{}), InterLinear); err == nil {
+		t.Error("Expected error for empty source")
+	}
+	if err := ResizeIntoRGBA(sheet, image.Rect(0, 0, 5, 5), src, Interpolation(42)); err == nil {
+		t.Error("Expected error for invalid interpolation")
+	}
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,104 @@
+package resize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// ResizeMaskGray resizes a binary mask (0 - background, 255 - foreground) to the given size without the gray halos of
+// the interpolating methods and the jagged, area biased result of InterNearest. Every output pixel is foreground if the
+// fraction of its area covered by the foreground of the input (see MaskCoverageGray) is at least the threshold, so the
+// result contains only 0 and 255 and with a threshold of 0.5 the foreground area is preserved. The threshold has to be
+// in the (0, 1] interval and the new size has to be positive.
+// Example of usage:
+//
+//	res, err := resize.ResizeMaskGray(mask, 128, 128, 0.5)
+func ResizeMaskGray(img *image.Gray, newWidth, newHeight int, threshold float64) (*image.Gray, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, errors.New("threshold should be in the (0, 1] interval")
+	}
+	coverage, err := MaskCoverageGray(img, newWidth, newHeight)
+	if err != nil {
+		return nil, err
+	}
+	res := image.NewGray(image.Rect(0, 0, newWidth, newHeight))
+	utils.ParallelForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if coverage[x][y] >= threshold {
+			res.Pix[y*res.Stride+x] = utils.MaxUint8
+		}
+	})
+	return res, nil
+}
+
+// MaskCoverageGray returns the soft coverage map of a mask resized to the given size, indexed as coverage[x][y]. Every
+// value is the area weighted average of the input pixels under the output pixel, divided by 255, so for a binary mask
+// it is the fraction of the output pixel covered by the foreground, in the [0, 1] interval. The input pixels which are
+// only partly covered by an output pixel contribute proportionally to the overlapping area. The map can be used e.g.
+// for loss weighting at the boundaries of the objects. Returns an error if the new size is not positive or the mask is
+// empty.
+// Example of usage:
+//
+//	coverage, err := resize.MaskCoverageGray(mask, 128, 128)
+func MaskCoverageGray(img *image.Gray, newWidth, newHeight int) ([][]float64, error) {
+	if newWidth <= 0 || newHeight <= 0 {
+		return nil, errors.New("the new size should be positive")
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, errors.New("empty mask")
+	}
+	tapsX := areaTaps(size.X, newWidth)
+	tapsY := areaTaps(size.Y, newHeight)
+	// horizontal pass, rows[y][x]
+	rows := make([][]float64, size.Y)
+	utils.ParallelForEachRow(image.Point{X: newWidth, Y: size.Y}, func(y int) {
+		rows[y] = make([]float64, newWidth)
+		line := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x, taps := range tapsX {
+			var sum float64
+			for _, t := range taps {
+				sum += float64(line[t.index]) * t.weight
+			}
+			rows[y][x] = sum / float64(utils.MaxUint8)
+		}
+	})
+	coverage := make([][]float64, newWidth)
+	for x := range coverage {
+		coverage[x] = make([]float64, newHeight)
+	}
+	utils.ParallelForEachPixel(image.Point{X: newWidth, Y: newHeight}, func(x, y int) {
+		var sum float64
+		for _, t := range tapsY[y] {
+			sum += rows[t.index][x] * t.weight
+		}
+		coverage[x][y] = utils.ClampF64(sum, 0, 1)
+	})
+	return coverage, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// areaTap is the weight of an input pixel in an output pixel.
+type areaTap struct {
+	index  int
+	weight float64
+}
+
+// areaTaps returns for every output pixel the input pixels overlapping it, weighted by the length of the overlap
+// relative to the length of the output pixel, so the weights of every output pixel sum to 1.
+func areaTaps(length, newLength int) [][]areaTap {
+	scale := float64(length) / float64(newLength)
+	taps := make([][]areaTap, newLength)
+	for i := range taps {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for p := int(math.Floor(start)); p < length && float64(p) < end; p++ {
+			overlap := math.Min(end, float64(p+1)) - math.Max(start, float64(p))
+			if overlap > 0 {
+				taps[i] = append(taps[i], areaTap{index: p, weight: overlap / scale})
+			}
+		}
+	}
+	return taps
+}
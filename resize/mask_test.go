@@ -0,0 +1,87 @@
+package resize
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ResizeMaskGray_AreaPreserved(t *testing.T) {
+	mask := randomBlobs(480, 480, 14, 7)
+	fraction := foregroundFraction(mask)
+	for _, factor := range []int{1, 2, 3, 4, 5, 6, 8} {
+		res, err := ResizeMaskGray(mask, 480/factor, 480/factor, 0.5)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for _, v := range res.Pix {
+			if v != 0 && v != 255 {
+				t.Fatalf("Expected only 0 and 255 in the result - actual: %d", v)
+			}
+		}
+		if actual := foregroundFraction(res); math.Abs(actual-fraction) > 0.01 {
+			t.Errorf("Expected the foreground fraction %f for a %dx downscale - actual: %f", fraction, factor, actual)
+		}
+	}
+	// a 2x upscale replicates the pixels
+	small := randomBlobs(20, 16, 3, 1)
+	res, err := ResizeMaskGray(small, 40, 32, 0.5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if res.GrayAt(x, y) != small.GrayAt(x/2, y/2) {
+			t.Fatalf("Expected the upscaled pixel at %d, %d to match the input pixel", x, y)
+		}
+	})
+}
+
+func Test_MaskCoverageGray(t *testing.T) {
+	// a 3x1 mask with the foreground in the middle, resized to 2x1: both output pixels cover half of it
+	mask := image.NewGray(image.Rect(0, 0, 3, 1))
+	mask.SetGray(1, 0, color.Gray{Y: 255})
+	coverage, err := MaskCoverageGray(mask, 2, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 2; x++ {
+		if !utils.IsEqualFloat64(coverage[x][0], 1.0/3) {
+			t.Errorf("Expected the coverage 1/3 at %d - actual: %f", x, coverage[x][0])
+		}
+	}
+	if _, err := MaskCoverageGray(mask, 0, 1); err == nil {
+		t.Error("Expected error for zero width")
+	}
+	for _, threshold := range []float64{0, 1.5} {
+		if _, err := ResizeMaskGray(mask, 2, 1, threshold); err == nil {
+			t.Errorf("Expected error for threshold %f", threshold)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// randomBlobs draws the given number of random disks on an empty mask.
+func randomBlobs(width, height, count int, seed int64) *image.Gray {
+	rnd := rand.New(rand.NewSource(seed))
+	mask := image.NewGray(image.Rect(0, 0, width, height))
+	for i := 0; i < count; i++ {
+		cx, cy := rnd.Float64()*float64(width), rnd.Float64()*float64(height)
+		r := float64(width) * (0.04 + 0.1*rnd.Float64())
+		utils.ForEachPixel(mask.Bounds().Size(), func(x, y int) {
+			if math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy) <= r {
+				mask.SetGray(x, y, color.Gray{Y: 255})
+			}
+		})
+	}
+	return mask
+}
+
+func foregroundFraction(mask *image.Gray) float64 {
+	size := mask.Bounds().Size()
+	return float64(utils.CountNonZeroGray(mask)) / float64(size.X*size.Y)
+}
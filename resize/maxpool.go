@@ -0,0 +1,42 @@
+package resize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// DownscaleMaxGray downscales a grayscale image by an integer factor taking the maximum of every factor x factor block
+// instead of averaging it (max pooling), so the thin bright features, e.g. the edges of an edge map, survive the
+// downscale with their full intensity. The blocks at the right and bottom borders are cropped if the size of the image
+// is not a multiple of the factor, so the result has ceil(width / factor) x ceil(height / factor) pixels. Returns an
+// error if the factor is smaller then 1.
+// Example of usage:
+//
+//	res, err := resize.DownscaleMaxGray(edges, 2)
+func DownscaleMaxGray(img *image.Gray, factor int) (*image.Gray, error) {
+	if factor < 1 {
+		return nil, errors.New("the factor should be at least 1")
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	newSize := image.Point{X: (size.X + factor - 1) / factor, Y: (size.Y + factor - 1) / factor}
+	res := image.NewGray(image.Rect(0, 0, newSize.X, newSize.Y))
+	utils.ParallelForEachRow(newSize, func(y int) {
+		rowEnd := utils.ClampInt((y+1)*factor, 0, size.Y)
+		for x := 0; x < newSize.X; x++ {
+			columnEnd := utils.ClampInt((x+1)*factor, 0, size.X)
+			var max uint8
+			for sy := y * factor; sy < rowEnd; sy++ {
+				offset := img.PixOffset(bounds.Min.X, bounds.Min.Y+sy)
+				for _, v := range img.Pix[offset+x*factor : offset+columnEnd] {
+					if v > max {
+						max = v
+					}
+				}
+			}
+			res.Pix[y*res.Stride+x] = max
+		}
+	})
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package resize
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_DownscaleMaxGray_ThinLine(t *testing.T) {
+	// a one pixel wide vertical line on an odd column and a horizontal one on an odd row
+	img := image.NewGray(image.Rect(0, 0, 20, 16))
+	for y := 0; y < 16; y++ {
+		img.SetGray(7, y, color.Gray{Y: 255})
+	}
+	for x := 0; x < 20; x++ {
+		img.SetGray(x, 11, color.Gray{Y: 200})
+	}
+	res, err := DownscaleMaxGray(img, 2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	averaged, err := ResizeGray(img, 0.5, 0.5, InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != averaged.Bounds() {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", averaged.Bounds(), res.Bounds())
+	}
+	utils.ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		expected := uint8(0)
+		if y == 5 {
+			expected = 200
+		}
+		if x == 3 {
+			expected = 255
+		}
+		if actual := res.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected value: %d - actual value: %d at: %d %d", expected, actual, x, y)
+		}
+	})
+	// averaging loses at least half of the intensity of the lines
+	if v := averaged.GrayAt(3, 1).Y; v > 128 {
+		t.Errorf("Expected the vertical line to fade with averaging - actual value: %d", v)
+	}
+	if v := averaged.GrayAt(1, 5).Y; v > 100 {
+		t.Errorf("Expected the horizontal line to fade with averaging - actual value: %d", v)
+	}
+}
+
+func Test_DownscaleMaxGray_CroppedBlocks(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 9, 7))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	sub := img.SubImage(image.Rect(1, 1, 8, 7)).(*image.Gray)
+	res, err := DownscaleMaxGray(sub, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the sub-image is 7x6, the last column of blocks has one pixel
+	expected := &image.Gray{Rect: image.Rect(0, 0, 3, 2), Stride: 3, Pix: []uint8{
+		30, 33, 34,
+		57, 60, 61,
+	}}
+	utils.CompareGrayImages(t, expected, res)
+	same, err := DownscaleMaxGray(img, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, img, same)
+	if _, err := DownscaleMaxGray(img, 0); err == nil {
+		t.Error("Expected error for a factor of 0")
+	}
+}
+
+// -------------------------------------------------------------------------------
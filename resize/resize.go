@@ -23,6 +23,7 @@ const (
 )
 
 func resizeNearestGray(img *image.Gray, fx float64, fy float64) (*image.Gray, error) {
+	origin := img.Bounds().Min
 	oldSize := img.Bounds().Size()
 	newSize := image.Point{X: int(float64(oldSize.X) * fx), Y: int(float64(oldSize.Y) * fy)}
 	newImg := image.NewGray(image.Rect(0, 0, newSize.X, newSize.Y))
@@ -41,54 +42,27 @@ func resizeNearestGray(img *image.Gray, fx float64, fy float64) (*image.Gray, er
 		} else {
 			oldY = int(oldYTemp)
 		}
-		newImg.SetGray(x, y, img.GrayAt(oldX, oldY))
+		newImg.SetGray(x, y, img.GrayAt(origin.X+utils.ClampInt(oldX, 0, oldSize.X-1), origin.Y+utils.ClampInt(oldY, 0, oldSize.Y-1)))
 	})
 	return newImg, nil
 }
 
-func resizeLinearGray(img *image.Gray, fx float64, fy float64) (*image.Gray, error) {
-	res, err := resizeHorizontalGray(img, fx, NewLinear())
+// resizeSeparableGray scales the image horizontally and then vertically with the given filter, the horizontally scaled
+// intermediate image is taken from the pool, which may be nil.
+func resizeSeparableGray(img *image.Gray, fx float64, fy float64, filter Filter, pool *utils.BufferPool) (*image.Gray, error) {
+	tmp, err := resizeHorizontalGray(img, fx, filter, pool)
 	if err != nil {
 		return nil, err
 	}
-	res, err = resizeVerticalGray(res, fy, NewLinear())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
-}
-
-func resizeCatmullRomGray(img *image.Gray, fx float64, fy float64) (*image.Gray, error) {
-	res, err := resizeHorizontalGray(img, fx, NewCatmullRom())
-	if err != nil {
-		return nil, err
-	}
-	res, err = resizeVerticalGray(res, fy, NewCatmullRom())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
-}
-
-func resizeLanczosGray(img *image.Gray, fx float64, fy float64) (*image.Gray, error) {
-	res, err := resizeHorizontalGray(img, fx, NewLanczos())
-	if err != nil {
-		return nil, err
-	}
-	res, err = resizeVerticalGray(res, fy, NewLanczos())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
+	defer pool.PutGray(tmp)
+	return resizeVerticalGray(tmp, fy, filter)
 }
 
-func resizeHorizontalGray(img *image.Gray, fx float64, filter Filter) (*image.Gray, error) {
+func resizeHorizontalGray(img *image.Gray, fx float64, filter Filter, pool *utils.BufferPool) (*image.Gray, error) {
+	origin := img.Bounds().Min
 	originalSize := img.Bounds().Size()
 	newWidth := int(float64(originalSize.X) * fx)
-	res := image.NewGray(image.Rect(0, 0, newWidth, originalSize.Y))
+	res := pool.NewGray(image.Rect(0, 0, newWidth, originalSize.Y))
 	dfx := 1 / fx
 
 	radius := math.Ceil(fx * filter.GetS())
@@ -101,7 +75,7 @@ func resizeHorizontalGray(img *image.Gray, fx float64, filter Filter) (*image.Gr
 			var sum float64
 			for i := start; i < end; i++ {
 				filterValue := filter.Interpolate(float64(i)-ix) / fx
-				pix := img.GrayAt(i, y)
+				pix := img.GrayAt(origin.X+i, origin.Y+y)
 				fPix += float64(pix.Y) * filterValue
 				sum += filterValue
 			}
@@ -112,6 +86,7 @@ func resizeHorizontalGray(img *image.Gray, fx float64, filter Filter) (*image.Gr
 }
 
 func resizeVerticalGray(img *image.Gray, fy float64, filter Filter) (*image.Gray, error) {
+	origin := img.Bounds().Min
 	originalSize := img.Bounds().Size()
 	newHeight := int(float64(originalSize.Y) * fy)
 	res := image.NewGray(image.Rect(0, 0, originalSize.X, newHeight))
@@ -127,7 +102,7 @@ func resizeVerticalGray(img *image.Gray, fy float64, filter Filter) (*image.Gray
 			var fPix float64
 			for i := start; i < end; i++ {
 				filterValue := filter.Interpolate(float64(i)-iy) / fy
-				pix := img.GrayAt(x, i)
+				pix := img.GrayAt(origin.X+x, origin.Y+i)
 				fPix += float64(pix.Y) * filterValue
 				sum += filterValue
 			}
@@ -138,6 +113,7 @@ func resizeVerticalGray(img *image.Gray, fy float64, filter Filter) (*image.Gray
 }
 
 func resizeNearestRGBA(img *image.RGBA, fx float64, fy float64) (*image.RGBA, error) {
+	origin := img.Bounds().Min
 	oldSize := img.Bounds().Size()
 	newSize := image.Point{X: int(float64(oldSize.X) * fx), Y: int(float64(oldSize.Y) * fy)}
 	newImg := image.NewRGBA(image.Rect(0, 0, newSize.X, newSize.Y))
@@ -156,54 +132,23 @@ func resizeNearestRGBA(img *image.RGBA, fx float64, fy float64) (*image.RGBA, er
 		} else {
 			oldY = int(oldYTemp)
 		}
-		newImg.SetRGBA(x, y, img.RGBAAt(oldX, oldY))
+		newImg.SetRGBA(x, y, img.RGBAAt(origin.X+utils.ClampInt(oldX, 0, oldSize.X-1), origin.Y+utils.ClampInt(oldY, 0, oldSize.Y-1)))
 	})
 	return newImg, nil
 }
 
-func resizeLinearRGBA(img *image.RGBA, fx float64, fy float64) (*image.RGBA, error) {
-	res, err := resizeHorizontalRGBA(img, fx, NewLinear())
-	if err != nil {
-		return nil, err
-	}
-	res, err = resizeVerticalRGBA(res, fy, NewLinear())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
-}
-
-func resizeCatmullRomRGBA(img *image.RGBA, fx float64, fy float64) (*image.RGBA, error) {
-	res, err := resizeHorizontalRGBA(img, fx, NewCatmullRom())
-	if err != nil {
-		return nil, err
-	}
-	res, err = resizeVerticalRGBA(res, fy, NewCatmullRom())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
+// resizeSeparableRGBA scales the image horizontally and then vertically with the given filter, the horizontally scaled
+// intermediate image is taken from the pool, which may be nil.
+func resizeSeparableRGBA(img *image.RGBA, fx float64, fy float64, filter Filter, pool *utils.BufferPool) (*image.RGBA, error) {
+	tmp := resizeHorizontalRGBAToWidth(img, int(float64(img.Bounds().Dx())*fx), fx, filter, pool)
+	defer pool.PutRGBA(tmp)
+	return resizeVerticalRGBA(tmp, fy, filter)
 }
 
-func resizeLanczosRGBA(img *image.RGBA, fx float64, fy float64) (*image.RGBA, error) {
-	res, err := resizeHorizontalRGBA(img, fx, NewLanczos())
-	if err != nil {
-		return nil, err
-	}
-	res, err = resizeVerticalRGBA(res, fy, NewLanczos())
-	if err != nil {
-		return nil, err
-	}
-
-	return res, nil
-}
-
-func resizeHorizontalRGBA(img *image.RGBA, fx float64, filter Filter) (*image.RGBA, error) {
+func resizeHorizontalRGBAToWidth(img *image.RGBA, newWidth int, fx float64, filter Filter, pool *utils.BufferPool) *image.RGBA {
+	origin := img.Bounds().Min
 	originalSize := img.Bounds().Size()
-	newWidth := int(float64(originalSize.X) * fx)
-	res := image.NewRGBA(image.Rect(0, 0, newWidth, originalSize.Y))
+	res := pool.NewRGBA(image.Rect(0, 0, newWidth, originalSize.Y))
 	dfx := 1 / fx
 
 	radius := math.Ceil(fx * filter.GetS())
@@ -219,7 +164,7 @@ func resizeHorizontalRGBA(img *image.RGBA, fx float64, filter Filter) (*image.RG
 			var sum float64
 			for i := start; i < end; i++ {
 				filterValue := filter.Interpolate(float64(i)-ix) / fx
-				pix := img.RGBAAt(i, y)
+				pix := img.RGBAAt(origin.X+i, origin.Y+y)
 				fPixR += float64(pix.R) * filterValue
 				fPixG += float64(pix.G) * filterValue
 				fPixB += float64(pix.B) * filterValue
@@ -232,21 +177,30 @@ func resizeHorizontalRGBA(img *image.RGBA, fx float64, filter Filter) (*image.RG
 				A: uint8(utils.ClampF64(fPixA/sum+0.5, 0, 255))})
 		}
 	}
-	return res, nil
+	return res
 }
 
 func resizeVerticalRGBA(img *image.RGBA, fy float64, filter Filter) (*image.RGBA, error) {
 	originalSize := img.Bounds().Size()
 	newHeight := int(float64(originalSize.Y) * fy)
 	res := image.NewRGBA(image.Rect(0, 0, originalSize.X, newHeight))
+	resizeVerticalRGBAInto(res, res.Bounds(), res.Bounds(), img, fy, filter)
+	return res, nil
+}
+
+// resizeVerticalRGBAInto scales img vertically to the height of dstRect and writes the rows into dst starting at
+// dstRect.Min, only the pixels inside of clip are written. The width of img has to match the width of dstRect.
+func resizeVerticalRGBAInto(dst *image.RGBA, dstRect image.Rectangle, clip image.Rectangle, img *image.RGBA, fy float64, filter Filter) {
+	origin := img.Bounds().Min
+	originalSize := img.Bounds().Size()
 	dfy := 1 / fy
 
 	radius := math.Ceil(fy * filter.GetS())
-	for y := 0; y < newHeight; y++ {
+	for y := clip.Min.Y - dstRect.Min.Y; y < clip.Max.Y-dstRect.Min.Y; y++ {
 		iy := (float64(y)+0.5)*dfy - 0.5
 		start := utils.ClampInt(int(iy-radius+0.5), 0, originalSize.Y)
 		end := utils.ClampInt(int(iy+radius), 0, originalSize.Y)
-		for x := 0; x < originalSize.X; x++ {
+		for x := clip.Min.X - dstRect.Min.X; x < clip.Max.X-dstRect.Min.X; x++ {
 			var fPixR float64
 			var fPixG float64
 			var fPixB float64
@@ -254,42 +208,43 @@ func resizeVerticalRGBA(img *image.RGBA, fy float64, filter Filter) (*image.RGBA
 			var sum float64
 			for i := start; i < end; i++ {
 				filterValue := filter.Interpolate(float64(i)-iy) / fy
-				pix := img.RGBAAt(x, i)
+				pix := img.RGBAAt(origin.X+x, origin.Y+i)
 				fPixR += float64(pix.R) * filterValue
 				fPixG += float64(pix.G) * filterValue
 				fPixB += float64(pix.B) * filterValue
 				fPixA += float64(pix.A) * filterValue
 				sum += filterValue
 			}
-			res.SetRGBA(x, y, color.RGBA{R: uint8(utils.ClampF64(fPixR/sum+0.5, 0, 255)),
+			dst.SetRGBA(dstRect.Min.X+x, dstRect.Min.Y+y, color.RGBA{R: uint8(utils.ClampF64(fPixR/sum+0.5, 0, 255)),
 				G: uint8(utils.ClampF64(fPixG/sum+0.5, 0, 255)),
 				B: uint8(utils.ClampF64(fPixB/sum+0.5, 0, 255)),
 				A: uint8(utils.ClampF64(fPixA/sum+0.5, 0, 255))})
 		}
 	}
-	return res, nil
 }
 
 // ResizeGray resizes an grayscale (Gray) image.
 // Input parameters: rbga imaga which will be resized; fx, fy scaling factors, their value has to be a positive float,
 // the new size of the image will be computed as originalWidth * fx and originalHeight * fy; interpolation method,
-// currently the following methods are supported: InterNearest, InterLinear, InterCatmullRom, InterLanczos.
+// currently the following methods are supported: InterNearest, InterLinear, InterCatmullRom, InterLanczos. With
+// utils.WithPool the intermediate image of the two pass interpolations is taken from the pool.
 // Example of usage:
 //
 //	res, err := resize.ResizeGray(img, 2.5, 3.5, resize.InterLinear)
-func ResizeGray(img *image.Gray, fx float64, fy float64, interpolation Interpolation) (*image.Gray, error) {
+func ResizeGray(img *image.Gray, fx float64, fy float64, interpolation Interpolation, opts ...utils.Option) (*image.Gray, error) {
 	if fx < 0 || fy < 0 {
 		return nil, errors.New("scale value should be greater then 0")
 	}
+	pool := utils.ApplyOptions(opts).Pool
 	switch interpolation {
 	case InterNearest:
 		return resizeNearestGray(img, fx, fy)
 	case InterLinear:
-		return resizeLinearGray(img, fx, fy)
+		return resizeSeparableGray(img, fx, fy, NewLinear(), pool)
 	case InterCatmullRom:
-		return resizeCatmullRomGray(img, fx, fy)
+		return resizeSeparableGray(img, fx, fy, NewCatmullRom(), pool)
 	case InterLanczos:
-		return resizeLanczosGray(img, fx, fy)
+		return resizeSeparableGray(img, fx, fy, NewLanczos(), pool)
 	}
 	return nil, errors.New("invalid interpolation method")
 }
@@ -297,23 +252,25 @@ func ResizeGray(img *image.Gray, fx float64, fy float64, interpolation Interpola
 // ResizeRGBA resizes an RGBA image.
 // Input parameters: rbga imaga which will be resized; fx, fy scaling factors, their value has to be a positive float,
 // the new size of the image will be computed as originalWidth * fx and originalHeight * fy; interpolation method,
-// currently the following methods are supported: InterNearest, InterLinear, InterCatmullRom, InterLanczos.
+// currently the following methods are supported: InterNearest, InterLinear, InterCatmullRom, InterLanczos. With
+// utils.WithPool the intermediate image of the two pass interpolations is taken from the pool.
 // Example of usage:
 //
 //	res, err := resize.ResizeRGBA(img, 2.5, 3.5, resize.InterLinear)
-func ResizeRGBA(img *image.RGBA, fx float64, fy float64, interpolation Interpolation) (*image.RGBA, error) {
+func ResizeRGBA(img *image.RGBA, fx float64, fy float64, interpolation Interpolation, opts ...utils.Option) (*image.RGBA, error) {
 	if fx < 0 || fy < 0 {
 		return nil, errors.New("scale value should be greater then 0")
 	}
+	pool := utils.ApplyOptions(opts).Pool
 	switch interpolation {
 	case InterNearest:
 		return resizeNearestRGBA(img, fx, fy)
 	case InterLinear:
-		return resizeLinearRGBA(img, fx, fy)
+		return resizeSeparableRGBA(img, fx, fy, NewLinear(), pool)
 	case InterCatmullRom:
-		return resizeCatmullRomRGBA(img, fx, fy)
+		return resizeSeparableRGBA(img, fx, fy, NewCatmullRom(), pool)
 	case InterLanczos:
-		return resizeLanczosRGBA(img, fx, fy)
+		return resizeSeparableRGBA(img, fx, fy, NewLanczos(), pool)
 	}
 	return nil, errors.New("invalid interpolation method")
 }
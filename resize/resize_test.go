@@ -2,10 +2,84 @@ package resize
 
 import (
 	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"math/rand"
 	"testing"
 )
 
+// ---------------------------------Unit tests------------------------------------
+func Test_ResizeGray_SubImage(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	parent := image.NewGray(image.Rect(0, 0, 50, 40))
+	rnd.Read(parent.Pix)
+	sub := parent.SubImage(image.Rect(7, 5, 38, 29)).(*image.Gray)
+	copied := utils.CloneGray(sub)
+	for _, interpolation := range []Interpolation{InterNearest, InterLinear, InterCatmullRom, InterLanczos} {
+		for _, f := range []float64{0.5, 1.5, 2} {
+			expected, err := ResizeGray(copied, f, f, interpolation)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			actual, err := ResizeGray(sub, f, f, interpolation)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			utils.CompareGrayImages(t, expected, actual)
+		}
+	}
+	expected, err := ResizeGrayAntiAlias(copied, 0.3, 0.3, InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	actual, err := ResizeGrayAntiAlias(sub, 0.3, 0.3, InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, expected, actual)
+}
+
+func Test_ResizeRGBA_SubImage(t *testing.T) {
+	rnd := rand.New(rand.NewSource(12))
+	parent := image.NewRGBA(image.Rect(0, 0, 50, 40))
+	rnd.Read(parent.Pix)
+	sub := parent.SubImage(image.Rect(7, 5, 38, 29)).(*image.RGBA)
+	copied := utils.CloneRGBA(sub)
+	for _, interpolation := range []Interpolation{InterNearest, InterLinear, InterCatmullRom, InterLanczos} {
+		for _, f := range []float64{0.5, 1.5, 2} {
+			expected, err := ResizeRGBA(copied, f, f, interpolation)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			actual, err := ResizeRGBA(sub, f, f, interpolation)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			utils.CompareRGBAImages(t, expected, actual)
+		}
+		expected := image.NewRGBA(image.Rect(0, 0, 40, 40))
+		if err := ResizeIntoRGBA(expected, image.Rect(5, 5, 30, 35), copied, interpolation); err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual := image.NewRGBA(image.Rect(0, 0, 40, 40))
+		if err := ResizeIntoRGBA(actual, image.Rect(5, 5, 30, 35), sub, interpolation); err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareRGBAImages(t, expected, actual)
+	}
+	expected, err := ResizeRGBAAntiAlias(copied, 0.3, 0.3, InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	actual, err := ResizeRGBAAntiAlias(sub, 0.3, 0.3, InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImages(t, expected, actual)
+}
+
+// ----------------------------------------------------------------------------------
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/girl.jpg"
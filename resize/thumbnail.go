@@ -0,0 +1,79 @@
+package resize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// ThumbnailRGBA creates a small preview of an RGBA image in a single call. The image is scaled so that its longer side
+// is maxSize pixels and the aspect ratio is preserved. If square is true the thumbnail is maxSize x maxSize: the image
+// is scaled so that its shorter side fills the square and the centered part of the longer side is kept. The pixels
+// are computed by area averaging, every output pixel is the average of the input pixels under it weighted by the
+// overlapping area, so fine details do not alias when the image is scaled down. Returns an error if maxSize is not
+// positive or the image is empty.
+// Example of usage:
+//
+//	thumbnail, err := resize.ThumbnailRGBA(img, 128, true)
+func ThumbnailRGBA(img *image.RGBA, maxSize int, square bool) (*image.RGBA, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the size of the thumbnail should be positive")
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, errors.New("empty image")
+	}
+	if square {
+		// cropping the centered square before the scaling gives the same pixels as cropping after it
+		side := size.X
+		if size.Y < side {
+			side = size.Y
+		}
+		min := bounds.Min.Add(image.Point{X: (size.X - side) / 2, Y: (size.Y - side) / 2})
+		crop := img.SubImage(image.Rectangle{Min: min, Max: min.Add(image.Point{X: side, Y: side})}).(*image.RGBA)
+		return resizeAreaRGBA(crop, maxSize, maxSize), nil
+	}
+	longer := size.X
+	if size.Y > longer {
+		longer = size.Y
+	}
+	scale := float64(maxSize) / float64(longer)
+	newWidth := utils.ClampInt(int(math.Round(float64(size.X)*scale)), 1, maxSize)
+	newHeight := utils.ClampInt(int(math.Round(float64(size.Y)*scale)), 1, maxSize)
+	return resizeAreaRGBA(img, newWidth, newHeight), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// resizeAreaRGBA resizes an RGBA image by area averaging of the premultiplied channels (see areaTaps).
+func resizeAreaRGBA(img *image.RGBA, newWidth, newHeight int) *image.RGBA {
+	bounds := img.Bounds()
+	size := bounds.Size()
+	tapsX := areaTaps(size.X, newWidth)
+	tapsY := areaTaps(size.Y, newHeight)
+	// horizontal pass, 4 channels per output column
+	rows := make([][]float64, size.Y)
+	utils.ParallelForEachRow(image.Point{X: newWidth, Y: size.Y}, func(y int) {
+		rows[y] = make([]float64, 4*newWidth)
+		line := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x, taps := range tapsX {
+			for _, t := range taps {
+				for c := 0; c < 4; c++ {
+					rows[y][4*x+c] += float64(line[4*t.index+c]) * t.weight
+				}
+			}
+		}
+	})
+	res := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	utils.ParallelForEachPixel(image.Point{X: newWidth, Y: newHeight}, func(x, y int) {
+		for c := 0; c < 4; c++ {
+			var sum float64
+			for _, t := range tapsY[y] {
+				sum += rows[t.index][4*x+c] * t.weight
+			}
+			res.Pix[y*res.Stride+4*x+c] = uint8(utils.ClampF64(math.Round(sum), utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	})
+	return res
+}
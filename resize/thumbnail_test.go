@@ -0,0 +1,96 @@
+package resize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ThumbnailRGBA(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	// three vertical stripes: the quarter on the left, the center half and the quarter on the right
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			switch {
+			case x < 100:
+				img.SetRGBA(x, y, red)
+			case x < 300:
+				img.SetRGBA(x, y, green)
+			default:
+				img.SetRGBA(x, y, blue)
+			}
+		}
+	}
+	res, err := ThumbnailRGBA(img, 100, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 100, 50); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	for _, c := range []struct {
+		x        int
+		expected color.RGBA
+	}{{0, red}, {24, red}, {25, green}, {74, green}, {75, blue}, {99, blue}} {
+		if actual := res.RGBAAt(c.x, 25); actual != c.expected {
+			t.Errorf("Expected value: %v - actual value: %v at: %d 25", c.expected, actual, c.x)
+		}
+	}
+	// the center crop of the square thumbnail covers only the center stripe
+	square, err := ThumbnailRGBA(img, 100, true)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 100, 100); square.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, square.Bounds())
+	}
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			if actual := square.RGBAAt(x, y); actual != green {
+				t.Fatalf("Expected value: %v - actual value: %v at: %d %d", green, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_ThumbnailRGBA_AreaAveraging(t *testing.T) {
+	// a fine checkerboard averages to gray instead of aliasing into stripes or a solid color
+	img := image.NewRGBA(image.Rect(10, 20, 310, 170))
+	for y := 20; y < 170; y++ {
+		for x := 10; x < 310; x++ {
+			if (x+y)%2 == 0 {
+				img.SetRGBA(x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{A: 255})
+			}
+		}
+	}
+	res, err := ThumbnailRGBA(img, 64, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 64, 32); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	for i := 0; i < len(res.Pix); i += 4 {
+		if v := res.Pix[i]; v < 110 || v > 145 || res.Pix[i+3] != 255 {
+			t.Fatalf("Expected gray - actual value: %v at index: %d", res.Pix[i:i+4], i/4)
+		}
+	}
+}
+
+func Test_ThumbnailRGBA_Invalid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	if _, err := ThumbnailRGBA(img, 0, false); err == nil {
+		t.Error("Expected error for a size of 0")
+	}
+	if _, err := ThumbnailRGBA(image.NewRGBA(image.Rect(0, 0, 0, 5)), 10, true); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,177 @@
+package rle
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// EncodeRLE encodes a binary mask with the run-length encoding of the COCO dataset, so the result can be used as the
+// "counts" string of a COCO RLE object (together with the size [height, width] of the mask) and it can be decoded by
+// the COCO tooling. The pixels are visited in column-major order, the runs alternate between background and
+// foreground starting with background, and the run lengths are written with the compressed COCO string format.
+// The mask must be binary: every pixel has to be 0 (background) or 255 (foreground), any other value is reported as
+// an error instead of being thresholded silently.
+// Example of usage:
+//
+//	counts, err := rle.EncodeRLE(mask)
+func EncodeRLE(img *image.Gray) ([]byte, error) {
+	size := img.Bounds().Size()
+	var counts []int64
+	var run int64
+	var current uint8 = utils.MinUint8
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			p := img.Pix[y*img.Stride+x]
+			if p != utils.MinUint8 && p != utils.MaxUint8 {
+				return nil, errors.New("the mask is not binary")
+			}
+			if p != current {
+				counts = append(counts, run)
+				run = 0
+				current = p
+			}
+			run++
+		}
+	}
+	counts = append(counts, run)
+	return countsToBytes(counts), nil
+}
+
+// DecodeRLE decodes a COCO run-length encoded mask with the given size into a binary mask where the foreground is 255
+// and the background is 0. Returns an error if the data is corrupted or the runs do not cover the whole mask.
+// Example of usage:
+//
+//	mask, err := rle.DecodeRLE(counts, 640, 480)
+func DecodeRLE(data []byte, width, height int) (*image.Gray, error) {
+	if width < 0 || height < 0 {
+		return nil, errors.New("negative mask size")
+	}
+	counts, err := bytesToCounts(data)
+	if err != nil {
+		return nil, err
+	}
+	res := image.NewGray(image.Rect(0, 0, width, height))
+	var position int64
+	total := int64(width) * int64(height)
+	for i, c := range counts {
+		if c < 0 || position+c > total {
+			return nil, errors.New("the runs do not match the size of the mask")
+		}
+		if i%2 == 1 {
+			for j := position; j < position+c; j++ {
+				res.Pix[int(j%int64(height))*res.Stride+int(j/int64(height))] = utils.MaxUint8
+			}
+		}
+		position += c
+	}
+	if position != total {
+		return nil, errors.New("the runs do not match the size of the mask")
+	}
+	return res, nil
+}
+
+// Area returns the number of foreground pixels of a COCO run-length encoded mask without decoding it.
+// Example of usage:
+//
+//	area, err := rle.Area(counts)
+func Area(data []byte) (int, error) {
+	counts, err := bytesToCounts(data)
+	if err != nil {
+		return 0, err
+	}
+	var area int64
+	for i := 1; i < len(counts); i += 2 {
+		area += counts[i]
+	}
+	return int(area), nil
+}
+
+// ToBbox returns the bounding box of the foreground of a COCO run-length encoded mask with the given height without
+// decoding it. The returned rectangle is empty if the mask has no foreground pixels.
+// Example of usage:
+//
+//	bbox, err := rle.ToBbox(counts, 480)
+func ToBbox(data []byte, height int) (image.Rectangle, error) {
+	if height <= 0 {
+		return image.Rectangle{}, errors.New("the height of the mask should be positive")
+	}
+	counts, err := bytesToCounts(data)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	h := int64(height)
+	var bbox image.Rectangle
+	var position int64
+	for i, c := range counts {
+		if i%2 == 1 && c > 0 {
+			start, end := position, position+c-1
+			x0, x1 := int(start/h), int(end/h)
+			y0, y1 := int(start%h), int(end%h)
+			if x0 != x1 {
+				// the run continues in the next column, so it covers the whole height of the mask
+				y0, y1 = 0, height-1
+			}
+			bbox = bbox.Union(image.Rect(x0, y0, x1+1, y1+1))
+		}
+		position += c
+	}
+	return bbox, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// countsToBytes writes the run lengths with the compressed string format of the COCO API: every value is split into 5
+// bit groups with a continuation bit and an offset of 48, from the third run the values are stored as differences to
+// the run two positions earlier.
+func countsToBytes(counts []int64) []byte {
+	var res []byte
+	for i, c := range counts {
+		x := c
+		if i > 2 {
+			x -= counts[i-2]
+		}
+		for more := true; more; {
+			b := x & 0x1f
+			x >>= 5
+			if b&0x10 != 0 {
+				more = x != -1
+			} else {
+				more = x != 0
+			}
+			if more {
+				b |= 0x20
+			}
+			res = append(res, byte(b+48))
+		}
+	}
+	return res
+}
+
+func bytesToCounts(data []byte) ([]int64, error) {
+	var counts []int64
+	for p := 0; p < len(data); {
+		var x int64
+		k := uint(0)
+		for more := true; more; {
+			if p >= len(data) {
+				return nil, errors.New("truncated run-length data")
+			}
+			c := int64(data[p]) - 48
+			if c < 0 || c > 0x3f || k > 60 {
+				return nil, errors.New("invalid run-length data")
+			}
+			x |= (c & 0x1f) << (5 * k)
+			more = c&0x20 != 0
+			p++
+			k++
+			if !more && c&0x10 != 0 {
+				x |= -1 << (5 * k)
+			}
+		}
+		if len(counts) > 2 {
+			x += counts[len(counts)-2]
+		}
+		counts = append(counts, x)
+	}
+	return counts, nil
+}
@@ -0,0 +1,120 @@
+package rle
+
+import (
+	"bytes"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func setupTestCaseRandomMask(rnd *rand.Rand, width, height int, density float64) *image.Gray {
+	mask := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range mask.Pix {
+		if rnd.Float64() < density {
+			mask.Pix[i] = 0xFF
+		}
+	}
+	return mask
+}
+
+func Test_EncodeRLE_KnownStrings(t *testing.T) {
+	mask := image.NewGray(image.Rect(0, 0, 2, 2))
+	mask.SetGray(0, 1, color.Gray{Y: 0xFF})
+	mask.SetGray(1, 0, color.Gray{Y: 0xFF})
+	cases := []struct {
+		mask     *image.Gray
+		expected string
+	}{
+		// column-major runs [1 2 1]
+		{mask, "121"},
+		// runs [5 2 3 1], the last run is stored as the difference -1 to the run two positions earlier
+		{&image.Gray{Rect: image.Rect(0, 0, 1, 11), Stride: 1, Pix: []uint8{0, 0, 0, 0, 0, 0xFF, 0xFF, 0, 0, 0, 0xFF}}, "523O"},
+		// a run of 100 needs two characters
+		{image.NewGray(image.Rect(0, 0, 10, 10)), "T3"},
+	}
+	for _, c := range cases {
+		actual, err := EncodeRLE(c.mask)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if string(actual) != c.expected {
+			t.Errorf("Expected counts: %s - actual counts: %s", c.expected, actual)
+		}
+	}
+}
+
+func Test_RLE_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		width, height := 1+rnd.Intn(60), 1+rnd.Intn(60)
+		mask := setupTestCaseRandomMask(rnd, width, height, rnd.Float64())
+		data, err := EncodeRLE(mask)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		decoded, err := DecodeRLE(data, width, height)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, mask, decoded)
+
+		expectedArea := 0
+		var expectedBbox image.Rectangle
+		utils.ForEachPixel(mask.Bounds().Size(), func(x, y int) {
+			if mask.GrayAt(x, y).Y != 0 {
+				expectedArea++
+				expectedBbox = expectedBbox.Union(image.Rect(x, y, x+1, y+1))
+			}
+		})
+		if area, err := Area(data); err != nil || area != expectedArea {
+			t.Errorf("Expected area: %d - actual area: %d (%v)", expectedArea, area, err)
+		}
+		if bbox, err := ToBbox(data, height); err != nil || bbox != expectedBbox {
+			t.Errorf("Expected bbox: %v - actual bbox: %v (%v)", expectedBbox, bbox, err)
+		}
+	}
+}
+
+func Test_RLE_Invalid(t *testing.T) {
+	if _, err := EncodeRLE(&image.Gray{Rect: image.Rect(0, 0, 2, 1), Stride: 2, Pix: []uint8{0, 128}}); err == nil {
+		t.Error("Expected error for non-binary mask")
+	}
+	if _, err := DecodeRLE([]byte("121"), 3, 3); err == nil {
+		t.Error("Expected error for runs which do not cover the mask")
+	}
+	if _, err := DecodeRLE([]byte("T"), 10, 10); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+	if _, err := Area([]byte{0x20}); err == nil {
+		t.Error("Expected error for invalid characters")
+	}
+}
+
+func Test_RLE_SmallerThenPNG(t *testing.T) {
+	mask := image.NewGray(image.Rect(0, 0, 640, 480))
+	for x := 200; x < 260; x++ {
+		for y := 100; y < 180; y++ {
+			if (x-230)*(x-230)+(y-140)*(y-140) < 30*30 {
+				mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	data, err := EncodeRLE(mask)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, mask); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(data) >= buf.Len() {
+		t.Errorf("Expected RLE to be smaller then PNG - RLE: %d bytes, PNG: %d bytes", len(data), buf.Len())
+	}
+	t.Logf("RLE: %d bytes, PNG: %d bytes", len(data), buf.Len())
+}
+
+// -------------------------------------------------------------------------------
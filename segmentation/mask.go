@@ -0,0 +1,32 @@
+package segmentation
+
+import (
+	"github.com/yafeiliu/imger/morphology"
+	"github.com/yafeiliu/imger/threshold"
+	"image"
+)
+
+// CleanMaskGray builds a clean binary mask from a grayscale image: the pixels brighter then the threshold become 255
+// and the others 0, then the mask is opened with openKernel to remove the speckles smaller then the element and closed
+// with closeKernel to fill the small holes (see morphology.OpenGray and morphology.CloseGray). A nil kernel skips the
+// corresponding step. Returns an error if one of the structuring elements is invalid.
+// Example of usage:
+//
+//	mask, err := segmentation.CleanMaskGray(img, 128, morphology.Square3x3(), morphology.Square3x3())
+func CleanMaskGray(img *image.Gray, t uint8, openKernel, closeKernel [][]uint8) (*image.Gray, error) {
+	mask, err := threshold.Threshold(img, t, threshold.ThreshBinary)
+	if err != nil {
+		return nil, err
+	}
+	if openKernel != nil {
+		if mask, err = morphology.OpenGray(mask, openKernel); err != nil {
+			return nil, err
+		}
+	}
+	if closeKernel != nil {
+		if mask, err = morphology.CloseGray(mask, closeKernel); err != nil {
+			return nil, err
+		}
+	}
+	return mask, nil
+}
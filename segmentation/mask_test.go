@@ -0,0 +1,72 @@
+package segmentation
+
+import (
+	"github.com/yafeiliu/imger/morphology"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_CleanMaskGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	for i := range img.Pix {
+		// dark background noise below the threshold
+		img.Pix[i] = uint8(i*37%90) + 10
+	}
+	region := image.Rect(10, 8, 30, 22)
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(160 + (x*y)%90)})
+		}
+	}
+	// speckles outside of the region and holes inside of it
+	speckles := []image.Point{{2, 2}, {36, 4}, {5, 26}, {34, 27}}
+	holes := []image.Point{{15, 12}, {22, 17}, {26, 11}}
+	for _, p := range speckles {
+		img.SetGray(p.X, p.Y, color.Gray{Y: 250})
+	}
+	for _, p := range holes {
+		img.SetGray(p.X, p.Y, color.Gray{Y: 20})
+	}
+
+	res, err := CleanMaskGray(img, 128, morphology.Square3x3(), morphology.Square3x3())
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			expected := uint8(0)
+			if (image.Point{X: x, Y: y}).In(region) {
+				expected = 255
+			}
+			if actual := res.GrayAt(x, y).Y; actual != expected {
+				t.Fatalf("Expected value: %d - actual value: %d at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_CleanMaskGray_SkipSteps(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 9, 9))
+	img.SetGray(4, 4, color.Gray{Y: 200})
+	res, err := CleanMaskGray(img, 100, nil, nil)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.GrayAt(4, 4).Y != 255 {
+		t.Errorf("Expected the single pixel to be kept without an opening")
+	}
+	res, err = CleanMaskGray(img, 100, morphology.Square3x3(), nil)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.GrayAt(4, 4).Y != 0 {
+		t.Errorf("Expected the single pixel to be removed by the opening")
+	}
+	if _, err := CleanMaskGray(img, 100, [][]uint8{{1, 1}}, nil); err == nil {
+		t.Error("Expected error for an invalid structuring element")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,137 @@
+package segmentation
+
+import (
+	"container/heap"
+	"errors"
+	"image"
+)
+
+// WatershedBoundary is the label assigned to the pixels which separate two different regions.
+const WatershedBoundary = -1
+
+// Watershed segments a grayscale image using the marker-based watershed algorithm (priority-flood). The img argument
+// is the relief which will be flooded (usually a gradient or an inverted distance transform image), the markers
+// argument contains the seeds of the regions, indexed as markers[x][y]. A positive value marks a seed with the given
+// label, 0 marks an unknown pixel. The regions grow from the seeds in the order of the pixel intensities, the pixels
+// where two different regions meet are marked with WatershedBoundary.
+// Example of usage:
+//
+//	labels, err := segmentation.Watershed(gradient, markers)
+func Watershed(img *image.Gray, markers [][]int) ([][]int, error) {
+	size := img.Bounds().Size()
+	if len(markers) != size.X {
+		return nil, errors.New("the size of the markers does not match the size of the image")
+	}
+	labels := make([][]int, size.X)
+	for x := 0; x < size.X; x++ {
+		if len(markers[x]) != size.Y {
+			return nil, errors.New("the size of the markers does not match the size of the image")
+		}
+		labels[x] = make([]int, size.Y)
+		copy(labels[x], markers[x])
+	}
+
+	queued := make([][]bool, size.X)
+	for x := range queued {
+		queued[x] = make([]bool, size.Y)
+	}
+	queue := &pixelQueue{}
+	push := func(x, y int) {
+		for _, n := range neighbours4(x, y, size) {
+			if labels[n.X][n.Y] == 0 && !queued[n.X][n.Y] {
+				queued[n.X][n.Y] = true
+				heap.Push(queue, queuedPixel{point: n, value: img.GrayAt(n.X, n.Y).Y, order: queue.counter})
+				queue.counter++
+			}
+		}
+	}
+
+	seeds := 0
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			if labels[x][y] < 0 {
+				return nil, errors.New("negative marker value")
+			}
+			if labels[x][y] > 0 {
+				seeds++
+				push(x, y)
+			}
+		}
+	}
+	if seeds == 0 {
+		return nil, errors.New("markers do not contain any seed")
+	}
+
+	for queue.Len() > 0 {
+		p := heap.Pop(queue).(queuedPixel).point
+		label := 0
+		for _, n := range neighbours4(p.X, p.Y, size) {
+			l := labels[n.X][n.Y]
+			if l <= 0 {
+				continue
+			}
+			if label == 0 {
+				label = l
+			} else if label != l {
+				label = WatershedBoundary
+				break
+			}
+		}
+		labels[p.X][p.Y] = label
+		if label > 0 {
+			push(p.X, p.Y)
+		}
+	}
+	return labels, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func neighbours4(x int, y int, size image.Point) []image.Point {
+	res := make([]image.Point, 0, 4)
+	if x > 0 {
+		res = append(res, image.Point{X: x - 1, Y: y})
+	}
+	if y > 0 {
+		res = append(res, image.Point{X: x, Y: y - 1})
+	}
+	if x < size.X-1 {
+		res = append(res, image.Point{X: x + 1, Y: y})
+	}
+	if y < size.Y-1 {
+		res = append(res, image.Point{X: x, Y: y + 1})
+	}
+	return res
+}
+
+type queuedPixel struct {
+	point image.Point
+	value uint8
+	order int
+}
+
+// pixelQueue is a priority queue ordered by pixel intensity. Pixels with the same intensity are processed in the
+// order they were added.
+type pixelQueue struct {
+	items   []queuedPixel
+	counter int
+}
+
+func (q *pixelQueue) Len() int { return len(q.items) }
+
+func (q *pixelQueue) Less(i, j int) bool {
+	if q.items[i].value != q.items[j].value {
+		return q.items[i].value < q.items[j].value
+	}
+	return q.items[i].order < q.items[j].order
+}
+
+func (q *pixelQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *pixelQueue) Push(x interface{}) { q.items = append(q.items, x.(queuedPixel)) }
+
+func (q *pixelQueue) Pop() interface{} {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items = q.items[:n-1]
+	return item
+}
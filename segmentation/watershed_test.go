@@ -0,0 +1,66 @@
+package segmentation
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_Watershed_TwoDisks(t *testing.T) {
+	size := image.Point{X: 56, Y: 50}
+	c1 := image.Point{X: 20, Y: 25}
+	c2 := image.Point{X: 36, Y: 25}
+	radius := 12.0
+	img := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			d1 := math.Hypot(float64(x-c1.X), float64(y-c1.Y))
+			d2 := math.Hypot(float64(x-c2.X), float64(y-c2.Y))
+			d := math.Min(d1, d2)
+			if d > radius {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: uint8(d * 8)})
+			}
+		}
+	}
+	markers := make([][]int, size.X)
+	for x := range markers {
+		markers[x] = make([]int, size.Y)
+	}
+	markers[c1.X][c1.Y] = 1
+	markers[c2.X][c2.Y] = 2
+
+	labels, err := Watershed(img, markers)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for y := c1.Y - 6; y <= c1.Y+6; y++ {
+		for x := c1.X; x <= c2.X; x++ {
+			var expected int
+			switch {
+			case x < 28:
+				expected = 1
+			case x == 28:
+				expected = WatershedBoundary
+			default:
+				expected = 2
+			}
+			if labels[x][y] != expected {
+				t.Errorf("Expected label: %d - actual label: %d at: %d %d", expected, labels[x][y], x, y)
+			}
+		}
+	}
+}
+
+func Test_Watershed_InvalidMarkers(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 3, 3))
+	markers := [][]int{{0, 0, 0}, {0, 0, 0}}
+	if _, err := Watershed(img, markers); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,77 @@
+// Package stitch composes several images with known offsets into one canvas, e.g. the frames of a panorama which
+// were aligned with registration.PhaseCorrelate.
+package stitch
+
+import (
+	"errors"
+	"image"
+)
+
+// StitchRGBA places every image at its offset on a transparent canvas which is the union of the placed images. The
+// canvas starts at the origin, so an image with the smallest offset ends up at its top left corner. Without blending
+// the images are painted in the given order, so the later ones cover the earlier ones where they overlap. With
+// blending every pixel of the canvas is the average of the images covering it, which hides the seams between images
+// with a slightly different exposure. The averaging is done on the premultiplied channels, so a transparent pixel of
+// one image fades the others. Returns an error if no image is given or the number of offsets does not match.
+// Example of usage:
+//
+//	res, err := stitch.StitchRGBA([]*image.RGBA{left, right}, []image.Point{{0, 0}, {320, 4}}, true)
+func StitchRGBA(images []*image.RGBA, offsets []image.Point, blend bool) (*image.RGBA, error) {
+	if len(images) == 0 {
+		return nil, errors.New("at least one image has to be given")
+	}
+	if len(images) != len(offsets) {
+		return nil, errors.New("the number of offsets does not match the number of images")
+	}
+	canvas := image.Rectangle{}
+	for i, img := range images {
+		placed := image.Rectangle{Min: offsets[i], Max: offsets[i].Add(img.Bounds().Size())}
+		if i == 0 {
+			canvas = placed
+		} else {
+			canvas = canvas.Union(placed)
+		}
+	}
+	res := image.NewRGBA(image.Rect(0, 0, canvas.Dx(), canvas.Dy()))
+	if !blend {
+		for i, img := range images {
+			paste(res, img, offsets[i].Sub(canvas.Min))
+		}
+		return res, nil
+	}
+	sums := make([]int, len(res.Pix))
+	counts := make([]int, canvas.Dx()*canvas.Dy())
+	for i, img := range images {
+		origin := offsets[i].Sub(canvas.Min)
+		bounds := img.Bounds()
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				src := img.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+				dst := res.PixOffset(origin.X+x, origin.Y+y)
+				for c := 0; c < 4; c++ {
+					sums[dst+c] += int(img.Pix[src+c])
+				}
+				counts[dst/4]++
+			}
+		}
+	}
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		for c := 0; c < 4; c++ {
+			res.Pix[4*i+c] = uint8((sums[4*i+c] + count/2) / count)
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func paste(dst *image.RGBA, src *image.RGBA, origin image.Point) {
+	bounds := src.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		srcOffset := src.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		dstOffset := dst.PixOffset(origin.X, origin.Y+y)
+		copy(dst.Pix[dstOffset:dstOffset+4*bounds.Dx()], src.Pix[srcOffset:srcOffset+4*bounds.Dx()])
+	}
+}
@@ -0,0 +1,84 @@
+package stitch
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_StitchRGBA_Blend(t *testing.T) {
+	left := setupTestCaseStitch(20, 10, color.RGBA{R: 200, G: 40, B: 10, A: 255})
+	right := setupTestCaseStitch(20, 10, color.RGBA{R: 100, G: 61, B: 250, A: 255})
+	// the right image overlaps the right half of the left one and is shifted down by 2 rows
+	res, err := StitchRGBA([]*image.RGBA{left, right}, []image.Point{{0, 0}, {10, 2}}, true)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 30, 12); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	cases := []struct {
+		p        image.Point
+		expected color.RGBA
+	}{
+		{image.Point{X: 5, Y: 5}, left.RGBAAt(0, 0)},
+		{image.Point{X: 25, Y: 5}, right.RGBAAt(0, 0)},
+		{image.Point{X: 12, Y: 1}, left.RGBAAt(0, 0)},
+		{image.Point{X: 15, Y: 5}, color.RGBA{R: 150, G: 51, B: 130, A: 255}},
+		{image.Point{X: 19, Y: 9}, color.RGBA{R: 150, G: 51, B: 130, A: 255}},
+		// not covered by any image
+		{image.Point{X: 25, Y: 0}, color.RGBA{}},
+		{image.Point{X: 5, Y: 11}, color.RGBA{}},
+	}
+	for _, c := range cases {
+		if actual := res.RGBAAt(c.p.X, c.p.Y); actual != c.expected {
+			t.Errorf("Expected value: %v - actual value: %v at: %v", c.expected, actual, c.p)
+		}
+	}
+}
+
+func Test_StitchRGBA_NoBlend(t *testing.T) {
+	first := setupTestCaseStitch(8, 6, color.RGBA{R: 255, A: 255})
+	second := setupTestCaseStitch(8, 6, color.RGBA{B: 255, A: 255})
+	// negative offsets are moved to the origin of the canvas, the sub-image bounds do not matter
+	sub := second.SubImage(image.Rect(2, 1, 8, 6)).(*image.RGBA)
+	res, err := StitchRGBA([]*image.RGBA{first, sub}, []image.Point{{-4, -3}, {0, 0}}, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 10, 8); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	if actual := res.RGBAAt(3, 2); actual != first.RGBAAt(0, 0) {
+		t.Errorf("Expected the first image at 3 2 - actual value: %v", actual)
+	}
+	if actual := res.RGBAAt(5, 4); actual != second.RGBAAt(0, 0) {
+		t.Errorf("Expected the second image over the first one at 5 4 - actual value: %v", actual)
+	}
+	if actual := res.RGBAAt(9, 0); actual != (color.RGBA{}) {
+		t.Errorf("Expected a transparent pixel at 9 0 - actual value: %v", actual)
+	}
+}
+
+func Test_StitchRGBA_Errors(t *testing.T) {
+	img := setupTestCaseStitch(4, 4, color.RGBA{A: 255})
+	if _, err := StitchRGBA(nil, nil, true); err == nil {
+		t.Error("Expected error for no images")
+	}
+	if _, err := StitchRGBA([]*image.RGBA{img, img}, []image.Point{{0, 0}}, true); err == nil {
+		t.Error("Expected error for a missing offset")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseStitch(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
@@ -0,0 +1,97 @@
+package texture
+
+import (
+	"errors"
+	"image"
+)
+
+// GLCMGray computes the normalized gray-level co-occurrence matrix (GLCM) of a grayscale image for the given offset:
+// res[i][j] is the probability that a pixel with the value i has a pixel with the value j at the offset from it. The
+// matrix is symmetric, every pair is counted in both directions, so the offsets o and -o give the same matrix. The
+// matrix has 256x256 entries which sum up to 1. Returns an error if the offset is not smaller then the size of the
+// image, so no pair of pixels is found.
+// Example of usage:
+//
+//	glcm, err := texture.GLCMGray(img, image.Point{X: 1, Y: 0})
+func GLCMGray(img *image.Gray, offset image.Point) ([][]float64, error) {
+	bounds := img.Bounds()
+	dx, dy := abs(offset.X), abs(offset.Y)
+	if dx >= bounds.Dx() || dy >= bounds.Dy() {
+		return nil, errors.New("the offset must be smaller then the size of the image")
+	}
+	counts := make([][]uint64, 256)
+	for i := range counts {
+		counts[i] = make([]uint64, 256)
+	}
+	var total uint64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Point{X: x, Y: y}.Add(offset)
+			if !p.In(bounds) {
+				continue
+			}
+			i, j := img.Pix[img.PixOffset(x, y)], img.Pix[img.PixOffset(p.X, p.Y)]
+			counts[i][j]++
+			counts[j][i]++
+			total += 2
+		}
+	}
+	res := make([][]float64, 256)
+	for i := range res {
+		res[i] = make([]float64, 256)
+		for j, c := range counts[i] {
+			res[i][j] = float64(c) / float64(total)
+		}
+	}
+	return res, nil
+}
+
+// EnergyGray computes the energy (angular second moment) of the gray-level co-occurrence matrix of a grayscale image
+// for the given offset (see GLCMGray): the sum of the squared entries. It is 1 for a constant image and gets smaller
+// the more different pairs of gray levels occur. Returns an error if the offset is not smaller then the size of the
+// image.
+// Example of usage:
+//
+//	energy, err := texture.EnergyGray(img, image.Point{X: 1, Y: 0})
+func EnergyGray(img *image.Gray, offset image.Point) (float64, error) {
+	glcm, err := GLCMGray(img, offset)
+	if err != nil {
+		return 0, err
+	}
+	var res float64
+	for i := range glcm {
+		for _, p := range glcm[i] {
+			res += p * p
+		}
+	}
+	return res, nil
+}
+
+// ContrastGray computes the contrast of the gray-level co-occurrence matrix of a grayscale image for the given offset
+// (see GLCMGray): the sum of p(i, j) * (i - j)^2. It is 0 for a constant image and grows with the local intensity
+// variations, up to 255^2 when every pair is black and white. Returns an error if the offset is not smaller then the
+// size of the image.
+// Example of usage:
+//
+//	contrast, err := texture.ContrastGray(img, image.Point{X: 1, Y: 0})
+func ContrastGray(img *image.Gray, offset image.Point) (float64, error) {
+	glcm, err := GLCMGray(img, offset)
+	if err != nil {
+		return 0, err
+	}
+	var res float64
+	for i := range glcm {
+		for j, p := range glcm[i] {
+			res += p * float64((i-j)*(i-j))
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,85 @@
+package texture
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_GLCMGray(t *testing.T) {
+	img := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0x00, 0x01,
+			0x01, 0x02, 0x02,
+		},
+	}
+	glcm, err := GLCMGray(img, image.Point{X: 1, Y: 0})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the horizontal pairs are (0, 0), (0, 1), (1, 2) and (2, 2), each counted in both directions
+	expected := map[[2]int]float64{{0, 0}: 0.25, {0, 1}: 0.125, {1, 0}: 0.125, {1, 2}: 0.125, {2, 1}: 0.125, {2, 2}: 0.25}
+	for i := range glcm {
+		for j := range glcm[i] {
+			if !utils.IsEqualFloat64(expected[[2]int{i, j}], glcm[i][j]) {
+				t.Errorf("Expected value: %f - actual value: %f at: %d %d", expected[[2]int{i, j}], glcm[i][j], i, j)
+			}
+		}
+	}
+	// the opposite offset gives the same matrix
+	opposite, err := GLCMGray(img, image.Point{X: -1, Y: 0})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if opposite[0][1] != glcm[0][1] || opposite[2][2] != glcm[2][2] {
+		t.Errorf("Expected the same matrix for the opposite offset")
+	}
+	if _, err := GLCMGray(img, image.Point{X: 0, Y: 2}); err == nil {
+		t.Error("Expected error for an offset as large as the image")
+	}
+}
+
+func Test_EnergyGray_ContrastGray(t *testing.T) {
+	checkerboard := image.NewGray(image.Rect(0, 0, 32, 32))
+	gradient := image.NewGray(image.Rect(0, 0, 32, 32))
+	constant := image.NewGray(image.Rect(0, 0, 32, 32))
+	utils.ForEachPixel(checkerboard.Bounds().Size(), func(x, y int) {
+		checkerboard.Pix[y*32+x] = uint8((x + y) % 2 * 255)
+		gradient.Pix[y*32+x] = uint8(8 * x)
+		constant.Pix[y*32+x] = 128
+	})
+	offset := image.Point{X: 1, Y: 0}
+	contrastChecker, err := ContrastGray(checkerboard, offset)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	contrastGradient, err := ContrastGray(gradient, offset)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !utils.IsEqualFloat64(255*255, contrastChecker) || !utils.IsEqualFloat64(64, contrastGradient) {
+		t.Errorf("Expected contrast 65025 for the checkerboard and 64 for the gradient - actual: %f %f", contrastChecker, contrastGradient)
+	}
+	energyConstant, err := EnergyGray(constant, offset)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	energyGradient, err := EnergyGray(gradient, offset)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !utils.IsEqualFloat64(1, energyConstant) || energyGradient >= energyConstant {
+		t.Errorf("Expected energy 1 for a constant image and less for a gradient - actual: %f %f", energyConstant, energyGradient)
+	}
+	if _, err := ContrastGray(constant, image.Point{X: 32}); err == nil {
+		t.Error("Expected error for an offset as large as the image")
+	}
+	if _, err := EnergyGray(constant, image.Point{Y: -32}); err == nil {
+		t.Error("Expected error for an offset as large as the image")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,225 @@
+package texture
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/edgedetection"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// hogClip is the value the components of the L2 normalized blocks are clipped to by the L2-Hys normalization.
+const hogClip = 0.2
+
+// HOGDescriptorGray computes the histogram of oriented gradients (HOG) descriptor of a grayscale image, as introduced
+// by N. Dalal and B. Triggs for pedestrian detection. The gradients are computed with the Sobel operator (see
+// edgedetection.SobelGrayMagAngle) and every pixel votes with its gradient magnitude into the unsigned orientation
+// histograms (0 - 180 degrees, with the given number of bins) of the cells of cellSize pixels. The votes are
+// interpolated linearly between the two nearest orientation bins and bilinearly between the four nearest cells.
+// The cells are grouped into overlapping blocks of blockSize cells, moved by one cell. Every block is normalized with
+// L2-Hys (L2 normalization, clipping to 0.2 and renormalization) and the blocks are concatenated in row-major order,
+// within a block the cells are in row-major order too. If the size of the image is not divisible by the cell size,
+// the image is cropped to the largest size which is, keeping the top left corner.
+// With 8x8 cells, 2x2 blocks and 9 bins a 64x128 window gives the classic 3780 long descriptor.
+// Returns an error if the cell size, the block size or the number of bins is not positive, or the image is smaller
+// then a block.
+// Example of usage:
+//
+//	descriptor, err := texture.HOGDescriptorGray(img, image.Point{X: 8, Y: 8}, image.Point{X: 2, Y: 2}, 9)
+func HOGDescriptorGray(img *image.Gray, cellSize, blockSize image.Point, bins int) ([]float64, error) {
+	return hogDescriptor(img, cellSize, blockSize, bins, l2HysNormalize)
+}
+
+// HOGGray computes the HOG descriptor of a grayscale image like HOGDescriptorGray, with square cells of cellSize pixels
+// and square blocks of blockSize cells, but every block is only L2 normalized, without the clipping of L2-Hys. The
+// length of the descriptor is (cellsX - blockSize + 1) * (cellsY - blockSize + 1) * blockSize * blockSize * bins,
+// where cellsX and cellsY are the number of whole cells along the axes. Blocks without gradients are all zero.
+// Returns an error if the cell size, the block size or the number of bins is not positive, or the image is smaller
+// then a block.
+// Example of usage:
+//
+//	descriptor, err := texture.HOGGray(img, 8, 2, 9)
+func HOGGray(img *image.Gray, cellSize, blockSize, bins int) ([]float64, error) {
+	cell := image.Point{X: cellSize, Y: cellSize}
+	block := image.Point{X: blockSize, Y: blockSize}
+	return hogDescriptor(img, cell, block, bins, l2Normalize)
+}
+
+// VisualizeHOG renders the cell orientation histograms of a grayscale image (see HOGDescriptorGray) for debugging. Every
+// bin of every cell is drawn as a line through the center of the cell, along the edge direction of the bin (which is
+// perpendicular to the gradient), with a brightness proportional to the value of the bin. The result has the size of
+// the cropped image. Returns an error if the cell size or the number of bins is not positive, or the image is smaller
+// then a cell.
+// Example of usage:
+//
+//	res, err := texture.VisualizeHOG(img, image.Point{X: 8, Y: 8}, 9)
+func VisualizeHOG(img *image.Gray, cellSize image.Point, bins int) (*image.Gray, error) {
+	hist, cells, err := cellHistograms(img, cellSize, bins)
+	if err != nil {
+		return nil, err
+	}
+	var max float64
+	for cx := range hist {
+		for cy := range hist[cx] {
+			for _, v := range hist[cx][cy] {
+				max = math.Max(max, v)
+			}
+		}
+	}
+	res := image.NewGray(image.Rect(0, 0, cells.X*cellSize.X, cells.Y*cellSize.Y))
+	if max == 0 {
+		return res, nil
+	}
+	length := math.Min(float64(cellSize.X), float64(cellSize.Y)) / 2
+	for cx := range hist {
+		for cy := range hist[cx] {
+			centerX := (float64(cx) + 0.5) * float64(cellSize.X)
+			centerY := (float64(cy) + 0.5) * float64(cellSize.Y)
+			for b, v := range hist[cx][cy] {
+				value := uint8(math.Round(v / max * float64(utils.MaxUint8)))
+				// the edge is perpendicular to the gradient
+				sin, cos := math.Sincos((float64(b)+0.5)*math.Pi/float64(bins) + math.Pi/2)
+				for t := -length; t <= length; t += 0.5 {
+					x := int(math.Floor(centerX + t*cos))
+					y := int(math.Floor(centerY + t*sin))
+					if x < cx*cellSize.X || y < cy*cellSize.Y || x >= (cx+1)*cellSize.X || y >= (cy+1)*cellSize.Y {
+						continue
+					}
+					if res.GrayAt(x, y).Y < value {
+						res.SetGray(x, y, color.Gray{Y: value})
+					}
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// hogDescriptor groups the cell histograms into overlapping blocks, normalizes every block with the given function and
+// concatenates them.
+func hogDescriptor(img *image.Gray, cellSize, blockSize image.Point, bins int, normalize func(block []float64)) ([]float64, error) {
+	if blockSize.X <= 0 || blockSize.Y <= 0 {
+		return nil, errors.New("block size must be positive")
+	}
+	hist, cells, err := cellHistograms(img, cellSize, bins)
+	if err != nil {
+		return nil, err
+	}
+	if cells.X < blockSize.X || cells.Y < blockSize.Y {
+		return nil, errors.New("the image is smaller then a block")
+	}
+	blockLength := blockSize.X * blockSize.Y * bins
+	var descriptor []float64
+	for by := 0; by+blockSize.Y <= cells.Y; by++ {
+		for bx := 0; bx+blockSize.X <= cells.X; bx++ {
+			block := make([]float64, 0, blockLength)
+			for cy := by; cy < by+blockSize.Y; cy++ {
+				for cx := bx; cx < bx+blockSize.X; cx++ {
+					block = append(block, hist[cx][cy]...)
+				}
+			}
+			normalize(block)
+			descriptor = append(descriptor, block...)
+		}
+	}
+	return descriptor, nil
+}
+
+// cellHistograms computes the orientation histograms of the cells of the cropped image, indexed as hist[cx][cy][bin],
+// and the number of cells along both axes.
+func cellHistograms(img *image.Gray, cellSize image.Point, bins int) ([][][]float64, image.Point, error) {
+	if cellSize.X <= 0 || cellSize.Y <= 0 {
+		return nil, image.Point{}, errors.New("cell size must be positive")
+	}
+	if bins <= 0 {
+		return nil, image.Point{}, errors.New("the number of bins must be positive")
+	}
+	size := img.Bounds().Size()
+	cells := image.Point{X: size.X / cellSize.X, Y: size.Y / cellSize.Y}
+	if cells.X == 0 || cells.Y == 0 {
+		return nil, image.Point{}, errors.New("the image is smaller then a cell")
+	}
+	cropped := image.NewGray(image.Rect(0, 0, cells.X*cellSize.X, cells.Y*cellSize.Y))
+	for y := 0; y < cropped.Rect.Dy(); y++ {
+		copy(cropped.Pix[y*cropped.Stride:(y+1)*cropped.Stride], img.Pix[y*img.Stride:])
+	}
+	magnitude, angle, err := edgedetection.SobelGrayMagAngle(cropped, padding.BorderReplicate)
+	if err != nil {
+		return nil, image.Point{}, err
+	}
+	hist := make([][][]float64, cells.X)
+	for cx := range hist {
+		hist[cx] = make([][]float64, cells.Y)
+		for cy := range hist[cx] {
+			hist[cx][cy] = make([]float64, bins)
+		}
+	}
+	binWidth := math.Pi / float64(bins)
+	utils.ForEachPixel(cropped.Bounds().Size(), func(x, y int) {
+		m := float64(magnitude.GrayAt(x, y).Y)
+		if m == 0 {
+			return
+		}
+		// unsigned orientation in [0, Pi)
+		theta := math.Mod(angle[x][y]+math.Pi, math.Pi)
+		pos := theta/binWidth - 0.5
+		b0 := int(math.Floor(pos))
+		wb := pos - float64(b0)
+		b1 := (b0 + 1) % bins
+		b0 = (b0 + bins) % bins
+		// position relative to the cell centers
+		px := (float64(x)+0.5)/float64(cellSize.X) - 0.5
+		py := (float64(y)+0.5)/float64(cellSize.Y) - 0.5
+		cx0, cy0 := int(math.Floor(px)), int(math.Floor(py))
+		wx, wy := px-float64(cx0), py-float64(cy0)
+		for i := 0; i < 2; i++ {
+			cx := cx0 + i
+			if cx < 0 || cx >= cells.X {
+				continue
+			}
+			weightX := 1 - wx
+			if i == 1 {
+				weightX = wx
+			}
+			for j := 0; j < 2; j++ {
+				cy := cy0 + j
+				if cy < 0 || cy >= cells.Y {
+					continue
+				}
+				weightY := 1 - wy
+				if j == 1 {
+					weightY = wy
+				}
+				vote := m * weightX * weightY
+				hist[cx][cy][b0] += vote * (1 - wb)
+				hist[cx][cy][b1] += vote * wb
+			}
+		}
+	})
+	return hist, cells, nil
+}
+
+// l2Normalize normalizes a block in place to unit L2 norm, a small epsilon keeps blocks without gradients at zero.
+func l2Normalize(block []float64) {
+	const eps = 1e-3
+	var sum float64
+	for _, v := range block {
+		sum += v * v
+	}
+	norm := math.Sqrt(sum + eps*eps)
+	for i := range block {
+		block[i] /= norm
+	}
+}
+
+// l2HysNormalize normalizes a block in place with L2-Hys: L2 normalization, clipping to hogClip and renormalization.
+func l2HysNormalize(block []float64) {
+	l2Normalize(block)
+	for i := range block {
+		block[i] = math.Min(block[i], hogClip)
+	}
+	l2Normalize(block)
+}
@@ -0,0 +1,162 @@
+package texture
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_HOGDescriptorGray_Length(t *testing.T) {
+	img := hogTestImage(64, 128, false)
+	descriptor, err := HOGDescriptorGray(img, image.Point{X: 8, Y: 8}, image.Point{X: 2, Y: 2}, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(descriptor) != 3780 {
+		t.Errorf("Expected a descriptor of length 3780 - actual: %d", len(descriptor))
+	}
+	for _, v := range descriptor {
+		if v < 0 || v > 1 || math.IsNaN(v) {
+			t.Fatalf("Expected normalized values - actual: %f", v)
+		}
+	}
+}
+
+func Test_HOGDescriptorGray_Orientation(t *testing.T) {
+	// horizontal stripes have vertical gradients, which fall into the middle bin
+	img := hogTestImage(32, 32, true)
+	descriptor, err := HOGDescriptorGray(img, image.Point{X: 8, Y: 8}, image.Point{X: 1, Y: 1}, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for cell := 0; cell < len(descriptor)/9; cell++ {
+		hist := descriptor[cell*9 : (cell+1)*9]
+		for b, v := range hist {
+			if b != 4 && v >= hist[4] {
+				t.Fatalf("Expected the biggest value in bin 4 - actual: %v in cell %d", hist, cell)
+			}
+		}
+	}
+}
+
+func Test_HOGDescriptorGray_Crop(t *testing.T) {
+	img := hogTestImage(70, 133, false)
+	cropped := image.NewGray(image.Rect(0, 0, 64, 128))
+	utils.ForEachPixel(cropped.Bounds().Size(), func(x, y int) {
+		cropped.SetGray(x, y, img.GrayAt(x, y))
+	})
+	expected, err := HOGDescriptorGray(cropped, image.Point{X: 8, Y: 8}, image.Point{X: 2, Y: 2}, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	actual, err := HOGDescriptorGray(img, image.Point{X: 8, Y: 8}, image.Point{X: 2, Y: 2}, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected a descriptor of length %d - actual: %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if !utils.IsEqualFloat64(expected[i], actual[i]) {
+			t.Fatalf("Expected %f at %d - actual: %f", expected[i], i, actual[i])
+		}
+	}
+}
+
+func Test_HOGDescriptorGray_Errors(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	cell := image.Point{X: 8, Y: 8}
+	block := image.Point{X: 2, Y: 2}
+	if _, err := HOGDescriptorGray(img, image.Point{X: 0, Y: 8}, block, 9); err == nil {
+		t.Errorf("Expected error for zero cell size")
+	}
+	if _, err := HOGDescriptorGray(img, cell, image.Point{X: 0, Y: 0}, 9); err == nil {
+		t.Errorf("Expected error for zero block size")
+	}
+	if _, err := HOGDescriptorGray(img, cell, block, 0); err == nil {
+		t.Errorf("Expected error for zero bins")
+	}
+	if _, err := HOGDescriptorGray(img, cell, image.Point{X: 3, Y: 2}, 9); err == nil {
+		t.Errorf("Expected error for a block bigger then the image")
+	}
+}
+
+func Test_HOGGray(t *testing.T) {
+	img := hogTestImage(50, 37, false)
+	for _, params := range [][3]int{{8, 2, 9}, {6, 3, 12}, {5, 1, 6}} {
+		cellSize, blockSize, bins := params[0], params[1], params[2]
+		descriptor, err := HOGGray(img, cellSize, blockSize, bins)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		cellsX, cellsY := 50/cellSize, 37/cellSize
+		expected := (cellsX - blockSize + 1) * (cellsY - blockSize + 1) * blockSize * blockSize * bins
+		if len(descriptor) != expected {
+			t.Errorf("Expected a descriptor of length %d for %v - actual: %d", expected, params, len(descriptor))
+		}
+		// every block has unit length
+		blockLength := blockSize * blockSize * bins
+		for start := 0; start < len(descriptor); start += blockLength {
+			var sum float64
+			for _, v := range descriptor[start : start+blockLength] {
+				sum += v * v
+			}
+			if math.Abs(math.Sqrt(sum)-1) > 1e-3 {
+				t.Fatalf("Expected L2 normalized blocks for %v - actual norm: %f", params, math.Sqrt(sum))
+			}
+		}
+	}
+	uniform := image.NewGray(image.Rect(0, 0, 32, 32))
+	for i := range uniform.Pix {
+		uniform.Pix[i] = 120
+	}
+	descriptor, err := HOGGray(uniform, 8, 2, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, v := range descriptor {
+		if v != 0 {
+			t.Fatalf("Expected an all zero descriptor for a uniform image - actual: %f at %d", v, i)
+		}
+	}
+}
+
+func Test_VisualizeHOG(t *testing.T) {
+	// vertical stripes have horizontal gradients, so the glyphs are vertical lines
+	img := hogTestImage(35, 16, false)
+	res, err := VisualizeHOG(img, image.Point{X: 8, Y: 8}, 9)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds().Dx() != 32 || res.Bounds().Dy() != 16 {
+		t.Fatalf("Expected a 32x16 image - actual: %v", res.Bounds())
+	}
+	for cx := 0; cx < 4; cx++ {
+		for cy := 0; cy < 2; cy++ {
+			if res.GrayAt(cx*8+4, cy*8+1).Y == 0 {
+				t.Errorf("Expected a vertical glyph in cell (%d, %d)", cx, cy)
+			}
+			if res.GrayAt(cx*8+1, cy*8+4).Y != 0 {
+				t.Errorf("Expected no horizontal glyph in cell (%d, %d)", cx, cy)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// hogTestImage creates stripes with a period of 6 pixels, vertical or horizontal.
+func hogTestImage(width, height int, horizontal bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		t := x
+		if horizontal {
+			t = y
+		}
+		img.SetGray(x, y, color.Gray{Y: uint8(128 + 60*math.Sin(2*math.Pi*float64(t)/6))})
+	})
+	return img
+}
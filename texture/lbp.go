@@ -0,0 +1,136 @@
+package texture
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// LBPGray computes the local binary pattern (LBP) texture descriptor of a grayscale image. For every pixel the given
+// number of neighbours (1 to 8) are sampled on a circle of the given radius, starting on the right of the pixel and
+// going counterclockwise, and the bit p of the code is set if the neighbour p is bigger or equal to the pixel. For
+// radius 1 the neighbours are rounded to the 3x3 neighbourhood (the classic 8-neighbour LBP), for bigger radii the
+// neighbours are sampled with bilinear interpolation. The pixels near the border are computed on a replicate-padded
+// image, so the result has the same size as the input.
+// Returns the code image and its histogram. Without the uniform flag the histogram has 2^neighbors bins. With the
+// uniform flag the patterns with at most two 0-1 transitions (in circular order) get their own label, in increasing
+// order of their code, and all the other patterns share the last label, e.g. 8 neighbours give the 59 bins uniform
+// histogram. The code image contains these labels. Returns an error if the radius is smaller then 1 or the number of
+// neighbours is not between 1 and 8.
+// Example of usage:
+//
+//	codes, hist, err := texture.LBPGray(img, 1, 8, true)
+func LBPGray(img *image.Gray, radius int, neighbors int, uniform bool) (*image.Gray, []uint64, error) {
+	codes, err := lbpCodes(img, radius, neighbors)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels := make([]uint8, 1<<uint(neighbors))
+	bins := len(labels)
+	if uniform {
+		bins = uniformLabels(labels, neighbors)
+	} else {
+		for code := range labels {
+			labels[code] = uint8(code)
+		}
+	}
+	res, hist := labelCodes(codes, labels, bins)
+	return res, hist, nil
+}
+
+// LBPRotationInvariantGray computes the rotation invariant local binary pattern of a grayscale image: the LBP code of
+// every pixel (see LBPGray) is replaced by the smallest code which can be obtained by circularly rotating its bits, so
+// the histogram does not change if the texture is rotated by a multiple of 360 / neighbors degrees. The histogram has
+// 2^neighbors bins, most of them stay empty. Returns an error if the radius is smaller then 1 or the number of
+// neighbours is not between 1 and 8.
+// Example of usage:
+//
+//	codes, hist, err := texture.LBPRotationInvariantGray(img, 1, 8)
+func LBPRotationInvariantGray(img *image.Gray, radius int, neighbors int) (*image.Gray, []uint64, error) {
+	codes, err := lbpCodes(img, radius, neighbors)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels := make([]uint8, 1<<uint(neighbors))
+	mask := len(labels) - 1
+	for code := range labels {
+		min := code
+		rotated := code
+		for i := 1; i < neighbors; i++ {
+			rotated = (rotated>>1 | rotated<<uint(neighbors-1)) & mask
+			if rotated < min {
+				min = rotated
+			}
+		}
+		labels[code] = uint8(min)
+	}
+	res, hist := labelCodes(codes, labels, len(labels))
+	return res, hist, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func lbpCodes(img *image.Gray, radius int, neighbors int) (*image.Gray, error) {
+	if radius < 1 {
+		return nil, errors.New("radius must be at least 1")
+	}
+	if neighbors < 1 || neighbors > 8 {
+		return nil, errors.New("the number of neighbors must be between 1 and 8")
+	}
+	offsets := make([][2]float64, neighbors)
+	for p := range offsets {
+		sin, cos := math.Sincos(2 * math.Pi * float64(p) / float64(neighbors))
+		dx, dy := float64(radius)*cos, -float64(radius)*sin
+		if radius == 1 {
+			dx, dy = math.Round(dx), math.Round(dy)
+		}
+		offsets[p] = [2]float64{dx, dy}
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		center := img.GrayAt(x, y).Y
+		code := uint8(0)
+		for p, offset := range offsets {
+			if utils.BilinearSampleGray(img, float64(x)+offset[0], float64(y)+offset[1]) >= center {
+				code |= 1 << uint(p)
+			}
+		}
+		res.SetGray(x, y, color.Gray{Y: code})
+	})
+	return res, nil
+}
+
+// uniformLabels fills the label of every code for the uniform LBP and returns the number of labels.
+func uniformLabels(labels []uint8, neighbors int) int {
+	next := uint8(0)
+	var nonUniform []int
+	for code := range labels {
+		transitions := 0
+		for p := 0; p < neighbors; p++ {
+			if (code>>uint(p))&1 != (code>>uint((p+1)%neighbors))&1 {
+				transitions++
+			}
+		}
+		if transitions <= 2 {
+			labels[code] = next
+			next++
+		} else {
+			nonUniform = append(nonUniform, code)
+		}
+	}
+	for _, code := range nonUniform {
+		labels[code] = next
+	}
+	return int(next) + 1
+}
+
+func labelCodes(codes *image.Gray, labels []uint8, bins int) (*image.Gray, []uint64) {
+	hist := make([]uint64, bins)
+	for i, code := range codes.Pix {
+		codes.Pix[i] = labels[code]
+		hist[codes.Pix[i]]++
+	}
+	return codes, hist
+}
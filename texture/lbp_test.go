@@ -0,0 +1,150 @@
+package texture
+
+import (
+	"github.com/yafeiliu/imger/transform"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_LBPGray(t *testing.T) {
+	// the center is 50, the neighbours from the right going counterclockwise: 60, 40, 50, 10, 90, 20, 50, 70
+	img := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 3),
+		Stride: 3,
+		Pix: []uint8{
+			0x0A, 0x32, 0x28,
+			0x5A, 0x32, 0x3C,
+			0x14, 0x32, 0x46,
+		},
+	}
+	codes, hist, err := LBPGray(img, 1, 8, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// bits 0, 2, 4, 6 and 7 are set
+	expected := uint8(1 + 4 + 16 + 64 + 128)
+	if codes.GrayAt(1, 1).Y != expected {
+		t.Errorf("Expected code %d - actual: %d", expected, codes.GrayAt(1, 1).Y)
+	}
+	if len(hist) != 256 {
+		t.Errorf("Expected 256 bins - actual: %d", len(hist))
+	}
+	var total uint64
+	for _, count := range hist {
+		total += count
+	}
+	if total != 9 || hist[expected] == 0 {
+		t.Errorf("Expected a histogram of the 9 codes - actual: %v", hist)
+	}
+}
+
+func Test_LBPGray_Flat(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 12, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	for _, radius := range []int{1, 2, 3} {
+		for _, uniform := range []bool{false, true} {
+			codes, hist, err := LBPGray(img, radius, 8, uniform)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			expected := uint8(255)
+			expectedBins := 256
+			if uniform {
+				expected = 57
+				expectedBins = 59
+			}
+			if len(hist) != expectedBins {
+				t.Fatalf("Expected %d bins - actual: %d", expectedBins, len(hist))
+			}
+			for _, code := range codes.Pix {
+				if code != expected {
+					t.Fatalf("Expected the code %d everywhere - actual: %d", expected, code)
+				}
+			}
+			if hist[expected] != 120 {
+				t.Errorf("Expected all the pixels in a single bin - actual: %d", hist[expected])
+			}
+		}
+	}
+}
+
+func Test_LBPGray_UniformNonUniform(t *testing.T) {
+	// 0x55 = 01010101 has 8 transitions, so it goes into the last bin
+	img := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 3),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0xFF, 0x00,
+			0xFF, 0x80, 0xFF,
+			0x00, 0xFF, 0x00,
+		},
+	}
+	codes, hist, err := LBPGray(img, 1, 8, true)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if codes.GrayAt(1, 1).Y != 58 || hist[58] == 0 {
+		t.Errorf("Expected the non-uniform label 58 - actual: %d", codes.GrayAt(1, 1).Y)
+	}
+}
+
+func Test_LBPGray_Rotation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	img := image.NewGray(image.Rect(0, 0, 24, 24))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetGray(x, y, color.Gray{Y: uint8(rnd.Intn(256))})
+	})
+	rotated := transform.Rotate90Gray(img)
+
+	_, hist, _ := LBPGray(img, 1, 8, false)
+	_, rotatedHist, _ := LBPGray(rotated, 1, 8, false)
+	if equalHistograms(hist, rotatedHist) {
+		t.Errorf("Expected the basic LBP histogram to change after the rotation")
+	}
+	_, hist, err := LBPRotationInvariantGray(img, 1, 8)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	_, rotatedHist, err = LBPRotationInvariantGray(rotated, 1, 8)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !equalHistograms(hist, rotatedHist) {
+		t.Errorf("Expected the same rotation invariant histogram - actual: %v and %v", hist, rotatedHist)
+	}
+}
+
+func Test_LBPGray_Errors(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	if _, _, err := LBPGray(img, 0, 8, false); err == nil {
+		t.Errorf("Expected error for radius 0")
+	}
+	for _, neighbors := range []int{0, 9} {
+		if _, _, err := LBPGray(img, 1, neighbors, false); err == nil {
+			t.Errorf("Expected error for %d neighbors", neighbors)
+		}
+		if _, _, err := LBPRotationInvariantGray(img, 1, neighbors); err == nil {
+			t.Errorf("Expected error for %d neighbors", neighbors)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func equalHistograms(h1, h2 []uint64) bool {
+	if len(h1) != len(h2) {
+		return false
+	}
+	for i := range h1 {
+		if h1[i] != h2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package threshold
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// SauvolaThresholdGray binarizes a grayscale image with Sauvola's local threshold, which copes with uneven lighting,
+// e.g. shadows on a scanned document. The threshold of every pixel is computed from the mean m and the standard
+// deviation s of the window around it: t = m * (1 + k * (s / r - 1)), where r is the dynamic range of the standard
+// deviation (usually 128) and k is usually between 0.2 and 0.5. The pixels brighter then their threshold become 255,
+// the others 0, so dark text on a bright paper gives black text. The windows are cropped at the borders of the image.
+// The means and the standard deviations are computed with integral images, so the runtime does not depend on the size
+// of the window. Returns an error if the window is not a positive odd number or r is not positive.
+// Example of usage:
+//
+//	res, err := threshold.SauvolaThresholdGray(img, 25, 0.2, 128)
+func SauvolaThresholdGray(img *image.Gray, window int, k, r float64) (*image.Gray, error) {
+	if r <= 0 {
+		return nil, errors.New("the dynamic range of the standard deviation must be positive")
+	}
+	return localThreshold(img, window, func(mean, stdDev float64) float64 {
+		return mean * (1 + k*(stdDev/r-1))
+	})
+}
+
+// NiblackThresholdGray binarizes a grayscale image with Niblack's local threshold: t = m + k * s, where m and s are
+// the mean and the standard deviation of the window around the pixel and k is usually -0.2 for dark text on a bright
+// background. The pixels brighter then their threshold become 255, the others 0. Unlike SauvolaThresholdGray the
+// flat background regions are noisy, as their threshold is close to the pixel values. The windows are cropped at the
+// borders of the image. Returns an error if the window is not a positive odd number.
+// Example of usage:
+//
+//	res, err := threshold.NiblackThresholdGray(img, 25, -0.2)
+func NiblackThresholdGray(img *image.Gray, window int, k float64) (*image.Gray, error) {
+	return localThreshold(img, window, func(mean, stdDev float64) float64 {
+		return mean + k*stdDev
+	})
+}
+
+// -------------------------------------------------------------------------------------------------------
+// localThreshold compares every pixel with the threshold computed by f from the mean and the standard deviation of
+// the window around it, the sums of the windows are read from the integral images of the values and of the squared
+// values.
+func localThreshold(img *image.Gray, window int, f func(mean, stdDev float64) float64) (*image.Gray, error) {
+	if window < 1 || window%2 == 0 {
+		return nil, errors.New("the window must be a positive odd number")
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	w := size.X + 1
+	integral := make([]float64, w*(size.Y+1))
+	integralSq := make([]float64, w*(size.Y+1))
+	for y := 0; y < size.Y; y++ {
+		var rowSum, rowSumSq float64
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x := 0; x < size.X; x++ {
+			v := float64(row[x])
+			rowSum += v
+			rowSumSq += v * v
+			integral[(y+1)*w+x+1] = integral[y*w+x+1] + rowSum
+			integralSq[(y+1)*w+x+1] = integralSq[y*w+x+1] + rowSumSq
+		}
+	}
+	radius := window / 2
+	res := image.NewGray(bounds)
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		y0, y1 := utils.ClampInt(y-radius, 0, size.Y), utils.ClampInt(y+radius+1, 0, size.Y)
+		x0, x1 := utils.ClampInt(x-radius, 0, size.X), utils.ClampInt(x+radius+1, 0, size.X)
+		n := float64((x1 - x0) * (y1 - y0))
+		sum := integral[y1*w+x1] - integral[y0*w+x1] - integral[y1*w+x0] + integral[y0*w+x0]
+		sumSq := integralSq[y1*w+x1] - integralSq[y0*w+x1] - integralSq[y1*w+x0] + integralSq[y0*w+x0]
+		mean := sum / n
+		stdDev := math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+		i := img.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+		if float64(img.Pix[i]) > f(mean, stdDev) {
+			res.Pix[res.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)] = utils.MaxUint8
+		}
+	})
+	return res, nil
+}
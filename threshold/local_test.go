@@ -0,0 +1,123 @@
+package threshold
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_SauvolaThresholdGray_Shadow(t *testing.T) {
+	img, text := setupTestCaseShadowedDocument(96, 60)
+	sauvola, err := SauvolaThresholdGray(img, 15, 0.2, 128)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	otsu, err := OtsuThreshold(img, ThreshBinary)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if errors := countBinarizationErrors(sauvola, text); errors != 0 {
+		t.Errorf("Expected Sauvola to separate the text from the shadowed paper - wrong pixels: %d", errors)
+	}
+	if errors := countBinarizationErrors(otsu, text); errors < len(otsu.Pix)/10 {
+		t.Errorf("Expected the global Otsu threshold to fail on the shadow - wrong pixels: %d", errors)
+	}
+}
+
+func Test_NiblackThresholdGray_Text(t *testing.T) {
+	img, text := setupTestCaseShadowedDocument(96, 60)
+	res, err := NiblackThresholdGray(img, 15, -0.2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, v := range text.Pix {
+		if v != 0 && res.Pix[i] != 0 {
+			t.Fatalf("Expected every text pixel to be black - actual value: %d at: %d", res.Pix[i], i)
+		}
+	}
+}
+
+func Test_LocalThreshold_Reference(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 23, 17))
+	rand.New(rand.NewSource(4)).Read(img.Pix)
+	sub := img.SubImage(image.Rect(2, 3, 21, 17)).(*image.Gray)
+	sauvola, err := SauvolaThresholdGray(sub, 5, 0.3, 128)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	niblack, err := NiblackThresholdGray(sub, 7, -0.2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, localThresholdReference(sub, 5, func(m, s float64) float64 { return m * (1 + 0.3*(s/128-1)) }), sauvola)
+	utils.CompareGrayImages(t, localThresholdReference(sub, 7, func(m, s float64) float64 { return m - 0.2*s }), niblack)
+	for _, window := range []int{0, 4, -3} {
+		if _, err := NiblackThresholdGray(img, window, -0.2); err == nil {
+			t.Errorf("Expected error for window %d", window)
+		}
+	}
+	if _, err := SauvolaThresholdGray(img, 5, 0.2, 0); err == nil {
+		t.Error("Expected error for a dynamic range of 0")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// setupTestCaseShadowedDocument returns a page with short dark strokes, like lines of text, under a shadow which
+// darkens the paper from 230 on the left to 90 on the right, the strokes are 70 darker then the paper around them.
+// The second image marks the text pixels.
+func setupTestCaseShadowedDocument(width, height int) (*image.Gray, *image.Gray) {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	text := image.NewGray(image.Rect(0, 0, width, height))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		paper := 230 - 140*float64(x)/float64(width-1)
+		i := y*width + x
+		img.Pix[i] = uint8(math.Round(paper))
+		if y%10 >= 4 && y%10 < 6 && x%12 >= 2 && x%12 < 10 {
+			img.Pix[i] = uint8(math.Round(paper - 70))
+			text.Pix[i] = utils.MaxUint8
+		}
+	})
+	return img, text
+}
+
+// countBinarizationErrors counts the text pixels which are not black and the paper pixels which are not white.
+func countBinarizationErrors(res, text *image.Gray) int {
+	errors := 0
+	for i, v := range text.Pix {
+		if (v != 0) == (res.Pix[i] != 0) {
+			errors++
+		}
+	}
+	return errors
+}
+
+// localThresholdReference computes the local threshold by summing every window directly.
+func localThresholdReference(img *image.Gray, window int, f func(mean, stdDev float64) float64) *image.Gray {
+	bounds := img.Bounds()
+	res := image.NewGray(bounds)
+	radius := window / 2
+	utils.ForEachPixel(bounds.Size(), func(x, y int) {
+		var sum, sumSq, n float64
+		for wy := y - radius; wy <= y+radius; wy++ {
+			for wx := x - radius; wx <= x+radius; wx++ {
+				if wx < 0 || wy < 0 || wx >= bounds.Dx() || wy >= bounds.Dy() {
+					continue
+				}
+				v := float64(img.GrayAt(bounds.Min.X+wx, bounds.Min.Y+wy).Y)
+				sum += v
+				sumSq += v * v
+				n++
+			}
+		}
+		mean := sum / n
+		if float64(img.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y) > f(mean, math.Sqrt(math.Max(sumSq/n-mean*mean, 0))) {
+			res.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{Y: utils.MaxUint8})
+		}
+	})
+	return res
+}
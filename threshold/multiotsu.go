@@ -0,0 +1,69 @@
+package threshold
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/histogram"
+	"image"
+)
+
+// MultiOtsuGray extends Otsu's method to more then two classes, e.g. to separate the background, the tissue and the
+// bones of a scan. It returns the classes - 1 threshold values, in increasing order, which maximize the between-class
+// variance of the histogram of the image. Like with OtsuThreshold a threshold t separates the values up to t from the
+// values above t, so the class of a pixel is the number of thresholds smaller then its value. If several thresholds
+// give the same variance, e.g. in an empty range of the histogram between two peaks, the lowest one is returned. The
+// search is done with dynamic programming over the histogram, so its cost grows linearly with the number of classes.
+// Returns an error if
+// the number of classes is not between 2 and 256 or the image is empty.
+// Example of usage:
+//
+//	thresholds, err := threshold.MultiOtsuGray(img, 3)
+func MultiOtsuGray(img *image.Gray, classes int) ([]uint8, error) {
+	if classes < 2 || classes > 256 {
+		return nil, errors.New("the number of classes must be between 2 and 256")
+	}
+	hist := histogram.HistogramGray(img)
+	// prefix sums of the counts and of the values, count[i] covers the levels below i
+	var count, sum [257]float64
+	for i, bin := range hist {
+		count[i+1] = count[i] + float64(bin)
+		sum[i+1] = sum[i] + float64(i)*float64(bin)
+	}
+	if count[256] == 0 {
+		return nil, errors.New("empty image")
+	}
+	// the between-class variance is maximal where the sum of weight * mean^2 of the classes is maximal, as the total
+	// mean does not depend on the thresholds
+	score := func(from, to int) float64 {
+		n := count[to] - count[from]
+		if n == 0 {
+			return 0
+		}
+		s := sum[to] - sum[from]
+		return s * s / n
+	}
+	// best[c][i] is the best score of the levels below i split into c + 1 classes, last[c][i] is the first level of the
+	// last of these classes
+	best := make([][257]float64, classes)
+	last := make([][257]int, classes)
+	for i := 1; i <= 256; i++ {
+		best[0][i] = score(0, i)
+	}
+	for c := 1; c < classes; c++ {
+		for i := c + 1; i <= 256; i++ {
+			best[c][i] = -1
+			for j := c; j < i; j++ {
+				if v := best[c-1][j] + score(j, i); v > best[c][i] {
+					best[c][i] = v
+					last[c][i] = j
+				}
+			}
+		}
+	}
+	thresholds := make([]uint8, classes-1)
+	end := 256
+	for c := classes - 1; c > 0; c-- {
+		end = last[c][end]
+		thresholds[c-1] = uint8(end - 1)
+	}
+	return thresholds, nil
+}
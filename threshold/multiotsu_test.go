@@ -0,0 +1,70 @@
+package threshold
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_MultiOtsuGray_ThreePeaks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(12))
+	img := image.NewGray(image.Rect(0, 0, 90, 60))
+	peaks := []float64{40, 120, 210}
+	for i := range img.Pix {
+		v := peaks[i%3] + rnd.NormFloat64()*8
+		img.Pix[i] = uint8(math.Max(0, math.Min(255, math.Round(v))))
+	}
+	thresholds, err := MultiOtsuGray(img, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if len(thresholds) != 2 {
+		t.Fatalf("Expected 2 thresholds - actual: %v", thresholds)
+	}
+	if thresholds[0] <= 40 || thresholds[0] >= 120 || thresholds[1] <= 120 || thresholds[1] >= 210 {
+		t.Errorf("Expected the thresholds between the peaks %v - actual: %v", peaks, thresholds)
+	}
+	// the peaks do not overlap, so every pixel is put into the class of its peak
+	for i, v := range img.Pix {
+		class := 0
+		for _, threshold := range thresholds {
+			if v > threshold {
+				class++
+			}
+		}
+		if class != i%3 {
+			t.Fatalf("Expected class: %d - actual class: %d for value: %d", i%3, class, v)
+		}
+	}
+}
+
+func Test_MultiOtsuGray_TwoClasses(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 31, 27))
+	rand.New(rand.NewSource(2)).Read(img.Pix)
+	for i := range img.Pix[:300] {
+		img.Pix[i] /= 3
+	}
+	thresholds, err := MultiOtsuGray(img, 2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := otsuThresholdValue(img); len(thresholds) != 1 || thresholds[0] != expected {
+		t.Errorf("Expected the Otsu threshold %d for two classes - actual: %v", expected, thresholds)
+	}
+}
+
+func Test_MultiOtsuGray_Invalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for _, classes := range []int{1, 0, 257} {
+		if _, err := MultiOtsuGray(img, classes); err == nil {
+			t.Errorf("Expected error for %d classes", classes)
+		}
+	}
+	if _, err := MultiOtsuGray(image.NewGray(image.Rect(0, 0, 0, 0)), 3); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+}
+
+// -------------------------------------------------------------------------------
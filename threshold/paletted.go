@@ -0,0 +1,45 @@
+package threshold
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// PalettedThreshold thresholds a paletted image (e.g. a decoded GIF frame) without expanding it to RGBA. The gray value
+// of every palette entry is thresholded once and the pixels are mapped through the per index results, which gives the
+// same output as converting the image to grayscale and calling Threshold. The bounds of the input are kept.
+// Methods: ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv
+// Returns an error if the palette is empty, has more then 256 entries or if a pixel refers to an index outside of it.
+// Example of usage:
+//
+//	res, err := threshold.PalettedThreshold(frame, 128, threshold.ThreshBinary)
+func PalettedThreshold(img *image.Paletted, t uint8, method Method) (*image.Gray, error) {
+	if len(img.Palette) == 0 {
+		return nil, errors.New("the palette is empty")
+	}
+	if len(img.Palette) > 256 {
+		return nil, errors.New("the palette has more then 256 entries")
+	}
+	entries := image.NewGray(image.Rect(0, 0, len(img.Palette), 1))
+	for i, c := range img.Palette {
+		entries.Pix[i] = color.GrayModel.Convert(c).(color.Gray).Y
+	}
+	thresholded, err := Threshold(entries, t, method)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	res := image.NewGray(bounds)
+	for y := 0; y < bounds.Dy(); y++ {
+		src := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):][:bounds.Dx()]
+		dst := res.Pix[y*res.Stride : y*res.Stride+bounds.Dx()]
+		for x, index := range src {
+			if int(index) >= len(img.Palette) {
+				return nil, errors.New("palette index out of range")
+			}
+			dst[x] = thresholded.Pix[index]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+package threshold
+
+import (
+	"github.com/yafeiliu/imger/grayscale"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_PalettedThreshold(t *testing.T) {
+	img := setupTestCasePaletted(64, 48)
+	// reference: expand to RGBA, convert to grayscale and threshold every pixel
+	rgba := image.NewRGBA(img.Bounds())
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		rgba.Set(x, y, img.At(x, y))
+	})
+	gray := grayscale.Grayscale(rgba)
+	for _, method := range []Method{ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv} {
+		expected, err := Threshold(gray, 120, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual, err := PalettedThreshold(img, 120, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+	}
+}
+
+func Test_PalettedThreshold_SubImage(t *testing.T) {
+	img := setupTestCasePaletted(20, 20)
+	full, err := PalettedThreshold(img, 100, ThreshBinary)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	rect := image.Rect(5, 3, 15, 12)
+	res, err := PalettedThreshold(img.SubImage(rect).(*image.Paletted), 100, ThreshBinary)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != rect {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", rect, res.Bounds())
+	}
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			if res.GrayAt(x, y) != full.GrayAt(x, y) {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", full.GrayAt(x, y).Y, res.GrayAt(x, y).Y, x, y)
+			}
+		}
+	}
+}
+
+func Test_PalettedThreshold_Invalid(t *testing.T) {
+	if _, err := PalettedThreshold(image.NewPaletted(image.Rect(0, 0, 4, 4), nil), 100, ThreshBinary); err == nil {
+		t.Error("Expected error for an empty palette")
+	}
+	palette := make(color.Palette, 257)
+	for i := range palette {
+		palette[i] = color.Gray{Y: uint8(i)}
+	}
+	if _, err := PalettedThreshold(image.NewPaletted(image.Rect(0, 0, 4, 4), palette), 100, ThreshBinary); err == nil {
+		t.Error("Expected error for a palette with 257 entries")
+	}
+	img := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black, color.White})
+	img.Pix[5] = 2
+	if _, err := PalettedThreshold(img, 100, ThreshBinary); err == nil {
+		t.Error("Expected error for an index outside of the palette")
+	}
+}
+
+func Benchmark_PalettedThreshold(b *testing.B) {
+	img := setupTestCasePaletted(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = PalettedThreshold(img, 120, ThreshBinary)
+	}
+}
+
+func Benchmark_PalettedThreshold_ExpandToRGBA(b *testing.B) {
+	img := setupTestCasePaletted(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rgba := image.NewRGBA(img.Bounds())
+		utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			rgba.Set(x, y, img.At(x, y))
+		})
+		_, _ = Threshold(grayscale.Grayscale(rgba), 120, ThreshBinary)
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCasePaletted(width, height int) *image.Paletted {
+	rnd := rand.New(rand.NewSource(5))
+	palette := make(color.Palette, 256)
+	for i := range palette {
+		palette[i] = color.RGBA{R: uint8(rnd.Intn(256)), G: uint8(rnd.Intn(256)), B: uint8(rnd.Intn(256)), A: 0xFF}
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rnd.Intn(len(palette)))
+	}
+	return img
+}
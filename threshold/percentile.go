@@ -0,0 +1,44 @@
+package threshold
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/histogram"
+	"image"
+)
+
+// ThresholdByPercentileGray segments a grayscale image with ThreshBinary at the value of the given percentile of its
+// histogram, e.g. to keep the strongest 10 percent of a gradient magnitude image as edges with a percentile of 90.
+// The value is the lowest intensity with at least percentile percent of the pixels at or below it, so a percentile
+// of 50 gives the median and a percentile of 0 the minimum of the image. The value is returned together with the
+// segmented image. Returns an error if the percentile is not in the [0, 100] interval or the image is empty.
+// Example of usage:
+//
+//	res, t, err := threshold.ThresholdByPercentileGray(magnitude, 90)
+func ThresholdByPercentileGray(img *image.Gray, percentile float64) (*image.Gray, uint8, error) {
+	if percentile < 0 || percentile > 100 {
+		return nil, 0, errors.New("percentile should be in the [0, 100] interval")
+	}
+	hist := histogram.HistogramGray(img)
+	var total uint64
+	for _, bin := range hist {
+		total += bin
+	}
+	if total == 0 {
+		return nil, 0, errors.New("empty image")
+	}
+	limit := float64(total) * percentile / 100
+	var t uint8
+	var cdf uint64
+	for i, bin := range hist {
+		cdf += bin
+		if cdf > 0 && float64(cdf) >= limit {
+			t = uint8(i)
+			break
+		}
+	}
+	res, err := Threshold(img, t, ThreshBinary)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, t, nil
+}
@@ -0,0 +1,75 @@
+package threshold
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ThresholdByPercentileGray_Median(t *testing.T) {
+	// every intensity appears 4 times
+	img := image.NewGray(image.Rect(0, 0, 64, 16))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7 % 256)
+	}
+	res, value, err := ThresholdByPercentileGray(img, 50)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// half of the pixels are at or below the median
+	if value != 127 {
+		t.Errorf("Expected the median 127 - actual value: %d", value)
+	}
+	var foreground int
+	for i, v := range res.Pix {
+		if v != 0 && v != 255 {
+			t.Fatalf("Expected a binary image - actual value: %d", v)
+		}
+		if (v == 255) != (img.Pix[i] >= value) {
+			t.Fatalf("Expected the pixel %d with value %d to be thresholded at %d", i, img.Pix[i], value)
+		}
+		if v == 255 {
+			foreground++
+		}
+	}
+	if total := len(img.Pix); foreground < total*45/100 || foreground > total*55/100 {
+		t.Errorf("Expected roughly half of the %d pixels as foreground - actual: %d", total, foreground)
+	}
+}
+
+func Test_ThresholdByPercentileGray_Limits(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 1))
+	for x := 0; x < 10; x++ {
+		img.SetGray(x, 0, color.Gray{Y: uint8(20 + 10*x)})
+	}
+	cases := []struct {
+		percentile float64
+		expected   uint8
+	}{
+		{0, 20},
+		{10, 20},
+		{11, 30},
+		{90, 100},
+		{100, 110},
+	}
+	for _, c := range cases {
+		_, value, err := ThresholdByPercentileGray(img, c.percentile)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if value != c.expected {
+			t.Errorf("Expected value: %d - actual value: %d for percentile: %f", c.expected, value, c.percentile)
+		}
+	}
+	for _, percentile := range []float64{-1, 100.5} {
+		if _, _, err := ThresholdByPercentileGray(img, percentile); err == nil {
+			t.Errorf("Expected error for percentile: %f", percentile)
+		}
+	}
+	if _, _, err := ThresholdByPercentileGray(image.NewGray(image.Rect(0, 0, 0, 0)), 50); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,37 @@
+package threshold
+
+import (
+	"image"
+)
+
+// ThresholdRGBA thresholds the luminance of an RGBA image without an explicit grayscale conversion. The luminance of
+// every pixel is computed like color.GrayModel does (and so like grayscale.Grayscale) and mapped through a lookup
+// table holding the thresholded values of all 256 gray levels, which gives the same output as converting the image to
+// grayscale and calling Threshold. The bounds of the input are kept.
+// Methods: ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv
+// Returns an error if the method is invalid.
+// Example of usage:
+//
+//	mask, err := threshold.ThresholdRGBA(img, 128, threshold.ThreshBinary)
+func ThresholdRGBA(img *image.RGBA, t uint8, method Method) (*image.Gray, error) {
+	levels := image.NewGray(image.Rect(0, 0, 256, 1))
+	for i := range levels.Pix {
+		levels.Pix[i] = uint8(i)
+	}
+	lut, err := Threshold(levels, t, method)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	res := image.NewGray(bounds)
+	for y := 0; y < bounds.Dy(); y++ {
+		src := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):][:4*bounds.Dx()]
+		dst := res.Pix[y*res.Stride : y*res.Stride+bounds.Dx()]
+		for x := range dst {
+			// the same weights and rounding as color.GrayModel on the 16 bit values
+			r, g, b := uint32(src[4*x])*0x101, uint32(src[4*x+1])*0x101, uint32(src[4*x+2])*0x101
+			dst[x] = lut.Pix[(19595*r+38470*g+7471*b+1<<15)>>24]
+		}
+	}
+	return res, nil
+}
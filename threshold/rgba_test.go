@@ -0,0 +1,41 @@
+package threshold
+
+import (
+	"github.com/yafeiliu/imger/grayscale"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ThresholdRGBA_MatchesGrayscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 31, 23))
+	rand.New(rand.NewSource(9)).Read(img.Pix)
+	roi := image.Rect(4, 3, 27, 20)
+	for _, method := range []Method{ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv} {
+		expected, err := Threshold(grayscale.Grayscale(img), 97, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		actual, err := ThresholdRGBA(img, 97, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, expected, actual)
+		// a sub-image gives the corresponding part of the mask and keeps its bounds
+		actual, err = ThresholdRGBA(img.SubImage(roi).(*image.RGBA), 97, method)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if actual.Bounds() != roi {
+			t.Errorf("Expected bounds: %v - actual bounds: %v", roi, actual.Bounds())
+		}
+		utils.CompareGrayImages(t, utils.CloneGray(expected.SubImage(roi).(*image.Gray)), utils.CloneGray(actual))
+	}
+	if _, err := ThresholdRGBA(img, 97, Method(-1)); err == nil {
+		t.Error("Expected error for an invalid method")
+	}
+}
+
+// -------------------------------------------------------------------------------
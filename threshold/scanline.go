@@ -0,0 +1,100 @@
+package threshold
+
+import (
+	"errors"
+	"image"
+)
+
+// Run is a sequence of consecutive pixels of a binarized scanline which are all black or all white.
+type Run struct {
+	// Start is the x coordinate of the first pixel of the run.
+	Start int
+	// Length is the number of pixels of the run.
+	Length int
+	// Black is true for the runs below the threshold.
+	Black bool
+}
+
+// ScanlineRuns binarizes the row y of a grayscale image and returns its alternating black and white runs from left to
+// right, e.g. to decode a 1D barcode without binarizing the whole frame. The pixels smaller then the threshold are black,
+// the others are white, the same way as ThreshBinary. Returns an error if the row is outside of the image.
+// Example of usage:
+//
+//	runs, err := threshold.ScanlineRuns(img, 240, 128)
+func ScanlineRuns(img *image.Gray, y int, threshold uint8) ([]Run, error) {
+	return ScanlineRunsBand(img, y, 1, threshold)
+}
+
+// ScanlineRunsBand works like ScanlineRuns, but the band of bandHeight rows starting at the row y is averaged column
+// by column before the binarization, which suppresses the noise of a single row. Returns an error if the band height is
+// smaller then 1 or the band is not inside of the image.
+// Example of usage:
+//
+//	runs, err := threshold.ScanlineRunsBand(img, 236, 8, 128)
+func ScanlineRunsBand(img *image.Gray, y int, bandHeight int, threshold uint8) ([]Run, error) {
+	band, err := averageBand(img, y, bandHeight)
+	if err != nil {
+		return nil, err
+	}
+	return runs(band, float64(threshold)), nil
+}
+
+// ScanlineRunsAdaptive works like ScanlineRunsBand, but the threshold is the midpoint between the darkest and the
+// brightest value of the averaged band, which adapts to the lighting of every scanline of a camera frame. A band with
+// a single value gives a single white run. Returns an error if the band height is smaller then 1 or the band is not
+// inside of the image.
+// Example of usage:
+//
+//	runs, err := threshold.ScanlineRunsAdaptive(img, 236, 8)
+func ScanlineRunsAdaptive(img *image.Gray, y int, bandHeight int) ([]Run, error) {
+	band, err := averageBand(img, y, bandHeight)
+	if err != nil {
+		return nil, err
+	}
+	min, max := band[0], band[0]
+	for _, v := range band {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return runs(band, (min+max)/2), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// averageBand returns the column averages of the bandHeight rows starting at the row y.
+func averageBand(img *image.Gray, y int, bandHeight int) ([]float64, error) {
+	if bandHeight < 1 {
+		return nil, errors.New("band height must be at least 1")
+	}
+	size := img.Bounds().Size()
+	if y < 0 || y+bandHeight > size.Y || size.X == 0 {
+		return nil, errors.New("the scanline is outside of the image")
+	}
+	band := make([]float64, size.X)
+	for row := y; row < y+bandHeight; row++ {
+		for x := range band {
+			band[x] += float64(img.GrayAt(x, row).Y)
+		}
+	}
+	for x := range band {
+		band[x] /= float64(bandHeight)
+	}
+	return band, nil
+}
+
+// runs splits the binarized band into runs, the values smaller then the threshold are black.
+func runs(band []float64, threshold float64) []Run {
+	var res []Run
+	for x, v := range band {
+		black := v < threshold
+		if len(res) > 0 && res[len(res)-1].Black == black {
+			res[len(res)-1].Length++
+			continue
+		}
+		res = append(res, Run{Start: x, Length: 1, Black: black})
+	}
+	return res
+}
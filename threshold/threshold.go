@@ -52,6 +52,16 @@ const (
 // Threshold returns a 8 bit grayscale image as result which was segmented using one of the following methods:
 // ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv
 func Threshold(img *image.Gray, t uint8, method Method) (*image.Gray, error) {
+	return ThresholdWithMax(img, t, utils.MaxUint8, method)
+}
+
+// ThresholdWithMax works like Threshold, but ThreshBinary and ThreshBinaryInv set the pixels which pass the test to
+// maxValue instead of 255. A maxValue of 1 makes the result directly usable as a mask or as weights in arithmetic
+// operations. The other methods ignore maxValue.
+// Example of usage:
+//
+//	res, err := threshold.ThresholdWithMax(img, 100, 1, threshold.ThreshBinary)
+func ThresholdWithMax(img *image.Gray, t uint8, maxValue uint8, method Method) (*image.Gray, error) {
 	var setPixel func(*image.Gray, int, int)
 	switch method {
 	case ThreshBinary:
@@ -60,14 +70,14 @@ func Threshold(img *image.Gray, t uint8, method Method) (*image.Gray, error) {
 			if pixel < t {
 				gray.SetGray(x, y, color.Gray{Y: utils.MinUint8})
 			} else {
-				gray.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+				gray.SetGray(x, y, color.Gray{Y: maxValue})
 			}
 		}
 	case ThreshBinaryInv:
 		setPixel = func(gray *image.Gray, x int, y int) {
 			pixel := img.GrayAt(x, y).Y
 			if pixel < t {
-				gray.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+				gray.SetGray(x, y, color.Gray{Y: maxValue})
 			} else {
 				gray.SetGray(x, y, color.Gray{Y: utils.MinUint8})
 			}
@@ -107,6 +117,35 @@ func Threshold(img *image.Gray, t uint8, method Method) (*image.Gray, error) {
 	return threshold(img, setPixel), nil
 }
 
+// ThresholdROI segments only the given region of interest of a grayscale image with one of the threshold methods, the
+// other pixels of the returned copy are left untouched. Returns an error if the ROI is empty or it is not inside of
+// the bounds of the image.
+// Example of usage:
+//
+//	res, err := threshold.ThresholdROI(img, image.Rect(10, 10, 50, 40), 100, threshold.ThreshBinary)
+func ThresholdROI(img *image.Gray, roi image.Rectangle, t uint8, method Method) (*image.Gray, error) {
+	if err := utils.ValidateROI(img.Bounds(), roi); err != nil {
+		return nil, err
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(img.Bounds())
+	for y := 0; y < size.Y; y++ {
+		copy(res.Pix[y*res.Stride:y*res.Stride+size.X], img.Pix[y*img.Stride:y*img.Stride+size.X])
+	}
+	region := image.NewGray(image.Rect(0, 0, roi.Dx(), roi.Dy()))
+	for y := 0; y < roi.Dy(); y++ {
+		copy(region.Pix[y*region.Stride:(y+1)*region.Stride], img.Pix[img.PixOffset(roi.Min.X, roi.Min.Y+y):])
+	}
+	region, err := Threshold(region, t, method)
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < roi.Dy(); y++ {
+		copy(res.Pix[res.PixOffset(roi.Min.X, roi.Min.Y+y):], region.Pix[y*region.Stride:(y+1)*region.Stride])
+	}
+	return res, nil
+}
+
 // Threshold16 returns a grayscale image represented on 16 bits as result which was segmented using one of the following
 // Methods: ThreshBinary, ThreshBinaryInv, ThreshTrunc, ThreshToZero, ThreshToZeroInv
 func Threshold16(img *image.Gray16, t uint16, method Method) (*image.Gray16, error) {
@@ -172,6 +211,37 @@ func OtsuThreshold(img *image.Gray, method Method) (*image.Gray, error) {
 	return Threshold(img, otsuThresholdValue(img), method)
 }
 
+// OtsuThresholdGrayMasked segments a grayscale image using Otsu's method where the threshold value is computed only from
+// the pixels with a nonzero mask value. The result covers the whole image, the pixels outside of the mask are set to 0
+// or, if passThrough is true, they keep their original value. The threshold value is returned together with the
+// segmented image, it can be passed to Threshold to segment the pixels outside of the mask too. An empty mask gives the
+// threshold 0. Returns an error if the size of the mask does not match the size of the image.
+// Example of usage:
+//
+//	res, t, err := threshold.OtsuThresholdGrayMasked(img, mask, threshold.ThreshBinary, false)
+func OtsuThresholdGrayMasked(img *image.Gray, mask *image.Gray, method Method, passThrough bool) (*image.Gray, uint8, error) {
+	hist, err := histogram.HistogramGrayMasked(img, mask)
+	if err != nil {
+		return nil, 0, err
+	}
+	t := otsuThresholdFromHistogram(hist)
+	res, err := Threshold(img, t, method)
+	if err != nil {
+		return nil, 0, err
+	}
+	utils.ParallelForEachPixel(img.Bounds().Size(), func(x, y int) {
+		if mask.GrayAt(x, y).Y != 0 {
+			return
+		}
+		if passThrough {
+			res.SetGray(x, y, img.GrayAt(x, y))
+		} else {
+			res.SetGray(x, y, color.Gray{Y: utils.MinUint8})
+		}
+	})
+	return res, t, nil
+}
+
 // -------------------------------------------------------------------------------------------------------
 func threshold(img *image.Gray, setPixel func(*image.Gray, int, int)) *image.Gray {
 	size := img.Bounds().Size()
@@ -192,12 +262,14 @@ func threshold16(img *image.Gray16, setPixel16 func(*image.Gray16, int, int)) *i
 }
 
 func otsuThresholdValue(img *image.Gray) uint8 {
-	hist := histogram.HistogramGray(img)
-	size := img.Bounds().Size()
-	totalNumberOfPixels := size.X * size.Y
+	return otsuThresholdFromHistogram(histogram.HistogramGray(img))
+}
 
+func otsuThresholdFromHistogram(hist [256]uint64) uint8 {
+	var totalNumberOfPixels int
 	var sumHist float64
 	for i, bin := range hist {
+		totalNumberOfPixels += int(bin)
 		sumHist += float64(uint64(i) * bin)
 	}
 
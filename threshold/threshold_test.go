@@ -1,11 +1,265 @@
 package threshold
 
 import (
+	"github.com/yafeiliu/imger/histogram"
 	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"testing"
 )
 
+// ---------------------------------Unit tests------------------------------------
+func setupTestCaseDisk() (*image.Gray, *image.Gray) {
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	mask := image.NewGray(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			dx, dy := x-20, y-20
+			if dx*dx+dy*dy > 15*15 {
+				continue
+			}
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+			if x < 20 {
+				img.SetGray(x, y, color.Gray{Y: uint8(150 + y%10)})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: uint8(220 + y%10)})
+			}
+		}
+	}
+	return img, mask
+}
+
+func Test_OtsuThresholdGrayMasked(t *testing.T) {
+	img, mask := setupTestCaseDisk()
+	var diskHist [256]uint64
+	size := img.Bounds().Size()
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			if mask.GrayAt(x, y).Y != 0 {
+				diskHist[img.GrayAt(x, y).Y]++
+			}
+		}
+	}
+	expectedThresh := otsuThresholdFromHistogram(diskHist)
+	if global := otsuThresholdValue(img); global == expectedThresh {
+		t.Fatalf("Expected the global threshold to differ from the masked one: %d", global)
+	}
+	maskedHist, _ := histogram.HistogramGrayMasked(img, mask)
+	if actualThresh := otsuThresholdFromHistogram(maskedHist); actualThresh != expectedThresh {
+		t.Errorf("Expected threshold: %d - actual threshold: %d", expectedThresh, actualThresh)
+	}
+
+	res, thresh, err := OtsuThresholdGrayMasked(img, mask, ThreshBinary, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if thresh != expectedThresh {
+		t.Errorf("Expected threshold: %d - actual threshold: %d", expectedThresh, thresh)
+	}
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			var expected uint8
+			if mask.GrayAt(x, y).Y != 0 && img.GrayAt(x, y).Y >= expectedThresh {
+				expected = 0xFF
+			}
+			if actual := res.GrayAt(x, y).Y; actual != expected {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_OtsuThresholdGrayMasked_PassThrough(t *testing.T) {
+	img, mask := setupTestCaseDisk()
+	img.SetGray(0, 0, color.Gray{Y: 0x42})
+	res, _, err := OtsuThresholdGrayMasked(img, mask, ThreshBinary, true)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.GrayAt(0, 0).Y != 0x42 {
+		t.Errorf("Expected gray: %d - actual gray: %d at: 0 0", 0x42, res.GrayAt(0, 0).Y)
+	}
+	if _, _, err := OtsuThresholdGrayMasked(img, image.NewGray(image.Rect(0, 0, 3, 3)), ThreshBinary, true); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+func Test_OtsuThresholdGrayMasked_Distractor(t *testing.T) {
+	// a dark background with a gray region of interest on the left and a bright distractor on the right
+	img := image.NewGray(image.Rect(0, 0, 60, 40))
+	mask := image.NewGray(image.Rect(0, 0, 60, 40))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		value := 30 + (x+y)%5
+		if x >= 10 && x < 25 && y >= 10 && y < 30 {
+			value = 90 + (x+y)%5
+		} else if x >= 40 && y >= 5 && y < 35 {
+			value = 250
+		}
+		img.SetGray(x, y, color.Gray{Y: uint8(value)})
+		if x < 35 {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	_, empty, err := OtsuThresholdGrayMasked(img, image.NewGray(image.Rect(0, 0, 60, 40)), ThreshBinary, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if empty != 0 {
+		t.Errorf("Expected threshold 0 for an empty mask - actual threshold: %d", empty)
+	}
+	res, thresh, err := OtsuThresholdGrayMasked(img, mask, ThreshBinary, false)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if unmasked := otsuThresholdValue(img); unmasked == thresh || unmasked < 90 {
+		t.Errorf("Expected the unmasked threshold to separate the distractor - masked: %d, unmasked: %d", thresh, unmasked)
+	}
+	if thresh < 30 || thresh >= 90 {
+		t.Fatalf("Expected the masked threshold to separate the region of interest - actual threshold: %d", thresh)
+	}
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		expected := uint8(0)
+		if mask.GrayAt(x, y).Y != 0 && img.GrayAt(x, y).Y >= thresh {
+			expected = 0xFF
+		}
+		if x >= 10 && x < 25 && y >= 10 && y < 30 && res.GrayAt(x, y).Y != 0xFF {
+			t.Errorf("Expected the region of interest to be segmented at: %d %d", x, y)
+		}
+		if actual := res.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+		}
+	})
+	if _, _, err := OtsuThresholdGrayMasked(img, image.NewGray(image.Rect(0, 0, 3, 3)), ThreshBinary, false); err == nil {
+		t.Error("Expected error for a mask of a different size")
+	}
+}
+
+func Test_ThresholdWithMax(t *testing.T) {
+	gray := &image.Gray{
+		Rect:   image.Rect(0, 0, 4, 1),
+		Stride: 4,
+		Pix:    []uint8{0x00, 0x63, 0x64, 0xFF},
+	}
+	for _, maxValue := range []uint8{1, 128, 255} {
+		binary, err := ThresholdWithMax(gray, 100, maxValue, ThreshBinary)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, &image.Gray{Rect: gray.Rect, Stride: 4, Pix: []uint8{0, 0, maxValue, maxValue}}, binary)
+		binaryInv, err := ThresholdWithMax(gray, 100, maxValue, ThreshBinaryInv)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, &image.Gray{Rect: gray.Rect, Stride: 4, Pix: []uint8{maxValue, maxValue, 0, 0}}, binaryInv)
+		trunc, err := ThresholdWithMax(gray, 100, maxValue, ThreshTrunc)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, &image.Gray{Rect: gray.Rect, Stride: 4, Pix: []uint8{0x00, 0x63, 0x64, 0x64}}, trunc)
+	}
+	if _, err := ThresholdWithMax(gray, 100, 1, Method(42)); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+func Test_ThresholdROI(t *testing.T) {
+	img, _ := setupTestCaseDisk()
+	full, err := Threshold(img, 200, ThreshBinary)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	roi := image.Rect(10, 5, 35, 40)
+	res, err := ThresholdROI(img, roi, 200, ThreshBinary)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		expected := img.GrayAt(x, y).Y
+		if (image.Point{X: x, Y: y}).In(roi) {
+			expected = full.GrayAt(x, y).Y
+		}
+		if actual := res.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+		}
+	})
+	if _, err := ThresholdROI(img, image.Rect(30, 30, 50, 50), 200, ThreshBinary); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+func Test_ScanlineRuns(t *testing.T) {
+	// quiet zone, bars and spaces of 1 to 4 modules of 3 pixels, quiet zone
+	img, expected := setupTestCaseBarcode(60, 180, 0)
+	actual, err := ScanlineRuns(img, 2, 128)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	compareRuns(t, expected, actual)
+	if _, err := ScanlineRuns(img, 5, 128); err == nil {
+		t.Errorf("Expected error for a row outside of the image")
+	}
+	if _, err := ScanlineRunsBand(img, 3, 3, 128); err == nil {
+		t.Errorf("Expected error for a band outside of the image")
+	}
+	if _, err := ScanlineRunsBand(img, 0, 0, 128); err == nil {
+		t.Errorf("Expected error for an empty band")
+	}
+}
+
+func Test_ScanlineRunsAdaptive_Ramp(t *testing.T) {
+	// an overexposed frame: the bars are brighter then 128 and a ramp from 0 to 20 is added to the whole row
+	img, expected := setupTestCaseBarcode(140, 215, 20)
+	if actual, err := ScanlineRuns(img, 0, 128); err == nil && len(actual) == len(expected) {
+		t.Errorf("Expected the fixed threshold to fail on the ramp")
+	}
+	actual, err := ScanlineRunsAdaptive(img, 0, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	compareRuns(t, expected, actual)
+}
+
+// -------------------------------------------------------------------------------
+
+// setupTestCaseBarcode draws a 5 rows high barcode with the given values of the bars and the spaces and a brightness
+// ramp of the given amplitude from left to right, and returns it together with its runs.
+func setupTestCaseBarcode(bar, space, ramp int) (*image.Gray, []Run) {
+	modules := []int{4, 1, 1, 2, 3, 1, 4, 2, 1, 3, 1, 1, 4}
+	var expected []Run
+	x := 0
+	for i, m := range modules {
+		expected = append(expected, Run{Start: x, Length: 3 * m, Black: i%2 == 1})
+		x += 3 * m
+	}
+	img := image.NewGray(image.Rect(0, 0, x, 5))
+	for _, run := range expected {
+		for px := run.Start; px < run.Start+run.Length; px++ {
+			value := space
+			if run.Black {
+				value = bar
+			}
+			// the ramp and a little noise which differs row by row
+			value += ramp*px/x + (px*7+run.Start)%5
+			for y := 0; y < 5; y++ {
+				img.SetGray(px, y, color.Gray{Y: uint8(value - 2 + (px+y)%3)})
+			}
+		}
+	}
+	return img, expected
+}
+
+func compareRuns(t *testing.T, expected, actual []Run) {
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %d runs - actual: %d %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("Expected run %v - actual: %v at %d", expected[i], actual[i], i)
+		}
+	}
+}
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/girl.jpg"
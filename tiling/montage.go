@@ -0,0 +1,107 @@
+package tiling
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// MontageRGBA lays out images on a contact sheet: a grid with the given number of columns and as many rows as needed,
+// where every cell has the given size. Every image is scaled with the given interpolation to the largest size which
+// fits into its cell while keeping its aspect ratio, it is centered in the cell and the rest of the sheet is filled
+// with the background color. The images fill the grid row by row. Images which have exactly the size of the cell are
+// copied without resampling. Returns an error if there are no images, an image is nil or empty, the number of columns
+// or the cell size is not positive, or the interpolation method is invalid.
+// Example of usage:
+//
+//	sheet, err := tiling.MontageRGBA(thumbnails, 4, image.Point{X: 160, Y: 120}, color.RGBA{A: 255}, resize.InterLinear)
+func MontageRGBA(imgs []*image.RGBA, cols int, cellSize image.Point, background color.RGBA, interpolation resize.Interpolation) (*image.RGBA, error) {
+	if len(imgs) == 0 {
+		return nil, errors.New("no images")
+	}
+	if cols <= 0 {
+		return nil, errors.New("the number of columns must be positive")
+	}
+	if cellSize.X <= 0 || cellSize.Y <= 0 {
+		return nil, errors.New("cell size must be positive")
+	}
+	rows := (len(imgs) + cols - 1) / cols
+	res := image.NewRGBA(image.Rect(0, 0, cols*cellSize.X, rows*cellSize.Y))
+	for i := 0; i < len(res.Pix); i += 4 {
+		res.Pix[i], res.Pix[i+1], res.Pix[i+2], res.Pix[i+3] = background.R, background.G, background.B, background.A
+	}
+	for i, img := range imgs {
+		if img == nil || img.Bounds().Empty() {
+			return nil, errors.New("empty image")
+		}
+		size := img.Bounds().Size()
+		f := math.Min(float64(cellSize.X)/float64(size.X), float64(cellSize.Y)/float64(size.Y))
+		fitted := image.Point{
+			X: utils.ClampInt(int(math.Round(float64(size.X)*f)), 1, cellSize.X),
+			Y: utils.ClampInt(int(math.Round(float64(size.Y)*f)), 1, cellSize.Y),
+		}
+		cell := image.Point{X: (i % cols) * cellSize.X, Y: (i / cols) * cellSize.Y}
+		min := cell.Add(cellSize.Sub(fitted).Div(2))
+		rect := image.Rectangle{Min: min, Max: min.Add(fitted)}
+		if fitted == size {
+			for y := 0; y < size.Y; y++ {
+				src := img.Pix[y*img.Stride : y*img.Stride+4*size.X]
+				copy(res.Pix[res.PixOffset(rect.Min.X, rect.Min.Y+y):], src)
+			}
+			continue
+		}
+		if err := resize.ResizeIntoRGBA(res, rect, img, interpolation); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// SplitGrid slices an RGBA image into rows x cols tiles, e.g. to run a model on the tiles of a big image, and returns
+// them row by row. If the size of the image is not divisible by the number of tiles, the first columns are one pixel
+// wider and the first rows are one pixel higher then the others, so the tiles cover the whole image. The tiles are
+// copies, their top left corner is at the origin. Returns an error if the number of rows or columns is not positive or
+// bigger then the size of the image.
+// Example of usage:
+//
+//	tiles, err := tiling.SplitGrid(img, 2, 3)
+func SplitGrid(img *image.RGBA, rows, cols int) ([]*image.RGBA, error) {
+	size := img.Bounds().Size()
+	if rows <= 0 || cols <= 0 {
+		return nil, errors.New("the number of rows and columns must be positive")
+	}
+	if rows > size.Y || cols > size.X {
+		return nil, errors.New("more tiles then pixels")
+	}
+	xs := splitPositions(size.X, cols)
+	ys := splitPositions(size.Y, rows)
+	tiles := make([]*image.RGBA, 0, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			tile := image.NewRGBA(image.Rect(0, 0, xs[c+1]-xs[c], ys[r+1]-ys[r]))
+			for y := 0; y < tile.Rect.Dy(); y++ {
+				start := img.PixOffset(img.Rect.Min.X+xs[c], img.Rect.Min.Y+ys[r]+y)
+				copy(tile.Pix[y*tile.Stride:(y+1)*tile.Stride], img.Pix[start:])
+			}
+			tiles = append(tiles, tile)
+		}
+	}
+	return tiles, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// splitPositions returns the n + 1 boundaries of n parts of a length, the first length % n parts are one longer.
+func splitPositions(length, n int) []int {
+	positions := make([]int, n+1)
+	for i := 0; i < n; i++ {
+		part := length / n
+		if i < length%n {
+			part++
+		}
+		positions[i+1] = positions[i] + part
+	}
+	return positions
+}
@@ -0,0 +1,111 @@
+package tiling
+
+import (
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_MontageRGBA_Layout(t *testing.T) {
+	background := color.RGBA{R: 10, G: 20, B: 30, A: 255}
+	// a wide, a tall and a cell sized image, each with a uniform color
+	sizes := []image.Point{{X: 40, Y: 10}, {X: 5, Y: 20}, {X: 20, Y: 20}}
+	colors := []color.RGBA{{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}}
+	imgs := make([]*image.RGBA, len(sizes))
+	for i := range sizes {
+		imgs[i] = uniformRGBA(sizes[i], colors[i])
+	}
+	res, err := MontageRGBA(imgs, 2, image.Point{X: 20, Y: 20}, background, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if size := res.Bounds().Size(); size.X != 40 || size.Y != 40 {
+		t.Fatalf("Expected a 40x40 sheet - actual: %dx%d", size.X, size.Y)
+	}
+	checks := []struct {
+		x, y     int
+		expected color.RGBA
+	}{
+		// the wide image is scaled to 20x5 and centered vertically in the first cell
+		{10, 10, colors[0]}, {10, 2, background}, {10, 17, background},
+		// the tall image is scaled to 5x20 and centered horizontally in the second cell
+		{30, 10, colors[1]}, {22, 10, background}, {37, 10, background},
+		// the third image fills the first cell of the second row
+		{0, 20, colors[2]}, {19, 39, colors[2]},
+		// the last cell is empty
+		{30, 30, background},
+	}
+	for _, check := range checks {
+		if actual := res.RGBAAt(check.x, check.y); actual != check.expected {
+			t.Errorf("Expected %v at (%d, %d) - actual: %v", check.expected, check.x, check.y, actual)
+		}
+	}
+}
+
+func Test_MontageRGBA_Errors(t *testing.T) {
+	img := uniformRGBA(image.Point{X: 4, Y: 4}, color.RGBA{A: 255})
+	cell := image.Point{X: 4, Y: 4}
+	if _, err := MontageRGBA(nil, 1, cell, color.RGBA{}, resize.InterLinear); err == nil {
+		t.Error("Expected error for no images")
+	}
+	if _, err := MontageRGBA([]*image.RGBA{img}, 0, cell, color.RGBA{}, resize.InterLinear); err == nil {
+		t.Error("Expected error for zero columns")
+	}
+	if _, err := MontageRGBA([]*image.RGBA{img}, 1, image.Point{X: 4}, color.RGBA{}, resize.InterLinear); err == nil {
+		t.Error("Expected error for an empty cell")
+	}
+	if _, err := MontageRGBA([]*image.RGBA{img, nil}, 1, cell, color.RGBA{}, resize.InterLinear); err == nil {
+		t.Error("Expected error for a nil image")
+	}
+}
+
+func Test_SplitGrid_Remainder(t *testing.T) {
+	img := uniformRGBA(image.Point{X: 11, Y: 7}, color.RGBA{A: 255})
+	tiles, err := SplitGrid(img, 2, 3)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := []image.Point{{X: 4, Y: 4}, {X: 4, Y: 4}, {X: 3, Y: 4}, {X: 4, Y: 3}, {X: 4, Y: 3}, {X: 3, Y: 3}}
+	if len(tiles) != len(expected) {
+		t.Fatalf("Expected %d tiles - actual: %d", len(expected), len(tiles))
+	}
+	for i, tile := range tiles {
+		if size := tile.Bounds().Size(); size != expected[i] {
+			t.Errorf("Expected tile %d to be %v - actual: %v", i, expected[i], size)
+		}
+	}
+	for _, grid := range [][2]int{{0, 1}, {1, 0}, {8, 1}, {1, 12}} {
+		if _, err := SplitGrid(img, grid[0], grid[1]); err == nil {
+			t.Errorf("Expected error for a %dx%d grid", grid[0], grid[1])
+		}
+	}
+}
+
+func Test_SplitGrid_MontageRoundTrip(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 24, 18))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, color.RGBA{R: uint8(x * 10), G: uint8(y * 13), B: uint8(x*y + 7), A: 255})
+	})
+	tiles, err := SplitGrid(img, 3, 4)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	res, err := MontageRGBA(tiles, 4, image.Point{X: 6, Y: 6}, color.RGBA{}, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareRGBAImages(t, img, res)
+}
+
+// -------------------------------------------------------------------------------
+
+func uniformRGBA(size image.Point, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	utils.ForEachPixel(size, func(x, y int) {
+		img.SetRGBA(x, y, c)
+	})
+	return img
+}
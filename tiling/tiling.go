@@ -0,0 +1,107 @@
+package tiling
+
+import (
+	"errors"
+	"image"
+	"runtime"
+	"sync"
+)
+
+// ProcessTiledGray cuts a grayscale image into tiles of the given size, runs fn on each tile and stitches the results
+// back into a single image. Every tile is extended with overlap pixels of context on each side (where the image allows
+// it), so neighbourhood operations like convolution see valid pixels near the edges of the tile. The overlapping
+// margins are discarded when the results are stitched, so for an overlap at least as big as the radius of the
+// operation the result is identical to processing the whole image at once. The tiles passed to fn always start at
+// the origin and fn has to return an image with the same size as its input.
+// Example of usage:
+//
+//	res, err := tiling.ProcessTiledGray(img, image.Point{X: 512, Y: 512}, 8, func(tile *image.Gray) (*image.Gray, error) {
+//		return blur.BoxGray(tile, image.Point{X: 5, Y: 5}, image.Point{X: 2, Y: 2}, padding.BorderReflect)
+//	})
+func ProcessTiledGray(img *image.Gray, tileSize image.Point, overlap int, fn func(tile *image.Gray) (*image.Gray, error)) (*image.Gray, error) {
+	return ProcessTiledGrayParallel(img, tileSize, overlap, 1, fn)
+}
+
+// ProcessTiledGrayParallel works the same way as ProcessTiledGray, but the tiles are processed in parallel by the given
+// number of workers. If workers is not positive, the number of available processor threads is used. The result does
+// not depend on the number of workers, fn has to be safe for concurrent calls.
+func ProcessTiledGrayParallel(img *image.Gray, tileSize image.Point, overlap int, workers int, fn func(tile *image.Gray) (*image.Gray, error)) (*image.Gray, error) {
+	if tileSize.X <= 0 || tileSize.Y <= 0 {
+		return nil, errors.New("tile size must be positive")
+	}
+	if overlap < 0 {
+		return nil, errors.New("negative overlap")
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+
+	var tiles []image.Rectangle
+	for y := 0; y < size.Y; y += tileSize.Y {
+		for x := 0; x < size.X; x += tileSize.X {
+			tiles = append(tiles, image.Rect(x, y, x+tileSize.X, y+tileSize.Y).Intersect(image.Rect(0, 0, size.X, size.Y)))
+		}
+	}
+
+	errs := make([]error, len(tiles))
+	jobs := make(chan int)
+	var waitGroup sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := range jobs {
+				errs[i] = processTile(img, res, tiles[i], overlap, fn)
+			}
+		}()
+	}
+	for i := range tiles {
+		jobs <- i
+	}
+	close(jobs)
+	waitGroup.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// processTile extracts the tile (extended with the overlap) from img, runs fn on it and copies the inner part of the
+// result into res. The tile rectangle is relative to the origin of img.
+func processTile(img *image.Gray, res *image.Gray, tile image.Rectangle, overlap int, fn func(tile *image.Gray) (*image.Gray, error)) error {
+	size := img.Bounds().Size()
+	extended := image.Rect(tile.Min.X-overlap, tile.Min.Y-overlap, tile.Max.X+overlap, tile.Max.Y+overlap).
+		Intersect(image.Rect(0, 0, size.X, size.Y))
+	input := cropGray(img, extended)
+	output, err := fn(input)
+	if err != nil {
+		return err
+	}
+	if !output.Bounds().Size().Eq(extended.Size()) {
+		return errors.New("the size of the processed tile does not match the size of the input tile")
+	}
+	offset := tile.Min.Sub(extended.Min)
+	for y := 0; y < tile.Dy(); y++ {
+		src := output.PixOffset(output.Rect.Min.X+offset.X, output.Rect.Min.Y+offset.Y+y)
+		dst := res.PixOffset(tile.Min.X, tile.Min.Y+y)
+		copy(res.Pix[dst:dst+tile.Dx()], output.Pix[src:src+tile.Dx()])
+	}
+	return nil
+}
+
+// cropGray copies a region (relative to the origin of img) into a new image which starts at the origin.
+func cropGray(img *image.Gray, r image.Rectangle) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, r.Dx(), r.Dy()))
+	min := img.Bounds().Min
+	for y := 0; y < r.Dy(); y++ {
+		src := img.PixOffset(min.X+r.Min.X, min.Y+r.Min.Y+y)
+		copy(res.Pix[y*res.Stride:y*res.Stride+r.Dx()], img.Pix[src:src+r.Dx()])
+	}
+	return res
+}
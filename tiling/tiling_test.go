@@ -0,0 +1,68 @@
+package tiling
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/convolution"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func setupTestCaseGray() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 53, 41))
+	for x := 0; x < 53; x++ {
+		for y := 0; y < 41; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8((x*37 + y*91 + x*y) % 256)})
+		}
+	}
+	return img
+}
+
+func Test_ProcessTiledGray_ConvolutionEquivalence(t *testing.T) {
+	img := setupTestCaseGray()
+	kernel, _ := convolution.NewKernel(7, 7)
+	for x := 0; x < 7; x++ {
+		for y := 0; y < 7; y++ {
+			kernel.Set(x, y, float64(x+2*y+1))
+		}
+	}
+	kernel = kernel.Normalize()
+	convolve := func(tile *image.Gray) (*image.Gray, error) {
+		res, _, err := convolution.ConvolveGray(tile, kernel, image.Point{X: 3, Y: 3}, padding.BorderReflect)
+		return res, err
+	}
+	expected, _ := convolve(img)
+	for _, overlap := range []int{3, 4, 8} {
+		for _, workers := range []int{1, 4} {
+			actual, err := ProcessTiledGrayParallel(img, image.Point{X: 16, Y: 12}, overlap, workers, convolve)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			utils.CompareGrayImages(t, expected, actual)
+		}
+	}
+}
+
+func Test_ProcessTiledGray_Errors(t *testing.T) {
+	img := setupTestCaseGray()
+	identity := func(tile *image.Gray) (*image.Gray, error) { return tile, nil }
+	if _, err := ProcessTiledGray(img, image.Point{X: 0, Y: 10}, 2, identity); err == nil {
+		t.Error("Expected error for invalid tile size")
+	}
+	if _, err := ProcessTiledGray(img, image.Point{X: 10, Y: 10}, -1, identity); err == nil {
+		t.Error("Expected error for negative overlap")
+	}
+	failing := func(tile *image.Gray) (*image.Gray, error) { return nil, errors.New("failed") }
+	if _, err := ProcessTiledGray(img, image.Point{X: 10, Y: 10}, 2, failing); err == nil {
+		t.Error("Expected error from the tile function")
+	}
+	shrinking := func(tile *image.Gray) (*image.Gray, error) { return image.NewGray(image.Rect(0, 0, 1, 1)), nil }
+	if _, err := ProcessTiledGray(img, image.Point{X: 10, Y: 10}, 2, shrinking); err == nil {
+		t.Error("Expected error for mismatching tile size")
+	}
+}
+
+// -------------------------------------------------------------------------------
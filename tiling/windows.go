@@ -0,0 +1,33 @@
+package tiling
+
+import (
+	"errors"
+	"image"
+)
+
+// SlidingWindowsGray extracts the patches of a grayscale image under a window moved over the image by the given stride,
+// e.g. for patch based feature extraction. The windows are returned row by row, starting at the top left corner of
+// the image, the windows which would extend past the right or bottom border are left out. A stride smaller then the
+// window gives overlapping patches. The patches are copies which keep the bounds of their window, so the position of a
+// patch in the image is patch.Bounds().Min. Returns an error if the size of the window or the stride is not positive.
+// Example of usage:
+//
+//	patches, err := tiling.SlidingWindowsGray(img, image.Point{X: 32, Y: 32}, image.Point{X: 16, Y: 16})
+func SlidingWindowsGray(img *image.Gray, windowSize, stride image.Point) ([]*image.Gray, error) {
+	if windowSize.X <= 0 || windowSize.Y <= 0 || stride.X <= 0 || stride.Y <= 0 {
+		return nil, errors.New("the size of the window and the stride must be positive")
+	}
+	bounds := img.Bounds()
+	var patches []*image.Gray
+	for y := bounds.Min.Y; y+windowSize.Y <= bounds.Max.Y; y += stride.Y {
+		for x := bounds.Min.X; x+windowSize.X <= bounds.Max.X; x += stride.X {
+			patch := image.NewGray(image.Rect(x, y, x+windowSize.X, y+windowSize.Y))
+			for row := 0; row < windowSize.Y; row++ {
+				start := img.PixOffset(x, y+row)
+				copy(patch.Pix[row*patch.Stride:(row+1)*patch.Stride], img.Pix[start:start+windowSize.X])
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches, nil
+}
@@ -0,0 +1,69 @@
+package tiling
+
+import (
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_SlidingWindowsGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	patches, err := SlidingWindowsGray(img, image.Point{X: 4, Y: 4}, image.Point{X: 3, Y: 3})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the windows start at 0, 3 and 6 in both directions, a window at 9 would extend past the border
+	if len(patches) != 9 {
+		t.Fatalf("Expected 9 patches - actual: %d", len(patches))
+	}
+	for i, patch := range patches {
+		expected := image.Rect(3*(i%3), 3*(i/3), 3*(i%3)+4, 3*(i/3)+4)
+		if patch.Bounds() != expected {
+			t.Fatalf("Expected bounds: %v - actual bounds: %v for patch: %d", expected, patch.Bounds(), i)
+		}
+		for y := expected.Min.Y; y < expected.Max.Y; y++ {
+			for x := expected.Min.X; x < expected.Max.X; x++ {
+				if patch.GrayAt(x, y) != img.GrayAt(x, y) {
+					t.Fatalf("Expected value: %d - actual value: %d at: %d %d of patch: %d", img.GrayAt(x, y).Y, patch.GrayAt(x, y).Y, x, y, i)
+				}
+			}
+		}
+	}
+	// the patches are copies
+	patches[4].Pix[0] = 0
+	if img.GrayAt(3, 3).Y != 33 {
+		t.Errorf("Expected the image to be unchanged after a patch was modified")
+	}
+}
+
+func Test_SlidingWindowsGray_Edges(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	sub := img.SubImage(image.Rect(2, 1, 9, 6)).(*image.Gray)
+	patches, err := SlidingWindowsGray(sub, image.Point{X: 7, Y: 2}, image.Point{X: 1, Y: 2})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := []image.Rectangle{image.Rect(2, 1, 9, 3), image.Rect(2, 3, 9, 5)}
+	if len(patches) != len(expected) {
+		t.Fatalf("Expected %d patches - actual: %d", len(expected), len(patches))
+	}
+	for i, patch := range patches {
+		if patch.Bounds() != expected[i] {
+			t.Errorf("Expected bounds: %v - actual bounds: %v", expected[i], patch.Bounds())
+		}
+	}
+	patches, err = SlidingWindowsGray(img, image.Point{X: 11, Y: 4}, image.Point{X: 1, Y: 1})
+	if err != nil || len(patches) != 0 {
+		t.Errorf("Expected no patches for a window bigger then the image - actual: %d, error: %v", len(patches), err)
+	}
+	for _, c := range [][2]image.Point{{{X: 0, Y: 4}, {X: 1, Y: 1}}, {{X: 4, Y: 4}, {X: 1, Y: 0}}} {
+		if _, err := SlidingWindowsGray(img, c[0], c[1]); err == nil {
+			t.Errorf("Expected error for window %v and stride %v", c[0], c[1])
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
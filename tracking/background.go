@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// DefaultForegroundThreshold is the difference from the background above which a pixel is reported as foreground by
+// a new BackgroundSubtractor.
+const DefaultForegroundThreshold = 25
+
+// BackgroundSubtractor separates the moving foreground from a slowly changing background in a sequence of grayscale
+// frames. The background is a running average of the frames, it is kept in floating point so slow changes are learned
+// even if they are smaller then one gray level per frame. The first frame initializes the background and fixes the
+// size of the sequence.
+type BackgroundSubtractor struct {
+	// Threshold is the absolute difference from the background above which a pixel is foreground.
+	Threshold float64
+	alpha     float64
+	size      image.Point
+	// background[x][y]
+	background [][]float64
+}
+
+// NewRunningAverageSubtractor creates a BackgroundSubtractor whose background is the exponential moving average of the
+// frames: background = (1 - alpha) * background + alpha * frame after every frame. An alpha of 0 freezes the
+// background at the first frame, an alpha of 1 turns the subtractor into plain frame differencing. The threshold is
+// DefaultForegroundThreshold. Returns an error if alpha is not in the [0, 1] interval.
+// Example of usage:
+//
+//	subtractor, err := tracking.NewRunningAverageSubtractor(0.05)
+func NewRunningAverageSubtractor(alpha float64) (*BackgroundSubtractor, error) {
+	if alpha < 0 || alpha > 1 || math.IsNaN(alpha) {
+		return nil, errors.New("alpha must be in the [0, 1] interval")
+	}
+	return &BackgroundSubtractor{Threshold: DefaultForegroundThreshold, alpha: alpha}, nil
+}
+
+// Apply returns the foreground mask of the frame: 255 where the frame differs from the background more then the
+// threshold and 0 elsewhere. Afterwards the frame is blended into the background. The mask of the first frame is
+// empty. Returns an error if the size of the frame differs from the size of the first frame.
+// Example of usage:
+//
+//	mask, err := subtractor.Apply(frame)
+func (s *BackgroundSubtractor) Apply(frame *image.Gray) (*image.Gray, error) {
+	size := frame.Bounds().Size()
+	mask := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	if s.background == nil {
+		s.size = size
+		s.background = make([][]float64, size.X)
+		for x := range s.background {
+			s.background[x] = make([]float64, size.Y)
+		}
+		utils.ParallelForEachPixel(size, func(x, y int) {
+			s.background[x][y] = float64(frame.GrayAt(frame.Rect.Min.X+x, frame.Rect.Min.Y+y).Y)
+		})
+		return mask, nil
+	}
+	if size != s.size {
+		return nil, errors.New("the size of the frame does not match the size of the background")
+	}
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		value := float64(frame.GrayAt(frame.Rect.Min.X+x, frame.Rect.Min.Y+y).Y)
+		if math.Abs(value-s.background[x][y]) > s.Threshold {
+			mask.Pix[y*mask.Stride+x] = utils.MaxUint8
+		}
+		s.background[x][y] += s.alpha * (value - s.background[x][y])
+	})
+	return mask, nil
+}
+
+// FrameDifferenceGray returns the mask of the pixels whose absolute difference between the two frames is bigger then
+// the threshold: 255 for the changed pixels and 0 elsewhere. Returns an error if the sizes of the frames differ.
+// Example of usage:
+//
+//	mask, err := tracking.FrameDifferenceGray(previous, current, 25)
+func FrameDifferenceGray(previous, current *image.Gray, threshold uint8) (*image.Gray, error) {
+	size := previous.Bounds().Size()
+	if size != current.Bounds().Size() {
+		return nil, errors.New("the size of the two image does not match")
+	}
+	mask := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		p1 := float64(previous.GrayAt(previous.Rect.Min.X+x, previous.Rect.Min.Y+y).Y)
+		p2 := float64(current.GrayAt(current.Rect.Min.X+x, current.Rect.Min.Y+y).Y)
+		if math.Abs(p1-p2) > float64(threshold) {
+			mask.Pix[y*mask.Stride+x] = utils.MaxUint8
+		}
+	})
+	return mask, nil
+}
@@ -0,0 +1,118 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_BackgroundSubtractor_MovedSquare(t *testing.T) {
+	subtractor, err := NewRunningAverageSubtractor(0.5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	static := setupTestCaseScene(image.Rect(5, 5, 15, 15))
+	for i := 0; i < 3; i++ {
+		mask, err := subtractor.Apply(static)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if count := countForeground(mask, mask.Bounds()); count != 0 {
+			t.Fatalf("Expected an empty mask for the static scene - actual foreground pixels: %d", count)
+		}
+	}
+	moved := setupTestCaseScene(image.Rect(20, 20, 30, 30))
+	previous := -1
+	for i := 0; ; i++ {
+		mask, err := subtractor.Apply(moved)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		count := countForeground(mask, mask.Bounds())
+		if i == 0 {
+			// both the uncovered background and the square at its new position are foreground
+			if countForeground(mask, image.Rect(5, 5, 15, 15)) != 100 || countForeground(mask, image.Rect(20, 20, 30, 30)) != 100 || count != 200 {
+				t.Fatalf("Expected the old and the new position of the square in the mask - actual foreground pixels: %d", count)
+			}
+		} else if count > previous {
+			t.Fatalf("Expected the mask to shrink as the background adapts - %d after %d foreground pixels", count, previous)
+		}
+		if count == 0 {
+			break
+		}
+		if i > 10 {
+			t.Fatalf("Expected the background to adapt to the moved square")
+		}
+		previous = count
+	}
+	if _, err := subtractor.Apply(image.NewGray(image.Rect(0, 0, 10, 10))); err == nil {
+		t.Error("Expected error for a frame of different size")
+	}
+}
+
+func Test_BackgroundSubtractor_Frozen(t *testing.T) {
+	subtractor, err := NewRunningAverageSubtractor(0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	subtractor.Apply(setupTestCaseScene(image.Rect(5, 5, 15, 15)))
+	moved := setupTestCaseScene(image.Rect(20, 20, 30, 30))
+	for i := 0; i < 20; i++ {
+		mask, err := subtractor.Apply(moved)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if count := countForeground(mask, mask.Bounds()); count != 200 {
+			t.Fatalf("Expected the frozen background to keep the mask - actual foreground pixels: %d", count)
+		}
+	}
+	for _, alpha := range []float64{-0.1, 1.5} {
+		if _, err := NewRunningAverageSubtractor(alpha); err == nil {
+			t.Errorf("Expected error for alpha %f", alpha)
+		}
+	}
+}
+
+func Test_FrameDifferenceGray(t *testing.T) {
+	mask, err := FrameDifferenceGray(setupTestCaseScene(image.Rect(5, 5, 15, 15)), setupTestCaseScene(image.Rect(10, 5, 20, 15)), 25)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the overlapping part of the two positions does not change
+	if count := countForeground(mask, mask.Bounds()); count != 100 || countForeground(mask, image.Rect(10, 5, 15, 15)) != 0 {
+		t.Errorf("Expected the non-overlapping parts of the square in the mask - actual foreground pixels: %d", count)
+	}
+	if _, err := FrameDifferenceGray(mask, image.NewGray(image.Rect(0, 0, 3, 3)), 25); err == nil {
+		t.Error("Expected error for frames of different size")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+// setupTestCaseScene renders a bright square on a dark 40x40 background.
+func setupTestCaseScene(square image.Rectangle) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			value := uint8(50)
+			if (image.Point{X: x, Y: y}).In(square) {
+				value = 200
+			}
+			img.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+	return img
+}
+
+func countForeground(mask *image.Gray, rect image.Rectangle) int {
+	count := 0
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			if mask.GrayAt(x, y).Y != 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
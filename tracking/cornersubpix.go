@@ -0,0 +1,92 @@
+package tracking
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/geometry"
+	"github.com/yafeiliu/imger/pyramid"
+	"image"
+	"math"
+)
+
+// CornerSubPix refines the positions of corners (e.g. the ones found by a corner detector on a calibration pattern)
+// to sub-pixel accuracy. Around every corner a (2*winSize.X+1)x(2*winSize.Y+1) window is sampled and the corner is
+// moved to the point where the image gradients inside of the window are orthogonal to the vectors pointing from the
+// corner to their positions, this is repeated until the criteria are met. The refined positions are returned together
+// with a status for each corner, which is false when the window does not fit into the image, the window does not
+// contain enough gradient or the corner moved out of its window. For failed corners the original position is returned.
+// Example of usage:
+//
+//	refined, status, err := tracking.CornerSubPix(img, corners, image.Point{X: 5, Y: 5}, tracking.TermCriteria{MaxIterations: 40, Epsilon: 0.001})
+func CornerSubPix(img *image.Gray, corners []image.Point, winSize image.Point, criteria TermCriteria) ([]geometry.Point2f, []bool, error) {
+	if winSize.X < 1 || winSize.Y < 1 {
+		return nil, nil, errors.New("the half size of the window must be at least 1")
+	}
+	if criteria.MaxIterations <= 0 && criteria.Epsilon <= 0 {
+		return nil, nil, errors.New("at least one of the termination criteria has to be positive")
+	}
+	plane := pyramid.PlaneFromGray(img)
+	// the weights decrease towards the border of the window
+	weights := make([][]float64, 2*winSize.X+1)
+	for x := range weights {
+		weights[x] = make([]float64, 2*winSize.Y+1)
+		dx := float64(x-winSize.X) / float64(winSize.X)
+		for y := range weights[x] {
+			dy := float64(y-winSize.Y) / float64(winSize.Y)
+			weights[x][y] = math.Exp(-dx*dx - dy*dy)
+		}
+	}
+
+	positions := make([]geometry.Point2f, len(corners))
+	status := make([]bool, len(corners))
+	for i, c := range corners {
+		positions[i] = geometry.Point2f{X: float64(c.X), Y: float64(c.Y)}
+		// the gradients are computed with central differences, so one more pixel is needed on every side
+		if c.X-winSize.X-1 < 0 || c.Y-winSize.Y-1 < 0 || c.X+winSize.X+1 >= plane.Width || c.Y+winSize.Y+1 >= plane.Height {
+			continue
+		}
+		refined, ok := refineCorner(plane, positions[i], winSize, weights, criteria)
+		if !ok {
+			continue
+		}
+		positions[i] = refined
+		status[i] = true
+	}
+	return positions, status, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// refineCorner iteratively solves the normal equations of the gradient-orthogonality condition around the corner.
+func refineCorner(plane *pyramid.Plane, corner geometry.Point2f, winSize image.Point, weights [][]float64, criteria TermCriteria) (geometry.Point2f, bool) {
+	current := corner
+	for iteration := 0; criteria.MaxIterations <= 0 || iteration < criteria.MaxIterations; iteration++ {
+		var a11, a12, a22, b1, b2 float64
+		for wx := -winSize.X; wx <= winSize.X; wx++ {
+			for wy := -winSize.Y; wy <= winSize.Y; wy++ {
+				px, py := current.X+float64(wx), current.Y+float64(wy)
+				gx := (sampleBilinear(plane, px+1, py) - sampleBilinear(plane, px-1, py)) / 2
+				gy := (sampleBilinear(plane, px, py+1) - sampleBilinear(plane, px, py-1)) / 2
+				w := weights[wx+winSize.X][wy+winSize.Y]
+				gxx, gxy, gyy := w*gx*gx, w*gx*gy, w*gy*gy
+				a11 += gxx
+				a12 += gxy
+				a22 += gyy
+				b1 += gxx*px + gxy*py
+				b2 += gxy*px + gyy*py
+			}
+		}
+		det := a11*a22 - a12*a12
+		if math.Abs(det) < 1e-9*(a11+a22)*(a11+a22) || det == 0 {
+			return corner, false
+		}
+		next := geometry.Point2f{X: (a22*b1 - a12*b2) / det, Y: (a11*b2 - a12*b1) / det}
+		if math.Abs(next.X-corner.X) > float64(winSize.X) || math.Abs(next.Y-corner.Y) > float64(winSize.Y) {
+			return corner, false
+		}
+		shift := math.Hypot(next.X-current.X, next.Y-current.Y)
+		current = next
+		if criteria.Epsilon > 0 && shift < criteria.Epsilon {
+			break
+		}
+	}
+	return current, true
+}
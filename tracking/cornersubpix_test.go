@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseCheckerboard renders an anti-aliased checkerboard with squares of the given size, which is rotated by
+// angle around its origin (ox, oy). Returns the image and the positions of the inner corners.
+func setupTestCaseCheckerboard(ox, oy, square, angle float64) (*image.Gray, [][2]float64) {
+	const samples = 8
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	img := image.NewGray(image.Rect(0, 0, 120, 100))
+	for x := 0; x < 120; x++ {
+		for y := 0; y < 100; y++ {
+			white := 0
+			for sx := 0; sx < samples; sx++ {
+				for sy := 0; sy < samples; sy++ {
+					px := float64(x) - 0.5 + (float64(sx)+0.5)/samples - ox
+					py := float64(y) - 0.5 + (float64(sy)+0.5)/samples - oy
+					u := math.Floor((cos*px + sin*py) / square)
+					v := math.Floor((-sin*px + cos*py) / square)
+					if int(u+v)%2 == 0 {
+						white++
+					}
+				}
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(20 + 200*white/(samples*samples))})
+		}
+	}
+	var corners [][2]float64
+	for i := 1; i <= 4; i++ {
+		for j := 1; j <= 3; j++ {
+			u, v := float64(i)*square, float64(j)*square
+			corners = append(corners, [2]float64{ox + cos*u - sin*v, oy + sin*u + cos*v})
+		}
+	}
+	return img, corners
+}
+
+func Test_CornerSubPix(t *testing.T) {
+	img, corners := setupTestCaseCheckerboard(12.3, 8.65, 18, 0.12)
+	guesses := make([]image.Point, len(corners))
+	for i, c := range corners {
+		// start about a pixel away from the true corner
+		guesses[i] = image.Point{X: int(math.Round(c[0])) + 1, Y: int(math.Round(c[1])) - 1}
+	}
+	refined, status, err := CornerSubPix(img, guesses, image.Point{X: 5, Y: 5}, TermCriteria{MaxIterations: 40, Epsilon: 0.001})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, c := range corners {
+		if !status[i] {
+			t.Errorf("Expected corner %d to be refined", i)
+			continue
+		}
+		if d := math.Hypot(refined[i].X-c[0], refined[i].Y-c[1]); d > 0.1 {
+			t.Errorf("Expected corner: %.2f %.2f - actual corner: %.2f %.2f", c[0], c[1], refined[i].X, refined[i].Y)
+		}
+	}
+}
+
+func Test_CornerSubPix_Unrefined(t *testing.T) {
+	img, _ := setupTestCaseCheckerboard(12.3, 8.65, 18, 0.12)
+	flat := image.NewGray(image.Rect(0, 0, 40, 40))
+	corners := []image.Point{{X: 3, Y: 50}, {X: 115, Y: 50}}
+	refined, status, err := CornerSubPix(img, corners, image.Point{X: 5, Y: 5}, TermCriteria{MaxIterations: 10})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, c := range corners {
+		if status[i] || refined[i].X != float64(c.X) || refined[i].Y != float64(c.Y) {
+			t.Errorf("Expected corner %d with its window outside of the image to be unrefined - actual: %v %v", i, refined[i], status[i])
+		}
+	}
+	if _, status, _ := CornerSubPix(flat, []image.Point{{X: 20, Y: 20}}, image.Point{X: 5, Y: 5}, TermCriteria{Epsilon: 0.01}); status[0] {
+		t.Error("Expected a corner without gradients to be unrefined")
+	}
+	if _, _, err := CornerSubPix(img, corners, image.Point{X: 0, Y: 5}, TermCriteria{MaxIterations: 10}); err == nil {
+		t.Error("Expected error for an empty window")
+	}
+	if _, _, err := CornerSubPix(img, corners, image.Point{X: 5, Y: 5}, TermCriteria{}); err == nil {
+		t.Error("Expected error for disabled termination criteria")
+	}
+}
+
+// -------------------------------------------------------------------------------
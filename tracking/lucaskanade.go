@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/geometry"
+	"github.com/yafeiliu/imger/pyramid"
+	"image"
+	"math"
+)
+
+const (
+	// lkMaxIterations is the maximum number of iterations of the Lucas-Kanade solver on a pyramid level.
+	lkMaxIterations = 30
+	// lkEpsilon stops the iterations on a pyramid level when the update is smaller then this many pixels.
+	lkEpsilon = 0.01
+	// lkMinEigenvalue is the smallest accepted eigenvalue of the spatial gradient matrix, divided by the number of
+	// pixels in the window. Points with a smaller value do not have enough texture to be tracked.
+	lkMinEigenvalue = 1e-2
+)
+
+// LucasKanadeFlow tracks sparse points from the prev image to the next image using the pyramidal Lucas-Kanade optical
+// flow method. Both images are decomposed into Gaussian pyramids with maxLevel levels above the original image and the
+// displacement of every point is refined from the coarsest level to the finest one by solving the 2x2 normal
+// equations over a windowSize x windowSize window around the point. The new sub-pixel positions are returned together
+// with a status for each point, which is false when the window does not contain enough texture (the gradient matrix
+// is near-singular) or when the tracked point left the image. For failed points the original position is returned.
+// Example of usage:
+//
+//	positions, status, err := tracking.LucasKanadeFlow(prev, next, points, 21, 3)
+func LucasKanadeFlow(prev, next *image.Gray, points []image.Point, windowSize int, maxLevel int) ([]geometry.Point2f, []bool, error) {
+	if prev.Bounds().Size() != next.Bounds().Size() {
+		return nil, nil, errors.New("the size of the two image does not match")
+	}
+	if windowSize < 3 || windowSize%2 == 0 {
+		return nil, nil, errors.New("window size must be an odd number bigger then 2")
+	}
+	if maxLevel < 0 {
+		return nil, nil, errors.New("the maximum pyramid level must not be negative")
+	}
+	prevPyramid, err := pyramid.GaussianPyramid(pyramid.PlaneFromGray(prev), maxLevel+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	nextPyramid, err := pyramid.GaussianPyramid(pyramid.PlaneFromGray(next), maxLevel+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := prev.Bounds().Size()
+	positions := make([]geometry.Point2f, len(points))
+	status := make([]bool, len(points))
+	for i, p := range points {
+		positions[i] = geometry.Point2f{X: float64(p.X), Y: float64(p.Y)}
+		gx, gy, ok := trackPoint(prevPyramid, nextPyramid, float64(p.X), float64(p.Y), windowSize/2)
+		if !ok {
+			continue
+		}
+		nx, ny := float64(p.X)+gx, float64(p.Y)+gy
+		if nx < 0 || ny < 0 || nx > float64(size.X-1) || ny > float64(size.Y-1) {
+			continue
+		}
+		positions[i] = geometry.Point2f{X: nx, Y: ny}
+		status[i] = true
+	}
+	return positions, status, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// trackPoint computes the displacement of the point (x, y) between the two pyramids.
+func trackPoint(prevPyramid, nextPyramid []*pyramid.Plane, x, y float64, half int) (float64, float64, bool) {
+	var gx, gy float64
+	for level := len(prevPyramid) - 1; level >= 0; level-- {
+		scale := math.Pow(2, float64(level))
+		ux, uy := x/scale, y/scale
+		prevLevel, nextLevel := prevPyramid[level], nextPyramid[level]
+
+		var gxx, gxy, gyy float64
+		n := (2*half + 1) * (2*half + 1)
+		ix := make([]float64, 0, n)
+		iy := make([]float64, 0, n)
+		values := make([]float64, 0, n)
+		for wy := -half; wy <= half; wy++ {
+			for wx := -half; wx <= half; wx++ {
+				px, py := ux+float64(wx), uy+float64(wy)
+				dx := (sampleBilinear(prevLevel, px+1, py) - sampleBilinear(prevLevel, px-1, py)) / 2
+				dy := (sampleBilinear(prevLevel, px, py+1) - sampleBilinear(prevLevel, px, py-1)) / 2
+				gxx += dx * dx
+				gxy += dx * dy
+				gyy += dy * dy
+				ix = append(ix, dx)
+				iy = append(iy, dy)
+				values = append(values, sampleBilinear(prevLevel, px, py))
+			}
+		}
+		det := gxx*gyy - gxy*gxy
+		minEigenvalue := (gxx + gyy - math.Sqrt((gxx-gyy)*(gxx-gyy)+4*gxy*gxy)) / 2
+		if minEigenvalue/float64(n) < lkMinEigenvalue || det == 0 {
+			return 0, 0, false
+		}
+
+		var vx, vy float64
+		for iteration := 0; iteration < lkMaxIterations; iteration++ {
+			var bx, by float64
+			k := 0
+			for wy := -half; wy <= half; wy++ {
+				for wx := -half; wx <= half; wx++ {
+					diff := values[k] - sampleBilinear(nextLevel, ux+float64(wx)+gx+vx, uy+float64(wy)+gy+vy)
+					bx += diff * ix[k]
+					by += diff * iy[k]
+					k++
+				}
+			}
+			etaX := (gyy*bx - gxy*by) / det
+			etaY := (gxx*by - gxy*bx) / det
+			vx += etaX
+			vy += etaY
+			if math.Hypot(etaX, etaY) < lkEpsilon {
+				break
+			}
+		}
+		gx, gy = gx+vx, gy+vy
+		if level > 0 {
+			gx, gy = 2*gx, 2*gy
+		}
+	}
+	return gx, gy, true
+}
+
+// sampleBilinear samples the plane at a sub-pixel position, positions outside of the plane are clamped to the border.
+func sampleBilinear(p *pyramid.Plane, x, y float64) float64 {
+	x = math.Max(0, math.Min(x, float64(p.Width-1)))
+	y = math.Max(0, math.Min(y, float64(p.Height-1)))
+	x0, y0 := int(x), int(y)
+	x1, y1 := x0+1, y0+1
+	if x1 >= p.Width {
+		x1 = x0
+	}
+	if y1 >= p.Height {
+		y1 = y0
+	}
+	fx, fy := x-float64(x0), y-float64(y0)
+	return (1-fx)*(1-fy)*p.At(x0, y0) + fx*(1-fy)*p.At(x1, y0) + (1-fx)*fy*p.At(x0, y1) + fx*fy*p.At(x1, y1)
+}
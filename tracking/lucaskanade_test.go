@@ -0,0 +1,72 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseTexture renders a smooth synthetic texture shifted by (dx, dy).
+func setupTestCaseTexture(dx, dy float64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 160, 120))
+	for x := 0; x < 160; x++ {
+		for y := 0; y < 120; y++ {
+			u, v := float64(x)-dx, float64(y)-dy
+			value := 128 + 45*math.Sin(u*0.21) + 40*math.Cos(v*0.17) + 30*math.Sin((u+v)*0.11) + 20*math.Cos((u-2*v)*0.07)
+			img.SetGray(x, y, color.Gray{Y: uint8(math.Round(value))})
+		}
+	}
+	return img
+}
+
+func Test_LucasKanadeFlow(t *testing.T) {
+	dx, dy := 2.6, -1.4
+	prev := setupTestCaseTexture(0, 0)
+	next := setupTestCaseTexture(dx, dy)
+	points := []image.Point{{X: 40, Y: 40}, {X: 80, Y: 60}, {X: 120, Y: 30}, {X: 60, Y: 90}, {X: 110, Y: 80}}
+	positions, status, err := LucasKanadeFlow(prev, next, points, 15, 2)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i, p := range points {
+		if !status[i] {
+			t.Errorf("Expected point %v to be tracked", p)
+			continue
+		}
+		if math.Abs(positions[i].X-float64(p.X)-dx) > 0.2 || math.Abs(positions[i].Y-float64(p.Y)-dy) > 0.2 {
+			t.Errorf("Expected displacement: %f %f - actual displacement: %f %f for: %v", dx, dy,
+				positions[i].X-float64(p.X), positions[i].Y-float64(p.Y), p)
+		}
+	}
+}
+
+func Test_LucasKanadeFlow_Untrackable(t *testing.T) {
+	flat := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range flat.Pix {
+		flat.Pix[i] = 0x80
+	}
+	positions, status, err := LucasKanadeFlow(flat, flat, []image.Point{{X: 32, Y: 32}}, 9, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if status[0] || positions[0].X != 32 || positions[0].Y != 32 {
+		t.Errorf("Expected the point on a flat image to fail - actual: %v %v", status[0], positions[0])
+	}
+}
+
+func Test_LucasKanadeFlow_Invalid(t *testing.T) {
+	img := setupTestCaseTexture(0, 0)
+	if _, _, err := LucasKanadeFlow(img, image.NewGray(image.Rect(0, 0, 10, 10)), nil, 9, 1); err == nil {
+		t.Error("Should not reach this point")
+	}
+	if _, _, err := LucasKanadeFlow(img, img, nil, 8, 1); err == nil {
+		t.Error("Should not reach this point")
+	}
+	if _, _, err := LucasKanadeFlow(img, img, nil, 9, -1); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
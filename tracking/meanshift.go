@@ -0,0 +1,166 @@
+package tracking
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/geometry"
+	"image"
+	"math"
+)
+
+// TermCriteria defines when an iterative algorithm stops: after MaxIterations iterations or when the update of an
+// iteration is smaller then Epsilon. A non-positive value disables the given criterion, but at least one of them has to
+// be enabled.
+type TermCriteria struct {
+	MaxIterations int
+	Epsilon       float64
+}
+
+// camShiftTolerance is the number of pixels the search window of CamShift is extended with on every side when the size
+// and the orientation of the object are computed.
+const camShiftTolerance = 10
+
+// MeanShift finds the object on a probability image (e.g. a back-projection, see histogram.CalcBackProjectHSV) by
+// iteratively moving the search window to the centroid of the probability mass inside of it. The window moves by whole
+// pixels and the iterations stop when it does not move or it moves less then criteria.Epsilon pixels, after
+// criteria.MaxIterations iterations or when the window does not contain any probability mass. The window keeps its
+// size and it is clamped to the bounds of the image. Returns the final window and the number of iterations.
+// Example of usage:
+//
+//	window, iterations, err := tracking.MeanShift(prob, window, tracking.TermCriteria{MaxIterations: 10, Epsilon: 1})
+func MeanShift(probImage *image.Gray, window image.Rectangle, criteria TermCriteria) (image.Rectangle, int, error) {
+	if err := validateTracking(probImage, window, criteria); err != nil {
+		return image.Rectangle{}, 0, err
+	}
+	maxIterations := criteria.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = math.MaxInt32
+	}
+	window = clampWindow(window, probImage.Bounds())
+	iterations := 0
+	for iterations < maxIterations {
+		m := windowMoments(probImage, window)
+		if m.m00 == 0 {
+			break
+		}
+		iterations++
+		cx, cy := m.m10/m.m00, m.m01/m.m00
+		dx := int(math.Round(cx - (float64(window.Min.X) + float64(window.Dx()-1)/2)))
+		dy := int(math.Round(cy - (float64(window.Min.Y) + float64(window.Dy()-1)/2)))
+		moved := clampWindow(window.Add(image.Point{X: dx, Y: dy}), probImage.Bounds())
+		shift := moved.Min.Sub(window.Min)
+		window = moved
+		if math.Hypot(float64(shift.X), float64(shift.Y)) < math.Max(criteria.Epsilon, 1) {
+			break
+		}
+	}
+	return window, iterations, nil
+}
+
+// CamShift (Continuously Adaptive Mean Shift) finds the object on a probability image like MeanShift, then it adapts
+// the size of the search window to the size of the object and it computes the orientation of the object from the
+// second order moments of the probability mass. Returns the oriented rectangle of the object and the search window for
+// the next frame, which is clamped to the bounds of the image.
+// Example of usage:
+//
+//	box, window, err := tracking.CamShift(prob, window, tracking.TermCriteria{MaxIterations: 10, Epsilon: 1})
+func CamShift(probImage *image.Gray, window image.Rectangle, criteria TermCriteria) (geometry.RotatedRect, image.Rectangle, error) {
+	window, _, err := MeanShift(probImage, window, criteria)
+	if err != nil {
+		return geometry.RotatedRect{}, image.Rectangle{}, err
+	}
+	search := window.Inset(-camShiftTolerance).Intersect(probImage.Bounds())
+	m := windowMoments(probImage, search)
+	if m.m00 == 0 {
+		return geometry.RotatedRect{}, window, nil
+	}
+	xc, yc := m.m10/m.m00, m.m01/m.m00
+	a := m.m20/m.m00 - xc*xc
+	b := m.m11/m.m00 - xc*yc
+	c := m.m02/m.m00 - yc*yc
+	square := math.Sqrt(4*b*b + (a-c)*(a-c))
+	theta := math.Atan2(2*b, a-c+square)
+	// the axes of an ellipse with the same second order moments, twice the standard deviation on both sides
+	length := 4 * math.Sqrt(math.Max((a+c+square)/2, 0))
+	width := 4 * math.Sqrt(math.Max((a+c-square)/2, 0))
+
+	cos, sin := math.Abs(math.Cos(theta)), math.Abs(math.Sin(theta))
+	halfX := (length*cos + width*sin) / 2
+	halfY := (length*sin + width*cos) / 2
+	next := image.Rect(int(math.Floor(xc-halfX)), int(math.Floor(yc-halfY)),
+		int(math.Ceil(xc+halfX))+1, int(math.Ceil(yc+halfY))+1).Intersect(probImage.Bounds())
+	if next.Empty() {
+		next = window
+	}
+
+	angle := theta * 180 / math.Pi
+	if angle < 0 {
+		angle += 180
+	}
+	if angle >= 90 {
+		angle -= 90
+		length, width = width, length
+	}
+	box := geometry.RotatedRect{Center: geometry.Point2f{X: xc, Y: yc}, Width: length, Height: width, Angle: angle}
+	return box, next, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+type moments struct {
+	m00, m10, m01, m20, m11, m02 float64
+}
+
+func windowMoments(img *image.Gray, window image.Rectangle) moments {
+	var m moments
+	for y := window.Min.Y; y < window.Max.Y; y++ {
+		for x := window.Min.X; x < window.Max.X; x++ {
+			p := float64(img.GrayAt(x, y).Y)
+			if p == 0 {
+				continue
+			}
+			fx, fy := float64(x), float64(y)
+			m.m00 += p
+			m.m10 += p * fx
+			m.m01 += p * fy
+			m.m20 += p * fx * fx
+			m.m11 += p * fx * fy
+			m.m02 += p * fy * fy
+		}
+	}
+	return m
+}
+
+// clampWindow moves the window inside of the bounds keeping its size, the window is cropped if it is bigger then the
+// bounds.
+func clampWindow(window image.Rectangle, bounds image.Rectangle) image.Rectangle {
+	if window.Dx() > bounds.Dx() {
+		window.Min.X, window.Max.X = bounds.Min.X, bounds.Max.X
+	}
+	if window.Dy() > bounds.Dy() {
+		window.Min.Y, window.Max.Y = bounds.Min.Y, bounds.Max.Y
+	}
+	shift := image.Point{}
+	if window.Min.X < bounds.Min.X {
+		shift.X = bounds.Min.X - window.Min.X
+	} else if window.Max.X > bounds.Max.X {
+		shift.X = bounds.Max.X - window.Max.X
+	}
+	if window.Min.Y < bounds.Min.Y {
+		shift.Y = bounds.Min.Y - window.Min.Y
+	} else if window.Max.Y > bounds.Max.Y {
+		shift.Y = bounds.Max.Y - window.Max.Y
+	}
+	return window.Add(shift)
+}
+
+func validateTracking(probImage *image.Gray, window image.Rectangle, criteria TermCriteria) error {
+	if window.Empty() {
+		return errors.New("empty search window")
+	}
+	if probImage.Bounds().Empty() {
+		return errors.New("empty probability image")
+	}
+	if criteria.MaxIterations <= 0 && criteria.Epsilon <= 0 {
+		return errors.New("at least one of the termination criteria has to be positive")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseBlob renders a Gaussian blob with the given standard deviations along its axes, the major axis is
+// rotated with angle degrees from the x axis towards the y axis.
+func setupTestCaseBlob(cx, cy, sigmaMajor, sigmaMinor, angle float64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 200, 150))
+	radians := angle * math.Pi / 180
+	sin, cos := math.Sin(radians), math.Cos(radians)
+	for x := 0; x < 200; x++ {
+		for y := 0; y < 150; y++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			u, v := dx*cos+dy*sin, -dx*sin+dy*cos
+			value := 255 * math.Exp(-(u*u/(2*sigmaMajor*sigmaMajor) + v*v/(2*sigmaMinor*sigmaMinor)))
+			img.SetGray(x, y, color.Gray{Y: uint8(math.Round(value))})
+		}
+	}
+	return img
+}
+
+func Test_MeanShift(t *testing.T) {
+	prob := setupTestCaseBlob(100, 70, 10, 10, 0)
+	start := image.Rect(110, 50, 150, 90) // centered 30 pixels right of the blob
+	window, iterations, err := MeanShift(prob, start, TermCriteria{MaxIterations: 20, Epsilon: 1})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if iterations == 0 || iterations >= 20 {
+		t.Errorf("Expected to converge within 20 iterations - actual iterations: %d", iterations)
+	}
+	if window.Size() != start.Size() {
+		t.Errorf("Expected the window size to be kept - actual size: %v", window.Size())
+	}
+	cx, cy := float64(window.Min.X)+float64(window.Dx()-1)/2, float64(window.Min.Y)+float64(window.Dy()-1)/2
+	if math.Abs(cx-100) > 1 || math.Abs(cy-70) > 1 {
+		t.Errorf("Expected window center: 100 70 - actual center: %f %f", cx, cy)
+	}
+}
+
+func Test_MeanShift_Clamp(t *testing.T) {
+	prob := setupTestCaseBlob(4, 4, 6, 6, 0)
+	window, _, err := MeanShift(prob, image.Rect(-30, -20, 10, 20), TermCriteria{MaxIterations: 10})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !window.In(prob.Bounds()) || window.Size() != (image.Point{X: 40, Y: 40}) {
+		t.Errorf("Expected a 40x40 window inside of the image - actual window: %v", window)
+	}
+	if _, _, err := MeanShift(prob, image.Rect(0, 0, 10, 10), TermCriteria{}); err == nil {
+		t.Error("Expected error for disabled termination criteria")
+	}
+	if _, _, err := MeanShift(prob, image.Rectangle{}, TermCriteria{MaxIterations: 10}); err == nil {
+		t.Error("Expected error for empty window")
+	}
+}
+
+func Test_CamShift(t *testing.T) {
+	prob := setupTestCaseBlob(90, 80, 14, 5, 30)
+	box, next, err := CamShift(prob, image.Rect(100, 60, 140, 100), TermCriteria{MaxIterations: 20, Epsilon: 1})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if math.Abs(box.Center.X-90) > 1 || math.Abs(box.Center.Y-80) > 1 {
+		t.Errorf("Expected center: 90 80 - actual center: %f %f", box.Center.X, box.Center.Y)
+	}
+	if math.Abs(box.Angle-30) > 2 {
+		t.Errorf("Expected angle: 30 - actual angle: %f", box.Angle)
+	}
+	if box.Width <= 2*box.Height {
+		t.Errorf("Expected an elongated box - actual size: %fx%f", box.Width, box.Height)
+	}
+	if !next.In(prob.Bounds()) || !(image.Point{X: 90, Y: 80}).In(next) {
+		t.Errorf("Expected the next window to contain the object - actual window: %v", next)
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,40 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// AutoCropGray crops a grayscale image to its content, e.g. to trim the white margin of a scanned page. The content is
+// every pixel which differs from the background value by more then the tolerance, the image is cropped to the tightest
+// rectangle containing all of them. Returns the cropped copy, which starts at (0, 0), together with the crop rectangle
+// in the coordinates of the input image. Returns an error if the image contains only background.
+// Example of usage:
+//
+//	res, rect, err := transform.AutoCropGray(img, 255, 10)
+func AutoCropGray(img *image.Gray, background uint8, tolerance uint8) (*image.Gray, image.Rectangle, error) {
+	bounds := img.Bounds()
+	crop := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		offset := img.PixOffset(bounds.Min.X, y)
+		row := img.Pix[offset : offset+bounds.Dx()]
+		left, right := -1, -1
+		for x, v := range row {
+			if diff := int(v) - int(background); diff > int(tolerance) || diff < -int(tolerance) {
+				if left < 0 {
+					left = x
+				}
+				right = x
+			}
+		}
+		if left >= 0 {
+			// Union ignores empty rectangles, so the first row with content starts the crop
+			crop = crop.Union(image.Rect(bounds.Min.X+left, y, bounds.Min.X+right+1, y+1))
+		}
+	}
+	if crop.Empty() {
+		return nil, image.Rectangle{}, errors.New("the image contains only background")
+	}
+	return utils.CloneGray(img.SubImage(crop).(*image.Gray)), crop, nil
+}
@@ -0,0 +1,61 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_AutoCropGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	for i := range img.Pix {
+		// a white page with a slight noise within the tolerance
+		img.Pix[i] = 255 - uint8(i%4)
+	}
+	// an L shaped stroke
+	for y := 8; y < 20; y++ {
+		img.SetGray(12, y, color.Gray{Y: 30})
+	}
+	for x := 12; x < 27; x++ {
+		img.SetGray(x, 19, color.Gray{Y: 60})
+	}
+	res, rect, err := AutoCropGray(img, 255, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(12, 8, 27, 20); rect != expected {
+		t.Fatalf("Expected rectangle: %v - actual rectangle: %v", expected, rect)
+	}
+	utils.CompareGrayImages(t, utils.CloneGray(img.SubImage(rect).(*image.Gray)), res)
+	if res.Bounds().Min != (image.Point{}) {
+		t.Errorf("Expected the result to start at the origin - actual: %v", res.Bounds().Min)
+	}
+}
+
+func Test_AutoCropGray_SubImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 20))
+	img.SetGray(3, 3, color.Gray{Y: 200})
+	img.SetGray(15, 12, color.Gray{Y: 200})
+	sub := img.SubImage(image.Rect(5, 5, 20, 20)).(*image.Gray)
+	_, rect, err := AutoCropGray(sub, 0, 0)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(15, 12, 16, 13); rect != expected {
+		t.Errorf("Expected rectangle: %v - actual rectangle: %v", expected, rect)
+	}
+}
+
+func Test_AutoCropGray_OnlyBackground(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 250
+	}
+	if _, _, err := AutoCropGray(img, 255, 5); err == nil {
+		t.Error("Expected error for an image which contains only background")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,155 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/grayscale"
+	"github.com/yafeiliu/imger/threshold"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// DetectSkewGray estimates the dominant angle of the text lines of a scanned document. The document is expected to
+// contain dark text on a light background. The angle is searched in the [-maxAngleDeg, maxAngleDeg] interval by
+// projecting the dark pixels onto the direction perpendicular to the candidate lines and maximizing the variance of
+// the resulting projection profile. The returned angle is in degrees and it is positive if the lines are rotated
+// counterclockwise, so rotating the image with the negative angle straightens them.
+// Example of usage:
+//
+//	angle, err := transform.DetectSkewGray(img, 10.0)
+func DetectSkewGray(img *image.Gray, maxAngleDeg float64) (float64, error) {
+	if maxAngleDeg <= 0 || maxAngleDeg >= 45 {
+		return 0, errors.New("the maximum angle should be in the (0, 45) interval")
+	}
+	size := img.Bounds().Size()
+	var points []skewPoint
+	utils.ForEachPixel(size, func(x, y int) {
+		if w := float64(utils.MaxUint8 - img.GrayAt(x, y).Y); w > float64(utils.MaxUint8)/2 {
+			points = append(points, skewPoint{x: float64(x), y: float64(y), weight: w})
+		}
+	})
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	diag := math.Hypot(float64(size.X), float64(size.Y))
+	best, step := 0.0, 0.5
+	low, high := -maxAngleDeg, maxAngleDeg
+	for step >= 0.01 {
+		bestScore := math.Inf(-1)
+		for angle := low; angle <= high+step/2; angle += step {
+			if score := projectionScore(points, angle, diag); score > bestScore {
+				bestScore, best = score, angle
+			}
+		}
+		low, high = math.Max(best-step, -maxAngleDeg), math.Min(best+step, maxAngleDeg)
+		step /= 5
+	}
+	return best, nil
+}
+
+// DeskewGray detects the skew of a scanned document with DetectSkewGray and rotates it around its center with the
+// negative angle so the text lines become horizontal. The size of the image is kept and the uncovered areas are
+// filled with white.
+// Example of usage:
+//
+//	res, err := transform.DeskewGray(img, 10.0)
+func DeskewGray(img *image.Gray, maxAngleDeg float64) (*image.Gray, error) {
+	angle, err := DetectSkewGray(img, maxAngleDeg)
+	if err != nil {
+		return nil, err
+	}
+	return rotateGrayWhite(img, -angle), nil
+}
+
+// AutoDeskewGray straightens a scanned document without any parameters. The image is binarized with Otsu's method
+// first, so low contrast scans (e.g. gray text on a gray background) are handled as well, then the angle of the text
+// lines is searched in the [-20, 20] degrees interval with DetectSkewGray and the original image is rotated around its
+// center with the negative angle. Returns the straightened image and the detected angle in degrees, which is positive
+// if the lines were rotated counterclockwise.
+// Example of usage:
+//
+//	res, angle, err := transform.AutoDeskewGray(img)
+func AutoDeskewGray(img *image.Gray) (*image.Gray, float64, error) {
+	binary, err := threshold.OtsuThreshold(img, threshold.ThreshBinary)
+	if err != nil {
+		return nil, 0, err
+	}
+	angle, err := DetectSkewGray(binary, autoDeskewMaxAngle)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rotateGrayWhite(img, -angle), angle, nil
+}
+
+// DeskewRGBA detects the skew of a scanned document on its grayscale version with DetectSkewGray and rotates it
+// around its center with the negative angle so the text lines become horizontal. The size of the image is kept and
+// the uncovered areas are filled with opaque white.
+// Example of usage:
+//
+//	res, err := transform.DeskewRGBA(img, 10.0)
+func DeskewRGBA(img *image.RGBA, maxAngleDeg float64) (*image.RGBA, error) {
+	angle, err := DetectSkewGray(grayscale.Grayscale(img), maxAngleDeg)
+	if err != nil {
+		return nil, err
+	}
+	size := img.Bounds().Size()
+	radians := angleToRadians(-angle)
+	anchor := image.Point{X: size.X / 2, Y: size.Y / 2}
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		ox, oy := getOriginalPixelPosition(x, y, radians, anchor, image.Point{})
+		if ox < 0 || oy < 0 || ox >= size.X || oy >= size.Y {
+			res.SetRGBA(x, y, color.RGBA{R: utils.MaxUint8, G: utils.MaxUint8, B: utils.MaxUint8, A: utils.MaxUint8})
+			return
+		}
+		res.SetRGBA(x, y, img.RGBAAt(ox, oy))
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+const autoDeskewMaxAngle = 20
+
+// rotateGrayWhite rotates the image around its center with the given angle in degrees, the size of the image is kept
+// and the uncovered areas are filled with white.
+func rotateGrayWhite(img *image.Gray, angle float64) *image.Gray {
+	size := img.Bounds().Size()
+	radians := angleToRadians(angle)
+	anchor := image.Point{X: size.X / 2, Y: size.Y / 2}
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		ox, oy := getOriginalPixelPosition(x, y, radians, anchor, image.Point{})
+		if ox < 0 || oy < 0 || ox >= size.X || oy >= size.Y {
+			res.SetGray(x, y, color.Gray{Y: utils.MaxUint8})
+			return
+		}
+		res.SetGray(x, y, img.GrayAt(ox, oy))
+	})
+	return res
+}
+
+type skewPoint struct {
+	x, y, weight float64
+}
+
+// projectionScore returns the sum of squares of the projection profile perpendicular to lines with the given angle.
+// Each point is split between its two neighbouring bins so the score changes smoothly with the angle.
+func projectionScore(points []skewPoint, angle float64, diag float64) float64 {
+	radians := angleToRadians(angle)
+	sin, cos := math.Sin(radians), math.Cos(radians)
+	bins := make([]float64, 2*int(math.Ceil(diag))+2)
+	for _, p := range points {
+		r := p.y*cos + p.x*sin + diag
+		i := int(r)
+		frac := r - float64(i)
+		bins[i] += p.weight * (1 - frac)
+		bins[i+1] += p.weight * frac
+	}
+	var score float64
+	for _, b := range bins {
+		score += b * b
+	}
+	return score
+}
@@ -0,0 +1,119 @@
+package transform
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseSkewedText draws rows of dark "words" rotated counterclockwise with the given angle around the center.
+func setupTestCaseSkewedText(angle float64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 320, 240))
+	radians := angle * math.Pi / 180
+	sin, cos := math.Sin(radians), math.Cos(radians)
+	for x := 0; x < 320; x++ {
+		for y := 0; y < 240; y++ {
+			dx, dy := float64(x-160), float64(y-120)
+			u := dx*cos - dy*sin + 1000
+			v := dy*cos + dx*sin + 1000
+			c := color.Gray{Y: 0xFF}
+			if math.Mod(v, 18) < 4 && math.Mod(u, 37) < 28 && math.Abs(dx) < 130 && math.Abs(dy) < 90 {
+				c = color.Gray{Y: 0x10}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+	return img
+}
+
+func Test_DetectSkewGray(t *testing.T) {
+	for _, angle := range []float64{-10, -6.5, -2, 0, 1.3, 4, 7.7, 10} {
+		img := setupTestCaseSkewedText(angle)
+		actual, err := DetectSkewGray(img, 12)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if math.Abs(actual-angle) > 0.2 {
+			t.Errorf("Expected angle: %f - actual angle: %f", angle, actual)
+		}
+	}
+	if _, err := DetectSkewGray(image.NewGray(image.Rect(0, 0, 4, 4)), 0); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+func Test_DeskewGray(t *testing.T) {
+	img := setupTestCaseSkewedText(6)
+	res, err := DeskewGray(img, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != img.Bounds() {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", img.Bounds(), res.Bounds())
+	}
+	if c := res.GrayAt(0, 0).Y; c != 0xFF {
+		t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", 0xFF, c, 0, 0)
+	}
+	angle, err := DetectSkewGray(res, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if math.Abs(angle) > 0.2 {
+		t.Errorf("Expected angle: 0 - actual angle: %f", angle)
+	}
+}
+
+func Test_AutoDeskewGray(t *testing.T) {
+	img := setupTestCaseSkewedText(7)
+	// noisy low contrast scan, the text is lighter then mid gray
+	for i, p := range img.Pix {
+		img.Pix[i] = 0x90 + p/8 + uint8(i*7%5)
+	}
+	res, angle, err := AutoDeskewGray(img)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if math.Abs(angle-7) > 1 {
+		t.Errorf("Expected angle: 7 - actual angle: %f", angle)
+	}
+	// the text lines of the result are horizontal
+	binary := image.NewGray(res.Bounds())
+	for i, p := range res.Pix {
+		if p < 0x98 {
+			binary.Pix[i] = 0x00
+		} else {
+			binary.Pix[i] = 0xFF
+		}
+	}
+	residual, err := DetectSkewGray(binary, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if math.Abs(residual) > 1 {
+		t.Errorf("Expected angle of the result: 0 - actual angle: %f", residual)
+	}
+}
+
+func Test_DeskewRGBA(t *testing.T) {
+	gray := setupTestCaseSkewedText(-3)
+	rgba := image.NewRGBA(gray.Bounds())
+	for i, v := range gray.Pix {
+		rgba.Pix[4*i], rgba.Pix[4*i+1], rgba.Pix[4*i+2], rgba.Pix[4*i+3] = v, v, v, 0xFF
+	}
+	res, err := DeskewRGBA(rgba, 10)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected, _ := DeskewGray(gray, 10)
+	for x := 0; x < 320; x++ {
+		for y := 0; y < 240; y++ {
+			if c := res.RGBAAt(x, y); c.R != expected.GrayAt(x, y).Y || c.A != 0xFF {
+				t.Fatalf("Expected gray: %d - actual color: %v at: %d %d", expected.GrayAt(x, y).Y, c, x, y)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,193 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// UndistortGray removes the radial lens distortion (barrel or pincushion) of a grayscale image. The distortion is
+// described by the standard radial model where a point at radius r from the center of distortion is imaged at
+// radius r * (1 + k1 * r^2 + k2 * r^4). The radius is normalized by the half diagonal of the image. Every pixel of
+// the result is sampled from the distorted image with bilinear interpolation, pixels which map outside of the image
+// are set to 0. Positive k1 corrects barrel distortion, negative k1 corrects pincushion distortion.
+// Example of usage:
+//
+//	res, err := transform.UndistortGray(img, 0.1, 0, image.Point{X: 256, Y: 256})
+func UndistortGray(img *image.Gray, k1, k2 float64, center image.Point) (*image.Gray, error) {
+	mapping, err := newRadialMapping(img.Bounds().Size(), center)
+	if err != nil {
+		return nil, err
+	}
+	return remapGray(img, func(x, y float64) (float64, float64) {
+		return mapping.apply(x, y, func(r float64) float64 {
+			return r * (1 + k1*r*r + k2*r*r*r*r)
+		})
+	}), nil
+}
+
+// UndistortGrayInterp removes the radial lens distortion of a grayscale image the same way as UndistortGray, but the
+// distorted image is sampled with the given interpolation method (InterNearest, InterLinear, InterCatmullRom,
+// InterLanczos), see RemapGray. The pixels outside of the distorted image are treated as 0. Returns an error if the
+// center is outside of the image or the interpolation is invalid.
+// Example of usage:
+//
+//	res, err := transform.UndistortGrayInterp(img, 0.1, 0, image.Point{X: 256, Y: 256}, resize.InterCatmullRom)
+func UndistortGrayInterp(img *image.Gray, k1, k2 float64, center image.Point, interp resize.Interpolation) (*image.Gray, error) {
+	size := img.Bounds().Size()
+	mapping, err := newRadialMapping(size, center)
+	if err != nil {
+		return nil, err
+	}
+	mapX := make([][]float64, size.Y)
+	mapY := make([][]float64, size.Y)
+	for y := range mapX {
+		mapX[y] = make([]float64, size.X)
+		mapY[y] = make([]float64, size.X)
+		for x := range mapX[y] {
+			mapX[y][x], mapY[y][x] = mapping.apply(float64(x), float64(y), func(r float64) float64 {
+				return r * (1 + k1*r*r + k2*r*r*r*r)
+			})
+		}
+	}
+	return RemapGray(img, mapX, mapY, interp, padding.BorderConstant)
+}
+
+// UndistortRGBA removes the radial lens distortion (barrel or pincushion) of an RGBA image. The distortion is
+// described by the standard radial model where a point at radius r from the center of distortion is imaged at
+// radius r * (1 + k1 * r^2 + k2 * r^4). The radius is normalized by the half diagonal of the image. Every pixel of
+// the result is sampled from the distorted image with bilinear interpolation, pixels which map outside of the image
+// are set to transparent black. Positive k1 corrects barrel distortion, negative k1 corrects pincushion distortion.
+// Example of usage:
+//
+//	res, err := transform.UndistortRGBA(img, 0.1, 0, image.Point{X: 256, Y: 256})
+func UndistortRGBA(img *image.RGBA, k1, k2 float64, center image.Point) (*image.RGBA, error) {
+	mapping, err := newRadialMapping(img.Bounds().Size(), center)
+	if err != nil {
+		return nil, err
+	}
+	return remapRGBA(img, func(x, y float64) (float64, float64) {
+		return mapping.apply(x, y, func(r float64) float64 {
+			return r * (1 + k1*r*r + k2*r*r*r*r)
+		})
+	}), nil
+}
+
+// DistortGray applies radial lens distortion to a grayscale image, it is the inverse of UndistortGray with the same
+// coefficients and it can be used to generate test data. Pixels which map outside of the image are set to 0.
+// Example of usage:
+//
+//	res, err := transform.DistortGray(img, 0.1, 0, image.Point{X: 256, Y: 256})
+func DistortGray(img *image.Gray, k1, k2 float64, center image.Point) (*image.Gray, error) {
+	mapping, err := newRadialMapping(img.Bounds().Size(), center)
+	if err != nil {
+		return nil, err
+	}
+	return remapGray(img, func(x, y float64) (float64, float64) {
+		return mapping.apply(x, y, func(r float64) float64 {
+			return undistortRadius(r, k1, k2)
+		})
+	}), nil
+}
+
+// DistortRGBA applies radial lens distortion to an RGBA image, it is the inverse of UndistortRGBA with the same
+// coefficients and it can be used to generate test data. Pixels which map outside of the image are set to
+// transparent black.
+// Example of usage:
+//
+//	res, err := transform.DistortRGBA(img, 0.1, 0, image.Point{X: 256, Y: 256})
+func DistortRGBA(img *image.RGBA, k1, k2 float64, center image.Point) (*image.RGBA, error) {
+	mapping, err := newRadialMapping(img.Bounds().Size(), center)
+	if err != nil {
+		return nil, err
+	}
+	return remapRGBA(img, func(x, y float64) (float64, float64) {
+		return mapping.apply(x, y, func(r float64) float64 {
+			return undistortRadius(r, k1, k2)
+		})
+	}), nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// radialMapping converts pixel positions to normalized radii around the center of distortion and back.
+type radialMapping struct {
+	cx, cy float64
+	norm   float64
+}
+
+func newRadialMapping(size image.Point, center image.Point) (radialMapping, error) {
+	if center.X < 0 || center.Y < 0 || center.X > size.X || center.Y > size.Y {
+		return radialMapping{}, errors.New("invalid center position")
+	}
+	norm := math.Hypot(float64(size.X), float64(size.Y)) / 2
+	if norm == 0 {
+		norm = 1
+	}
+	return radialMapping{cx: float64(center.X), cy: float64(center.Y), norm: norm}, nil
+}
+
+// apply moves the point (x, y) along its radius so that its normalized radius r becomes f(r).
+func (m radialMapping) apply(x, y float64, f func(r float64) float64) (float64, float64) {
+	dx, dy := (x-m.cx)/m.norm, (y-m.cy)/m.norm
+	r := math.Hypot(dx, dy)
+	if r == 0 {
+		return x, y
+	}
+	scale := f(r) / r
+	return m.cx + dx*scale*m.norm, m.cy + dy*scale*m.norm
+}
+
+// undistortRadius solves rd = r * (1 + k1 * r^2 + k2 * r^4) for r using Newton's method.
+func undistortRadius(rd, k1, k2 float64) float64 {
+	r := rd
+	for i := 0; i < 20; i++ {
+		r2 := r * r
+		f := r*(1+k1*r2+k2*r2*r2) - rd
+		df := 1 + 3*k1*r2 + 5*k2*r2*r2
+		if df == 0 {
+			break
+		}
+		step := f / df
+		r -= step
+		if math.Abs(step) < 1e-12 {
+			break
+		}
+	}
+	return r
+}
+
+// remapGray builds an image where every pixel is sampled with bilinear interpolation from the position returned by
+// source. Pixels sampled outside of the image are set to 0.
+func remapGray(img *image.Gray, source func(x, y float64) (float64, float64)) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		if sx, sy := source(float64(x), float64(y)); insideImage(sx, sy, size) {
+			res.SetGray(x, y, color.Gray{Y: utils.BilinearSampleGray(img, sx, sy)})
+		}
+	})
+	return res
+}
+
+// remapRGBA builds an image where every pixel is sampled with bilinear interpolation from the position returned by
+// source. Pixels sampled outside of the image are set to transparent black.
+func remapRGBA(img *image.RGBA, source func(x, y float64) (float64, float64)) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		if sx, sy := source(float64(x), float64(y)); insideImage(sx, sy, size) {
+			res.SetRGBA(x, y, utils.BilinearSampleRGBA(img, sx, sy))
+		}
+	})
+	return res
+}
+
+// insideImage reports whether the fractional position (x, y) lies between the centers of the border pixels of an image
+// of the given size.
+func insideImage(x, y float64, size image.Point) bool {
+	return x >= 0 && y >= 0 && x <= float64(size.X-1) && y <= float64(size.Y-1)
+}
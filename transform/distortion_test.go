@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/resize"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+// setupTestCaseGrid draws a white image with black horizontal and vertical lines, 3 pixels wide, every 40 pixels.
+func setupTestCaseGrid() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 241, 201))
+	for x := 0; x < 241; x++ {
+		for y := 0; y < 201; y++ {
+			c := color.Gray{Y: 0xFF}
+			if (x+1)%40 < 3 || (y+1)%40 < 3 {
+				c = color.Gray{Y: 0x00}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+	return img
+}
+
+// maxLineDeviation returns the maximum distance between the center of the dark pixels around the horizontal line y0
+// and y0 for the columns in [fromX, toX), skipping the columns which cross vertical lines.
+func maxLineDeviation(img *image.Gray, y0 int, fromX int, toX int) float64 {
+	var deviation float64
+	for x := fromX; x < toX; x++ {
+		if (x+1)%40 < 8 || (x+1)%40 > 32 {
+			continue
+		}
+		var sum, weight float64
+		for y := y0 - 12; y <= y0+12; y++ {
+			w := float64(0xFF - img.GrayAt(x, y).Y)
+			sum += w * float64(y)
+			weight += w
+		}
+		if weight == 0 {
+			return math.Inf(1)
+		}
+		deviation = math.Max(deviation, math.Abs(sum/weight-float64(y0)))
+	}
+	return deviation
+}
+
+func Test_UndistortGray(t *testing.T) {
+	grid := setupTestCaseGrid()
+	center := image.Point{X: 120, Y: 100}
+	for _, k1 := range []float64{0.15, -0.15} {
+		distorted, err := DistortGray(grid, k1, 0, center)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		if d := maxLineDeviation(distorted, 40, 20, 220); d < 2 {
+			t.Fatalf("Expected the distorted line to be curved - actual deviation: %f", d)
+		}
+		res, err := UndistortGray(distorted, k1, 0, center)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for _, y0 := range []int{40, 80, 120, 160} {
+			if d := maxLineDeviation(res, y0, 20, 220); d > 1 {
+				t.Errorf("Expected straight line at: %d for k1: %f - actual deviation: %f", y0, k1, d)
+			}
+		}
+	}
+}
+
+func Test_UndistortGrayInterp(t *testing.T) {
+	grid := setupTestCaseGrid()
+	center := image.Point{X: 120, Y: 100}
+	// a synthetic barrel distortion
+	distorted, err := DistortGray(grid, 0.15, 0, center)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	before := maxLineDeviation(distorted, 40, 20, 220)
+	for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear, resize.InterCatmullRom, resize.InterLanczos} {
+		res, err := UndistortGrayInterp(distorted, 0.15, 0, center, interp)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for _, y0 := range []int{40, 80, 120, 160} {
+			if d := maxLineDeviation(res, y0, 20, 220); d > 1 || d >= before {
+				t.Errorf("Expected straight line at: %d with interpolation: %d - actual deviation: %f, before: %f", y0, interp, d, before)
+			}
+		}
+	}
+	if _, err := UndistortGrayInterp(distorted, 0.15, 0, image.Point{X: -1, Y: 0}, resize.InterLinear); err == nil {
+		t.Error("Expected error for a center outside of the image")
+	}
+	if _, err := UndistortGrayInterp(distorted, 0.15, 0, center, resize.Interpolation(9)); err == nil {
+		t.Error("Expected error for an invalid interpolation")
+	}
+}
+
+func Test_UndistortRGBA(t *testing.T) {
+	grid := setupTestCaseGrid()
+	rgba := image.NewRGBA(grid.Bounds())
+	for i, v := range grid.Pix {
+		rgba.Pix[4*i], rgba.Pix[4*i+1], rgba.Pix[4*i+2], rgba.Pix[4*i+3] = v, v, v, 0xFF
+	}
+	center := image.Point{X: 120, Y: 100}
+	distorted, err := DistortRGBA(rgba, 0.1, 0.05, center)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	res, err := UndistortRGBA(distorted, 0.1, 0.05, center)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expectedDistorted, _ := DistortGray(grid, 0.1, 0.05, center)
+	expected, _ := UndistortGray(expectedDistorted, 0.1, 0.05, center)
+	for x := 0; x < 241; x++ {
+		for y := 0; y < 201; y++ {
+			if c := res.RGBAAt(x, y); c.G != expected.GrayAt(x, y).Y {
+				t.Fatalf("Expected gray: %d - actual color: %v at: %d %d", expected.GrayAt(x, y).Y, c, x, y)
+			}
+		}
+	}
+	// the corners map outside of the distorted image
+	if c := res.RGBAAt(0, 0); c != (color.RGBA{}) {
+		t.Errorf("Expected transparent fill - actual color: %v", c)
+	}
+	if _, err := UndistortRGBA(rgba, 0.1, 0, image.Point{X: -1, Y: 0}); err == nil {
+		t.Error("Should not reach this point")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,88 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/blur"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"image"
+	"math/rand"
+)
+
+// ElasticDeformGray applies a random elastic deformation to a grayscale image, used to augment training data (P. Y.
+// Simard et al., "Best Practices for Convolutional Neural Networks Applied to Visual Document Analysis", 2003). Every
+// pixel gets a random displacement in [-1, 1] along both axes, the displacement fields are smoothed with a Gaussian of
+// the given sigma and scaled by alpha, then the image is remapped through them with BorderReflect, so no fill color
+// appears at the borders. Small sigmas give a noisy deformation, big sigmas approach a global translation. The same
+// seed gives the same deformation. Returns an error if sigma is not positive, alpha is negative or the interpolation
+// method is invalid.
+// Example of usage:
+//
+//	res, err := transform.ElasticDeformGray(img, 34, 4, 42, resize.InterLinear)
+func ElasticDeformGray(img *image.Gray, alpha, sigma float64, seed int64, interp resize.Interpolation) (*image.Gray, error) {
+	mapX, mapY, err := elasticMaps(img.Bounds().Size(), alpha, sigma, seed)
+	if err != nil {
+		return nil, err
+	}
+	return RemapGray(img, mapX, mapY, interp, padding.BorderReflect)
+}
+
+// ElasticDeformRGBA applies a random elastic deformation to an RGBA image, see ElasticDeformGray. The same seed gives
+// the same deformation as ElasticDeformGray for an image of the same size.
+// Example of usage:
+//
+//	res, err := transform.ElasticDeformRGBA(img, 34, 4, 42, resize.InterLinear)
+func ElasticDeformRGBA(img *image.RGBA, alpha, sigma float64, seed int64, interp resize.Interpolation) (*image.RGBA, error) {
+	mapX, mapY, err := elasticMaps(img.Bounds().Size(), alpha, sigma, seed)
+	if err != nil {
+		return nil, err
+	}
+	return RemapRGBA(img, mapX, mapY, interp, padding.BorderReflect)
+}
+
+// -------------------------------------------------------------------------------------------------------
+// elasticMaps returns the source positions of the pixels for an elastic deformation.
+func elasticMaps(size image.Point, alpha, sigma float64, seed int64) ([][]float64, [][]float64, error) {
+	dx, dy, err := elasticDisplacement(size, alpha, sigma, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapX := make([][]float64, size.Y)
+	mapY := make([][]float64, size.Y)
+	for y := 0; y < size.Y; y++ {
+		mapX[y] = make([]float64, size.X)
+		mapY[y] = make([]float64, size.X)
+		for x := 0; x < size.X; x++ {
+			mapX[y][x] = float64(x) + dx[y*size.X+x]
+			mapY[y][x] = float64(y) + dy[y*size.X+x]
+		}
+	}
+	return mapX, mapY, nil
+}
+
+// elasticDisplacement generates the smoothed random displacement fields as row-major planes.
+func elasticDisplacement(size image.Point, alpha, sigma float64, seed int64) ([]float64, []float64, error) {
+	if sigma <= 0 {
+		return nil, nil, errors.New("sigma must be positive")
+	}
+	if alpha < 0 {
+		return nil, nil, errors.New("alpha must not be negative")
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	var fields [2][]float64
+	for i := range fields {
+		field := make([]float64, size.X*size.Y)
+		for j := range field {
+			field[j] = 2*rnd.Float64() - 1
+		}
+		smooth, err := blur.FastGaussianBlurPlane(field, size, sigma)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j := range smooth {
+			smooth[j] *= alpha
+		}
+		fields[i] = smooth
+	}
+	return fields[0], fields[1], nil
+}
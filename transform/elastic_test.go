@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ElasticDeformGray_Seed(t *testing.T) {
+	img := setupTestCaseRandomGray(40, 30)
+	first, err := ElasticDeformGray(img, 20, 4, 7, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	second, err := ElasticDeformGray(img, 20, 4, 7, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	utils.CompareGrayImages(t, first, second)
+	other, err := ElasticDeformGray(img, 20, 4, 8, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	differ := 0
+	for i := range first.Pix {
+		if first.Pix[i] != other.Pix[i] {
+			differ++
+		}
+	}
+	if differ < len(first.Pix)/2 {
+		t.Errorf("Expected another seed to give another deformation - differing pixels: %d", differ)
+	}
+}
+
+func Test_ElasticDeform_LargeSigma(t *testing.T) {
+	size := image.Point{X: 64, Y: 48}
+	variance := func(sigma float64) float64 {
+		dx, dy, err := elasticDisplacement(size, 10, sigma, 3)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		var res float64
+		for _, field := range [][]float64{dx, dy} {
+			var sum, sumSq float64
+			for _, v := range field {
+				sum += v
+				sumSq += v * v
+			}
+			mean := sum / float64(len(field))
+			res += sumSq/float64(len(field)) - mean*mean
+		}
+		return res
+	}
+	small, large := variance(2), variance(500)
+	if large > small/100 || large > 1e-3 {
+		t.Errorf("Expected a nearly constant displacement for a large sigma - variance with sigma 2: %f, with sigma 500: %f", small, large)
+	}
+}
+
+func Test_ElasticDeformRGBA_NoHoles(t *testing.T) {
+	// every channel is at least 50, pixels filled with transparent black would show up as 0
+	img := image.NewRGBA(image.Rect(0, 0, 50, 40))
+	rnd := rand.New(rand.NewSource(43))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(50 + rnd.Intn(206))
+	}
+	for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear} {
+		res, err := ElasticDeformRGBA(img, 60, 3, 11, interp)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		for i, v := range res.Pix {
+			if v < 50 {
+				t.Fatalf("Expected no holes in the deformed image - value %d at: %d", v, i)
+			}
+		}
+	}
+	if _, err := ElasticDeformRGBA(img, 60, 0, 11, resize.InterLinear); err == nil {
+		t.Error("Expected error for a sigma of 0")
+	}
+	if _, err := ElasticDeformGray(image.NewGray(img.Bounds()), -1, 3, 11, resize.InterLinear); err == nil {
+		t.Error("Expected error for a negative alpha")
+	}
+}
+
+// -------------------------------------------------------------------------------
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math"
+)
+
+// RadonTransformGray computes the Radon transform (sinogram) of a grayscale image: for every angle (in degrees) the
+// image is integrated along parallel rays and the line integrals form a projection. At 0 degrees the rays are vertical,
+// so the projection is the sum of every column, and the rays rotate counterclockwise with the angle. The rays are
+// sampled with unit steps and bilinear interpolation, the pixels outside of the image are 0. The sinogram is indexed
+// as sinogram[t][a], where a is the index of the angle and t is the position on the detector: the detector has D bins,
+// where D is the smallest integer not smaller then the diagonal of the image whose parity matches the width of the
+// image, and bin t is at the distance t - (D - 1) / 2 from the center of the image. Returns an error if no angles are
+// given or the image is empty.
+// Example of usage:
+//
+//	sinogram, err := transform.RadonTransformGray(img, []float64{0, 45, 90, 135})
+func RadonTransformGray(img *image.Gray, angles []float64) ([][]float64, error) {
+	size := img.Bounds().Size()
+	if len(angles) == 0 {
+		return nil, errors.New("no angles are given")
+	}
+	if size.X == 0 || size.Y == 0 {
+		return nil, errors.New("empty image")
+	}
+	padded, err := padding.PaddingGray(utils.CloneGray(img), image.Point{X: 3, Y: 3}, image.Point{X: 1, Y: 1}, padding.BorderConstant)
+	if err != nil {
+		return nil, err
+	}
+	d := int(math.Ceil(math.Hypot(float64(size.X), float64(size.Y))))
+	if (d-size.X)%2 != 0 {
+		d++
+	}
+	cx, cy := float64(size.X-1)/2, float64(size.Y-1)/2
+	half := float64(d-1) / 2
+	sinogram := make([][]float64, d)
+	for t := range sinogram {
+		sinogram[t] = make([]float64, len(angles))
+	}
+	for a, angle := range angles {
+		sin, cos := math.Sincos(angle * math.Pi / 180)
+		for t := 0; t < d; t++ {
+			ft := float64(t) - half
+			var sum float64
+			// one more sample on both ends keeps the line integral exact for axis aligned rays
+			for s := -1; s <= d; s++ {
+				fs := float64(s) - half
+				// the black frame of the padded image makes the pixels outside of the image 0
+				sum += utils.BilinearSampleGrayF64(padded, cx+ft*cos-fs*sin+1, cy+ft*sin+fs*cos+1)
+			}
+			sinogram[t][a] = sum
+		}
+	}
+	return sinogram, nil
+}
+
+// HorizontalProjectionGray returns the sum of the pixels of every row of a grayscale image, e.g. to find the text
+// lines of a document. The length of the result is the height of the image.
+// Example of usage:
+//
+//	rows := transform.HorizontalProjectionGray(img)
+func HorizontalProjectionGray(img *image.Gray) []float64 {
+	size := img.Bounds().Size()
+	res := make([]float64, size.Y)
+	for y := 0; y < size.Y; y++ {
+		for _, v := range img.Pix[y*img.Stride : y*img.Stride+size.X] {
+			res[y] += float64(v)
+		}
+	}
+	return res
+}
+
+// VerticalProjectionGray returns the sum of the pixels of every column of a grayscale image. The length of the result
+// is the width of the image.
+// Example of usage:
+//
+//	columns := transform.VerticalProjectionGray(img)
+func VerticalProjectionGray(img *image.Gray) []float64 {
+	size := img.Bounds().Size()
+	res := make([]float64, size.X)
+	for y := 0; y < size.Y; y++ {
+		for x, v := range img.Pix[y*img.Stride : y*img.Stride+size.X] {
+			res[x] += float64(v)
+		}
+	}
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------------
@@ -0,0 +1,88 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_RadonTransformGray_Disk(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 41, 41))
+	// an anti-aliased disk, the coverage of every pixel is estimated with 4x4 samples
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		inside := 0
+		for sx := 0; sx < 4; sx++ {
+			for sy := 0; sy < 4; sy++ {
+				if math.Hypot(float64(x)+float64(sx)/4-20.375, float64(y)+float64(sy)/4-20.375) <= 12 {
+					inside++
+				}
+			}
+		}
+		img.SetGray(x, y, color.Gray{Y: uint8(200 * inside / 16)})
+	})
+	angles := []float64{0, 15, 30, 45, 60, 90, 120, 135, 170}
+	sinogram, err := RadonTransformGray(img, angles)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// the diagonal is 57.98, the smallest odd number not smaller then it is 59
+	if len(sinogram) != 59 || len(sinogram[0]) != len(angles) {
+		t.Fatalf("Expected a 59 x %d sinogram - actual: %d x %d", len(angles), len(sinogram), len(sinogram[0]))
+	}
+	peak := sinogram[29][0]
+	for tIndex := range sinogram {
+		for a := range angles {
+			if d := math.Abs(sinogram[tIndex][a] - sinogram[tIndex][0]); d > 0.05*peak {
+				t.Errorf("Expected the same projection for every angle - difference: %f at: %d, angle %f", d, tIndex, angles[a])
+			}
+		}
+	}
+}
+
+func Test_RadonTransformGray_ZeroAngle(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 13, 7))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7 % 256)
+	}
+	sinogram, err := RadonTransformGray(img, []float64{0})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	columns := VerticalProjectionGray(img)
+	offset := (len(sinogram) - len(columns)) / 2
+	for tIndex := range sinogram {
+		expected := 0.0
+		if x := tIndex - offset; x >= 0 && x < len(columns) {
+			expected = columns[x]
+		}
+		if math.Abs(sinogram[tIndex][0]-expected) > 1e-6 {
+			t.Errorf("Expected: %f - actual: %f at: %d", expected, sinogram[tIndex][0], tIndex)
+		}
+	}
+}
+
+func Test_Projections(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 3, 2))
+	copy(img.Pix, []uint8{1, 2, 3, 10, 20, 30})
+	rows, columns := HorizontalProjectionGray(img), VerticalProjectionGray(img)
+	if len(rows) != 2 || rows[0] != 6 || rows[1] != 60 {
+		t.Errorf("Expected row sums: [6 60] - actual: %v", rows)
+	}
+	if len(columns) != 3 || columns[0] != 11 || columns[1] != 22 || columns[2] != 33 {
+		t.Errorf("Expected column sums: [11 22 33] - actual: %v", columns)
+	}
+}
+
+func Test_RadonTransformGray_Invalid(t *testing.T) {
+	if _, err := RadonTransformGray(image.NewGray(image.Rect(0, 0, 3, 3)), nil); err == nil {
+		t.Error("Expected error for no angles")
+	}
+	if _, err := RadonTransformGray(image.NewGray(image.Rect(0, 0, 0, 0)), []float64{0}); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+}
+
+// -------------------------------------------------------------------------------
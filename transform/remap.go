@@ -0,0 +1,110 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+)
+
+// RemapGray builds a grayscale image where the pixel (x, y) is sampled from the position (mapX[y][x], mapY[y][x]) of
+// the source image with the given interpolation method (InterNearest, InterLinear, InterCatmullRom, InterLanczos).
+// The maps are indexed by rows like the maps of OpenCV. The positions are in pixel coordinates, the position (3, 4) is
+// the center of the pixel (3, 4). The result has the size of the maps: len(mapX[0]) columns and len(mapX) rows. Source
+// pixels outside of the image are taken according to the border type: BorderConstant treats them as 0,
+// BorderReplicate repeats the nearest pixel and BorderReflect mirrors the image. Returns an error if the two maps do
+// not have the same non-empty size, or if the interpolation or the border type is invalid.
+// Example of usage:
+//
+//	res, err := transform.RemapGray(img, mapX, mapY, resize.InterLinear, padding.BorderReflect)
+func RemapGray(img *image.Gray, mapX, mapY [][]float64, interp resize.Interpolation, border padding.Border) (*image.Gray, error) {
+	size, err := validateRemap(mapX, mapY, interp, border)
+	if err != nil {
+		return nil, err
+	}
+	origin := img.Bounds().Min
+	srcSize := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum float64
+		forEachRemapTap(mapX[y][x], mapY[y][x], srcSize, interp, border, func(sx, sy int, weight float64) {
+			sum += float64(img.GrayAt(origin.X+sx, origin.Y+sy).Y) * weight
+		})
+		res.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(sum+0.5, utils.MinUint8, float64(utils.MaxUint8)))})
+	})
+	return res, nil
+}
+
+// RemapRGBA builds an RGBA image where the pixel (x, y) is sampled from the position (mapX[y][x], mapY[y][x]) of the
+// source image, see RemapGray. BorderConstant treats the pixels outside of the image as transparent black.
+// Example of usage:
+//
+//	res, err := transform.RemapRGBA(img, mapX, mapY, resize.InterCatmullRom, padding.BorderReplicate)
+func RemapRGBA(img *image.RGBA, mapX, mapY [][]float64, interp resize.Interpolation, border padding.Border) (*image.RGBA, error) {
+	size, err := validateRemap(mapX, mapY, interp, border)
+	if err != nil {
+		return nil, err
+	}
+	origin := img.Bounds().Min
+	srcSize := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum [4]float64
+		forEachRemapTap(mapX[y][x], mapY[y][x], srcSize, interp, border, func(sx, sy int, weight float64) {
+			offset := img.PixOffset(origin.X+sx, origin.Y+sy)
+			for c := range sum {
+				sum[c] += float64(img.Pix[offset+c]) * weight
+			}
+		})
+		offset := res.PixOffset(x, y)
+		for c := range sum {
+			res.Pix[offset+c] = uint8(utils.ClampF64(sum[c]+0.5, utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func validateRemap(mapX, mapY [][]float64, interp resize.Interpolation, border padding.Border) (image.Point, error) {
+	if len(mapX) == 0 || len(mapX[0]) == 0 {
+		return image.Point{}, errors.New("empty map")
+	}
+	size := image.Point{X: len(mapX[0]), Y: len(mapX)}
+	if len(mapY) != size.Y {
+		return image.Point{}, errors.New("the size of the two maps does not match")
+	}
+	for y := 0; y < size.Y; y++ {
+		if len(mapX[y]) != size.X || len(mapY[y]) != size.X {
+			return image.Point{}, errors.New("the size of the two maps does not match")
+		}
+	}
+	if _, err := shiftTaps(0, interp); err != nil {
+		return image.Point{}, err
+	}
+	if border != padding.BorderConstant && border != padding.BorderReplicate && border != padding.BorderReflect {
+		return image.Point{}, errors.New("unknown border type")
+	}
+	return size, nil
+}
+
+// forEachRemapTap calls f with the source pixels and the interpolation weights needed to sample the position (x, y),
+// the pixels outside of the image are moved inside according to the border type or skipped for BorderConstant.
+func forEachRemapTap(x, y float64, size image.Point, interp resize.Interpolation, border padding.Border, f func(sx, sy int, weight float64)) {
+	tapsX, _ := shiftTaps(-x, interp)
+	tapsY, _ := shiftTaps(-y, interp)
+	for _, ty := range tapsY {
+		sy, ok := padding.BorderIndex(ty.offset, size.Y, border)
+		if !ok {
+			continue
+		}
+		for _, tx := range tapsX {
+			sx, ok := padding.BorderIndex(tx.offset, size.X, border)
+			if !ok {
+				continue
+			}
+			f(sx, sy, tx.weight*ty.weight)
+		}
+	}
+}
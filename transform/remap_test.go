@@ -0,0 +1,110 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_RemapGray_Translate(t *testing.T) {
+	img := setupTestCaseRandomGray(23, 17)
+	for _, border := range []padding.Border{padding.BorderConstant, padding.BorderReplicate, padding.BorderReflect} {
+		for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear, resize.InterCatmullRom, resize.InterLanczos} {
+			mapX, mapY := shiftMaps(img.Bounds().Size(), -3.25, 2.5)
+			actual, err := RemapGray(img, mapX, mapY, interp, border)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			expected, err := TranslateGray(img, 3.25, -2.5, border, interp)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			utils.CompareGrayImages(t, expected, actual)
+		}
+	}
+}
+
+func Test_RemapRGBA_Identity(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 13, 9))
+	rand.New(rand.NewSource(41)).Read(img.Pix)
+	mapX, mapY := shiftMaps(img.Bounds().Size(), 0, 0)
+	for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear, resize.InterCatmullRom, resize.InterLanczos} {
+		actual, err := RemapRGBA(img, mapX, mapY, interp, padding.BorderConstant)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareRGBAImages(t, img, actual)
+	}
+}
+
+func Test_RemapGray_IdentityAndFlip(t *testing.T) {
+	img := setupTestCaseRandomGray(14, 9)
+	size := img.Bounds().Size()
+	mapX, mapY := shiftMaps(size, 0, 0)
+	flipX, flipY := shiftMaps(size, 0, 0)
+	for y := range flipX {
+		for x := range flipX[y] {
+			flipX[y][x] = float64(size.X - 1 - x)
+		}
+	}
+	flipped := image.NewGray(img.Bounds())
+	utils.ForEachPixel(size, func(x, y int) {
+		flipped.SetGray(x, y, img.GrayAt(size.X-1-x, y))
+	})
+	for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear, resize.InterCatmullRom, resize.InterLanczos} {
+		identity, err := RemapGray(img, mapX, mapY, interp, padding.BorderReflect)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, img, identity)
+		mirrored, err := RemapGray(img, flipX, flipY, interp, padding.BorderReflect)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, flipped, mirrored)
+	}
+}
+
+func Test_RemapGray_Invalid(t *testing.T) {
+	img := setupTestCaseRandomGray(8, 8)
+	mapX, mapY := shiftMaps(image.Point{X: 8, Y: 8}, 0, 0)
+	if _, err := RemapGray(img, mapX, mapY[:7], resize.InterLinear, padding.BorderReflect); err == nil {
+		t.Error("Expected error for maps of different sizes")
+	}
+	if _, err := RemapGray(img, nil, nil, resize.InterLinear, padding.BorderReflect); err == nil {
+		t.Error("Expected error for empty maps")
+	}
+	if _, err := RemapGray(img, mapX, mapY, resize.Interpolation(9), padding.BorderReflect); err == nil {
+		t.Error("Expected error for an invalid interpolation")
+	}
+	if _, err := RemapGray(img, mapX, mapY, resize.InterLinear, padding.Border(9)); err == nil {
+		t.Error("Expected error for an invalid border")
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseRandomGray(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(int64(width * height))).Read(img.Pix)
+	return img
+}
+
+// shiftMaps returns the maps which sample every pixel from the position shifted by (dx, dy).
+func shiftMaps(size image.Point, dx, dy float64) ([][]float64, [][]float64) {
+	mapX := make([][]float64, size.Y)
+	mapY := make([][]float64, size.Y)
+	for y := range mapX {
+		mapX[y] = make([]float64, size.X)
+		mapY[y] = make([]float64, size.X)
+		for x := range mapX[y] {
+			mapX[y][x] = float64(x) + dx
+			mapY[y][x] = float64(y) + dy
+		}
+	}
+	return mapX, mapY
+}
@@ -0,0 +1,90 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// TransposeGray swaps the rows and the columns of a grayscale image: res(x, y) = img(y, x).
+func TransposeGray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(y, x, img.GrayAt(x, y))
+	})
+	return res
+}
+
+// TransposeRGBA swaps the rows and the columns of an RGBA image: res(x, y) = img(y, x).
+func TransposeRGBA(img *image.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetRGBA(y, x, img.RGBAAt(x, y))
+	})
+	return res
+}
+
+// Rotate90Gray rotates a grayscale image counterclockwise by 90 degrees. The pixels are copied directly without
+// resampling, the width and the height of the image are swapped.
+func Rotate90Gray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(y, size.X-1-x, img.GrayAt(x, y))
+	})
+	return res
+}
+
+// Rotate180Gray rotates a grayscale image by 180 degrees. The pixels are copied directly without resampling.
+func Rotate180Gray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(size.X-1-x, size.Y-1-y, img.GrayAt(x, y))
+	})
+	return res
+}
+
+// Rotate270Gray rotates a grayscale image counterclockwise by 270 degrees (clockwise by 90 degrees). The pixels are
+// copied directly without resampling, the width and the height of the image are swapped.
+func Rotate270Gray(img *image.Gray) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(size.Y-1-y, x, img.GrayAt(x, y))
+	})
+	return res
+}
+
+// Rotate90RGBA rotates an RGBA image counterclockwise by 90 degrees. The pixels are copied directly without
+// resampling, the width and the height of the image are swapped.
+func Rotate90RGBA(img *image.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetRGBA(y, size.X-1-x, img.RGBAAt(x, y))
+	})
+	return res
+}
+
+// Rotate180RGBA rotates an RGBA image by 180 degrees. The pixels are copied directly without resampling.
+func Rotate180RGBA(img *image.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetRGBA(size.X-1-x, size.Y-1-y, img.RGBAAt(x, y))
+	})
+	return res
+}
+
+// Rotate270RGBA rotates an RGBA image counterclockwise by 270 degrees (clockwise by 90 degrees). The pixels are
+// copied directly without resampling, the width and the height of the image are swapped.
+func Rotate270RGBA(img *image.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.Y, size.X))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		res.SetRGBA(size.Y-1-y, x, img.RGBAAt(x, y))
+	})
+	return res
+}
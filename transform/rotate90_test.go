@@ -0,0 +1,95 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests--------------------------------------
+var rotateInputGray = image.Gray{
+	Rect:   image.Rect(0, 0, 3, 2),
+	Stride: 3,
+	Pix: []uint8{
+		0x01, 0x02, 0x03,
+		0x04, 0x05, 0x06,
+	},
+}
+
+func Test_Rotate90Gray(t *testing.T) {
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 2, 3),
+		Stride: 2,
+		Pix: []uint8{
+			0x03, 0x06,
+			0x02, 0x05,
+			0x01, 0x04,
+		},
+	}
+	utils.CompareGrayImages(t, expected, Rotate90Gray(&rotateInputGray))
+}
+
+func Test_Rotate270Gray(t *testing.T) {
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 2, 3),
+		Stride: 2,
+		Pix: []uint8{
+			0x04, 0x01,
+			0x05, 0x02,
+			0x06, 0x03,
+		},
+	}
+	utils.CompareGrayImages(t, expected, Rotate270Gray(&rotateInputGray))
+}
+
+func Test_TransposeGray(t *testing.T) {
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 2, 3),
+		Stride: 2,
+		Pix: []uint8{
+			0x01, 0x04,
+			0x02, 0x05,
+			0x03, 0x06,
+		},
+	}
+	utils.CompareGrayImages(t, expected, TransposeGray(&rotateInputGray))
+}
+
+func Test_Rotate90Gray_FourTimes(t *testing.T) {
+	res := &rotateInputGray
+	for i := 0; i < 4; i++ {
+		res = Rotate90Gray(res)
+	}
+	utils.CompareGrayImages(t, &rotateInputGray, res)
+}
+
+func Test_Rotate180Gray_FlipBothAxes(t *testing.T) {
+	size := rotateInputGray.Bounds().Size()
+	flipped := image.NewGray(rotateInputGray.Bounds())
+	utils.ForEachPixel(size, func(x, y int) {
+		flipped.SetGray(x, y, rotateInputGray.GrayAt(size.X-1-x, size.Y-1-y))
+	})
+	utils.CompareGrayImages(t, flipped, Rotate180Gray(&rotateInputGray))
+	utils.CompareGrayImages(t, Rotate90Gray(Rotate90Gray(&rotateInputGray)), Rotate180Gray(&rotateInputGray))
+}
+
+func Test_Rotate90RGBA_FourTimes(t *testing.T) {
+	rgba := &image.RGBA{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3 * 4,
+		Pix: []uint8{
+			0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C,
+			0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18,
+		},
+	}
+	res := rgba
+	for i := 0; i < 4; i++ {
+		res = Rotate90RGBA(res)
+	}
+	utils.CompareRGBAImages(t, rgba, res)
+	utils.CompareRGBAImages(t, Rotate90RGBA(Rotate90RGBA(rgba)), Rotate180RGBA(rgba))
+	utils.CompareRGBAImages(t, Rotate90RGBA(Rotate180RGBA(rgba)), Rotate270RGBA(rgba))
+	utils.CompareRGBAImages(t, TransposeRGBA(TransposeRGBA(rgba)), rgba)
+}
+
+// ---------------------------------------------------------------------------------
@@ -0,0 +1,34 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+)
+
+// TileGray repeats a grayscale image in both directions to fill a canvas of targetW x targetH pixels, e.g. to build a
+// texture background from a small pattern. The first tile is placed at the top left corner of the canvas and the
+// tiles at the right and the bottom edges are cropped to fit. Returns an error if the target size is not positive or
+// the image is empty.
+// Example of usage:
+//
+//	res, err := transform.TileGray(pattern, 1920, 1080)
+func TileGray(img *image.Gray, targetW, targetH int) (*image.Gray, error) {
+	if targetW <= 0 || targetH <= 0 {
+		return nil, errors.New("the target size should be positive")
+	}
+	bounds := img.Bounds()
+	size := bounds.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, errors.New("empty image")
+	}
+	res := image.NewGray(image.Rect(0, 0, targetW, targetH))
+	utils.ParallelForEachRow(image.Point{X: targetW, Y: targetH}, func(y int) {
+		src := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y%size.Y):]
+		row := res.Pix[y*res.Stride : y*res.Stride+targetW]
+		for x := 0; x < targetW; x += size.X {
+			copy(row[x:], src[:size.X])
+		}
+	})
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package transform
+
+import (
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_TileGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 3, 3))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(10 * (i + 1))
+	}
+	res, err := TileGray(img, 7, 7)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expectedRows := [][]uint8{
+		{10, 20, 30, 10, 20, 30, 10},
+		{40, 50, 60, 40, 50, 60, 40},
+		{70, 80, 90, 70, 80, 90, 70},
+	}
+	if res.Bounds() != image.Rect(0, 0, 7, 7) {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", image.Rect(0, 0, 7, 7), res.Bounds())
+	}
+	for y := 0; y < 7; y++ {
+		// the last row and column are the first row and column of the cropped tiles
+		expected := expectedRows[y%3]
+		for x := 0; x < 7; x++ {
+			if actual := res.GrayAt(x, y).Y; actual != expected[x] {
+				t.Errorf("Expected value: %d - actual value: %d at: %d %d", expected[x], actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_TileGray_SubImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 6, 4))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	sub := img.SubImage(image.Rect(2, 1, 4, 3)).(*image.Gray)
+	// a target smaller then the image gives a crop of the first tile
+	res, err := TileGray(sub, 5, 1)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	expected := []uint8{8, 9, 8, 9, 8}
+	for x, v := range expected {
+		if actual := res.GrayAt(x, 0).Y; actual != v {
+			t.Errorf("Expected value: %d - actual value: %d at: %d 0", v, actual, x)
+		}
+	}
+	if _, err := TileGray(sub, 0, 5); err == nil {
+		t.Error("Expected error for a target width of 0")
+	}
+	if _, err := TileGray(image.NewGray(image.Rect(0, 0, 0, 0)), 5, 5); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+}
+
+// -------------------------------------------------------------------------------
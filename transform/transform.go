@@ -2,53 +2,150 @@ package transform
 
 import (
 	"errors"
+	"github.com/yafeiliu/imger/resize"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"math"
 )
 
 // RotateGray rotates a grayscale image counterclockwise with a given angle. The point which will represent the center
 // ot the rotation is specified by the anchor argument. The result image can have its original size or it can be
-// resized to fit in the area of the image.
+// resized to fit in the area of the image. The pixels are taken from the nearest source pixel, see RotateGrayInterp
+// for smoother results.
 // Example of usage:
 //
 //	res, err := transform.RotateGray(img, 90.0, {512, 512}, true)
 func RotateGray(img *image.Gray, angle float64, anchor image.Point, resizeToFit bool) (*image.Gray, error) {
+	return RotateGrayInterp(img, angle, anchor, resizeToFit, resize.InterNearest)
+}
+
+// RotateGrayInterp rotates a grayscale image the same way as RotateGray, but the source image is sampled with the given
+// interpolation method (InterNearest, InterLinear, InterCatmullRom, InterLanczos). InterNearest is fast and it never
+// invents new values, so it should be used for masks and label images, the other methods give smoother results for
+// photos. The pixels outside of the source image are treated as black.
+// Example of usage:
+//
+//	res, err := transform.RotateGrayInterp(img, 30.0, image.Point{X: 512, Y: 512}, true, resize.InterLinear)
+func RotateGrayInterp(img *image.Gray, angle float64, anchor image.Point, resizeToFit bool, interp resize.Interpolation) (*image.Gray, error) {
 	size := img.Bounds().Size()
 	if anchor.X < 0 || anchor.Y < 0 || anchor.X > size.X || anchor.Y > size.Y {
 		return nil, errors.New("invalid anchor position")
 	}
+	if _, err := shiftTaps(0, interp); err != nil {
+		return nil, err
+	}
 	radians := angleToRadians(angle)
 	newSize := size
 	if resizeToFit {
 		newSize = computeFitSize(size, radians)
 	}
 	result := image.NewGray(image.Rect(0, 0, newSize.X, newSize.Y))
+	offset := computeOffset(size, newSize)
+	if interp == resize.InterNearest {
+		utils.ParallelForEachPixel(newSize, func(x, y int) {
+			result.SetGray(x, y, img.GrayAt(getOriginalPixelPosition(x, y, radians, anchor, offset)))
+		})
+		return result, nil
+	}
 	utils.ParallelForEachPixel(newSize, func(x, y int) {
-		result.SetGray(x, y, img.GrayAt(getOriginalPixelPosition(x, y, radians, anchor, computeOffset(size, newSize))))
+		var sum float64
+		forEachRotationTap(x, y, radians, anchor, offset, size, interp, func(sx, sy int, weight float64) {
+			sum += float64(img.GrayAt(sx, sy).Y) * weight
+		})
+		result.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(sum+0.5, utils.MinUint8, float64(utils.MaxUint8)))})
 	})
 	return result, nil
 }
 
+// RotateGrayWithMatrix rotates a grayscale image counterclockwise around its center like RotateGrayInterp with
+// resizeToFit, so no part of the image is clipped, and it also returns the 2x3 affine matrix M which maps the
+// coordinates of the original image to the coordinates of the rotated image, including the translation to the origin
+// of the enlarged canvas:
+//
+//	x' = M[0][0] * x + M[0][1] * y + M[0][2]
+//	y' = M[1][0] * x + M[1][1] * y + M[1][2]
+//
+// The coordinates are continuous, the pixel (x, y) covers the [x, x + 1) x [y, y + 1) square, so the center of a pixel
+// is mapped by applying the matrix to (x + 0.5, y + 0.5). InterNearest samples the source at the mapped top left corner
+// of every output pixel instead of its center, so its pixels can be up to half a pixel away from the mapped positions.
+// The matrix can be used to map e.g. keypoints or bounding boxes to the rotated image. Returns an error if the
+// interpolation method is invalid.
+// Example of usage:
+//
+//	res, m, err := transform.RotateGrayWithMatrix(img, 30.0, resize.InterLinear)
+func RotateGrayWithMatrix(img *image.Gray, angle float64, interp resize.Interpolation) (*image.Gray, [2][3]float64, error) {
+	size := img.Bounds().Size()
+	anchor := image.Point{X: size.X / 2, Y: size.Y / 2}
+	res, err := RotateGrayInterp(img, angle, anchor, true, interp)
+	if err != nil {
+		return nil, [2][3]float64{}, err
+	}
+	// the inverse of the rotation of getOriginalPosition
+	radians := angleToRadians(angle)
+	cos, sin := math.Cos(radians), math.Sin(radians)
+	center := anchor.Add(computeOffset(size, computeFitSize(size, radians)))
+	m := [2][3]float64{
+		{cos, sin, float64(center.X) - cos*float64(anchor.X) - sin*float64(anchor.Y)},
+		{-sin, cos, float64(center.Y) + sin*float64(anchor.X) - cos*float64(anchor.Y)},
+	}
+	return res, m, nil
+}
+
 // RotateRGBA rotates an RGBA image counterclockwise with a given angle. The point which will represent the center
 // ot the rotation is specified by the anchor argument. The result image can have its original size or it can be
-// resized to fit in the area of the image.
+// resized to fit in the area of the image. The pixels are taken from the nearest source pixel, see RotateRGBAInterp
+// for smoother results.
 // Example of usage:
 //
 //	res, err := transform.RotateGray(img, 90.0, {512, 512}, true)
 func RotateRGBA(img *image.RGBA, angle float64, anchor image.Point, resizeToFit bool) (*image.RGBA, error) {
+	return RotateRGBAInterp(img, angle, anchor, resizeToFit, resize.InterNearest)
+}
+
+// RotateRGBAInterp rotates an RGBA image the same way as RotateRGBA, but the source image is sampled with the given
+// interpolation method (InterNearest, InterLinear, InterCatmullRom, InterLanczos). InterNearest is fast and it never
+// invents new colors, the other methods give smoother results for photos. The pixels outside of the source image are
+// treated as transparent black, so the corners exposed by the rotation are fully transparent and the edges of the
+// rotated image fade out smoothly, e.g. for data augmentation.
+// Example of usage:
+//
+//	res, err := transform.RotateRGBAInterp(img, 30.0, image.Point{X: 512, Y: 512}, true, resize.InterCatmullRom)
+func RotateRGBAInterp(img *image.RGBA, angle float64, anchor image.Point, resizeToFit bool, interp resize.Interpolation) (*image.RGBA, error) {
 	size := img.Bounds().Size()
 	if anchor.X < 0 || anchor.Y < 0 || anchor.X > size.X || anchor.Y > size.Y {
 		return nil, errors.New("invalid anchor position")
 	}
+	if _, err := shiftTaps(0, interp); err != nil {
+		return nil, err
+	}
 	radians := angleToRadians(angle)
 	newSize := size
 	if resizeToFit {
 		newSize = computeFitSize(size, radians)
 	}
 	result := image.NewRGBA(image.Rect(0, 0, newSize.X, newSize.Y))
+	offset := computeOffset(size, newSize)
+	if interp == resize.InterNearest {
+		utils.ParallelForEachPixel(newSize, func(x, y int) {
+			result.SetRGBA(x, y, img.RGBAAt(getOriginalPixelPosition(x, y, radians, anchor, offset)))
+		})
+		return result, nil
+	}
 	utils.ParallelForEachPixel(newSize, func(x, y int) {
-		result.SetRGBA(x, y, img.RGBAAt(getOriginalPixelPosition(x, y, radians, anchor, computeOffset(size, newSize))))
+		var sum [4]float64
+		forEachRotationTap(x, y, radians, anchor, offset, size, interp, func(sx, sy int, weight float64) {
+			pixel := img.RGBAAt(sx, sy)
+			sum[0] += float64(pixel.R) * weight
+			sum[1] += float64(pixel.G) * weight
+			sum[2] += float64(pixel.B) * weight
+			sum[3] += float64(pixel.A) * weight
+		})
+		var c [4]uint8
+		for i := range c {
+			c[i] = uint8(utils.ClampF64(sum[i]+0.5, utils.MinUint8, float64(utils.MaxUint8)))
+		}
+		result.SetRGBA(x, y, color.RGBA{R: c[0], G: c[1], B: c[2], A: c[3]})
 	})
 	return result, nil
 }
@@ -70,9 +167,34 @@ func computeOffset(size image.Point, fittingSize image.Point) image.Point {
 }
 
 func getOriginalPixelPosition(x int, y int, radians float64, anchor image.Point, offset image.Point) (int, int) {
-	dx := x - anchor.X - offset.X
-	dy := y - anchor.Y - offset.Y
-	originalX := int(math.Floor(math.Cos(radians)*float64(dx) - math.Sin(radians)*float64(dy) + float64(anchor.X)))
-	originalY := int(math.Floor(math.Sin(radians)*float64(dx) + math.Cos(radians)*float64(dy) + float64(anchor.Y)))
+	originalX, originalY := getOriginalPosition(float64(x), float64(y), radians, anchor, offset)
+	return int(math.Floor(originalX)), int(math.Floor(originalY))
+}
+
+func getOriginalPosition(x float64, y float64, radians float64, anchor image.Point, offset image.Point) (float64, float64) {
+	dx := x - float64(anchor.X+offset.X)
+	dy := y - float64(anchor.Y+offset.Y)
+	originalX := math.Cos(radians)*dx - math.Sin(radians)*dy + float64(anchor.X)
+	originalY := math.Sin(radians)*dx + math.Cos(radians)*dy + float64(anchor.Y)
 	return originalX, originalY
 }
+
+// forEachRotationTap calls f with the source pixels and the interpolation weights needed for the pixel (x, y) of the
+// rotated image, the source pixels outside of the image are skipped. The center of the pixel is rotated, so a rotation
+// by 0 degrees samples the source pixels exactly.
+func forEachRotationTap(x, y int, radians float64, anchor, offset, size image.Point, interp resize.Interpolation, f func(sx, sy int, weight float64)) {
+	originalX, originalY := getOriginalPosition(float64(x)+0.5, float64(y)+0.5, radians, anchor, offset)
+	tapsX, _ := shiftTaps(0.5-originalX, interp)
+	tapsY, _ := shiftTaps(0.5-originalY, interp)
+	for _, ty := range tapsY {
+		if ty.offset < 0 || ty.offset >= size.Y {
+			continue
+		}
+		for _, tx := range tapsX {
+			if tx.offset < 0 || tx.offset >= size.X {
+				continue
+			}
+			f(tx.offset, ty.offset, tx.weight*ty.weight)
+		}
+	}
+}
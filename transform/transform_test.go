@@ -2,8 +2,10 @@ package transform
 
 import (
 	"github.com/yafeiliu/imger/imgio"
+	"github.com/yafeiliu/imger/resize"
 	"github.com/yafeiliu/imger/utils"
 	"image"
+	"image/color"
 	"math"
 	"testing"
 )
@@ -181,6 +183,179 @@ func Test_GetOriginalPixelPosition45(t *testing.T) {
 	}
 }
 
+func Test_RotateGrayInterp_Mask(t *testing.T) {
+	// a binary mask with a filled rectangle
+	mask := image.NewGray(image.Rect(0, 0, 40, 40))
+	utils.ForEachPixel(mask.Bounds().Size(), func(x, y int) {
+		if x >= 10 && x < 30 && y >= 14 && y < 26 {
+			mask.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	})
+	anchor := image.Point{X: 20, Y: 20}
+	nearest, err := RotateGrayInterp(mask, 30, anchor, false, resize.InterNearest)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	foreground := 0
+	for _, v := range nearest.Pix {
+		if v != 0 && v != 0xFF {
+			t.Fatalf("Expected only the values of the mask with nearest interpolation - actual: %d", v)
+		}
+		if v == 0xFF {
+			foreground++
+		}
+	}
+	if foreground < 200 || foreground > 280 {
+		t.Errorf("Expected about 240 foreground pixels - actual: %d", foreground)
+	}
+	linear, err := RotateGrayInterp(mask, 30, anchor, false, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	intermediate := 0
+	for _, v := range linear.Pix {
+		if v != 0 && v != 0xFF {
+			intermediate++
+		}
+	}
+	if intermediate == 0 {
+		t.Errorf("Expected intermediate values with linear interpolation")
+	}
+	if _, err := RotateGrayInterp(mask, 30, anchor, false, resize.Interpolation(9)); err == nil {
+		t.Errorf("Expected error for an unknown interpolation")
+	}
+}
+
+func Test_RotateGrayWithMatrix_Corners(t *testing.T) {
+	// bright 4x4 markers in the corners of a dark image
+	img := image.NewGray(image.Rect(0, 0, 40, 24))
+	corners := []image.Point{{0, 0}, {36, 0}, {0, 20}, {36, 20}}
+	for _, c := range corners {
+		utils.ForEachPixel(image.Point{X: 4, Y: 4}, func(x, y int) {
+			img.SetGray(c.X+x, c.Y+y, color.Gray{Y: 0xFF})
+		})
+	}
+	apply := func(m [2][3]float64, x, y float64) (float64, float64) {
+		return m[0][0]*x + m[0][1]*y + m[0][2], m[1][0]*x + m[1][1]*y + m[1][2]
+	}
+	for _, angle := range []float64{90, 30, -45} {
+		res, m, err := RotateGrayWithMatrix(img, angle, resize.InterLinear)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		size := res.Bounds().Size()
+		// the corners of the original image touch the border of the enlarged canvas
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, c := range []image.Point{{0, 0}, {40, 0}, {0, 24}, {40, 24}} {
+			x, y := apply(m, float64(c.X), float64(c.Y))
+			minX, minY = math.Min(minX, x), math.Min(minY, y)
+			maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+		}
+		if math.Abs(minX) > 1 || math.Abs(minY) > 1 || math.Abs(maxX-float64(size.X)) > 1 || math.Abs(maxY-float64(size.Y)) > 1 {
+			t.Errorf("Expected the corners on the border of the canvas %v - actual: [%f, %f] x [%f, %f] for angle: %f", size, minX, maxX, minY, maxY, angle)
+		}
+		// the markers are found where the matrix maps their centers
+		for _, c := range corners {
+			x, y := apply(m, float64(c.X)+2, float64(c.Y)+2)
+			if v := res.GrayAt(int(math.Floor(x)), int(math.Floor(y))).Y; v < 0xC0 {
+				t.Errorf("Expected the marker of the corner %v at: %f %f - actual value: %d for angle: %f", c, x, y, v, angle)
+			}
+		}
+	}
+	res, m, err := RotateGrayWithMatrix(img, 90, resize.InterNearest)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 24, 40); res.Bounds() != expected {
+		t.Errorf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	// a rotation by 90 degrees counterclockwise moves the top right corner to the top left corner
+	if x, y := apply(m, 40, 0); math.Abs(x) > 1e-9 || math.Abs(y) > 1e-9 {
+		t.Errorf("Expected the top right corner at: 0 0 - actual: %f %f", x, y)
+	}
+	if _, _, err := RotateGrayWithMatrix(img, 30, resize.Interpolation(9)); err == nil {
+		t.Error("Expected error for an unknown interpolation")
+	}
+}
+
+func Test_RotateGrayInterp_Identity(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 9, 7))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 3)
+	}
+	for _, interp := range []resize.Interpolation{resize.InterNearest, resize.InterLinear, resize.InterCatmullRom, resize.InterLanczos} {
+		res, err := RotateGrayInterp(img, 0, image.Point{X: 4, Y: 3}, false, interp)
+		if err != nil {
+			t.Fatalf("Error should not be returned. Error value: %s", err)
+		}
+		utils.CompareGrayImages(t, img, res)
+	}
+}
+
+func Test_RotateRGBAInterp_Mask(t *testing.T) {
+	mask := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	utils.ForEachPixel(mask.Bounds().Size(), func(x, y int) {
+		if x >= 5 && x < 15 && y >= 7 && y < 13 {
+			mask.SetRGBA(x, y, color.RGBA{R: 0xFF, G: 0x80, B: 0x00, A: 0xFF})
+		}
+	})
+	nearest, err := RotateRGBAInterp(mask, 45, image.Point{X: 10, Y: 10}, true, resize.InterNearest)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for i := 0; i < len(nearest.Pix); i += 4 {
+		if a := nearest.Pix[i+3]; a != 0 && a != 0xFF {
+			t.Fatalf("Expected only opaque or transparent pixels with nearest interpolation - actual alpha: %d", a)
+		}
+	}
+	cubic, err := RotateRGBAInterp(mask, 45, image.Point{X: 10, Y: 10}, true, resize.InterCatmullRom)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if cubic.Bounds() != nearest.Bounds() {
+		t.Errorf("Expected the same bounds - actual: %v and %v", cubic.Bounds(), nearest.Bounds())
+	}
+}
+
+func Test_RotateRGBAInterp_TransparentCorners(t *testing.T) {
+	// an opaque image with a red top left quadrant and blue elsewhere
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	utils.ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, color.RGBA{B: 0xFF, A: 0xFF})
+		if x < 10 && y < 10 {
+			img.SetRGBA(x, y, color.RGBA{R: 0xFF, A: 0xFF})
+		}
+	})
+	res, err := RotateRGBAInterp(img, 45, image.Point{X: 10, Y: 10}, true, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if expected := image.Rect(0, 0, 28, 28); res.Bounds() != expected {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", expected, res.Bounds())
+	}
+	// the corners exposed by the rotation are fully transparent
+	for _, p := range []image.Point{{0, 0}, {27, 0}, {0, 27}, {27, 27}, {3, 2}} {
+		if c := res.RGBAAt(p.X, p.Y); c != (color.RGBA{}) {
+			t.Errorf("Expected a transparent pixel at %v - actual: %v", p, c)
+		}
+	}
+	// the content is centered and the red quadrant is turned counterclockwise to the left of the center
+	cases := []struct {
+		p        image.Point
+		expected color.RGBA
+	}{
+		{image.Point{X: 6, Y: 14}, color.RGBA{R: 0xFF, A: 0xFF}},
+		{image.Point{X: 22, Y: 14}, color.RGBA{B: 0xFF, A: 0xFF}},
+		{image.Point{X: 14, Y: 6}, color.RGBA{B: 0xFF, A: 0xFF}},
+		{image.Point{X: 14, Y: 22}, color.RGBA{B: 0xFF, A: 0xFF}},
+	}
+	for _, c := range cases {
+		if actual := res.RGBAAt(c.p.X, c.p.Y); actual != c.expected {
+			t.Errorf("Expected value: %v - actual value: %v at: %v", c.expected, actual, c.p)
+		}
+	}
+}
+
 // -----------------------------Acceptance tests------------------------------------
 func setupTestCaseGray(t *testing.T) *image.Gray {
 	path := "../res/building.jpg"
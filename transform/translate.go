@@ -0,0 +1,104 @@
+package transform
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// TranslateGray shifts the content of a grayscale image by (dx, dy). The regions which are exposed by the shift are
+// filled according to the border type (BorderConstant, BorderReplicate, BorderReflect). Fractional shifts are resolved
+// using the given interpolation method (InterNearest, InterLinear, InterCatmullRom, InterLanczos), integer shifts copy
+// the pixels without resampling.
+// Example of usage:
+//
+//	res, err := transform.TranslateGray(img, 5.5, -2, padding.BorderConstant, resize.InterLinear)
+func TranslateGray(img *image.Gray, dx, dy float64, border padding.Border, interp resize.Interpolation) (*image.Gray, error) {
+	if border != padding.BorderConstant && border != padding.BorderReplicate && border != padding.BorderReflect {
+		return nil, errors.New("unknown border type")
+	}
+	tapsX, err := shiftTaps(dx, interp)
+	if err != nil {
+		return nil, err
+	}
+	tapsY, err := shiftTaps(dy, interp)
+	if err != nil {
+		return nil, err
+	}
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		var sum float64
+		for _, ty := range tapsY {
+			sy, okY := padding.BorderIndex(y+ty.offset, size.Y, border)
+			if !okY {
+				continue
+			}
+			for _, tx := range tapsX {
+				sx, okX := padding.BorderIndex(x+tx.offset, size.X, border)
+				if !okX {
+					continue
+				}
+				sum += float64(img.GrayAt(sx, sy).Y) * tx.weight * ty.weight
+			}
+		}
+		res.SetGray(x, y, color.Gray{Y: uint8(utils.ClampF64(sum+0.5, utils.MinUint8, float64(utils.MaxUint8)))})
+	})
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// tap is a source pixel offset together with its interpolation weight.
+type tap struct {
+	offset int
+	weight float64
+}
+
+// shiftTaps computes the source offsets and weights needed to sample an image shifted by d along one axis.
+func shiftTaps(d float64, interp resize.Interpolation) ([]tap, error) {
+	var filter resize.Filter
+	switch interp {
+	case resize.InterNearest:
+	case resize.InterLinear:
+		filter = resize.NewLinear()
+	case resize.InterCatmullRom:
+		filter = resize.NewCatmullRom()
+	case resize.InterLanczos:
+		filter = resize.NewLanczos()
+	default:
+		return nil, errors.New("invalid interpolation method")
+	}
+	base := math.Floor(-d)
+	frac := -d - base
+	if frac < 1e-9 {
+		return []tap{{offset: int(base), weight: 1}}, nil
+	}
+	if 1-frac < 1e-9 {
+		return []tap{{offset: int(base) + 1, weight: 1}}, nil
+	}
+	if filter == nil {
+		if frac >= 0.5 {
+			return []tap{{offset: int(base) + 1, weight: 1}}, nil
+		}
+		return []tap{{offset: int(base), weight: 1}}, nil
+	}
+	support := int(math.Ceil(filter.GetS()))
+	var taps []tap
+	var sum float64
+	for i := 1 - support; i <= support; i++ {
+		w := filter.Interpolate(float64(i) - frac)
+		if w == 0 {
+			continue
+		}
+		taps = append(taps, tap{offset: int(base) + i, weight: w})
+		sum += w
+	}
+	for i := range taps {
+		taps[i].weight /= sum
+	}
+	return taps, nil
+}
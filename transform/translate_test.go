@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"github.com/yafeiliu/imger/padding"
+	"github.com/yafeiliu/imger/resize"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests--------------------------------------
+func setupTranslateInput() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 20, 4))
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 4; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x*10 + y + 1)})
+		}
+	}
+	return img
+}
+
+func Test_TranslateGray_IntegerShift(t *testing.T) {
+	img := setupTranslateInput()
+	res, err := TranslateGray(img, 5, 0, padding.BorderConstant, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 4; y++ {
+			var expected uint8
+			if x >= 5 {
+				expected = img.GrayAt(x-5, y).Y
+			}
+			if actual := res.GrayAt(x, y).Y; actual != expected {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_TranslateGray_ReplicateBorder(t *testing.T) {
+	img := setupTranslateInput()
+	res, err := TranslateGray(img, 0, -2, padding.BorderReplicate, resize.InterNearest)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 0; x < 20; x++ {
+		expected := []uint8{img.GrayAt(x, 2).Y, img.GrayAt(x, 3).Y, img.GrayAt(x, 3).Y, img.GrayAt(x, 3).Y}
+		for y := 0; y < 4; y++ {
+			if actual := res.GrayAt(x, y).Y; actual != expected[y] {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected[y], actual, x, y)
+			}
+		}
+	}
+}
+
+func Test_TranslateGray_FractionalShift(t *testing.T) {
+	img := setupTranslateInput()
+	res, err := TranslateGray(img, 0.5, 0, padding.BorderReplicate, resize.InterLinear)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	for x := 1; x < 20; x++ {
+		expected := uint8((int(img.GrayAt(x-1, 0).Y) + int(img.GrayAt(x, 0).Y) + 1) / 2)
+		if actual := res.GrayAt(x, 0).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, 0)
+		}
+	}
+}
+
+func Test_TranslateGray_InvalidInterpolation(t *testing.T) {
+	img := setupTranslateInput()
+	if _, err := TranslateGray(img, 0.5, 0, padding.BorderConstant, resize.Interpolation(-1)); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
+
+// ---------------------------------------------------------------------------------
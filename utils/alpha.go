@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+)
+
+// Unpremultiply converts an RGBA image, where the color channels are premultiplied by the alpha channel, into an
+// NRGBA image with straight (non-premultiplied) colors, e.g. before applying an effect which must not depend on the
+// opacity. Every color channel is divided by the alpha and rounded, fully transparent pixels become transparent black.
+// Example of usage:
+//
+//	res := utils.Unpremultiply(img)
+func Unpremultiply(img *image.RGBA) *image.NRGBA {
+	res := image.NewNRGBA(img.Bounds())
+	ParallelForEachPixel(img.Bounds().Size(), func(x, y int) {
+		pixel := img.RGBAAt(x, y)
+		if pixel.A == 0 {
+			return
+		}
+		unpremultiply := func(c uint8) uint8 {
+			return uint8(ClampInt((int(c)*int(MaxUint8)+int(pixel.A)/2)/int(pixel.A), MinUint8, int(MaxUint8)))
+		}
+		res.SetNRGBA(x, y, color.NRGBA{R: unpremultiply(pixel.R), G: unpremultiply(pixel.G), B: unpremultiply(pixel.B), A: pixel.A})
+	})
+	return res
+}
+
+// Premultiply converts an NRGBA image with straight (non-premultiplied) colors into an RGBA image, where every color
+// channel is multiplied by the alpha channel and rounded. It is the inverse of Unpremultiply.
+// Example of usage:
+//
+//	res := utils.Premultiply(img)
+func Premultiply(img *image.NRGBA) *image.RGBA {
+	res := image.NewRGBA(img.Bounds())
+	ParallelForEachPixel(img.Bounds().Size(), func(x, y int) {
+		pixel := img.NRGBAAt(x, y)
+		premultiply := func(c uint8) uint8 {
+			return uint8((int(c)*int(pixel.A) + int(MaxUint8)/2) / int(MaxUint8))
+		}
+		res.SetRGBA(x, y, color.RGBA{R: premultiply(pixel.R), G: premultiply(pixel.G), B: premultiply(pixel.B), A: pixel.A})
+	})
+	return res
+}
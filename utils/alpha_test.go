@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_Unpremultiply(t *testing.T) {
+	img := &image.RGBA{
+		Rect:   image.Rect(0, 0, 2, 2),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0x80, 0x40, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00,
+			0x10, 0x20, 0x30, 0xFF, 0x33, 0x00, 0x66, 0x66,
+		},
+	}
+	expected := &image.NRGBA{
+		Rect:   image.Rect(0, 0, 2, 2),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0xFF, 0x80, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00,
+			0x10, 0x20, 0x30, 0xFF, 0x80, 0x00, 0xFF, 0x66,
+		},
+	}
+	actual := Unpremultiply(img)
+	for i := range expected.Pix {
+		if expected.Pix[i] != actual.Pix[i] {
+			t.Fatalf("Expected %v - actual: %v", expected.Pix, actual.Pix)
+		}
+	}
+}
+
+func Test_Premultiply_RoundTrip(t *testing.T) {
+	// every premultiplied color with every alpha survives unpremultiply and premultiply
+	img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		a := uint8(y)
+		c := uint8(x * int(a) / 255)
+		img.SetRGBA(x, y, color.RGBA{R: c, G: a - c, B: c / 2, A: a})
+	})
+	CompareRGBAImages(t, img, Premultiply(Unpremultiply(img)))
+
+	// straight colors are recovered within the rounding error of the alpha
+	straight := image.NewNRGBA(image.Rect(0, 0, 256, 256))
+	ForEachPixel(straight.Bounds().Size(), func(x, y int) {
+		straight.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(255 - x), B: 0x80, A: uint8(y)})
+	})
+	actual := Unpremultiply(Premultiply(straight))
+	ForEachPixel(straight.Bounds().Size(), func(x, y int) {
+		expected := straight.NRGBAAt(x, y)
+		pixel := actual.NRGBAAt(x, y)
+		if expected.A == 0 {
+			if pixel != (color.NRGBA{}) {
+				t.Fatalf("Expected transparent black - actual: %v", pixel)
+			}
+			return
+		}
+		tolerance := float64(MaxUint8)/(2*float64(expected.A)) + 1
+		for _, c := range [][2]uint8{{expected.R, pixel.R}, {expected.G, pixel.G}, {expected.B, pixel.B}} {
+			if diff := float64(c[0]) - float64(c[1]); diff > tolerance || diff < -tolerance {
+				t.Fatalf("Expected %v - actual: %v", expected, pixel)
+			}
+		}
+		if pixel.A != expected.A {
+			t.Fatalf("Expected alpha %d - actual: %d", expected.A, pixel.A)
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// ClippedShadowLevel is the luminance at or below which a pixel counts as a clipped shadow in ImageStats.
+const ClippedShadowLevel = 2
+
+// ClippedHighlightLevel is the luminance at or above which a pixel counts as a clipped highlight in ImageStats.
+const ClippedHighlightLevel = 253
+
+// ImageStats is a statistical profile of an image which helps to decide which enhancements (contrast equalization,
+// sharpening, denoising, white balance) an image needs. The channels are ordered as R, G, B.
+type ImageStats struct {
+	Mean   [3]float64
+	StdDev [3]float64
+	// LuminanceHistogram holds the number of pixels for every luminance value.
+	LuminanceHistogram [256]int
+	// Sharpness is the variance of the Laplacian of the luminance, blurry images have low values.
+	Sharpness float64
+	// Noise is the estimated standard deviation of the noise of the luminance.
+	Noise float64
+	// ClippedHighlights and ClippedShadows are the fractions of the pixels with a luminance at or above
+	// ClippedHighlightLevel and at or below ClippedShadowLevel.
+	ClippedHighlights float64
+	ClippedShadows    float64
+	// ColorCast holds the mean of every channel divided by the average of the three channel means. A neutral image
+	// gives {1, 1, 1}, a bluish image has a blue ratio bigger then 1.
+	ColorCast [3]float64
+}
+
+// AnalyzeRGBA computes the statistical profile of an RGBA image, see ImageStats. The luminance is computed the same way
+// as by the grayscale package. The alpha channel is ignored.
+// Example of usage:
+//
+//	stats := utils.AnalyzeRGBA(img)
+//	if stats.Sharpness < 100 { ... }
+func AnalyzeRGBA(img *image.RGBA) ImageStats {
+	var stats ImageStats
+	stats.Mean, stats.StdDev = ChannelMeanStdDevRGBA(img)
+	stats.ColorCast = ColorCastRGBA(img)
+	lum := luminance(img)
+	stats.LuminanceHistogram = LuminanceHistogramGray(lum)
+	stats.Sharpness = LaplacianVarianceGray(lum)
+	stats.Noise = EstimateNoiseGray(lum)
+	stats.ClippedHighlights, stats.ClippedShadows = ClippedFractionsGray(lum)
+	return stats
+}
+
+// ChannelMeanStdDevRGBA returns the mean and the standard deviation of the R, G and B channels of an RGBA image.
+// Example of usage:
+//
+//	mean, stdDev := utils.ChannelMeanStdDevRGBA(img)
+func ChannelMeanStdDevRGBA(img *image.RGBA) (mean, stdDev [3]float64) {
+	size := img.Bounds().Size()
+	n := float64(size.X * size.Y)
+	if n == 0 {
+		return mean, stdDev
+	}
+	var sum, sumSq [3]float64
+	for y := 0; y < size.Y; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+4*size.X]
+		for i := 0; i < len(row); i += 4 {
+			for c := 0; c < 3; c++ {
+				v := float64(row[i+c])
+				sum[c] += v
+				sumSq[c] += v * v
+			}
+		}
+	}
+	for c := 0; c < 3; c++ {
+		mean[c] = sum[c] / n
+		stdDev[c] = math.Sqrt(math.Max(sumSq[c]/n-mean[c]*mean[c], 0))
+	}
+	return mean, stdDev
+}
+
+// MeanStdDevGrayMasked returns the mean and the standard deviation of the pixels of a grayscale image where the mask
+// is nonzero, e.g. to measure a region of interest, and the number of these pixels. An empty mask gives 0 for all
+// the values. Returns an error if the size of the mask does not match the size of the image.
+// Example of usage:
+//
+//	mean, stdDev, count, err := utils.MeanStdDevGrayMasked(img, mask)
+func MeanStdDevGrayMasked(img *image.Gray, mask *image.Gray) (mean, stdDev float64, count int, err error) {
+	size := img.Bounds().Size()
+	if !size.Eq(mask.Bounds().Size()) {
+		return 0, 0, 0, errors.New("the size of the mask does not match the size of the image")
+	}
+	var sum, sumSq float64
+	for y := 0; y < size.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, img.Rect.Min.Y+y):]
+		maskRow := mask.Pix[mask.PixOffset(mask.Rect.Min.X, mask.Rect.Min.Y+y):]
+		for x := 0; x < size.X; x++ {
+			if maskRow[x] == 0 {
+				continue
+			}
+			v := float64(row[x])
+			sum += v
+			sumSq += v * v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, 0, nil
+	}
+	n := float64(count)
+	mean = sum / n
+	stdDev = math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+	return mean, stdDev, count, nil
+}
+
+// ColorCastRGBA returns the mean of every channel (R, G, B) of an RGBA image divided by the average of the three
+// channel means. A neutral image gives {1, 1, 1}. A black image gives {1, 1, 1} as well.
+// Example of usage:
+//
+//	cast := utils.ColorCastRGBA(img)
+func ColorCastRGBA(img *image.RGBA) [3]float64 {
+	mean, _ := ChannelMeanStdDevRGBA(img)
+	avg := (mean[0] + mean[1] + mean[2]) / 3
+	if avg == 0 {
+		return [3]float64{1, 1, 1}
+	}
+	return [3]float64{mean[0] / avg, mean[1] / avg, mean[2] / avg}
+}
+
+// LuminanceHistogramGray returns the number of pixels for every gray value of a grayscale image.
+// Example of usage:
+//
+//	hist := utils.LuminanceHistogramGray(img)
+func LuminanceHistogramGray(img *image.Gray) [256]int {
+	var hist [256]int
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		for _, v := range img.Pix[y*img.Stride : y*img.Stride+size.X] {
+			hist[v]++
+		}
+	}
+	return hist
+}
+
+// CountNonZeroGray returns the number of non-zero pixels of a grayscale image, e.g. the area of a binary mask.
+// Example of usage:
+//
+//	area := utils.CountNonZeroGray(mask)
+func CountNonZeroGray(img *image.Gray) int {
+	count := 0
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		for _, v := range img.Pix[y*img.Stride : y*img.Stride+size.X] {
+			if v != 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// LaplacianVarianceGray estimates the sharpness of a grayscale image as the variance of its 4-connected Laplacian
+// ({0, 1, 0}, {1, -4, 1}, {0, 1, 0}) over the inner pixels. Defocused images give lower values then sharp ones, so the
+// value is a common focus measure, e.g. to cull out-of-focus photos. It is not normalized, so the values are only
+// comparable between images of similar content. Images smaller then 3x3 and constant images give 0.
+// Example of usage:
+//
+//	sharpness := utils.LaplacianVarianceGray(img)
+func LaplacianVarianceGray(img *image.Gray) float64 {
+	response := highPass(img, func(at func(dx, dy int) float64) float64 {
+		return at(-1, 0) + at(1, 0) + at(0, -1) + at(0, 1) - 4*at(0, 0)
+	})
+	if len(response) == 0 {
+		return 0
+	}
+	var sum, sumSq float64
+	for _, v := range response {
+		sum += v
+		sumSq += v * v
+	}
+	n := float64(len(response))
+	mean := sum / n
+	return math.Max(sumSq/n-mean*mean, 0)
+}
+
+// EstimateNoiseGray estimates the standard deviation of the noise of a grayscale image. The image is filtered with
+// the high-pass kernel {1, -2, 1}, {-2, 4, -2}, {1, -2, 1}, which cancels smooth gradients, and the robust median
+// absolute deviation of the response is scaled to the standard deviation of Gaussian noise. Images smaller then 3x3
+// give 0.
+// Example of usage:
+//
+//	sigma := utils.EstimateNoiseGray(img)
+func EstimateNoiseGray(img *image.Gray) float64 {
+	response := highPass(img, func(at func(dx, dy int) float64) float64 {
+		return at(-1, -1) + at(1, -1) + at(-1, 1) + at(1, 1) - 2*(at(0, -1)+at(0, 1)+at(-1, 0)+at(1, 0)) + 4*at(0, 0)
+	})
+	if len(response) == 0 {
+		return 0
+	}
+	median := medianF64(response)
+	for i, v := range response {
+		response[i] = math.Abs(v - median)
+	}
+	// 1.4826 * MAD is the standard deviation of normal data, the kernel scales the noise by sqrt(36)
+	return 1.4826 * medianF64(response) / 6
+}
+
+// ClippedFractionsGray returns the fractions of the pixels of a grayscale image which are at or above
+// ClippedHighlightLevel and at or below ClippedShadowLevel.
+// Example of usage:
+//
+//	highlights, shadows := utils.ClippedFractionsGray(img)
+func ClippedFractionsGray(img *image.Gray) (highlights, shadows float64) {
+	hist := LuminanceHistogramGray(img)
+	size := img.Bounds().Size()
+	n := float64(size.X * size.Y)
+	if n == 0 {
+		return 0, 0
+	}
+	var high, low int
+	for v, count := range hist {
+		if v >= ClippedHighlightLevel {
+			high += count
+		}
+		if v <= ClippedShadowLevel {
+			low += count
+		}
+	}
+	return float64(high) / n, float64(low) / n
+}
+
+// -------------------------------------------------------------------------------------------------------
+func luminance(img *image.RGBA) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			o := y*img.Stride + 4*x
+			c := color.RGBA{R: img.Pix[o], G: img.Pix[o+1], B: img.Pix[o+2], A: MaxUint8}
+			res.Pix[y*res.Stride+x] = color.GrayModel.Convert(c).(color.Gray).Y
+		}
+	}
+	return res
+}
+
+// highPass evaluates a 3x3 filter on the inner pixels of a grayscale image, at returns the value of the neighbour at
+// the given offset.
+func highPass(img *image.Gray, filter func(at func(dx, dy int) float64) float64) []float64 {
+	size := img.Bounds().Size()
+	if size.X < 3 || size.Y < 3 {
+		return nil
+	}
+	res := make([]float64, 0, (size.X-2)*(size.Y-2))
+	for y := 1; y < size.Y-1; y++ {
+		for x := 1; x < size.X-1; x++ {
+			at := func(dx, dy int) float64 {
+				return float64(img.Pix[(y+dy)*img.Stride+x+dx])
+			}
+			res = append(res, filter(at))
+		}
+	}
+	return res
+}
+
+func medianF64(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ChannelMeanStdDevRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.SetRGBA(x, 0, color.RGBA{R: 10, G: 100, B: 50, A: 0xFF})
+		img.SetRGBA(x, 1, color.RGBA{R: 30, G: 100, B: 250, A: 0xFF})
+	}
+	mean, stdDev := ChannelMeanStdDevRGBA(img)
+	expectedMean, expectedStdDev := [3]float64{20, 100, 150}, [3]float64{10, 0, 100}
+	for c := 0; c < 3; c++ {
+		if !IsEqualFloat64(mean[c], expectedMean[c]) || !IsEqualFloat64(stdDev[c], expectedStdDev[c]) {
+			t.Errorf("Expected mean: %v, stddev: %v - actual mean: %v, stddev: %v", expectedMean, expectedStdDev, mean, stdDev)
+		}
+	}
+}
+
+func Test_MeanStdDevGrayMasked(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 10))
+	mask := image.NewGray(image.Rect(0, 0, 20, 10))
+	rand.New(rand.NewSource(3)).Read(img.Pix)
+	// the bright region alternates between 200 and 220 and is the only masked part
+	for y := 2; y < 6; y++ {
+		for x := 5; x < 15; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(200 + 20*(x%2))})
+			mask.SetGray(x, y, color.Gray{Y: 1})
+		}
+	}
+	mean, stdDev, count, err := MeanStdDevGrayMasked(img, mask)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if !IsEqualFloat64(210, mean) || !IsEqualFloat64(10, stdDev) || count != 40 {
+		t.Errorf("Expected mean: 210, stddev: 10, count: 40 - actual mean: %f, stddev: %f, count: %d", mean, stdDev, count)
+	}
+	// the pixels of a sub-image are matched with the mask by their position relative to the corner
+	sub := img.SubImage(image.Rect(5, 2, 15, 10)).(*image.Gray)
+	subMask := image.NewGray(image.Rect(0, 0, 10, 8))
+	for i := 0; i < 40; i++ {
+		subMask.Pix[i] = 255
+	}
+	if mean, _, count, _ := MeanStdDevGrayMasked(sub, subMask); !IsEqualFloat64(210, mean) || count != 40 {
+		t.Errorf("Expected mean: 210, count: 40 for the sub-images - actual mean: %f, count: %d", mean, count)
+	}
+	mean, stdDev, count, err = MeanStdDevGrayMasked(img, image.NewGray(img.Bounds()))
+	if err != nil || mean != 0 || stdDev != 0 || count != 0 {
+		t.Errorf("Expected zeros for an empty mask - actual mean: %f, stddev: %f, count: %d, error: %v", mean, stdDev, count, err)
+	}
+	if _, _, _, err := MeanStdDevGrayMasked(img, image.NewGray(image.Rect(0, 0, 5, 5))); err == nil {
+		t.Error("Expected error for a mask of a different size")
+	}
+}
+
+func Test_ColorCastRGBA(t *testing.T) {
+	neutral := uniformRGBA(color.RGBA{R: 90, G: 90, B: 90, A: 0xFF})
+	if cast := ColorCastRGBA(neutral); cast != [3]float64{1, 1, 1} {
+		t.Errorf("Expected no color cast - actual: %v", cast)
+	}
+	bluish := uniformRGBA(color.RGBA{R: 80, G: 100, B: 120, A: 0xFF})
+	if cast := ColorCastRGBA(bluish); !IsEqualFloat64(cast[0], 0.8) || !IsEqualFloat64(cast[1], 1) || !IsEqualFloat64(cast[2], 1.2) {
+		t.Errorf("Expected color cast: [0.8 1 1.2] - actual: %v", cast)
+	}
+}
+
+func Test_LuminanceHistogramGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 2))
+	copy(img.Pix, []uint8{0, 0, 7, 255, 7, 7, 1, 2, 3, 255})
+	hist := LuminanceHistogramGray(img)
+	if hist[0] != 2 || hist[7] != 3 || hist[255] != 2 || hist[1] != 1 || hist[4] != 0 {
+		t.Errorf("Unexpected histogram values: %d %d %d %d %d", hist[0], hist[7], hist[255], hist[1], hist[4])
+	}
+}
+
+func Test_CountNonZeroGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 8))
+	for x := 2; x < 7; x++ {
+		for y := 1; y < 5; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * y)})
+		}
+	}
+	if count := CountNonZeroGray(img); count != 20 {
+		t.Errorf("Expected 20 non-zero pixels - actual: %d", count)
+	}
+	// the view of a sub-image does not count the pixels outside of it
+	if count := CountNonZeroGray(img.SubImage(image.Rect(0, 0, 4, 8)).(*image.Gray)); count != 8 {
+		t.Errorf("Expected 8 non-zero pixels in the sub-image - actual: %d", count)
+	}
+}
+
+func Test_LaplacianVarianceGray(t *testing.T) {
+	sharp := image.NewGray(image.Rect(0, 0, 32, 32))
+	ForEachPixel(sharp.Bounds().Size(), func(x, y int) {
+		if (x/4+y/4)%2 == 0 {
+			sharp.SetGray(x, y, color.Gray{Y: 200})
+		} else {
+			sharp.SetGray(x, y, color.Gray{Y: 50})
+		}
+	})
+	// 3x3 box blur as a defocused version of the same scene
+	defocused := image.NewGray(sharp.Bounds())
+	ForEachPixel(sharp.Bounds().Size(), func(x, y int) {
+		sum := 0
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				sum += int(sharp.GrayAt(ClampInt(x+dx, 0, 31), ClampInt(y+dy, 0, 31)).Y)
+			}
+		}
+		defocused.SetGray(x, y, color.Gray{Y: uint8(sum / 9)})
+	})
+	s, d := LaplacianVarianceGray(sharp), LaplacianVarianceGray(defocused)
+	if s <= d || d <= 0 {
+		t.Errorf("Expected the defocused image to be less sharp - sharp: %f, defocused: %f", s, d)
+	}
+	if v := LaplacianVarianceGray(uniformGray(120)); v != 0 {
+		t.Errorf("Expected 0 for a flat image - actual: %f", v)
+	}
+}
+
+func Test_EstimateNoiseGray(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, sigma := range []float64{3, 10} {
+		img := image.NewGray(image.Rect(0, 0, 128, 128))
+		ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			// a smooth gradient plus Gaussian noise
+			v := 60 + float64(x+y)/2 + rng.NormFloat64()*sigma
+			img.SetGray(x, y, color.Gray{Y: uint8(ClampF64(math.Round(v), 0, 255))})
+		})
+		if estimate := EstimateNoiseGray(img); math.Abs(estimate-sigma) > 0.15*sigma+0.5 {
+			t.Errorf("Expected noise close to: %f - actual: %f", sigma, estimate)
+		}
+	}
+	gradient := image.NewGray(image.Rect(0, 0, 64, 64))
+	ForEachPixel(gradient.Bounds().Size(), func(x, y int) {
+		gradient.SetGray(x, y, color.Gray{Y: uint8(x*2 + y)})
+	})
+	if estimate := EstimateNoiseGray(gradient); estimate != 0 {
+		t.Errorf("Expected no noise on a clean gradient - actual: %f", estimate)
+	}
+}
+
+func Test_ClippedFractionsGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		switch {
+		case y < 2:
+			img.SetGray(x, y, color.Gray{Y: 255})
+		case y < 5:
+			img.SetGray(x, y, color.Gray{Y: 1})
+		default:
+			img.SetGray(x, y, color.Gray{Y: 128})
+		}
+	})
+	if high, low := ClippedFractionsGray(img); !IsEqualFloat64(high, 0.2) || !IsEqualFloat64(low, 0.3) {
+		t.Errorf("Expected clipped fractions: 0.2 0.3 - actual: %f %f", high, low)
+	}
+}
+
+func Test_AnalyzeRGBA(t *testing.T) {
+	img := uniformRGBA(color.RGBA{R: 255, G: 255, B: 255, A: 0xFF})
+	stats := AnalyzeRGBA(img)
+	if stats.Mean != [3]float64{255, 255, 255} || stats.ClippedHighlights != 1 || stats.ClippedShadows != 0 {
+		t.Errorf("Unexpected statistics for a white image: %+v", stats)
+	}
+	if stats.LuminanceHistogram[255] != 16*16 || stats.Sharpness != 0 || stats.Noise != 0 {
+		t.Errorf("Unexpected statistics for a white image: %+v", stats)
+	}
+	if stats.ColorCast != [3]float64{1, 1, 1} {
+		t.Errorf("Expected no color cast - actual: %v", stats.ColorCast)
+	}
+}
+
+// -------------------------------------------------------------------------------
+func uniformRGBA(c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, c)
+	})
+	return img
+}
+
+func uniformGray(v uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := range img.Pix {
+		img.Pix[i] = v
+	}
+	return img
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"image"
+)
+
+// FromBytesGray creates a grayscale image from raw row-major 8 bit pixels, e.g. a frame of a sensor or a tensor of a
+// machine learning model. The data is copied, so the buffer can be reused by the caller. Returns an error if the size
+// is negative or the length of the data is not width * height.
+// Example of usage:
+//
+//	img, err := utils.FromBytesGray(frame, 640, 480)
+func FromBytesGray(data []byte, width, height int) (*image.Gray, error) {
+	if width < 0 || height < 0 {
+		return nil, errors.New("negative size")
+	}
+	if len(data) != width*height {
+		return nil, errors.New("the length of the data does not match the size of the image")
+	}
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	copy(img.Pix, data)
+	return img, nil
+}
+
+// ToBytesGray returns the pixels of a grayscale image as tightly packed row-major bytes, the padding at the end of the
+// rows (see Stride) and the pixels outside of the bounds of a sub-image are left out. The result does not share memory
+// with the image.
+// Example of usage:
+//
+//	data := utils.ToBytesGray(img)
+func ToBytesGray(img *image.Gray) []byte {
+	bounds := img.Bounds()
+	data := make([]byte, bounds.Dx()*bounds.Dy())
+	if len(data) == 0 {
+		return data
+	}
+	cloneRows(data, bounds.Dx(), img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), bounds.Dx(), bounds.Dy())
+	return data
+}
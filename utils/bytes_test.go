@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_FromBytesGray_RoundTrip(t *testing.T) {
+	data := make([]byte, 7*5)
+	for i := range data {
+		data[i] = uint8(i * 7)
+	}
+	img, err := FromBytesGray(data, 7, 5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if img.GrayAt(3, 2).Y != data[2*7+3] {
+		t.Errorf("Expected value: %d - actual value: %d", data[2*7+3], img.GrayAt(3, 2).Y)
+	}
+	// the image owns a copy of the data
+	data[0] = 255
+	if img.Pix[0] != 0 {
+		t.Error("Expected the data to be copied")
+	}
+	data[0] = 0
+	if actual := ToBytesGray(img); !bytes.Equal(data, actual) {
+		t.Errorf("Expected bytes: %v - actual bytes: %v", data, actual)
+	}
+}
+
+func Test_ToBytesGray_SubImage(t *testing.T) {
+	img := &image.Gray{Rect: image.Rect(0, 0, 4, 3), Stride: 4, Pix: []uint8{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	}}
+	sub := img.SubImage(image.Rect(1, 1, 3, 3)).(*image.Gray)
+	if actual := ToBytesGray(sub); !bytes.Equal([]byte{6, 7, 10, 11}, actual) {
+		t.Errorf("Expected bytes: %v - actual bytes: %v", []byte{6, 7, 10, 11}, actual)
+	}
+	if actual := ToBytesGray(image.NewGray(image.Rect(0, 0, 0, 3))); len(actual) != 0 {
+		t.Errorf("Expected no bytes for an empty image - actual: %v", actual)
+	}
+}
+
+func Test_FromBytesGray_Invalid(t *testing.T) {
+	if _, err := FromBytesGray(make([]byte, 11), 4, 3); err == nil {
+		t.Error("Expected error for a length mismatch")
+	}
+	if _, err := FromBytesGray(nil, -2, -3); err == nil {
+		t.Error("Expected error for a negative size")
+	}
+}
+
+// -------------------------------------------------------------------------------
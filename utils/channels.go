@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// SplitRGBA splits an RGBA image into four grayscale images, one for each channel (red, green, blue and alpha).
+// Example of usage:
+//
+//	r, g, b, a := utils.SplitRGBA(img)
+func SplitRGBA(img *image.RGBA) (r, g, b, a *image.Gray) {
+	r = image.NewGray(img.Bounds())
+	g = image.NewGray(img.Bounds())
+	b = image.NewGray(img.Bounds())
+	a = image.NewGray(img.Bounds())
+	ParallelForEachPixel(img.Bounds().Size(), func(x, y int) {
+		pixel := img.RGBAAt(x, y)
+		r.SetGray(x, y, color.Gray{Y: pixel.R})
+		g.SetGray(x, y, color.Gray{Y: pixel.G})
+		b.SetGray(x, y, color.Gray{Y: pixel.B})
+		a.SetGray(x, y, color.Gray{Y: pixel.A})
+	})
+	return r, g, b, a
+}
+
+// MergeRGBA combines four grayscale images into an RGBA image, each of the grayscale images represents a channel
+// (red, green, blue and alpha). Returns an error if the images do not share the same bounds.
+// Example of usage:
+//
+//	res, err := utils.MergeRGBA(r, g, b, a)
+func MergeRGBA(r, g, b, a *image.Gray) (*image.RGBA, error) {
+	bounds := r.Bounds()
+	if !g.Bounds().Eq(bounds) || !b.Bounds().Eq(bounds) || !a.Bounds().Eq(bounds) {
+		return nil, errors.New("the bounds of the channels do not match")
+	}
+	res := image.NewRGBA(bounds)
+	ParallelForEachPixel(bounds.Size(), func(x, y int) {
+		res.SetRGBA(x, y, color.RGBA{R: r.GrayAt(x, y).Y, G: g.GrayAt(x, y).Y, B: b.GrayAt(x, y).Y, A: a.GrayAt(x, y).Y})
+	})
+	return res, nil
+}
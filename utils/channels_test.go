@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"image"
+	"testing"
+)
+
+func Test_SplitMergeRGBA(t *testing.T) {
+	rgba := &image.RGBA{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3 * 4,
+		Pix: []uint8{
+			0x01, 0x02, 0x03, 0x04, 0x10, 0x20, 0x30, 0x40, 0xFF, 0x00, 0x80, 0xFF,
+			0xAA, 0xBB, 0xCC, 0xDD, 0x00, 0x00, 0x00, 0x00, 0x7F, 0x80, 0x81, 0x82,
+		},
+	}
+	r, g, b, a := SplitRGBA(rgba)
+	expectedRed := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x01, 0x10, 0xFF,
+			0xAA, 0x00, 0x7F,
+		},
+	}
+	CompareGrayImages(t, expectedRed, r)
+	merged, err := MergeRGBA(r, g, b, a)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	CompareRGBAImages(t, rgba, merged)
+}
+
+func Test_MergeRGBA_DifferentBounds(t *testing.T) {
+	c := image.NewGray(image.Rect(0, 0, 3, 3))
+	other := image.NewGray(image.Rect(0, 0, 3, 2))
+	if _, err := MergeRGBA(c, c, other, c); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
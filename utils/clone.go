@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"image"
+)
+
+// CloneGray returns a deep copy of a grayscale image, the bounds of the copy start at (0, 0). The copy does not share
+// memory with the input, so it can be modified while the input is read by other goroutines.
+// Example of usage:
+//
+//	res := utils.CloneGray(img)
+func CloneGray(img *image.Gray) *image.Gray {
+	bounds := img.Bounds()
+	res := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	cloneRows(res.Pix, res.Stride, img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), bounds.Dx(), bounds.Dy())
+	return res
+}
+
+// CloneRGBA returns a deep copy of an RGBA image, the bounds of the copy start at (0, 0). The copy does not share
+// memory with the input, so it can be modified while the input is read by other goroutines.
+// Example of usage:
+//
+//	res := utils.CloneRGBA(img)
+func CloneRGBA(img *image.RGBA) *image.RGBA {
+	bounds := img.Bounds()
+	res := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	cloneRows(res.Pix, res.Stride, img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), 4*bounds.Dx(), bounds.Dy())
+	return res
+}
+
+// CloneNRGBA returns a deep copy of an NRGBA image, the bounds of the copy start at (0, 0). The copy does not share
+// memory with the input, so it can be modified while the input is read by other goroutines.
+// Example of usage:
+//
+//	res := utils.CloneNRGBA(img)
+func CloneNRGBA(img *image.NRGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	res := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	cloneRows(res.Pix, res.Stride, img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), 4*bounds.Dx(), bounds.Dy())
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------------
+// cloneRows copies rows of rowLength bytes, the first row of the source starts at offset.
+func cloneRows(dst []uint8, dstStride int, src []uint8, srcStride int, offset int, rowLength int, rows int) {
+	for y := 0; y < rows; y++ {
+		start := offset + y*srcStride
+		copy(dst[y*dstStride:y*dstStride+rowLength], src[start:start+rowLength])
+	}
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_CloneGray(t *testing.T) {
+	img := image.NewGray(image.Rect(-2, 3, 8, 9))
+	for x := -2; x < 8; x++ {
+		for y := 3; y < 9; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x*17 + y*5 + 40)})
+		}
+	}
+	sub := img.SubImage(image.Rect(1, 4, 6, 8)).(*image.Gray)
+	res := CloneGray(sub)
+	if res.Bounds() != image.Rect(0, 0, 5, 4) {
+		t.Fatalf("Expected bounds [0 0 5 4] - actual: %v", res.Bounds())
+	}
+	ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if res.GrayAt(x, y) != img.GrayAt(x+1, y+4) {
+			t.Errorf("Expected: %v - Actual: %v at %d, %d", img.GrayAt(x+1, y+4), res.GrayAt(x, y), x, y)
+		}
+	})
+	// the copy does not share memory with the input
+	for i := range res.Pix {
+		res.Pix[i] = 0
+	}
+	if img.GrayAt(1, 4).Y != 1*17+4*5+40 {
+		t.Errorf("Expected the input to be unchanged")
+	}
+}
+
+func Test_CloneRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 7, 5))
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		img.SetRGBA(x, y, color.RGBA{R: uint8(x * 30), G: uint8(y * 40), B: uint8(x + y), A: uint8(200 + x)})
+	})
+	sub := img.SubImage(image.Rect(2, 1, 7, 4)).(*image.RGBA)
+	res := CloneRGBA(sub)
+	if res.Bounds() != image.Rect(0, 0, 5, 3) {
+		t.Fatalf("Expected bounds [0 0 5 3] - actual: %v", res.Bounds())
+	}
+	ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if res.RGBAAt(x, y) != img.RGBAAt(x+2, y+1) {
+			t.Errorf("Expected: %v - Actual: %v at %d, %d", img.RGBAAt(x+2, y+1), res.RGBAAt(x, y), x, y)
+		}
+	})
+	res.SetRGBA(0, 0, color.RGBA{})
+	if img.RGBAAt(2, 1) != (color.RGBA{R: 60, G: 40, B: 3, A: 202}) {
+		t.Errorf("Expected the input to be unchanged")
+	}
+}
+
+func Test_CloneNRGBA(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(3, 3, 9, 7))
+	for x := 3; x < 9; x++ {
+		for y := 3; y < 7; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x * y), A: uint8(10 * x)})
+		}
+	}
+	res := CloneNRGBA(img)
+	if res.Bounds() != image.Rect(0, 0, 6, 4) {
+		t.Fatalf("Expected bounds [0 0 6 4] - actual: %v", res.Bounds())
+	}
+	ForEachPixel(res.Bounds().Size(), func(x, y int) {
+		if res.NRGBAAt(x, y) != img.NRGBAAt(x+3, y+3) {
+			t.Errorf("Expected: %v - Actual: %v at %d, %d", img.NRGBAAt(x+3, y+3), res.NRGBAAt(x, y), x, y)
+		}
+	})
+}
+
+// -------------------------------------------------------------------------------
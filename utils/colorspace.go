@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"math"
+)
+
+// RGBToHSV converts an RGB color to the HSV color space. The hue is in degrees in the [0, 360) interval, the saturation
+// and the value are in [0, 1]. The hue of gray colors (zero saturation) is 0.
+// Example of usage:
+//
+//	h, s, v := utils.RGBToHSV(pixel.R, pixel.G, pixel.B)
+func RGBToHSV(r, g, b uint8) (h, s, v float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	max := math.Max(rf, math.Max(gf, bf))
+	min := math.Min(rf, math.Min(gf, bf))
+	delta := max - min
+	v = max
+	if max > 0 {
+		s = delta / max
+	}
+	if delta == 0 {
+		return 0, s, v
+	}
+	switch max {
+	case rf:
+		h = 60 * math.Mod((gf-bf)/delta, 6)
+	case gf:
+		h = 60 * ((bf-rf)/delta + 2)
+	default:
+		h = 60 * ((rf-gf)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+// HSVToRGB converts a HSV color (hue in degrees, saturation and value in [0, 1]) to the RGB color space.
+// Example of usage:
+//
+//	r, g, b := utils.HSVToRGB(120, 1, 0.5)
+func HSVToRGB(h, s, v float64) (r, g, b uint8) {
+	c := v * s
+	hp := math.Mod(h/60, 6)
+	if hp < 0 {
+		hp += 6
+	}
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var rf, gf, bf float64
+	switch {
+	case hp < 1:
+		rf, gf, bf = c, x, 0
+	case hp < 2:
+		rf, gf, bf = x, c, 0
+	case hp < 3:
+		rf, gf, bf = 0, c, x
+	case hp < 4:
+		rf, gf, bf = 0, x, c
+	case hp < 5:
+		rf, gf, bf = x, 0, c
+	default:
+		rf, gf, bf = c, 0, x
+	}
+	m := v - c
+	toUint8 := func(f float64) uint8 {
+		return uint8(ClampF64(math.Round((f+m)*255), MinUint8, float64(MaxUint8)))
+	}
+	return toUint8(rf), toUint8(gf), toUint8(bf)
+}
+
+// RGBToLab converts an sRGB color to the CIE L*a*b* color space using the D65 white point. L is in the [0, 100]
+// interval, a and b are roughly in the [-128, 127] interval.
+// Example of usage:
+//
+//	l, a, b := utils.RGBToLab(pixel.R, pixel.G, pixel.B)
+func RGBToLab(r, g, b uint8) (l, a, bb float64) {
+	rl, gl, bl := SRGBToLinear(r), SRGBToLinear(g), SRGBToLinear(b)
+	x := (0.4124564*rl + 0.3575761*gl + 0.1804375*bl) / labWhiteX
+	y := 0.2126729*rl + 0.7151522*gl + 0.0721750*bl
+	z := (0.0193339*rl + 0.1191920*gl + 0.9503041*bl) / labWhiteZ
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// LabToRGB converts a CIE L*a*b* color (D65 white point) to the sRGB color space. Colors outside of the sRGB gamut are
+// clamped.
+// Example of usage:
+//
+//	r, g, b := utils.LabToRGB(50, 20, -30)
+func LabToRGB(l, a, bb float64) (r, g, b uint8) {
+	fy := (l + 16) / 116
+	x := labWhiteX * labFInv(fy+a/500)
+	y := labFInv(fy)
+	z := labWhiteZ * labFInv(fy-bb/200)
+	rl := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	gl := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	bl := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return LinearToSRGB(rl), LinearToSRGB(gl), LinearToSRGB(bl)
+}
+
+// SRGBToLinear converts an 8 bit sRGB encoded channel value to linear light in the [0, 1] interval, e.g. to average
+// colors in a physically correct way.
+// Example of usage:
+//
+//	l := utils.SRGBToLinear(pixel.R)
+func SRGBToLinear(v uint8) float64 {
+	return srgbToLinearTable[v]
+}
+
+// LinearToSRGB converts a linear light value to an 8 bit sRGB encoded channel value, it is the inverse of
+// SRGBToLinear. Values outside of the [0, 1] interval are clamped.
+// Example of usage:
+//
+//	v := utils.LinearToSRGB(0.5)
+func LinearToSRGB(c float64) uint8 {
+	c = ClampF64(c, 0, 1)
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(ClampF64(math.Round(c*255), MinUint8, float64(MaxUint8)))
+}
+
+// -------------------------------------------------------------------------------------------------------
+const (
+	labWhiteX  = 0.95047
+	labWhiteZ  = 1.08883
+	labEpsilon = 216.0 / 24389.0
+	labKappa   = 24389.0 / 27.0
+)
+
+var srgbToLinearTable = func() [256]float64 {
+	var table [256]float64
+	for i := range table {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			table[i] = c / 12.92
+		} else {
+			table[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return table
+}()
+
+func labF(t float64) float64 {
+	if t > labEpsilon {
+		return math.Cbrt(t)
+	}
+	return (labKappa*t + 16) / 116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > labEpsilon {
+		return t3
+	}
+	return (116*t - 16) / labKappa
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_RGBToHSV(t *testing.T) {
+	cases := []struct {
+		r, g, b uint8
+		h, s, v float64
+	}{
+		{0xFF, 0x00, 0x00, 0, 1, 1},
+		{0x00, 0xFF, 0x00, 120, 1, 1},
+		{0x00, 0x00, 0xFF, 240, 1, 1},
+		{0xFF, 0x00, 0xFF, 300, 1, 1},
+		{0x80, 0x80, 0x80, 0, 0, 128.0 / 255},
+		{0x00, 0x00, 0x00, 0, 0, 0},
+	}
+	for _, c := range cases {
+		h, s, v := RGBToHSV(c.r, c.g, c.b)
+		if !IsEqualFloat64(h, c.h) || !IsEqualFloat64(s, c.s) || !IsEqualFloat64(v, c.v) {
+			t.Errorf("Expected HSV: %f %f %f - actual HSV: %f %f %f for %d %d %d", c.h, c.s, c.v, h, s, v, c.r, c.g, c.b)
+		}
+	}
+}
+
+func Test_HSVToRGB_RoundTrip(t *testing.T) {
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 17 {
+			for b := 0; b < 256; b += 51 {
+				ar, ag, ab := HSVToRGB(RGBToHSV(uint8(r), uint8(g), uint8(b)))
+				if ar != uint8(r) || ag != uint8(g) || ab != uint8(b) {
+					t.Errorf("Expected RGB: %d %d %d - actual RGB: %d %d %d", r, g, b, ar, ag, ab)
+				}
+			}
+		}
+	}
+}
+
+func Test_RGBToLab(t *testing.T) {
+	cases := []struct {
+		r, g, b  uint8
+		l, a, bb float64
+	}{
+		{0xFF, 0xFF, 0xFF, 100, 0, 0},
+		{0x00, 0x00, 0x00, 0, 0, 0},
+		{0xFF, 0x00, 0x00, 53.24, 80.09, 67.20},
+		{0x00, 0x00, 0xFF, 32.30, 79.19, -107.86},
+	}
+	for _, c := range cases {
+		l, a, bb := RGBToLab(c.r, c.g, c.b)
+		if math.Abs(l-c.l) > 0.05 || math.Abs(a-c.a) > 0.05 || math.Abs(bb-c.bb) > 0.05 {
+			t.Errorf("Expected Lab: %f %f %f - actual Lab: %f %f %f for %d %d %d", c.l, c.a, c.bb, l, a, bb, c.r, c.g, c.b)
+		}
+	}
+}
+
+func Test_LabToRGB_RoundTrip(t *testing.T) {
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 17 {
+			for b := 0; b < 256; b += 51 {
+				ar, ag, ab := LabToRGB(RGBToLab(uint8(r), uint8(g), uint8(b)))
+				if ar != uint8(r) || ag != uint8(g) || ab != uint8(b) {
+					t.Errorf("Expected RGB: %d %d %d - actual RGB: %d %d %d", r, g, b, ar, ag, ab)
+				}
+			}
+		}
+	}
+}
+
+func Test_SRGBToLinear_RoundTrip(t *testing.T) {
+	if SRGBToLinear(0) != 0 || SRGBToLinear(MaxUint8) != 1 {
+		t.Errorf("Expected the ends of the range to be kept - actual: %f %f", SRGBToLinear(0), SRGBToLinear(MaxUint8))
+	}
+	for v := 0; v < 256; v++ {
+		if actual := LinearToSRGB(SRGBToLinear(uint8(v))); actual != uint8(v) {
+			t.Errorf("Expected: %d - actual: %d", v, actual)
+		}
+	}
+	if LinearToSRGB(-1) != 0 || LinearToSRGB(2) != MaxUint8 {
+		t.Error("Expected values outside of [0, 1] to be clamped")
+	}
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"errors"
+	"image"
+)
+
+// CopyToGray copies the pixels of src into dst where the mask is nonzero, the other pixels of dst are left untouched.
+// Returns an error if the three images do not share the same bounds.
+// Example of usage:
+//
+//	err := utils.CopyToGray(src, dst, mask)
+func CopyToGray(src, dst *image.Gray, mask *image.Gray) error {
+	bounds := src.Bounds()
+	if !dst.Bounds().Eq(bounds) || !mask.Bounds().Eq(bounds) {
+		return errors.New("the bounds of the images do not match")
+	}
+	size := bounds.Size()
+	for y := 0; y < size.Y; y++ {
+		srcRow := src.Pix[y*src.Stride : y*src.Stride+size.X]
+		dstRow := dst.Pix[y*dst.Stride : y*dst.Stride+size.X]
+		maskRow := mask.Pix[y*mask.Stride : y*mask.Stride+size.X]
+		for x, m := range maskRow {
+			if m != 0 {
+				dstRow[x] = srcRow[x]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_CopyToGray(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 30, 30))
+	dst := image.NewGray(image.Rect(0, 0, 30, 30))
+	mask := image.NewGray(image.Rect(0, 0, 30, 30))
+	inSquare := func(x, y int) bool { return x >= 5 && x < 20 && y >= 5 && y < 20 }
+	inCircle := func(x, y int) bool { return (x-20)*(x-20)+(y-20)*(y-20) <= 8*8 }
+	ForEachPixel(src.Bounds().Size(), func(x, y int) {
+		if inSquare(x, y) {
+			src.SetGray(x, y, color.Gray{Y: 0xF0})
+		}
+		dst.SetGray(x, y, color.Gray{Y: 0x10})
+		if inCircle(x, y) {
+			mask.SetGray(x, y, color.Gray{Y: 0x01})
+		}
+	})
+	if err := CopyToGray(src, dst, mask); err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	ForEachPixel(dst.Bounds().Size(), func(x, y int) {
+		expected := uint8(0x10)
+		if inCircle(x, y) {
+			expected = src.GrayAt(x, y).Y
+		}
+		if actual := dst.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+		}
+	})
+	if dst.GrayAt(15, 15).Y != 0xF0 || dst.GrayAt(6, 6).Y != 0x10 {
+		t.Errorf("Expected only the intersection of the square and the circle to be bright")
+	}
+	if err := CopyToGray(src, image.NewGray(image.Rect(0, 0, 3, 3)), mask); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"image"
+)
+
+// DiffGray compares two grayscale images and returns an image of the absolute per-pixel differences together with the
+// number of differing pixels. It is useful to visualize where two filter outputs differ. Returns an error if the
+// bounds of the images do not match.
+// Example of usage:
+//
+//	diff, count, err := utils.DiffGray(expected, actual)
+func DiffGray(a, b *image.Gray) (*image.Gray, int, error) {
+	if !a.Bounds().Eq(b.Bounds()) {
+		return nil, 0, errors.New("the bounds of the images do not match")
+	}
+	size := a.Bounds().Size()
+	res := image.NewGray(a.Bounds())
+	count := 0
+	for y := 0; y < size.Y; y++ {
+		rowA := a.Pix[y*a.Stride : y*a.Stride+size.X]
+		rowB := b.Pix[y*b.Stride : y*b.Stride+size.X]
+		dst := res.Pix[y*res.Stride : y*res.Stride+size.X]
+		for x := range rowA {
+			if rowA[x] > rowB[x] {
+				dst[x] = rowA[x] - rowB[x]
+			} else {
+				dst[x] = rowB[x] - rowA[x]
+			}
+			if dst[x] != 0 {
+				count++
+			}
+		}
+	}
+	return res, count, nil
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_DiffGray(t *testing.T) {
+	a := image.NewGray(image.Rect(0, 0, 7, 5))
+	for i := range a.Pix {
+		a.Pix[i] = uint8(i * 9)
+	}
+	b := image.NewGray(a.Bounds())
+	copy(b.Pix, a.Pix)
+	b.SetGray(4, 2, color.Gray{Y: a.GrayAt(4, 2).Y - 30})
+	diff, count, err := DiffGray(a, b)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 differing pixel - actual: %d", count)
+	}
+	ForEachPixel(diff.Bounds().Size(), func(x, y int) {
+		var expected uint8
+		if x == 4 && y == 2 {
+			expected = 30
+		}
+		if actual := diff.GrayAt(x, y).Y; actual != expected {
+			t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", expected, actual, x, y)
+		}
+	})
+	if _, _, err := DiffGray(a, image.NewGray(image.Rect(0, 0, 2, 2))); err == nil {
+		t.Fatal("Should not reach this point")
+	}
+}
@@ -22,6 +22,30 @@ func ForEachGrayPixel(img *image.Gray, f func(pixel color.Gray)) {
 	})
 }
 
+// ForEachPixelGray loops through the image row by row and calls fn with the position and the value of each pixel. The
+// position is relative to the top left corner of the image bounds. The iteration stops at the first error returned by
+// fn and the error is returned.
+// Example of usage:
+//
+//	err := utils.ForEachPixelGray(img, func(x, y int, v uint8) error {
+//		if v != 0 && v != utils.MaxUint8 {
+//			return fmt.Errorf("non binary pixel at %d, %d", x, y)
+//		}
+//		return nil
+//	})
+func ForEachPixelGray(img *image.Gray, fn func(x, y int, v uint8) error) error {
+	size := img.Bounds().Size()
+	for y := 0; y < size.Y; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+size.X]
+		for x, v := range row {
+			if err := fn(x, y, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ForEachRGBAPixel loops through the image and calls f functions for each RGBA pixel.
 func ForEachRGBAPixel(img *image.RGBA, f func(pixel color.RGBA)) {
 	ForEachPixel(img.Bounds().Size(), func(x, y int) {
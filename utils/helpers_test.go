@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ForEachPixelGray(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 3))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	stop := errors.New("stop")
+	var visited []image.Point
+	err := ForEachPixelGray(img, func(x, y int, v uint8) error {
+		if v != img.GrayAt(x, y).Y {
+			t.Errorf("Expected value: %d - actual value: %d at: %d %d", img.GrayAt(x, y).Y, v, x, y)
+		}
+		visited = append(visited, image.Point{X: x, Y: y})
+		if x == 1 && y == 1 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatalf("Expected error: %v - actual error: %v", stop, err)
+	}
+	// row by row up to and including {1, 1}
+	if len(visited) != 6 || visited[len(visited)-1] != (image.Point{X: 1, Y: 1}) {
+		t.Errorf("Expected the iteration to stop at {1, 1} after 6 pixels - actual: %v", visited)
+	}
+}
+
+func Test_ForEachPixelGray_SubImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	sub := img.SubImage(image.Rect(2, 1, 4, 3)).(*image.Gray)
+	sum, count := 0, 0
+	err := ForEachPixelGray(sub, func(x, y int, v uint8) error {
+		sum += int(v)
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	// pixels 7, 8, 12, 13
+	if count != 4 || sum != 40 {
+		t.Errorf("Expected 4 pixels with sum 40 - actual: %d pixels with sum %d", count, sum)
+	}
+}
+
+// -------------------------------------------------------------------------------
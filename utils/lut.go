@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"image"
+)
+
+// ApplyLUTGray maps every pixel of a grayscale image through a 256 entry lookup table, the value v of a pixel is
+// replaced by lut[v]. The image is processed in a single pass over its Pix slice, so the table can be computed once
+// and shared by point operations like gamma correction, brightness/contrast or solarization.
+// Example of usage:
+//
+//	res := utils.ApplyLUTGray(img, lut)
+func ApplyLUTGray(img *image.Gray, lut [256]uint8) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		src := img.Pix[y*img.Stride : y*img.Stride+size.X]
+		dst := res.Pix[y*res.Stride : y*res.Stride+size.X]
+		for i, v := range src {
+			dst[i] = lut[v]
+		}
+	}
+	return res
+}
+
+// ApplyLUTRGBA maps the red, green and blue channels of an RGBA image through independent 256 entry lookup tables,
+// which makes it suitable for color grading curves. The alpha channel is copied unchanged.
+// Example of usage:
+//
+//	res := utils.ApplyLUTRGBA(img, rLUT, gLUT, bLUT)
+func ApplyLUTRGBA(img *image.RGBA, rLUT, gLUT, bLUT [256]uint8) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		src := img.Pix[y*img.Stride : y*img.Stride+4*size.X]
+		dst := res.Pix[y*res.Stride : y*res.Stride+4*size.X]
+		for i := 0; i < len(src); i += 4 {
+			dst[i] = rLUT[src[i]]
+			dst[i+1] = gLUT[src[i+1]]
+			dst[i+2] = bLUT[src[i+2]]
+			dst[i+3] = src[i+3]
+		}
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"image"
+	"testing"
+)
+
+func Test_ApplyLUTGray(t *testing.T) {
+	gray := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0x01, 0x80,
+			0x7F, 0xFE, 0xFF,
+		},
+	}
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0xFF, 0xFE, 0x7F,
+			0x80, 0x01, 0x00,
+		},
+	}
+	var lut [256]uint8
+	for i := range lut {
+		lut[i] = MaxUint8 - uint8(i)
+	}
+	actual := ApplyLUTGray(gray, lut)
+	CompareGrayImages(t, expected, actual)
+}
+
+func Test_ApplyLUTRGBA(t *testing.T) {
+	rgba := &image.RGBA{
+		Rect:   image.Rect(0, 0, 2, 2),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0x00, 0x10, 0x20, 0xFF, 0x80, 0x90, 0xA0, 0x80,
+			0x7F, 0x01, 0xFE, 0x00, 0xFF, 0x00, 0x42, 0x11,
+		},
+	}
+	expected := &image.RGBA{
+		Rect:   image.Rect(0, 0, 2, 2),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0xFF, 0x10, 0x20, 0xFF, 0x7F, 0x90, 0xA0, 0x80,
+			0x80, 0x01, 0xFE, 0x00, 0x00, 0x00, 0x42, 0x11,
+		},
+	}
+	var identity, inverted [256]uint8
+	for i := range identity {
+		identity[i] = uint8(i)
+		inverted[i] = MaxUint8 - uint8(i)
+	}
+	actual := ApplyLUTRGBA(rgba, inverted, identity, identity)
+	CompareRGBAImages(t, expected, actual)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+	"sync"
+)
+
+// MapGray calls fn for every pixel of the grayscale image and returns a new image containing the returned values. The
+// rows are processed by several goroutines (see ParallelForEachPixel), so fn is called concurrently and it must be safe
+// for concurrent use, e.g. it must not update shared state without synchronization.
+// Example of usage:
+//
+//	res := utils.MapGray(img, func(x, y int, v uint8) uint8 { return utils.MaxUint8 - v })
+func MapGray(img *image.Gray, fn func(x, y int, v uint8) uint8) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	ParallelForEachPixel(size, func(x, y int) {
+		res.SetGray(x, y, color.Gray{Y: fn(x, y, img.GrayAt(x, y).Y)})
+	})
+	return res
+}
+
+// MapRGBA calls fn for every pixel of the RGBA image and returns a new image containing the returned colors. Like in
+// MapGray, fn is called concurrently and it must be safe for concurrent use.
+// Example of usage:
+//
+//	res := utils.MapRGBA(img, func(x, y int, c color.RGBA) color.RGBA { return color.RGBA{R: c.G, G: c.R, B: c.B, A: c.A} })
+func MapRGBA(img *image.RGBA, fn func(x, y int, c color.RGBA) color.RGBA) *image.RGBA {
+	size := img.Bounds().Size()
+	res := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	ParallelForEachPixel(size, func(x, y int) {
+		res.SetRGBA(x, y, fn(x, y, img.RGBAAt(x, y)))
+	})
+	return res
+}
+
+// MapGrayParallel calls fn for every pixel of the grayscale image and returns a new image containing the returned
+// values. Unlike MapGray, which uses the workers set by SetParallelism, the rows of the image are split into contiguous
+// bands processed by the given number of workers, each worker writing only into its own rows. If workers is smaller
+// than 1, runtime.NumCPU() workers are used. fn is called concurrently so it must be safe for concurrent use.
+// Example of usage:
+//
+//	res := utils.MapGrayParallel(img, func(x, y int, v uint8) uint8 { return utils.MaxUint8 - v }, 0)
+func MapGrayParallel(img *image.Gray, fn func(x, y int, v uint8) uint8, workers int) *image.Gray {
+	size := img.Bounds().Size()
+	res := image.NewGray(image.Rect(0, 0, size.X, size.Y))
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > size.Y {
+		workers = size.Y
+	}
+	var waitGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		waitGroup.Add(1)
+		go func(startY, endY int) {
+			defer waitGroup.Done()
+			for y := startY; y < endY; y++ {
+				for x := 0; x < size.X; x++ {
+					res.Pix[y*res.Stride+x] = fn(x, y, img.GrayAt(x, y).Y)
+				}
+			}
+		}(i*size.Y/workers, (i+1)*size.Y/workers)
+	}
+	waitGroup.Wait()
+	return res
+}
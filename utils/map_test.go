@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func Test_MapGray(t *testing.T) {
+	gray := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0x10, 0x20,
+			0x30, 0x40, 0x50,
+		},
+	}
+	expected := &image.Gray{
+		Rect:   image.Rect(0, 0, 3, 2),
+		Stride: 3,
+		Pix: []uint8{
+			0x00, 0x11, 0x22,
+			0x40, 0x51, 0x62,
+		},
+	}
+	actual := MapGray(gray, func(x, y int, v uint8) uint8 {
+		return v + uint8(x) + uint8(y)*0x10
+	})
+	CompareGrayImages(t, expected, actual)
+}
+
+func Test_MapRGBA(t *testing.T) {
+	rgba := &image.RGBA{
+		Rect:   image.Rect(0, 0, 2, 1),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0x01, 0x02, 0x03, 0x04, 0x10, 0x20, 0x30, 0x40,
+		},
+	}
+	expected := &image.RGBA{
+		Rect:   image.Rect(0, 0, 2, 1),
+		Stride: 2 * 4,
+		Pix: []uint8{
+			0x03, 0x02, 0x01, 0x04, 0x30, 0x20, 0x10, 0x40,
+		},
+	}
+	actual := MapRGBA(rgba, func(x, y int, c color.RGBA) color.RGBA {
+		return color.RGBA{R: c.B, G: c.G, B: c.R, A: c.A}
+	})
+	CompareRGBAImages(t, expected, actual)
+}
+
+func Test_MapGray_PanicReachesCaller(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 200, 200))
+	recovered := func() (r interface{}) {
+		defer func() { r = recover() }()
+		MapGray(gray, func(x, y int, v uint8) uint8 {
+			if x == 150 && y == 120 {
+				panic("boom")
+			}
+			return v
+		})
+		return nil
+	}()
+	if recovered != "boom" {
+		t.Errorf("Expected the panic of fn to reach the caller of MapGray - actual: %v", recovered)
+	}
+}
+
+func Test_MapGrayParallel(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 37, 23))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 7)
+	}
+	fn := func(x, y int, v uint8) uint8 {
+		return v ^ uint8(x*y)
+	}
+	expected := MapGray(gray, fn)
+	for _, workers := range []int{-1, 0, 1, 3, 8, 100} {
+		actual := MapGrayParallel(gray, fn, workers)
+		CompareGrayImages(t, expected, actual)
+	}
+}
+
+func setupBenchmarkMapGray() (*image.Gray, func(x, y int, v uint8) uint8) {
+	gray := image.NewGray(image.Rect(0, 0, 2048, 2048))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i)
+	}
+	return gray, func(x, y int, v uint8) uint8 {
+		return uint8(math.Sqrt(float64(v) * float64(MaxUint8)))
+	}
+}
+
+func benchmarkMapGrayParallel(b *testing.B, workers int) {
+	gray, fn := setupBenchmarkMapGray()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MapGrayParallel(gray, fn, workers)
+	}
+}
+
+func Benchmark_MapGray(b *testing.B) {
+	gray, fn := setupBenchmarkMapGray()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MapGray(gray, fn)
+	}
+}
+
+func Benchmark_MapGrayParallel_1(b *testing.B) { benchmarkMapGrayParallel(b, 1) }
+
+func Benchmark_MapGrayParallel_2(b *testing.B) { benchmarkMapGrayParallel(b, 2) }
+
+func Benchmark_MapGrayParallel_4(b *testing.B) { benchmarkMapGrayParallel(b, 4) }
+
+func Benchmark_MapGrayParallel_NumCPU(b *testing.B) { benchmarkMapGrayParallel(b, 0) }
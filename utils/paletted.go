@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// PalettedApplyLUT applies a point operation to a paletted image (e.g. a decoded GIF frame) by transforming only the
+// palette, so the transform is evaluated once per palette entry instead of once per pixel. The indices are copied
+// unchanged and the bounds of the input are kept. Returns an error if the palette is empty or has more then 256
+// entries.
+// Example of usage:
+//
+//	res, err := utils.PalettedApplyLUT(frame, func(c color.Color) color.Color {...})
+func PalettedApplyLUT(img *image.Paletted, transform func(color.Color) color.Color) (*image.Paletted, error) {
+	if err := checkPalette(img.Palette); err != nil {
+		return nil, err
+	}
+	palette := make(color.Palette, len(img.Palette))
+	for i, c := range img.Palette {
+		palette[i] = transform(c)
+	}
+	res := image.NewPaletted(img.Bounds(), palette)
+	bounds := img.Bounds()
+	cloneRows(res.Pix, res.Stride, img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), bounds.Dx(), bounds.Dy())
+	return res, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func checkPalette(palette color.Palette) error {
+	if len(palette) == 0 {
+		return errors.New("the palette is empty")
+	}
+	if len(palette) > 256 {
+		return errors.New("the palette has more then 256 entries")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_PalettedApplyLUT(t *testing.T) {
+	img := setupTestCasePaletted(64, 48)
+	res, err := PalettedApplyLUT(img, invertColor)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	if res.Bounds() != img.Bounds() {
+		t.Fatalf("Expected bounds: %v - actual bounds: %v", img.Bounds(), res.Bounds())
+	}
+	// reference: expand to RGBA and apply the transform on every pixel
+	rgba := image.NewRGBA(img.Bounds())
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		rgba.Set(x, y, img.At(x, y))
+	})
+	ForEachPixel(img.Bounds().Size(), func(x, y int) {
+		expected := color.RGBAModel.Convert(invertColor(rgba.At(x, y)))
+		if actual := color.RGBAModel.Convert(res.At(x, y)); actual != expected {
+			t.Fatalf("Expected color: %v - actual color: %v at: %d %d", expected, actual, x, y)
+		}
+	})
+	res.Pix[0]++
+	if img.Pix[0] == res.Pix[0] {
+		t.Error("Expected the result not to share the indices with the input")
+	}
+}
+
+func Test_PalettedApplyLUT_InvalidPalette(t *testing.T) {
+	if _, err := PalettedApplyLUT(image.NewPaletted(image.Rect(0, 0, 4, 4), nil), invertColor); err == nil {
+		t.Error("Expected error for an empty palette")
+	}
+	palette := make(color.Palette, 257)
+	for i := range palette {
+		palette[i] = color.Gray{Y: uint8(i)}
+	}
+	if _, err := PalettedApplyLUT(image.NewPaletted(image.Rect(0, 0, 4, 4), palette), invertColor); err == nil {
+		t.Error("Expected error for a palette with 257 entries")
+	}
+}
+
+func Benchmark_PalettedApplyLUT(b *testing.B) {
+	img := setupTestCasePaletted(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = PalettedApplyLUT(img, invertColor)
+	}
+}
+
+func Benchmark_PalettedApplyLUT_ExpandToRGBA(b *testing.B) {
+	img := setupTestCasePaletted(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rgba := image.NewRGBA(img.Bounds())
+		ForEachPixel(img.Bounds().Size(), func(x, y int) {
+			rgba.Set(x, y, invertColor(img.At(x, y)))
+		})
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCasePaletted(width, height int) *image.Paletted {
+	rnd := rand.New(rand.NewSource(3))
+	palette := make(color.Palette, 200)
+	for i := range palette {
+		palette[i] = color.RGBA{R: uint8(rnd.Intn(256)), G: uint8(rnd.Intn(256)), B: uint8(rnd.Intn(256)), A: 0xFF}
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rnd.Intn(len(palette)))
+	}
+	return img
+}
+
+func invertColor(c color.Color) color.Color {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return color.RGBA{R: rgba.A - rgba.R, G: rgba.A - rgba.G, B: rgba.A - rgba.B, A: rgba.A}
+}
@@ -1,44 +1,128 @@
 package utils
 
 import (
+	"fmt"
 	"image"
-	"math"
 	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
+// minParallelPixels is the number of pixels below which the parallel loops run in the calling goroutine, because
+// starting the workers would cost more then the work itself.
+const minParallelPixels = 4096
+
+// parallelism is the number of workers used by the parallel loops, 0 means runtime.GOMAXPROCS(0).
+var parallelism int32
+
+// SetParallelism sets the number of workers used by ParallelForEachPixel and ParallelForEachRow. A value smaller then 1
+// restores the default, which is the value of runtime.GOMAXPROCS(0).
+func SetParallelism(workers int) {
+	if workers < 1 {
+		workers = 0
+	}
+	atomic.StoreInt32(&parallelism, int32(workers))
+}
+
 // ParallelForEachPixel loops through the image and calls f functions for each [x, y] position.
-// The image is divided into N * N blocks, where N is the number of available processor threads. For each block a
-// parallel Goroutine is started.
+// The rows of the image are divided into chunks which are processed by a pool of workers (see SetParallelism). Each
+// row is processed by a single worker from left to right, so f can safely write data belonging to the current pixel or
+// row. If f panics the remaining chunks are skipped and the panic is raised again in the calling goroutine once all
+// workers have stopped, so it can be recovered there like the panic of a serial loop.
 func ParallelForEachPixel(size image.Point, f func(x int, y int)) {
-	procs := runtime.GOMAXPROCS(0)
-	var waitGroup sync.WaitGroup
-	for i := 0; i < procs; i++ {
-		startX := i * int(math.Floor(float64(size.X)/float64(procs)))
-		var endX int
-		if i < procs-1 {
-			endX = (i + 1) * int(math.Floor(float64(size.X)/float64(procs)))
-		} else {
-			endX = size.X
+	ParallelForEachRow(size, pixelsOfRow(size, f))
+}
+
+// ParallelForEachRow calls f for every row index of the image, it is the row based variant of ParallelForEachPixel
+// which lets f access whole rows of the Pix slices directly. The rows are divided into chunks which are processed by a
+// pool of workers (see SetParallelism). If f panics the panic is raised again in the calling goroutine.
+func ParallelForEachRow(size image.Point, f func(y int)) {
+	if r := parallelRows(size, f); r != nil {
+		panic(r)
+	}
+}
+
+// TryParallelForEachPixel is the variant of ParallelForEachPixel which returns a panic of f as an error instead of
+// raising it again.
+func TryParallelForEachPixel(size image.Point, f func(x int, y int)) error {
+	return TryParallelForEachRow(size, pixelsOfRow(size, f))
+}
+
+// TryParallelForEachRow is the variant of ParallelForEachRow which returns a panic of f as an error instead of raising
+// it again.
+func TryParallelForEachRow(size image.Point, f func(y int)) error {
+	if r := parallelRows(size, f); r != nil {
+		return fmt.Errorf("panic in parallel loop: %v", r)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+func pixelsOfRow(size image.Point, f func(x int, y int)) func(y int) {
+	return func(y int) {
+		for x := 0; x < size.X; x++ {
+			f(x, y)
 		}
-		for j := 0; j < procs; j++ {
-			startY := j * int(math.Floor(float64(size.Y)/float64(procs)))
-			var endY int
-			if j < procs-1 {
-				endY = (j + 1) * int(math.Floor(float64(size.Y)/float64(procs)))
-			} else {
-				endY = size.Y
-			}
-			waitGroup.Add(1)
-			go func(sX int, eX int, sY int, eY int) {
-				defer waitGroup.Done()
-				for x := sX; x < eX; x++ {
-					for y := sY; y < eY; y++ {
-						f(x, y)
-					}
+	}
+}
+
+// parallelRows runs f for every row and returns the value of the first recovered panic, or nil.
+func parallelRows(size image.Point, f func(y int)) interface{} {
+	if size.X <= 0 || size.Y <= 0 {
+		return nil
+	}
+	workers := int(atomic.LoadInt32(&parallelism))
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > size.Y {
+		workers = size.Y
+	}
+	if workers == 1 || size.X*size.Y < minParallelPixels {
+		return runRows(0, size.Y, f)
+	}
+
+	// several chunks per worker balance the load when the rows do not cost the same
+	chunkSize := size.Y / (workers * 4)
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	var next int64
+	var failed int32
+	var firstPanic interface{}
+	var panicOnce sync.Once
+	var waitGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				start := int(atomic.AddInt64(&next, int64(chunkSize))) - chunkSize
+				if start >= size.Y {
+					return
 				}
-			}(startX, endX, startY, endY)
-			waitGroup.Wait()
-		}
+				end := start + chunkSize
+				if end > size.Y {
+					end = size.Y
+				}
+				if r := runRows(start, end, f); r != nil {
+					panicOnce.Do(func() { firstPanic = r })
+					atomic.StoreInt32(&failed, 1)
+					return
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+	return firstPanic
+}
+
+func runRows(start int, end int, f func(y int)) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	for y := start; y < end; y++ {
+		f(y)
 	}
+	return nil
 }
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"image"
+	"math"
 	"testing"
 )
 
@@ -28,3 +29,94 @@ func Test_ParallelForEachPixel(t *testing.T) {
 		}
 	}
 }
+
+func Test_ParallelForEachRow(t *testing.T) {
+	defer SetParallelism(0)
+	for _, workers := range []int{0, 1, 3, 16} {
+		SetParallelism(workers)
+		size := image.Point{X: 100, Y: 301}
+		visits := make([]int, size.Y)
+		ParallelForEachRow(size, func(y int) {
+			visits[y]++
+		})
+		for y, v := range visits {
+			if v != 1 {
+				t.Errorf("Expected row %d to be visited once - actual: %d with %d workers", y, v, workers)
+			}
+		}
+	}
+}
+
+func Test_ParallelForEachPixel_Panic(t *testing.T) {
+	defer SetParallelism(0)
+	for _, workers := range []int{1, 4} {
+		SetParallelism(workers)
+		err := TryParallelForEachPixel(image.Point{X: 200, Y: 200}, func(x int, y int) {
+			if x == 150 && y == 120 {
+				panic("boom")
+			}
+		})
+		if err == nil {
+			t.Errorf("Expected the panic to be returned as an error with %d workers", workers)
+		}
+	}
+}
+
+func Test_ParallelForEachPixel_Repanic(t *testing.T) {
+	defer SetParallelism(0)
+	for _, workers := range []int{1, 4} {
+		SetParallelism(workers)
+		recovered := func() (r interface{}) {
+			defer func() { r = recover() }()
+			ParallelForEachPixel(image.Point{X: 200, Y: 200}, func(x int, y int) {
+				if x == 150 && y == 120 {
+					panic("boom")
+				}
+			})
+			return nil
+		}()
+		if recovered != "boom" {
+			t.Errorf("Expected the panic to be raised in the calling goroutine with %d workers - actual: %v", workers, recovered)
+		}
+	}
+}
+
+func heavyPixel(x int, y int) {
+	v := float64(x*y + 1)
+	for i := 0; i < 200; i++ {
+		v = math.Sqrt(v + float64(i))
+	}
+	if v < 0 {
+		panic("unreachable")
+	}
+}
+
+func Benchmark_ForEachPixel_Heavy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ForEachPixel(image.Point{X: 256, Y: 256}, heavyPixel)
+	}
+}
+
+func Benchmark_ParallelForEachPixel_Heavy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParallelForEachPixel(image.Point{X: 256, Y: 256}, heavyPixel)
+	}
+}
+
+func Benchmark_ForEachPixel_TrivialSmall(b *testing.B) {
+	pix := make([]uint8, 32*32)
+	for i := 0; i < b.N; i++ {
+		ForEachPixel(image.Point{X: 32, Y: 32}, func(x int, y int) {
+			pix[y*32+x]++
+		})
+	}
+}
+
+func Benchmark_ParallelForEachPixel_TrivialSmall(b *testing.B) {
+	pix := make([]uint8, 32*32)
+	for i := 0; i < b.N; i++ {
+		ParallelForEachPixel(image.Point{X: 32, Y: 32}, func(x int, y int) {
+			pix[y*32+x]++
+		})
+	}
+}
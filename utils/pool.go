@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"image"
+	"sync"
+)
+
+// BufferPool keeps pixel buffers of released intermediate images, so that long running services calling the same
+// operations over and over do not allocate a new buffer for every intermediate image. The buffers are grouped by their
+// length and every group is backed by a sync.Pool, so the pool is safe for concurrent use and idle buffers are freed by
+// the garbage collector. A nil *BufferPool is valid and allocates a new buffer for every request.
+type BufferPool struct {
+	pools sync.Map
+}
+
+// NewBufferPool creates an empty buffer pool, which can be shared by any number of goroutines.
+// Example of usage:
+//
+//	pool := utils.NewBufferPool()
+//	res, _, err := convolution.ConvolveGray(img, kernel, image.Point{X: 1, Y: 1}, padding.BorderReflect, utils.WithPool(pool))
+func NewBufferPool() *BufferPool {
+	return &BufferPool{}
+}
+
+// Get returns a zeroed buffer of the given length, taken from the pool if a buffer of the same length was put back
+// earlier. The buffer is owned by the caller until it is returned with Put.
+// Example of usage:
+//
+//	buf := pool.Get(width * height)
+func (p *BufferPool) Get(length int) []uint8 {
+	if p == nil {
+		return make([]uint8, length)
+	}
+	if buf, ok := p.lengthPool(length).Get().(*[]uint8); ok {
+		pix := *buf
+		for i := range pix {
+			pix[i] = 0
+		}
+		return pix
+	}
+	return make([]uint8, length)
+}
+
+// Put returns a buffer to the pool, the buffer must not be used by the caller afterwards. Put on a nil pool does
+// nothing.
+// Example of usage:
+//
+//	pool.Put(buf)
+func (p *BufferPool) Put(buf []uint8) {
+	if p == nil || len(buf) == 0 {
+		return
+	}
+	p.lengthPool(len(buf)).Put(&buf)
+}
+
+// NewGray returns a black grayscale image with the given bounds, its pixels are taken from the pool.
+// Example of usage:
+//
+//	tmp := pool.NewGray(image.Rect(0, 0, 640, 480))
+//	defer pool.PutGray(tmp)
+func (p *BufferPool) NewGray(r image.Rectangle) *image.Gray {
+	return &image.Gray{Pix: p.Get(r.Dx() * r.Dy()), Stride: r.Dx(), Rect: r}
+}
+
+// PutGray returns the pixels of a grayscale image created by NewGray to the pool.
+func (p *BufferPool) PutGray(img *image.Gray) {
+	p.Put(img.Pix)
+}
+
+// NewRGBA returns a transparent black RGBA image with the given bounds, its pixels are taken from the pool.
+// Example of usage:
+//
+//	tmp := pool.NewRGBA(image.Rect(0, 0, 640, 480))
+//	defer pool.PutRGBA(tmp)
+func (p *BufferPool) NewRGBA(r image.Rectangle) *image.RGBA {
+	return &image.RGBA{Pix: p.Get(4 * r.Dx() * r.Dy()), Stride: 4 * r.Dx(), Rect: r}
+}
+
+// PutRGBA returns the pixels of an RGBA image created by NewRGBA to the pool.
+func (p *BufferPool) PutRGBA(img *image.RGBA) {
+	p.Put(img.Pix)
+}
+
+// Options holds the optional settings which can be passed to the operations supporting them.
+type Options struct {
+	// Pool provides the buffers of the intermediate images, nil allocates them
+	Pool *BufferPool
+}
+
+// Option changes one of the optional settings of an operation.
+type Option func(*Options)
+
+// WithPool makes an operation take the buffers of its intermediate images from the given pool and return them before
+// the operation finishes. The result of the operation is always a new image owned by the caller.
+// Example of usage:
+//
+//	res, err := resize.ResizeGray(img, 0.5, 0.5, resize.InterLinear, utils.WithPool(pool))
+func WithPool(pool *BufferPool) Option {
+	return func(o *Options) {
+		o.Pool = pool
+	}
+}
+
+// ApplyOptions collects the given options into an Options value, the settings which are not given keep their zero
+// values.
+// Example of usage:
+//
+//	o := utils.ApplyOptions(opts)
+func ApplyOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// -------------------------------------------------------------------------------------------------------
+func (p *BufferPool) lengthPool(length int) *sync.Pool {
+	if pool, ok := p.pools.Load(length); ok {
+		return pool.(*sync.Pool)
+	}
+	pool, _ := p.pools.LoadOrStore(length, &sync.Pool{})
+	return pool.(*sync.Pool)
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"image"
+	"sync"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_BufferPool_Get(t *testing.T) {
+	pool := NewBufferPool()
+	buf := pool.Get(16)
+	if len(buf) != 16 {
+		t.Fatalf("Expected length: 16 - actual length: %d", len(buf))
+	}
+	for i := range buf {
+		buf[i] = 255
+	}
+	pool.Put(buf)
+	// a reused buffer must not leak the values of its previous user
+	for i := 0; i < 10; i++ {
+		buf = pool.Get(16)
+		for j, v := range buf {
+			if v != 0 {
+				t.Fatalf("Expected zeroed buffer - actual value: %d at: %d", v, j)
+			}
+		}
+		pool.Put(buf)
+	}
+	if other := pool.Get(32); len(other) != 32 {
+		t.Errorf("Expected length: 32 - actual length: %d", len(other))
+	}
+}
+
+func Test_BufferPool_Nil(t *testing.T) {
+	var pool *BufferPool
+	img := pool.NewGray(image.Rect(2, 3, 7, 5))
+	if img.Bounds() != image.Rect(2, 3, 7, 5) || len(img.Pix) != 10 || img.Stride != 5 {
+		t.Fatalf("Unexpected image: bounds %v, length %d, stride %d", img.Bounds(), len(img.Pix), img.Stride)
+	}
+	pool.PutGray(img)
+	rgba := pool.NewRGBA(image.Rect(0, 0, 3, 2))
+	if len(rgba.Pix) != 24 || rgba.Stride != 12 {
+		t.Errorf("Unexpected image: length %d, stride %d", len(rgba.Pix), rgba.Stride)
+	}
+	pool.PutRGBA(rgba)
+}
+
+func Test_BufferPool_Concurrent(t *testing.T) {
+	pool := NewBufferPool()
+	var wg sync.WaitGroup
+	for w := 0; w < 16; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				img := pool.NewGray(image.Rect(0, 0, 8+i%3, 8))
+				for j := range img.Pix {
+					if img.Pix[j] != 0 {
+						t.Errorf("Expected zeroed buffer - actual value: %d", img.Pix[j])
+						return
+					}
+					img.Pix[j] = uint8(w + 1)
+				}
+				pool.PutGray(img)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func Test_ApplyOptions(t *testing.T) {
+	if o := ApplyOptions(nil); o.Pool != nil {
+		t.Error("Expected no pool without options")
+	}
+	pool := NewBufferPool()
+	if o := ApplyOptions([]Option{WithPool(pool)}); o.Pool != pool {
+		t.Error("Expected the given pool")
+	}
+}
+
+// -------------------------------------------------------------------------------
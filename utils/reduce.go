@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// Axis is an enum type for the direction of a reduction
+type Axis int
+
+const (
+	// AxisRows reduces every row to a single value, the result has one value per row
+	AxisRows Axis = iota
+	// AxisColumns reduces every column to a single value, the result has one value per column
+	AxisColumns
+)
+
+// ReduceOp is an enum type for the operations used to reduce a row or a column to a single value
+type ReduceOp int
+
+const (
+	// ReduceSum - sum of the pixel values
+	ReduceSum ReduceOp = iota
+	// ReduceMean - mean of the pixel values
+	ReduceMean
+	// ReduceMin - smallest pixel value
+	ReduceMin
+	// ReduceMax - largest pixel value
+	ReduceMax
+)
+
+// ReduceGray reduces every row or every column of a grayscale image to a single value, e.g. to get the projection
+// profile of a document or the brightness of every scanline. The image is read in a single row-major pass over its
+// Pix slice, also when the columns are reduced. Returns an error if the image is empty or the axis or the operation
+// is invalid.
+// Example of usage:
+//
+//	profile, err := utils.ReduceGray(img, utils.AxisRows, utils.ReduceSum)
+func ReduceGray(img *image.Gray, axis Axis, op ReduceOp) ([]float64, error) {
+	bounds := img.Bounds()
+	res, err := reduce(img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), bounds.Dx(), bounds.Dy(), 1, axis, op)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// ReduceRGBA reduces every row or every column of an RGBA image to a single value per channel, see ReduceGray. The
+// vectors are returned in the order red, green, blue and alpha.
+// Example of usage:
+//
+//	means, err := utils.ReduceRGBA(img, utils.AxisColumns, utils.ReduceMean)
+func ReduceRGBA(img *image.RGBA, axis Axis, op ReduceOp) ([4][]float64, error) {
+	var channels [4][]float64
+	bounds := img.Bounds()
+	res, err := reduce(img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), bounds.Dx(), bounds.Dy(), 4, axis, op)
+	if err != nil {
+		return channels, err
+	}
+	copy(channels[:], res)
+	return channels, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+// reduce reduces the rows or the columns of an interleaved image with the given number of channels per pixel.
+func reduce(pix []uint8, stride int, offset int, width int, height int, channels int, axis Axis, op ReduceOp) ([][]float64, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("empty image")
+	}
+	var length, count int
+	switch axis {
+	case AxisRows:
+		length, count = height, width
+	case AxisColumns:
+		length, count = width, height
+	default:
+		return nil, errors.New("invalid axis")
+	}
+	var init float64
+	switch op {
+	case ReduceSum, ReduceMean:
+	case ReduceMin:
+		init = math.Inf(1)
+	case ReduceMax:
+		init = math.Inf(-1)
+	default:
+		return nil, errors.New("invalid reduce operation")
+	}
+	res := make([][]float64, channels)
+	for c := range res {
+		res[c] = make([]float64, length)
+		for i := range res[c] {
+			res[c][i] = init
+		}
+	}
+	for y := 0; y < height; y++ {
+		row := pix[offset+y*stride : offset+y*stride+width*channels]
+		for c := 0; c < channels; c++ {
+			if axis == AxisColumns {
+				reduceInto(res[c], row[c:], channels, op)
+			} else {
+				res[c][y] = reduceLine(row[c:], channels, width, init, op)
+			}
+		}
+	}
+	if op == ReduceMean {
+		for c := range res {
+			for i := range res[c] {
+				res[c][i] /= float64(count)
+			}
+		}
+	}
+	return res, nil
+}
+
+// reduceInto combines every step-th value of the row with the matching element of dst.
+func reduceInto(dst []float64, row []uint8, step int, op ReduceOp) {
+	switch op {
+	case ReduceSum, ReduceMean:
+		for i := range dst {
+			dst[i] += float64(row[i*step])
+		}
+	case ReduceMin:
+		for i := range dst {
+			if v := float64(row[i*step]); v < dst[i] {
+				dst[i] = v
+			}
+		}
+	case ReduceMax:
+		for i := range dst {
+			if v := float64(row[i*step]); v > dst[i] {
+				dst[i] = v
+			}
+		}
+	}
+}
+
+// reduceLine reduces count values of the row, which are step values apart, to a single value.
+func reduceLine(row []uint8, step int, count int, init float64, op ReduceOp) float64 {
+	acc := init
+	for i := 0; i < count; i++ {
+		v := float64(row[i*step])
+		switch {
+		case op == ReduceSum || op == ReduceMean:
+			acc += v
+		case op == ReduceMin && v < acc:
+			acc = v
+		case op == ReduceMax && v > acc:
+			acc = v
+		}
+	}
+	return acc
+}
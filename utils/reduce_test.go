@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_ReduceGray(t *testing.T) {
+	rnd := rand.New(rand.NewSource(21))
+	parent := image.NewGray(image.Rect(0, 0, 31, 23))
+	rnd.Read(parent.Pix)
+	img := parent.SubImage(image.Rect(2, 3, 29, 20)).(*image.Gray)
+	for _, axis := range []Axis{AxisRows, AxisColumns} {
+		for _, op := range []ReduceOp{ReduceSum, ReduceMean, ReduceMin, ReduceMax} {
+			actual, err := ReduceGray(img, axis, op)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			expected := bruteForceReduce(img.Bounds(), func(x, y int) float64 { return float64(img.GrayAt(x, y).Y) }, axis, op)
+			compareVectors(t, expected, actual)
+		}
+	}
+}
+
+func Test_ReduceRGBA(t *testing.T) {
+	rnd := rand.New(rand.NewSource(22))
+	img := image.NewRGBA(image.Rect(0, 0, 19, 14))
+	rnd.Read(img.Pix)
+	for _, axis := range []Axis{AxisRows, AxisColumns} {
+		for _, op := range []ReduceOp{ReduceSum, ReduceMean, ReduceMin, ReduceMax} {
+			actual, err := ReduceRGBA(img, axis, op)
+			if err != nil {
+				t.Fatalf("Error should not be returned. Error value: %s", err)
+			}
+			for c := 0; c < 4; c++ {
+				expected := bruteForceReduce(img.Bounds(), func(x, y int) float64 {
+					return float64(img.Pix[img.PixOffset(x, y)+c])
+				}, axis, op)
+				compareVectors(t, expected, actual[c])
+			}
+		}
+	}
+}
+
+func Test_ReduceGray_Invalid(t *testing.T) {
+	if _, err := ReduceGray(image.NewGray(image.Rect(0, 0, 0, 5)), AxisRows, ReduceSum); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+	if _, err := ReduceRGBA(image.NewRGBA(image.Rect(0, 0, 5, 0)), AxisColumns, ReduceSum); err == nil {
+		t.Error("Expected error for an empty image")
+	}
+	img := image.NewGray(image.Rect(0, 0, 5, 5))
+	if _, err := ReduceGray(img, Axis(7), ReduceSum); err == nil {
+		t.Error("Expected error for an invalid axis")
+	}
+	if _, err := ReduceGray(img, AxisRows, ReduceOp(7)); err == nil {
+		t.Error("Expected error for an invalid operation")
+	}
+}
+
+func Benchmark_ReduceGray_Columns(b *testing.B) {
+	img := setupTestCaseReduce(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ReduceGray(img, AxisColumns, ReduceSum)
+	}
+}
+
+func Benchmark_ReduceGray_ColumnsNaive(b *testing.B) {
+	img := setupTestCaseReduce(2048, 2048)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// column-major loop which jumps a whole row between the reads
+		res := make([]float64, 2048)
+		for x := 0; x < 2048; x++ {
+			for y := 0; y < 2048; y++ {
+				res[x] += float64(img.Pix[y*img.Stride+x])
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+
+func setupTestCaseReduce(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	rand.New(rand.NewSource(23)).Read(img.Pix)
+	return img
+}
+
+func bruteForceReduce(bounds image.Rectangle, value func(x, y int) float64, axis Axis, op ReduceOp) []float64 {
+	outer, inner := bounds.Dy(), bounds.Dx()
+	if axis == AxisColumns {
+		outer, inner = inner, outer
+	}
+	res := make([]float64, outer)
+	for i := 0; i < outer; i++ {
+		var values []float64
+		for j := 0; j < inner; j++ {
+			if axis == AxisRows {
+				values = append(values, value(bounds.Min.X+j, bounds.Min.Y+i))
+			} else {
+				values = append(values, value(bounds.Min.X+i, bounds.Min.Y+j))
+			}
+		}
+		res[i] = values[0]
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+			switch op {
+			case ReduceMin:
+				res[i] = math.Min(res[i], v)
+			case ReduceMax:
+				res[i] = math.Max(res[i], v)
+			}
+		}
+		switch op {
+		case ReduceSum:
+			res[i] = sum
+		case ReduceMean:
+			res[i] = sum / float64(inner)
+		}
+	}
+	return res
+}
+
+func compareVectors(t *testing.T, expected, actual []float64) {
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected length: %d - actual length: %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if !IsEqualFloat64(expected[i], actual[i]) {
+			t.Errorf("Expected value: %f - actual value: %f at: %d", expected[i], actual[i], i)
+		}
+	}
+}
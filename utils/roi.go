@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"errors"
+	"image"
+)
+
+// ValidateROI returns an error if the region of interest is empty or it is not inside of the given bounds.
+// Example of usage:
+//
+//	err := utils.ValidateROI(img.Bounds(), roi)
+func ValidateROI(bounds image.Rectangle, roi image.Rectangle) error {
+	if roi.Empty() {
+		return errors.New("empty region of interest")
+	}
+	if !roi.In(bounds) {
+		return errors.New("region of interest is outside of the image bounds")
+	}
+	return nil
+}
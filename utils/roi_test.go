@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"image"
+	"testing"
+)
+
+func Test_ValidateROI(t *testing.T) {
+	bounds := image.Rect(0, 0, 10, 8)
+	if err := ValidateROI(bounds, image.Rect(2, 2, 10, 8)); err != nil {
+		t.Errorf("Error should not be returned. Error value: %s", err)
+	}
+	if err := ValidateROI(bounds, image.Rect(2, 2, 2, 5)); err == nil {
+		t.Error("Expected error for empty ROI")
+	}
+	if err := ValidateROI(bounds, image.Rect(-1, 2, 5, 5)); err == nil {
+		t.Error("Expected error for ROI outside of the bounds")
+	}
+}
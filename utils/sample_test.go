@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func setupSampleImage() *image.Gray {
+	return &image.Gray{
+		Rect:   image.Rect(0, 0, 4, 3),
+		Stride: 4,
+		Pix: []uint8{
+			0x10, 0x20, 0x40, 0x80,
+			0x00, 0x30, 0x60, 0xFF,
+			0x08, 0x08, 0x08, 0x08,
+		},
+	}
+}
+
+func Test_BilinearSampleGray(t *testing.T) {
+	img := setupSampleImage()
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			if actual := BilinearSampleGray(img, float64(x), float64(y)); actual != img.GrayAt(x, y).Y {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", img.GrayAt(x, y).Y, actual, x, y)
+			}
+		}
+	}
+	if actual := BilinearSampleGray(img, 1.5, 0); actual != 0x30 {
+		t.Errorf("Expected gray: %d - actual gray: %d", 0x30, actual)
+	}
+	if actual := BilinearSampleGray(img, 2, 0.5); actual != 0x50 {
+		t.Errorf("Expected gray: %d - actual gray: %d", 0x50, actual)
+	}
+	if actual := BilinearSampleGray(img, 10, -3); actual != 0x80 {
+		t.Errorf("Expected clamped gray: %d - actual gray: %d", 0x80, actual)
+	}
+}
+
+func Test_BilinearSampleGrayF64(t *testing.T) {
+	img := setupSampleImage()
+	if actual := BilinearSampleGrayF64(img, 0.25, 0); !IsEqualFloat64(actual, 0x14) {
+		t.Errorf("Expected gray: %d - actual gray: %f", 0x14, actual)
+	}
+	if actual := BilinearSampleGrayF64(img, 0.5, 2); !IsEqualFloat64(actual, 0x08) {
+		t.Errorf("Expected gray: %d - actual gray: %f", 0x08, actual)
+	}
+}
+
+func Test_BilinearSampleRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	copy(img.Pix, []uint8{0x10, 0x20, 0x30, 0xFF, 0x30, 0x40, 0x50, 0x00})
+	if actual := BilinearSampleRGBA(img, 0, 0); actual != img.RGBAAt(0, 0) {
+		t.Errorf("Expected color: %v - actual color: %v", img.RGBAAt(0, 0), actual)
+	}
+	expected := color.RGBA{R: 0x20, G: 0x30, B: 0x40, A: 0x80}
+	if actual := BilinearSampleRGBA(img, 0.5, 0); actual != expected {
+		t.Errorf("Expected color: %v - actual color: %v", expected, actual)
+	}
+	if actual := BilinearSampleRGBA(img, 5, 3); actual != img.RGBAAt(1, 0) {
+		t.Errorf("Expected clamped color: %v - actual color: %v", img.RGBAAt(1, 0), actual)
+	}
+}
+
+func Test_BicubicSampleGray(t *testing.T) {
+	img := setupSampleImage()
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			if actual := BicubicSampleGray(img, float64(x), float64(y)); actual != img.GrayAt(x, y).Y {
+				t.Errorf("Expected gray: %d - actual gray: %d at: %d %d", img.GrayAt(x, y).Y, actual, x, y)
+			}
+		}
+	}
+	// a constant row is reproduced exactly
+	if actual := BicubicSampleGray(img, 1.7, 2); actual != 0x08 {
+		t.Errorf("Expected gray: %d - actual gray: %d", 0x08, actual)
+	}
+	if actual := BicubicSampleGray(img, -5, 20); actual != 0x08 {
+		t.Errorf("Expected clamped gray: %d - actual gray: %d", 0x08, actual)
+	}
+}
@@ -0,0 +1,42 @@
+// Package visualize renders the results of the image processing operations, e.g. segmentation masks, on top of the
+// images they were computed from.
+package visualize
+
+import (
+	"errors"
+	"github.com/yafeiliu/imger/utils"
+	"image"
+	"image/color"
+	"math"
+)
+
+// OverlayMaskRGBA tints the masked region of an RGBA image, e.g. to show the result of a segmentation. Wherever the
+// mask is nonzero the pixel is blended with the tint color: res = (1 - alpha) * pixel + alpha * tint for every channel
+// including the alpha channel, the other pixels are copied unchanged. The alpha has to be in the [0, 1] interval.
+// Returns an error if the alpha is invalid or the size of the mask does not match the size of the image.
+// Example of usage:
+//
+//	res, err := visualize.OverlayMaskRGBA(img, mask, color.RGBA{R: 255, A: 255}, 0.5)
+func OverlayMaskRGBA(img *image.RGBA, mask *image.Gray, tint color.RGBA, alpha float64) (*image.RGBA, error) {
+	if alpha < 0 || alpha > 1 {
+		return nil, errors.New("alpha must be in the [0, 1] interval")
+	}
+	size := img.Bounds().Size()
+	if !size.Eq(mask.Bounds().Size()) {
+		return nil, errors.New("the size of the mask does not match the size of the image")
+	}
+	res := utils.CloneRGBA(img)
+	maskOrigin := mask.Bounds().Min
+	tints := [4]float64{float64(tint.R), float64(tint.G), float64(tint.B), float64(tint.A)}
+	utils.ParallelForEachPixel(size, func(x, y int) {
+		if mask.Pix[mask.PixOffset(maskOrigin.X+x, maskOrigin.Y+y)] == 0 {
+			return
+		}
+		offset := res.PixOffset(x, y)
+		for c, t := range tints {
+			value := (1-alpha)*float64(res.Pix[offset+c]) + alpha*t
+			res.Pix[offset+c] = uint8(utils.ClampF64(math.Round(value), utils.MinUint8, float64(utils.MaxUint8)))
+		}
+	})
+	return res, nil
+}
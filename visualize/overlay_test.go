@@ -0,0 +1,54 @@
+package visualize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ---------------------------------Unit tests------------------------------------
+func Test_OverlayMaskRGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 12, 10))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 100, 100, 100, 255
+	}
+	mask := image.NewGray(image.Rect(0, 0, 12, 10))
+	square := image.Rect(3, 2, 8, 7)
+	for y := square.Min.Y; y < square.Max.Y; y++ {
+		for x := square.Min.X; x < square.Max.X; x++ {
+			mask.SetGray(x, y, color.Gray{Y: 1})
+		}
+	}
+	res, err := OverlayMaskRGBA(img, mask, color.RGBA{R: 255, A: 255}, 0.5)
+	if err != nil {
+		t.Fatalf("Error should not be returned. Error value: %s", err)
+	}
+	tinted := color.RGBA{R: 178, G: 50, B: 50, A: 255}
+	unchanged := color.RGBA{R: 100, G: 100, B: 100, A: 255}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 12; x++ {
+			expected := unchanged
+			if (image.Point{X: x, Y: y}).In(square) {
+				expected = tinted
+			}
+			if actual := res.RGBAAt(x, y); actual != expected {
+				t.Fatalf("Expected color: %v - actual color: %v at: %d %d", expected, actual, x, y)
+			}
+		}
+	}
+	if img.RGBAAt(4, 4) != unchanged {
+		t.Error("Expected the input image to be unchanged")
+	}
+}
+
+func Test_OverlayMaskRGBA_Invalid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if _, err := OverlayMaskRGBA(img, image.NewGray(image.Rect(0, 0, 4, 3)), color.RGBA{}, 0.5); err == nil {
+		t.Error("Expected error for a mask of a different size")
+	}
+	if _, err := OverlayMaskRGBA(img, image.NewGray(image.Rect(0, 0, 4, 4)), color.RGBA{}, 1.5); err == nil {
+		t.Error("Expected error for an alpha outside of [0, 1]")
+	}
+}
+
+// -------------------------------------------------------------------------------